@@ -0,0 +1,104 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
+	"github.com/Azure/ARO-RP/pkg/util/billing"
+	"github.com/Azure/ARO-RP/pkg/util/clusterdata"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// billingReconcile compares the billing records with the cluster documents,
+// records the clusters' workers and, if BILLING_EXPORT_STORAGE_ACCOUNT_ID is
+// set, exports usage to that storage account
+func billingReconcile(ctx context.Context, log *logrus.Entry) error {
+	_env, err := env.NewEnv(ctx, log, env.COMPONENT_BILLING)
+	if err != nil {
+		return err
+	}
+
+	if !_env.IsLocalDevelopmentMode() {
+		if err = env.ValidateVars("MDM_ACCOUNT", "MDM_NAMESPACE"); err != nil {
+			return err
+		}
+	}
+
+	if err = env.ValidateVars(envDatabaseAccountName); err != nil {
+		return err
+	}
+
+	m := statsd.New(ctx, log.WithField("component", "billing"), _env, os.Getenv("MDM_ACCOUNT"), os.Getenv("MDM_NAMESPACE"), os.Getenv("MDM_STATSD_SOCKET"))
+
+	msiToken, err := _env.NewMSITokenCredential()
+	if err != nil {
+		return err
+	}
+
+	aead, err := encryption.NewMulti(ctx, _env.ServiceKeyvault(), env.EncryptionSecretV2Name, env.EncryptionSecretName)
+	if err != nil {
+		return err
+	}
+
+	dbAccountName := os.Getenv(envDatabaseAccountName)
+	clientOptions := &policy.ClientOptions{
+		ClientOptions: _env.Environment().ManagedIdentityCredentialOptions().ClientOptions,
+	}
+	dbAuthorizer, err := database.NewMasterKeyAuthorizer(ctx, log.WithField("component", "database"), msiToken, clientOptions, _env.SubscriptionID(), _env.ResourceGroup(), dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	dbc, err := database.NewDatabaseClient(log.WithField("component", "database"), _env, dbAuthorizer, m, aead, dbAccountName)
+	if err != nil {
+		return err
+	}
+
+	dbName, err := DBName(_env.IsLocalDevelopmentMode())
+	if err != nil {
+		return err
+	}
+
+	dbBilling, err := database.NewBilling(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	dbOpenShiftClusters, err := database.NewOpenShiftClusters(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	report, err := billing.NewReconciler(log, dbBilling, dbOpenShiftClusters, m, clusterdata.NewParallelEnricher(m, _env)).Reconcile(ctx)
+	if err != nil {
+		return err
+	}
+
+	storageAccountID := os.Getenv(envBillingExportStorageAccountID)
+	if storageAccountID == "" {
+		log.Printf("%s not set, not exporting usage", envBillingExportStorageAccountID)
+		return nil
+	}
+
+	exporter, err := billing.NewExporter(_env, storageAccountID)
+	if err != nil {
+		return err
+	}
+
+	err = exporter.Export(ctx, report)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("exported %d usage records", len(report.Usage))
+	return nil
+}
@@ -10,4 +10,22 @@ const (
 	envDBTokenUrl            = "DBTOKEN_URL"
 	envOpenShiftVersions     = "OPENSHIFT_VERSIONS"
 	envInstallerImageDigests = "INSTALLER_IMAGE_DIGESTS"
+
+	envMonitorBalanceHysteresis = "MONITOR_BALANCE_HYSTERESIS"
+
+	envMaxCreatesPerSubscription = "MAX_CREATES_PER_SUBSCRIPTION"
+
+	envDBTokenPolicy = "DBTOKEN_POLICY"
+
+	envGatewayMaxConnectionsPerCluster = "GATEWAY_MAX_CONNECTIONS_PER_CLUSTER"
+	envGatewayIdleTimeout              = "GATEWAY_IDLE_TIMEOUT"
+
+	envBillingExportStorageAccountID = "BILLING_EXPORT_STORAGE_ACCOUNT_ID"
+
+	envPortalRecordingStorageAccountID = "PORTAL_RECORDING_STORAGE_ACCOUNT_ID"
+	envPortalRequireAccessGrants       = "PORTAL_REQUIRE_ACCESS_GRANTS"
 )
+
+// defaultMonitorBalanceHysteresis lets a monitor keep its buckets until its
+// load is 10% above its fair share
+const defaultMonitorBalanceHysteresis = 0.1
@@ -5,6 +5,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"net"
 	"os"
 
@@ -21,16 +22,35 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 )
 
-func dbtoken(ctx context.Context, log *logrus.Entry) error {
+func dbtoken(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 	_env, err := env.NewCore(ctx, log, env.COMPONENT_DBTOKEN)
 	if err != nil {
 		return err
 	}
 
-	if err := env.ValidateVars("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID", "AZURE_DBTOKEN_CLIENT_ID"); err != nil {
+	if err := env.ValidateVars("AZURE_DBTOKEN_CLIENT_ID"); err != nil {
 		return err
 	}
 
+	// without an explicit policy, only the gateway is granted access
+	var dbtokenPolicy *pkgdbtoken.Policy
+	if v := os.Getenv(envDBTokenPolicy); v != "" {
+		dbtokenPolicy, err = pkgdbtoken.ParsePolicy([]byte(v))
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", envDBTokenPolicy, err)
+		}
+	} else {
+		if err := env.ValidateVars("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID"); err != nil {
+			return err
+		}
+
+		dbtokenPolicy = pkgdbtoken.DefaultPolicy(os.Getenv("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID"))
+		err = dbtokenPolicy.Validate()
+		if err != nil {
+			return err
+		}
+	}
+
 	if !_env.IsLocalDevelopmentMode() {
 		if err := env.ValidateVars("MDM_ACCOUNT", "MDM_NAMESPACE"); err != nil {
 			return err
@@ -83,7 +103,7 @@ func dbtoken(ctx context.Context, log *logrus.Entry) error {
 
 	userc := cosmosdb.NewUserClient(dbc, dbName)
 
-	err = pkgdbtoken.ConfigurePermissions(ctx, dbName, userc)
+	err = pkgdbtoken.ConfigurePermissions(ctx, dbName, userc, dbtokenPolicy)
 	if err != nil {
 		return err
 	}
@@ -121,7 +141,7 @@ func dbtoken(ctx context.Context, log *logrus.Entry) error {
 
 	log.Print("listening")
 
-	server, err := pkgdbtoken.NewServer(ctx, _env, log.WithField("component", "dbtoken"), log.WithField("component", "dbtoken-access"), l, servingKey, servingCerts, verifier, userc, m)
+	server, err := pkgdbtoken.NewServer(ctx, _env, log.WithField("component", "dbtoken"), log.WithField("component", "dbtoken-access"), audit, l, servingKey, servingCerts, verifier, userc, dbtokenPolicy, m)
 	if err != nil {
 		return err
 	}
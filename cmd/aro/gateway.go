@@ -5,8 +5,10 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -109,9 +111,25 @@ func gateway(ctx context.Context, log *logrus.Entry) error {
 		return err
 	}
 
+	var maxConnectionsPerCluster int
+	if v := os.Getenv(envGatewayMaxConnectionsPerCluster); v != "" {
+		maxConnectionsPerCluster, err = strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", envGatewayMaxConnectionsPerCluster, v)
+		}
+	}
+
+	var idleTimeout time.Duration
+	if v := os.Getenv(envGatewayIdleTimeout); v != "" {
+		idleTimeout, err = time.ParseDuration(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", envGatewayIdleTimeout, v)
+		}
+	}
+
 	log.Print("listening")
 
-	p, err := pkggateway.NewGateway(ctx, _env, log.WithField("component", "gateway"), log.WithField("component", "gateway-access"), dbGateway, httpsl, httpl, healthListener, os.Getenv("ACR_RESOURCE_ID"), os.Getenv("GATEWAY_DOMAINS"), m)
+	p, err := pkggateway.NewGateway(ctx, _env, log.WithField("component", "gateway"), log.WithField("component", "gateway-access"), dbGateway, httpsl, httpl, healthListener, os.Getenv("ACR_RESOURCE_ID"), os.Getenv("GATEWAY_DOMAINS"), maxConnectionsPerCluster, idleTimeout, m)
 	if err != nil {
 		return err
 	}
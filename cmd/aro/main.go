@@ -20,12 +20,14 @@ import (
 
 func usage() {
 	fmt.Fprint(flag.CommandLine.Output(), "usage:\n")
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s billing-reconcile\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s dbtoken\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s deploy config.yaml location\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s gateway\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s mirror [release_image...]\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s monitor\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s portal\n", os.Args[0])
+	fmt.Fprintf(flag.CommandLine.Output(), "  %s reencrypt\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s rp\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s operator {master,worker}\n", os.Args[0])
 	fmt.Fprintf(flag.CommandLine.Output(), "  %s update-versions\n", os.Args[0])
@@ -48,9 +50,12 @@ func main() {
 
 	var err error
 	switch strings.ToLower(flag.Arg(0)) {
+	case "billing-reconcile":
+		checkArgs(1)
+		err = billingReconcile(ctx, log)
 	case "dbtoken":
 		checkArgs(1)
-		err = dbtoken(ctx, log)
+		err = dbtoken(ctx, log, audit)
 	case "deploy":
 		checkArgs(3)
 		err = deploy(ctx, log)
@@ -63,6 +68,9 @@ func main() {
 	case "monitor":
 		checkArgs(1)
 		err = monitor(ctx, log)
+	case "reencrypt":
+		checkArgs(1)
+		err = reencrypt(ctx, log)
 	case "rp":
 		checkArgs(1)
 		err = rp(ctx, log, audit)
@@ -5,7 +5,9 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
 	"github.com/Azure/go-autorest/tracing"
@@ -131,7 +133,15 @@ func monitor(ctx context.Context, log *logrus.Entry) error {
 		return err
 	}
 
-	mon := pkgmonitor.NewMonitor(log.WithField("component", "monitor"), dialer, dbMonitors, dbOpenShiftClusters, dbSubscriptions, m, clusterm, liveConfig, _env)
+	balanceHysteresis := defaultMonitorBalanceHysteresis
+	if v := os.Getenv(envMonitorBalanceHysteresis); v != "" {
+		balanceHysteresis, err = strconv.ParseFloat(v, 64)
+		if err != nil || balanceHysteresis < 0 {
+			return fmt.Errorf("invalid %s %q", envMonitorBalanceHysteresis, v)
+		}
+	}
+
+	mon := pkgmonitor.NewMonitor(log.WithField("component", "monitor"), dialer, dbMonitors, dbOpenShiftClusters, dbSubscriptions, m, clusterm, liveConfig, _env, balanceHysteresis)
 
 	return mon.Run(ctx)
 }
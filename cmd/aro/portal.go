@@ -18,6 +18,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
 	"github.com/Azure/ARO-RP/pkg/metrics/statsd/golang"
 	pkgportal "github.com/Azure/ARO-RP/pkg/portal"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
@@ -126,6 +127,15 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		return err
 	}
 
+	dbPortalAccessGrants, err := database.NewPortalAccessGrants(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	// Access grants can always be requested and reviewed, but elevated SSH and
+	// kubeconfig access only requires one once this is set.
+	_, requireAccessGrants := os.LookupEnv(envPortalRequireAccessGrants)
+
 	portalKeyvaultURI := keyvault.URI(_env, env.PortalKeyvaultSuffix, keyVaultPrefix)
 	portalKeyvault := keyvault.NewManager(msiKVAuthorizer, portalKeyvaultURI)
 
@@ -159,6 +169,33 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 		return err
 	}
 
+	// Session recording is enabled by configuring a storage account to write
+	// the recordings to.
+	var recorder *recording.Recorder
+	if storageAccountID := os.Getenv(envPortalRecordingStorageAccountID); storageAccountID != "" {
+		recordingKey, err := portalKeyvault.GetBase64Secret(ctx, env.PortalRecordingKeySecretName, "")
+		if err != nil {
+			return err
+		}
+
+		recordingAEAD, err := encryption.NewXChaCha20Poly1305(ctx, recordingKey)
+		if err != nil {
+			return err
+		}
+
+		msiAuthorizer, err := _env.NewMSIAuthorizer(_env.Environment().ResourceManagerScope)
+		if err != nil {
+			return err
+		}
+
+		store, err := recording.NewBlobStore(_env, storageAccountID, msiAuthorizer)
+		if err != nil {
+			return err
+		}
+
+		recorder = recording.NewRecorder(log.WithField("component", "portal-recording"), recordingAEAD, store)
+	}
+
 	clientID := os.Getenv("AZURE_PORTAL_CLIENT_ID")
 	verifier, err := oidc.NewVerifier(ctx, _env.Environment().ActiveDirectoryEndpoint+_env.TenantID()+"/v2.0", clientID)
 	if err != nil {
@@ -194,7 +231,7 @@ func portal(ctx context.Context, log *logrus.Entry, audit *logrus.Entry) error {
 
 	log.Printf("listening %s", address)
 
-	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, dialer, m)
+	p := pkgportal.NewPortal(_env, audit, log.WithField("component", "portal"), log.WithField("component", "portal-access"), l, sshl, verifier, hostname, servingKey, servingCerts, clientID, clientKey, clientCerts, sessionKey, sshKey, groupIDs, elevatedGroupIDs, dbOpenShiftClusters, dbPortal, dbPortalAccessGrants, requireAccessGrants, dialer, recorder, m)
 
 	return p.Run(ctx)
 }
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm/policy"
@@ -163,7 +164,20 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 		return err
 	}
 
-	go database.EmitMetrics(ctx, log, dbOpenShiftClusters, metrics)
+	dbBatchOperations, err := database.NewBatchOperations(ctx, dbc, dbName)
+	if err != nil {
+		return err
+	}
+
+	maxCreatesPerSubscription := backend.DefaultMaxCreatesPerSubscription
+	if v := os.Getenv(envMaxCreatesPerSubscription); v != "" {
+		maxCreatesPerSubscription, err = strconv.Atoi(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s %q", envMaxCreatesPerSubscription, v)
+		}
+	}
+
+	go database.EmitMetrics(ctx, log, dbOpenShiftClusters, metrics, maxCreatesPerSubscription)
 
 	feAead, err := encryption.NewMulti(ctx, _env.ServiceKeyvault(), env.FrontendEncryptionSecretV2Name, env.FrontendEncryptionSecretName)
 	if err != nil {
@@ -173,12 +187,12 @@ func rp(ctx context.Context, log, audit *logrus.Entry) error {
 	if err != nil {
 		return err
 	}
-	f, err := frontend.NewFrontend(ctx, audit, log.WithField("component", "frontend"), _env, dbAsyncOperations, dbClusterManagerConfiguration, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, api.APIs, metrics, clusterm, feAead, hiveClusterManager, adminactions.NewKubeActions, adminactions.NewAzureActions, clusterdata.NewParallelEnricher(metrics, _env))
+	f, err := frontend.NewFrontend(ctx, audit, log.WithField("component", "frontend"), _env, dbAsyncOperations, dbClusterManagerConfiguration, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, dbBatchOperations, api.APIs, metrics, clusterm, feAead, hiveClusterManager, adminactions.NewKubeActions, adminactions.NewAzureActions, clusterdata.NewParallelEnricher(metrics, _env))
 	if err != nil {
 		return err
 	}
 
-	b, err := backend.NewBackend(ctx, log.WithField("component", "backend"), _env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, aead, metrics)
+	b, err := backend.NewBackend(ctx, log.WithField("component", "backend"), _env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, dbBatchOperations, aead, metrics, clusterm, maxCreatesPerSubscription)
 	if err != nil {
 		return err
 	}
@@ -172,6 +172,14 @@ import_certs_secrets() {
         --vault-name "$KEYVAULT_PREFIX-por" \
         --name portal-session-key \
         --value "$(openssl rand -base64 32)" >/dev/null
+    az keyvault secret list \
+        --vault-name "$KEYVAULT_PREFIX-por" \
+        --query '[].name' \
+        -o tsv | grep -q ^portal-recording-key$ || \
+    az keyvault secret set \
+        --vault-name "$KEYVAULT_PREFIX-por" \
+        --name portal-recording-key \
+        --value "$(openssl rand -base64 32)" >/dev/null
     az keyvault secret list \
         --vault-name "$KEYVAULT_PREFIX-por" \
         --query '[].name' \
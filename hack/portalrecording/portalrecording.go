@@ -0,0 +1,108 @@
+package main
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/keyvault"
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+)
+
+const (
+	KeyVaultPrefix = "KEYVAULT_PREFIX"
+)
+
+// openers opens values sealed with any of several keys
+type openers []encryption.AEAD
+
+func (o openers) Open(input []byte) ([]byte, error) {
+	for _, aead := range o {
+		b, err := aead.Open(input)
+		if err == nil {
+			return b, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no version of %s opens the value", env.PortalRecordingKeySecretName)
+}
+
+func (o openers) Seal([]byte) ([]byte, error) {
+	return nil, fmt.Errorf("not implemented")
+}
+
+func run(ctx context.Context, log *logrus.Entry) error {
+	fileName := flag.String("file", "-", "Recording to decrypt. '-' for stdin.")
+
+	flag.Parse()
+
+	var (
+		file io.Reader
+		err  error
+	)
+
+	if *fileName == "-" {
+		file = os.Stdin
+	} else {
+		f, err := os.Open(*fileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		file = f
+	}
+
+	_env, err := env.NewCore(ctx, log, env.COMPONENT_TOOLING)
+	if err != nil {
+		return err
+	}
+
+	msiKVAuthorizer, err := _env.NewMSIAuthorizer(_env.Environment().KeyVaultScope)
+	if err != nil {
+		return err
+	}
+
+	if err := env.ValidateVars(KeyVaultPrefix); err != nil {
+		return err
+	}
+	keyVaultPrefix := os.Getenv(KeyVaultPrefix)
+	portalKeyvaultURI := keyvault.URI(_env, env.PortalKeyvaultSuffix, keyVaultPrefix)
+	portalKeyvault := keyvault.NewManager(msiKVAuthorizer, portalKeyvaultURI)
+
+	// recordings outlive key rotations, so try every version of the key
+	keys, err := portalKeyvault.GetBase64Secrets(ctx, env.PortalRecordingKeySecretName)
+	if err != nil {
+		return err
+	}
+
+	var o openers
+	for _, key := range keys {
+		aead, err := encryption.NewXChaCha20Poly1305(ctx, key)
+		if err != nil {
+			return err
+		}
+
+		o = append(o, aead)
+	}
+
+	return recording.Decode(os.Stdout, file, o)
+}
+
+func main() {
+	log := utillog.GetLogger()
+
+	if err := run(context.Background(), log); err != nil {
+		log.Fatal(err)
+	}
+}
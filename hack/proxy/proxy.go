@@ -4,8 +4,13 @@ package main
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"flag"
+	"os"
+	"strings"
 
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/metrics/statsd"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	"github.com/Azure/ARO-RP/pkg/util/version"
@@ -15,7 +20,9 @@ func main() {
 	certFile := flag.String("certFile", "secrets/proxy.crt", "file containing server certificate")
 	keyFile := flag.String("keyFile", "secrets/proxy.key", "file containing server key")
 	clientCertFile := flag.String("clientCertFile", "secrets/proxy-client.crt", "file containing client certificate")
+	insecureNoClientAuth := flag.Bool("insecureNoClientAuth", false, "allow starting with an empty clientCertFile, disabling mutual TLS")
 	subnet := flag.String("subnet", "10.0.0.0/8", "allowed subnet")
+	allowlist := flag.String("allowlist", "", "comma separated host:port destination patterns, e.g. 10.0.0.0/8:6443,*.example.com:443")
 
 	log := utillog.GetLogger()
 
@@ -23,13 +30,28 @@ func main() {
 
 	flag.Parse()
 
+	ctx := context.Background()
+
+	_env, err := env.NewCore(ctx, log, env.COMPONENT_TOOLING)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m := statsd.New(ctx, log.WithField("component", "proxy"), _env, os.Getenv("MDM_ACCOUNT"), os.Getenv("MDM_NAMESPACE"), os.Getenv("MDM_STATSD_SOCKET"))
+
 	s := &proxy.Server{
 		Log: log,
+		M:   m,
+
+		CertFile:             *certFile,
+		KeyFile:              *keyFile,
+		ClientCertFile:       *clientCertFile,
+		InsecureNoClientAuth: *insecureNoClientAuth,
+		Subnet:               *subnet,
+	}
 
-		CertFile:       *certFile,
-		KeyFile:        *keyFile,
-		ClientCertFile: *clientCertFile,
-		Subnet:         *subnet,
+	if *allowlist != "" {
+		s.Allowlist = strings.Split(*allowlist, ",")
 	}
 
 	if err := s.Run(); err != nil {
@@ -0,0 +1,96 @@
+package admin
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// BatchOperationList represents a list of batch operations.
+type BatchOperationList struct {
+	BatchOperations []*BatchOperation `json:"value"`
+}
+
+// BatchOperation represents an admin action run across many clusters.
+type BatchOperation struct {
+	// The ID for the resource.
+	ID string `json:"id,omitempty"`
+
+	// The properties for the BatchOperation resource.
+	Properties BatchOperationProperties `json:"properties,omitempty"`
+}
+
+// BatchOperationProperties represents the properties of a BatchOperation.
+type BatchOperationProperties struct {
+	// Action is the admin action to run against each selected cluster.
+	Action BatchOperationAction `json:"action,omitempty"`
+
+	// Parameters holds the action-specific parameters.
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	// Selector selects the clusters that the action runs against.
+	Selector BatchOperationSelector `json:"selector,omitempty"`
+
+	// MaxConcurrency is the maximum number of clusters that the action runs
+	// against at once.
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+
+	// FailureBudget is the number of per-cluster failures tolerated before
+	// the batch operation stops.
+	FailureBudget int `json:"failureBudget,omitempty"`
+
+	State     BatchOperationState `json:"state,omitempty" swagger:"readOnly"`
+	Error     string              `json:"error,omitempty" swagger:"readOnly"`
+	StartTime *time.Time          `json:"startTime,omitempty" swagger:"readOnly"`
+	EndTime   *time.Time          `json:"endTime,omitempty" swagger:"readOnly"`
+
+	Clusters []BatchOperationCluster `json:"clusters,omitempty" swagger:"readOnly"`
+}
+
+// BatchOperationAction represents an admin action supported by batch
+// operations.
+type BatchOperationAction string
+
+// BatchOperationAction constants.
+const (
+	BatchOperationActionApproveCSR             BatchOperationAction = "approvecsr"
+	BatchOperationActionEtcdCertificateRenew   BatchOperationAction = "etcdcertificaterenew"
+	BatchOperationActionKubernetesObjectDelete BatchOperationAction = "kubernetesobjectdelete"
+	BatchOperationActionRedeployVM             BatchOperationAction = "redeployvm"
+)
+
+// BatchOperationSelector selects the clusters a batch operation runs against.
+// All non-empty fields must match for a cluster to be selected.
+type BatchOperationSelector struct {
+	ResourceIDs     []string `json:"resourceIds,omitempty"`
+	SubscriptionIDs []string `json:"subscriptionIds,omitempty"`
+	Locations       []string `json:"locations,omitempty"`
+
+	// MinVersion is the inclusive lower bound of the cluster version.
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// MaxVersion is the exclusive upper bound of the cluster version.
+	MaxVersion string `json:"maxVersion,omitempty"`
+}
+
+// BatchOperationState represents the state of a batch operation or of one of
+// its clusters.
+type BatchOperationState string
+
+// BatchOperationState constants.
+const (
+	BatchOperationStatePending   BatchOperationState = "Pending"
+	BatchOperationStateRunning   BatchOperationState = "Running"
+	BatchOperationStateSucceeded BatchOperationState = "Succeeded"
+	BatchOperationStateFailed    BatchOperationState = "Failed"
+	BatchOperationStateSkipped   BatchOperationState = "Skipped"
+)
+
+// BatchOperationCluster represents the progress of a batch operation against
+// a single cluster.
+type BatchOperationCluster struct {
+	ResourceID string              `json:"resourceId,omitempty"`
+	State      BatchOperationState `json:"state,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
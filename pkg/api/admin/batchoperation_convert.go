@@ -0,0 +1,105 @@
+package admin
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+type batchOperationConverter struct{}
+
+// batchOperationConverter.ToExternal returns a new external representation
+// of the internal object, reading from the subset of the internal object's
+// fields that appear in the external representation.  ToExternal does not
+// modify its argument; there is no pointer aliasing between the passed and
+// returned objects.
+func (batchOperationConverter) ToExternal(bo *api.BatchOperation) interface{} {
+	out := &BatchOperation{
+		ID: bo.ID,
+		Properties: BatchOperationProperties{
+			Action:         BatchOperationAction(bo.Action),
+			MaxConcurrency: bo.MaxConcurrency,
+			FailureBudget:  bo.FailureBudget,
+			State:          BatchOperationState(bo.State),
+			Error:          bo.Error,
+			Selector: BatchOperationSelector{
+				ResourceIDs:     append([]string(nil), bo.Selector.ResourceIDs...),
+				SubscriptionIDs: append([]string(nil), bo.Selector.SubscriptionIDs...),
+				Locations:       append([]string(nil), bo.Selector.Locations...),
+				MinVersion:      bo.Selector.MinVersion,
+				MaxVersion:      bo.Selector.MaxVersion,
+			},
+		},
+	}
+
+	if bo.Parameters != nil {
+		out.Properties.Parameters = make(map[string]string, len(bo.Parameters))
+		for k, v := range bo.Parameters {
+			out.Properties.Parameters[k] = v
+		}
+	}
+
+	if bo.StartTime != nil {
+		t := *bo.StartTime
+		out.Properties.StartTime = &t
+	}
+
+	if bo.EndTime != nil {
+		t := *bo.EndTime
+		out.Properties.EndTime = &t
+	}
+
+	if bo.Clusters != nil {
+		out.Properties.Clusters = make([]BatchOperationCluster, 0, len(bo.Clusters))
+		for _, c := range bo.Clusters {
+			out.Properties.Clusters = append(out.Properties.Clusters, BatchOperationCluster{
+				ResourceID: c.ResourceID,
+				State:      BatchOperationState(c.State),
+				Error:      c.Error,
+			})
+		}
+	}
+
+	return out
+}
+
+// ToExternalList returns a slice of external representations of the internal
+// objects
+func (c batchOperationConverter) ToExternalList(bos []*api.BatchOperation) interface{} {
+	l := &BatchOperationList{
+		BatchOperations: make([]*BatchOperation, 0, len(bos)),
+	}
+
+	for _, bo := range bos {
+		l.BatchOperations = append(l.BatchOperations, c.ToExternal(bo).(*BatchOperation))
+	}
+
+	return l
+}
+
+// ToInternal overwrites in place a pre-existing internal object, setting (only)
+// all mapped fields from the external representation. Read-only fields are not
+// mapped. ToInternal modifies its argument; there is no pointer aliasing
+// between the passed and returned objects
+func (c batchOperationConverter) ToInternal(_new interface{}, out *api.BatchOperation) {
+	new := _new.(*BatchOperation)
+
+	out.Action = api.BatchOperationAction(new.Properties.Action)
+	out.MaxConcurrency = new.Properties.MaxConcurrency
+	out.FailureBudget = new.Properties.FailureBudget
+
+	out.Parameters = nil
+	if new.Properties.Parameters != nil {
+		out.Parameters = make(map[string]string, len(new.Properties.Parameters))
+		for k, v := range new.Properties.Parameters {
+			out.Parameters[k] = v
+		}
+	}
+
+	out.Selector.ResourceIDs = append([]string(nil), new.Properties.Selector.ResourceIDs...)
+	out.Selector.SubscriptionIDs = append([]string(nil), new.Properties.Selector.SubscriptionIDs...)
+	out.Selector.Locations = append([]string(nil), new.Properties.Selector.Locations...)
+	out.Selector.MinVersion = new.Properties.Selector.MinVersion
+	out.Selector.MaxVersion = new.Properties.Selector.MaxVersion
+}
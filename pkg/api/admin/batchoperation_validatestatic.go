@@ -0,0 +1,139 @@
+package admin
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+// MaxBatchOperationConcurrency is the upper bound of
+// BatchOperationProperties.MaxConcurrency
+const MaxBatchOperationConcurrency = 50
+
+type batchOperationStaticValidator struct{}
+
+// Validate validates a BatchOperation.  Batch operations cannot be modified
+// once they are created.
+func (sv batchOperationStaticValidator) Static(_new interface{}) error {
+	new := _new.(*BatchOperation)
+
+	err := sv.validateAction(new.Properties.Action, new.Properties.Parameters)
+	if err != nil {
+		return err
+	}
+
+	err = sv.validateSelector(&new.Properties.Selector)
+	if err != nil {
+		return err
+	}
+
+	if new.Properties.MaxConcurrency < 0 || new.Properties.MaxConcurrency > MaxBatchOperationConcurrency {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.maxConcurrency", fmt.Sprintf("The provided maxConcurrency '%d' is invalid: must be between 0 and %d.", new.Properties.MaxConcurrency, MaxBatchOperationConcurrency))
+	}
+
+	if new.Properties.FailureBudget < 0 {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.failureBudget", fmt.Sprintf("The provided failureBudget '%d' is invalid: must not be negative.", new.Properties.FailureBudget))
+	}
+
+	return nil
+}
+
+func (sv batchOperationStaticValidator) validateAction(action BatchOperationAction, parameters map[string]string) error {
+	var required, allowed []string
+
+	switch action {
+	case BatchOperationActionApproveCSR, BatchOperationActionEtcdCertificateRenew:
+	case BatchOperationActionKubernetesObjectDelete:
+		required = []string{"kind", "name"}
+		allowed = []string{"namespace", "force"}
+	case BatchOperationActionRedeployVM:
+		required = []string{"vmNameSuffix"}
+	default:
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.action", fmt.Sprintf("The provided action '%s' is invalid.", action))
+	}
+
+	for _, k := range required {
+		if parameters[k] == "" {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.parameters."+k, "Must be provided.")
+		}
+	}
+
+	keys := make([]string, 0, len(parameters))
+	for k := range parameters {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		if !contains(required, k) && !contains(allowed, k) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.parameters."+k, fmt.Sprintf("The parameter '%s' is not supported by action '%s'.", k, action))
+		}
+	}
+
+	if force, ok := parameters["force"]; ok {
+		_, err := strconv.ParseBool(force)
+		if err != nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.parameters.force", fmt.Sprintf("The provided force value '%s' is invalid.", force))
+		}
+	}
+
+	return nil
+}
+
+func (sv batchOperationStaticValidator) validateSelector(s *BatchOperationSelector) error {
+	if len(s.ResourceIDs) == 0 && len(s.SubscriptionIDs) == 0 && len(s.Locations) == 0 &&
+		s.MinVersion == "" && s.MaxVersion == "" {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.selector", "Must select at least one of resourceIds, subscriptionIds, locations, minVersion or maxVersion.")
+	}
+
+	for i, resourceID := range s.ResourceIDs {
+		r, err := azure.ParseResourceID(resourceID)
+		if err != nil ||
+			!strings.EqualFold(r.Provider, "Microsoft.RedHatOpenShift") ||
+			!strings.EqualFold(r.ResourceType, "openShiftClusters") {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, fmt.Sprintf("properties.selector.resourceIds[%d]", i), fmt.Sprintf("The provided resource ID '%s' is invalid.", resourceID))
+		}
+	}
+
+	var min, max *version.Version
+	var err error
+
+	if s.MinVersion != "" {
+		min, err = version.ParseVersion(s.MinVersion)
+		if err != nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.selector.minVersion", fmt.Sprintf("The provided version '%s' is invalid.", s.MinVersion))
+		}
+	}
+
+	if s.MaxVersion != "" {
+		max, err = version.ParseVersion(s.MaxVersion)
+		if err != nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.selector.maxVersion", fmt.Sprintf("The provided version '%s' is invalid.", s.MaxVersion))
+		}
+	}
+
+	if min != nil && max != nil && !min.Lt(max) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.selector.maxVersion", "Must be greater than minVersion.")
+	}
+
+	return nil
+}
+
+func contains(s []string, v string) bool {
+	for _, i := range s {
+		if i == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,147 @@
+package admin
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/test/validate"
+)
+
+func TestBatchOperationStaticValidate(t *testing.T) {
+	validBatchOperation := func() *BatchOperation {
+		return &BatchOperation{
+			Properties: BatchOperationProperties{
+				Action: BatchOperationActionKubernetesObjectDelete,
+				Parameters: map[string]string{
+					"kind":      "Pod",
+					"namespace": "openshift-azure-operator",
+					"name":      "aro-operator-master-0",
+				},
+				Selector: BatchOperationSelector{
+					ResourceIDs: []string{"/subscriptions/af848f0a-dbe3-449f-9ccd-6f23ac6ef9f1/resourcegroups/resourceGroup/providers/microsoft.redhatopenshift/openshiftclusters/resourceName"},
+					MinVersion:  "4.12.0",
+					MaxVersion:  "4.14.0",
+				},
+				MaxConcurrency: 20,
+				FailureBudget:  2,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		modify  func(*BatchOperation)
+		wantErr string
+	}{
+		{
+			name: "valid",
+		},
+		{
+			name: "valid without parameters",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Action = BatchOperationActionApproveCSR
+				bo.Properties.Parameters = nil
+			},
+		},
+		{
+			name: "invalid action",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Action = "reboot"
+			},
+			wantErr: "400: InvalidParameter: properties.action: The provided action 'reboot' is invalid.",
+		},
+		{
+			name: "missing parameter",
+			modify: func(bo *BatchOperation) {
+				delete(bo.Properties.Parameters, "name")
+			},
+			wantErr: "400: InvalidParameter: properties.parameters.name: Must be provided.",
+		},
+		{
+			name: "unsupported parameter",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Action = BatchOperationActionEtcdCertificateRenew
+			},
+			wantErr: "400: InvalidParameter: properties.parameters.kind: The parameter 'kind' is not supported by action 'etcdcertificaterenew'.",
+		},
+		{
+			name: "invalid force",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Parameters["force"] = "maybe"
+			},
+			wantErr: "400: InvalidParameter: properties.parameters.force: The provided force value 'maybe' is invalid.",
+		},
+		{
+			name: "empty selector",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Selector = BatchOperationSelector{}
+			},
+			wantErr: "400: InvalidParameter: properties.selector: Must select at least one of resourceIds, subscriptionIds, locations, minVersion or maxVersion.",
+		},
+		{
+			name: "invalid resource ID",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Selector.ResourceIDs = []string{"/subscriptions/af848f0a-dbe3-449f-9ccd-6f23ac6ef9f1/resourcegroups/resourceGroup"}
+			},
+			wantErr: "400: InvalidParameter: properties.selector.resourceIds[0]: The provided resource ID '/subscriptions/af848f0a-dbe3-449f-9ccd-6f23ac6ef9f1/resourcegroups/resourceGroup' is invalid.",
+		},
+		{
+			name: "invalid version",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Selector.MinVersion = "latest"
+			},
+			wantErr: "400: InvalidParameter: properties.selector.minVersion: The provided version 'latest' is invalid.",
+		},
+		{
+			name: "empty version range",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.Selector.MaxVersion = "4.12.0"
+			},
+			wantErr: "400: InvalidParameter: properties.selector.maxVersion: Must be greater than minVersion.",
+		},
+		{
+			name: "concurrency too high",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.MaxConcurrency = 51
+			},
+			wantErr: "400: InvalidParameter: properties.maxConcurrency: The provided maxConcurrency '51' is invalid: must be between 0 and 50.",
+		},
+		{
+			name: "negative failure budget",
+			modify: func(bo *BatchOperation) {
+				bo.Properties.FailureBudget = -1
+			},
+			wantErr: "400: InvalidParameter: properties.failureBudget: The provided failureBudget '-1' is invalid: must not be negative.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			bo := validBatchOperation()
+			if tt.modify != nil {
+				tt.modify(bo)
+			}
+
+			err := (&batchOperationStaticValidator{}).Static(bo)
+			if err == nil {
+				if tt.wantErr != "" {
+					t.Error(err)
+				}
+			} else {
+				if err.Error() != tt.wantErr {
+					t.Error(err)
+				}
+
+				cloudErr := err.(*api.CloudError)
+
+				if cloudErr.StatusCode != http.StatusBadRequest {
+					t.Error(cloudErr.StatusCode)
+				}
+
+				validate.CloudError(t, err)
+			}
+		})
+	}
+}
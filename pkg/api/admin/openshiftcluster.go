@@ -36,6 +36,9 @@ type OpenShiftClusterProperties struct {
 	MaintenanceTask         MaintenanceTask         `json:"maintenanceTask,omitempty" mutable:"true"`
 	OperatorFlags           OperatorFlags           `json:"operatorFlags,omitempty" mutable:"true"`
 	OperatorVersion         string                  `json:"operatorVersion,omitempty" mutable:"true"`
+	UpgradeVersion          string                  `json:"upgradeVersion,omitempty" mutable:"true"`
+	UpgradeProgress         string                  `json:"upgradeProgress,omitempty" swagger:"readOnly"`
+	ConsoleBanners          []ConsoleBanner         `json:"consoleBanners,omitempty" mutable:"true"`
 	CreatedAt               time.Time               `json:"createdAt,omitempty"`
 	CreatedBy               string                  `json:"createdBy,omitempty"`
 	ProvisionedBy           string                  `json:"provisionedBy,omitempty"`
@@ -104,6 +107,7 @@ const (
 	MaintenanceTaskEverything MaintenanceTask = "Everything"
 	MaintenanceTaskOperator   MaintenanceTask = "OperatorUpdate"
 	MaintenanceTaskRenewCerts MaintenanceTask = "CertificatesRenewal"
+	MaintenanceTaskUpgrade    MaintenanceTask = "Upgrade"
 
 	//
 	// Maintenance tasks for updating customer maintenance signals
@@ -123,6 +127,35 @@ const (
 // Operator feature flags
 type OperatorFlags map[string]string
 
+// ConsoleBannerSeverity selects the colours of a console banner.
+type ConsoleBannerSeverity string
+
+// ConsoleBannerSeverity constants.
+const (
+	ConsoleBannerSeverityInfo     ConsoleBannerSeverity = "Info"
+	ConsoleBannerSeverityWarning  ConsoleBannerSeverity = "Warning"
+	ConsoleBannerSeverityCritical ConsoleBannerSeverity = "Critical"
+)
+
+// ConsoleBanner is a notification shown at the top of the cluster's web
+// console from StartTime (or immediately, if not set) until ExpiryTime.
+type ConsoleBanner struct {
+	Name       string                `json:"name,omitempty"`
+	Severity   ConsoleBannerSeverity `json:"severity,omitempty"`
+	MessageID  string                `json:"messageId,omitempty"`
+	Language   string                `json:"language,omitempty"`
+	Parameters map[string]string     `json:"parameters,omitempty"`
+	Link       *ConsoleBannerLink    `json:"link,omitempty"`
+	StartTime  *time.Time            `json:"startTime,omitempty"`
+	ExpiryTime *time.Time            `json:"expiryTime,omitempty"`
+}
+
+// ConsoleBannerLink is a link shown after the text of a console banner.
+type ConsoleBannerLink struct {
+	Href string `json:"href,omitempty"`
+	Text string `json:"text,omitempty"`
+}
+
 // ClusterProfile represents a cluster profile.
 type ClusterProfile struct {
 	Domain               string               `json:"domain,omitempty"`
@@ -221,10 +254,19 @@ type NetworkProfile struct {
 	APIServerPrivateEndpointIP string               `json:"privateEndpointIp,omitempty"`
 	GatewayPrivateEndpointIP   string               `json:"gatewayPrivateEndpointIp,omitempty"`
 	GatewayPrivateLinkID       string               `json:"gatewayPrivateLinkId,omitempty"`
+	GatewayEgressAllowList     []GatewayEgressRule  `json:"gatewayEgressAllowList,omitempty" mutable:"true"`
 	PreconfiguredNSG           PreconfiguredNSG     `json:"preconfigureNSG,omitempty"`
 	LoadBalancerProfile        *LoadBalancerProfile `json:"loadBalancerProfile,omitempty"`
 }
 
+// GatewayEgressRule allows connections from the cluster through the gateway
+// to an FQDN.  An FQDN starting with "*." matches any subdomain of the rest of
+// the FQDN.  If Ports is empty, only port 443 is allowed.
+type GatewayEgressRule struct {
+	FQDN  string `json:"fqdn,omitempty"`
+	Ports []int  `json:"ports,omitempty"`
+}
+
 // PreconfiguredNSG represents whether customers want to use their own NSG attached to the subnets
 type PreconfiguredNSG string
 
@@ -466,4 +508,7 @@ type HiveProfile struct {
 	// of clusters that were created by Hive to avoid deleting existing
 	// ClusterDeployments.
 	CreatedByHive bool `json:"createdByHive,omitempty"`
+
+	// Shard is the Hive (AKS) shard which manages the cluster.
+	Shard int `json:"shard,omitempty"`
 }
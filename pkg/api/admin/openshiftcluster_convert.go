@@ -28,6 +28,8 @@ func (c openShiftClusterConverter) ToExternal(oc *api.OpenShiftCluster) interfac
 			MaintenanceTask:         MaintenanceTask(oc.Properties.MaintenanceTask),
 			OperatorFlags:           OperatorFlags(oc.Properties.OperatorFlags),
 			OperatorVersion:         oc.Properties.OperatorVersion,
+			UpgradeVersion:          oc.Properties.UpgradeVersion,
+			UpgradeProgress:         oc.Properties.UpgradeProgress,
 			CreatedAt:               oc.Properties.CreatedAt,
 			CreatedBy:               oc.Properties.CreatedBy,
 			ProvisionedBy:           oc.Properties.ProvisionedBy,
@@ -117,6 +119,43 @@ func (c openShiftClusterConverter) ToExternal(oc *api.OpenShiftCluster) interfac
 		}
 	}
 
+	if oc.Properties.ConsoleBanners != nil {
+		out.Properties.ConsoleBanners = make([]ConsoleBanner, 0, len(oc.Properties.ConsoleBanners))
+		for _, b := range oc.Properties.ConsoleBanners {
+			banner := ConsoleBanner{
+				Name:       b.Name,
+				Severity:   ConsoleBannerSeverity(b.Severity),
+				MessageID:  b.MessageID,
+				Language:   b.Language,
+				StartTime:  b.StartTime,
+				ExpiryTime: b.ExpiryTime,
+			}
+			if b.Parameters != nil {
+				banner.Parameters = make(map[string]string, len(b.Parameters))
+				for k, v := range b.Parameters {
+					banner.Parameters[k] = v
+				}
+			}
+			if b.Link != nil {
+				banner.Link = &ConsoleBannerLink{
+					Href: b.Link.Href,
+					Text: b.Link.Text,
+				}
+			}
+			out.Properties.ConsoleBanners = append(out.Properties.ConsoleBanners, banner)
+		}
+	}
+
+	if oc.Properties.NetworkProfile.GatewayEgressAllowList != nil {
+		out.Properties.NetworkProfile.GatewayEgressAllowList = make([]GatewayEgressRule, 0, len(oc.Properties.NetworkProfile.GatewayEgressAllowList))
+		for _, r := range oc.Properties.NetworkProfile.GatewayEgressAllowList {
+			out.Properties.NetworkProfile.GatewayEgressAllowList = append(out.Properties.NetworkProfile.GatewayEgressAllowList, GatewayEgressRule{
+				FQDN:  r.FQDN,
+				Ports: append([]int(nil), r.Ports...),
+			})
+		}
+	}
+
 	if oc.Properties.WorkerProfiles != nil {
 		out.Properties.WorkerProfiles = make([]WorkerProfile, 0, len(oc.Properties.WorkerProfiles))
 		for _, p := range oc.Properties.WorkerProfiles {
@@ -183,6 +222,7 @@ func (c openShiftClusterConverter) ToExternal(oc *api.OpenShiftCluster) interfac
 	out.Properties.HiveProfile = HiveProfile{
 		Namespace:     oc.Properties.HiveProfile.Namespace,
 		CreatedByHive: oc.Properties.HiveProfile.CreatedByHive,
+		Shard:         oc.Properties.HiveProfile.Shard,
 	}
 
 	return out
@@ -225,6 +265,7 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 	out.Properties.InfraID = oc.Properties.InfraID
 	out.Properties.HiveProfile.Namespace = oc.Properties.HiveProfile.Namespace
 	out.Properties.HiveProfile.CreatedByHive = oc.Properties.HiveProfile.CreatedByHive
+	out.Properties.HiveProfile.Shard = oc.Properties.HiveProfile.Shard
 	out.Properties.ProvisioningState = api.ProvisioningState(oc.Properties.ProvisioningState)
 	out.Properties.LastProvisioningState = api.ProvisioningState(oc.Properties.LastProvisioningState)
 	out.Properties.FailedProvisioningState = api.ProvisioningState(oc.Properties.FailedProvisioningState)
@@ -232,6 +273,35 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 	out.Properties.MaintenanceTask = api.MaintenanceTask(oc.Properties.MaintenanceTask)
 	out.Properties.OperatorFlags = api.OperatorFlags(oc.Properties.OperatorFlags)
 	out.Properties.OperatorVersion = oc.Properties.OperatorVersion
+	out.Properties.UpgradeVersion = oc.Properties.UpgradeVersion
+	out.Properties.UpgradeProgress = oc.Properties.UpgradeProgress
+	out.Properties.ConsoleBanners = nil
+	if oc.Properties.ConsoleBanners != nil {
+		out.Properties.ConsoleBanners = make([]api.ConsoleBanner, 0, len(oc.Properties.ConsoleBanners))
+		for _, b := range oc.Properties.ConsoleBanners {
+			banner := api.ConsoleBanner{
+				Name:       b.Name,
+				Severity:   api.ConsoleBannerSeverity(b.Severity),
+				MessageID:  b.MessageID,
+				Language:   b.Language,
+				StartTime:  b.StartTime,
+				ExpiryTime: b.ExpiryTime,
+			}
+			if b.Parameters != nil {
+				banner.Parameters = make(map[string]string, len(b.Parameters))
+				for k, v := range b.Parameters {
+					banner.Parameters[k] = v
+				}
+			}
+			if b.Link != nil {
+				banner.Link = &api.ConsoleBannerLink{
+					Href: b.Link.Href,
+					Text: b.Link.Text,
+				}
+			}
+			out.Properties.ConsoleBanners = append(out.Properties.ConsoleBanners, banner)
+		}
+	}
 	out.Properties.CreatedBy = oc.Properties.CreatedBy
 	out.Properties.ProvisionedBy = oc.Properties.ProvisionedBy
 	out.Properties.MaintenanceState = api.MaintenanceState(oc.Properties.MaintenanceState)
@@ -251,6 +321,16 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 	out.Properties.NetworkProfile.APIServerPrivateEndpointIP = oc.Properties.NetworkProfile.APIServerPrivateEndpointIP
 	out.Properties.NetworkProfile.GatewayPrivateEndpointIP = oc.Properties.NetworkProfile.GatewayPrivateEndpointIP
 	out.Properties.NetworkProfile.GatewayPrivateLinkID = oc.Properties.NetworkProfile.GatewayPrivateLinkID
+	out.Properties.NetworkProfile.GatewayEgressAllowList = nil
+	if oc.Properties.NetworkProfile.GatewayEgressAllowList != nil {
+		out.Properties.NetworkProfile.GatewayEgressAllowList = make([]api.GatewayEgressRule, 0, len(oc.Properties.NetworkProfile.GatewayEgressAllowList))
+		for _, r := range oc.Properties.NetworkProfile.GatewayEgressAllowList {
+			out.Properties.NetworkProfile.GatewayEgressAllowList = append(out.Properties.NetworkProfile.GatewayEgressAllowList, api.GatewayEgressRule{
+				FQDN:  r.FQDN,
+				Ports: append([]int(nil), r.Ports...),
+			})
+		}
+	}
 	if oc.Properties.NetworkProfile.LoadBalancerProfile != nil {
 		loadBalancerProfile := api.LoadBalancerProfile{}
 
@@ -356,6 +436,7 @@ func (c openShiftClusterConverter) ToInternal(_oc interface{}, out *api.OpenShif
 func (c openShiftClusterConverter) ExternalNoReadOnly(_oc interface{}) {
 	oc := _oc.(*OpenShiftCluster)
 	oc.Properties.WorkerProfilesStatus = nil
+	oc.Properties.UpgradeProgress = ""
 	if oc.Properties.NetworkProfile.LoadBalancerProfile != nil {
 		oc.Properties.NetworkProfile.LoadBalancerProfile.EffectiveOutboundIPs = nil
 	}
@@ -4,10 +4,19 @@ package admin
 // Licensed under the Apache License 2.0.
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/util/immutable"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/util/consolebanner"
+	"github.com/Azure/ARO-RP/pkg/util/stringutils"
+	"github.com/Azure/ARO-RP/pkg/util/version"
 )
 
 type openShiftClusterStaticValidator struct{}
@@ -29,7 +38,22 @@ func (sv openShiftClusterStaticValidator) validateDelta(oc, current *OpenShiftCl
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodePropertyChangeNotAllowed, err.Target, err.Message)
 	}
 
-	return validateMaintenanceTask(oc.Properties.MaintenanceTask)
+	err = validateMaintenanceTask(oc.Properties.MaintenanceTask)
+	if err != nil {
+		return err
+	}
+
+	err = validateUpgradeVersion(oc.Properties.MaintenanceTask, oc.Properties.UpgradeVersion)
+	if err != nil {
+		return err
+	}
+
+	err = validateGatewayEgressAllowList(oc.Properties.NetworkProfile.GatewayEgressAllowList)
+	if err != nil {
+		return err
+	}
+
+	return validateConsoleBanners(oc.Properties.ConsoleBanners)
 }
 
 func validateMaintenanceTask(task MaintenanceTask) error {
@@ -37,6 +61,7 @@ func validateMaintenanceTask(task MaintenanceTask) error {
 		task == MaintenanceTaskEverything ||
 		task == MaintenanceTaskOperator ||
 		task == MaintenanceTaskRenewCerts ||
+		task == MaintenanceTaskUpgrade ||
 		task == MaintenanceTaskPending ||
 		task == MaintenanceTaskNone ||
 		task == MaintenanceTaskCustomerActionNeeded) {
@@ -45,3 +70,132 @@ func validateMaintenanceTask(task MaintenanceTask) error {
 
 	return nil
 }
+
+func validateUpgradeVersion(task MaintenanceTask, upgradeVersion string) error {
+	if task != MaintenanceTaskUpgrade {
+		return nil
+	}
+
+	if upgradeVersion == "" {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.upgradeVersion", "Must be provided for the Upgrade maintenance task.")
+	}
+
+	v, err := version.ParseVersion(upgradeVersion)
+	if err != nil || v.Suffix != "" {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.upgradeVersion", "The provided version '%s' is invalid.", upgradeVersion)
+	}
+
+	return nil
+}
+
+func validateGatewayEgressAllowList(rules []GatewayEgressRule) error {
+	for i, r := range rules {
+		path := fmt.Sprintf("properties.networkProfile.gatewayEgressAllowList[%d]", i)
+
+		fqdn := strings.ToLower(r.FQDN)
+		wildcard := strings.HasPrefix(fqdn, "*.")
+		fqdn = strings.TrimPrefix(fqdn, "*.")
+
+		// a wildcard must not cover a whole top-level domain
+		if !validate.RxDomainNameRFC1123.MatchString(fqdn) || (wildcard && !strings.Contains(fqdn, ".")) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".fqdn", "The provided FQDN '%s' is invalid.", r.FQDN)
+		}
+
+		for _, port := range r.Ports {
+			if port < 1 || port > 65535 {
+				return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".ports", "The provided port '%d' is invalid.", port)
+			}
+		}
+	}
+
+	return nil
+}
+
+const maxConsoleBanners = 10
+
+var rxConsoleBannerName = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,38}[a-z0-9])?$`)
+
+func validateConsoleBanners(banners []ConsoleBanner) error {
+	if len(banners) > maxConsoleBanners {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "properties.consoleBanners", "At most %d console banners may be set.", maxConsoleBanners)
+	}
+
+	names := map[string]struct{}{}
+	for i, b := range banners {
+		path := fmt.Sprintf("properties.consoleBanners[%d]", i)
+
+		if !rxConsoleBannerName.MatchString(b.Name) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".name", "The provided name '%s' is invalid.", b.Name)
+		}
+		if _, ok := names[b.Name]; ok {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".name", "The provided name '%s' is not unique.", b.Name)
+		}
+		names[b.Name] = struct{}{}
+
+		switch b.Severity {
+		case "", ConsoleBannerSeverityInfo, ConsoleBannerSeverityWarning, ConsoleBannerSeverityCritical:
+		default:
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".severity", "Invalid enum parameter.")
+		}
+
+		err := validateConsoleBannerMessage(path, &b)
+		if err != nil {
+			return err
+		}
+
+		if b.Link != nil {
+			u, err := url.Parse(b.Link.Href)
+			if err != nil || u.Scheme != "https" || u.Host == "" {
+				return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".link.href", "The provided href '%s' is invalid.", b.Link.Href)
+			}
+			if b.Link.Text == "" {
+				return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".link.text", "The link text must not be empty.")
+			}
+		}
+
+		// banners must expire, so that they are not left up by mistake
+		if b.ExpiryTime == nil {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".expiryTime", "The expiryTime must be set.")
+		}
+		if b.StartTime != nil && !b.StartTime.Before(*b.ExpiryTime) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".expiryTime", "The expiryTime must be after the startTime.")
+		}
+	}
+
+	return nil
+}
+
+// validateConsoleBannerMessage checks that the operator can render the
+// banner's message: the message ID and language must be known, and exactly
+// the message's parameters must be given
+func validateConsoleBannerMessage(path string, b *ConsoleBanner) error {
+	languages := consolebanner.Languages(b.MessageID)
+	if languages == nil {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".messageId", "The provided messageId '%s' is invalid.", b.MessageID)
+	}
+
+	if b.Language != "" && !stringutils.Contains(languages, b.Language) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".language", "The provided language '%s' is not supported for messageId '%s'.", b.Language, b.MessageID)
+	}
+
+	parameters := consolebanner.Parameters(b.MessageID)
+	for _, name := range parameters {
+		if b.Parameters[name] == "" {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".parameters", "The parameter '%s' is required for messageId '%s'.", name, b.MessageID)
+		}
+	}
+
+	names := make([]string, 0, len(b.Parameters))
+	for name := range b.Parameters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if !stringutils.Contains(parameters, name) {
+			return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, path+".parameters", "The parameter '%s' is not used by messageId '%s'.", name, b.MessageID)
+		}
+	}
+
+	return nil
+}
@@ -663,6 +663,234 @@ func TestOpenShiftClusterStaticValidateDelta(t *testing.T) {
 				oc.Properties.MaintenanceTask = MaintenanceTaskOperator
 			},
 		},
+		{
+			name: "maintenanceTask change to Upgrade with upgradeVersion is allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{
+					Properties: OpenShiftClusterProperties{
+						MaintenanceTask: "",
+					},
+				}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.MaintenanceTask = MaintenanceTaskUpgrade
+				oc.Properties.UpgradeVersion = "4.12.25"
+			},
+		},
+		{
+			name: "maintenanceTask change to Upgrade without upgradeVersion is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{
+					Properties: OpenShiftClusterProperties{
+						MaintenanceTask: "",
+					},
+				}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.MaintenanceTask = MaintenanceTaskUpgrade
+			},
+			wantErr: "400: InvalidParameter: properties.upgradeVersion: Must be provided for the Upgrade maintenance task.",
+		},
+		{
+			name: "maintenanceTask change to Upgrade with invalid upgradeVersion is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{
+					Properties: OpenShiftClusterProperties{
+						MaintenanceTask: "",
+					},
+				}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.MaintenanceTask = MaintenanceTaskUpgrade
+				oc.Properties.UpgradeVersion = "4.12.0-rc.1"
+			},
+			wantErr: "400: InvalidParameter: properties.upgradeVersion: The provided version '4.12.0-rc.1' is invalid.",
+		},
+		{
+			name: "gatewayEgressAllowList change is allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.NetworkProfile.GatewayEgressAllowList = []GatewayEgressRule{
+					{FQDN: "registry.example.com"},
+					{FQDN: "*.example.com", Ports: []int{443, 8443}},
+				}
+			},
+		},
+		{
+			name: "gatewayEgressAllowList with an invalid FQDN is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.NetworkProfile.GatewayEgressAllowList = []GatewayEgressRule{
+					{FQDN: "*.com"},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.networkProfile.gatewayEgressAllowList[0].fqdn: The provided FQDN '*.com' is invalid.",
+		},
+		{
+			name: "gatewayEgressAllowList with an invalid port is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.NetworkProfile.GatewayEgressAllowList = []GatewayEgressRule{
+					{FQDN: "example.com", Ports: []int{0}},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.networkProfile.gatewayEgressAllowList[0].ports: The provided port '0' is invalid.",
+		},
+		{
+			name: "consoleBanners change is allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				expiry := start.Add(24 * time.Hour)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{
+						Name:       "maintenance",
+						Severity:   ConsoleBannerSeverityWarning,
+						MessageID:  "ContactSupport",
+						Language:   "en",
+						Link:       &ConsoleBannerLink{Href: "https://example.com/maintenance", Text: "Learn more"},
+						StartTime:  &start,
+						ExpiryTime: &expiry,
+					},
+				}
+			},
+		},
+		{
+			name: "consoleBanners with an unknown messageId are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", MessageID: "Maintenance", ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].messageId: The provided messageId 'Maintenance' is invalid.",
+		},
+		{
+			name: "consoleBanners with an unsupported language are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "support", MessageID: "ContactSupport", Language: "de", ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].language: The provided language 'de' is not supported for messageId 'ContactSupport'.",
+		},
+		{
+			name: "consoleBanners with an unused parameter are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "support", MessageID: "ContactSupport", Parameters: map[string]string{"windowStart": "1 June"}, ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].parameters: The parameter 'windowStart' is not used by messageId 'ContactSupport'.",
+		},
+		{
+			name: "consoleBanners with an invalid name is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "Maintenance", MessageID: "ContactSupport", ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].name: The provided name 'Maintenance' is invalid.",
+		},
+		{
+			name: "consoleBanners with duplicate names are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", MessageID: "ContactSupport", ExpiryTime: &expiry},
+					{Name: "maintenance", MessageID: "ContactSupport", ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[1].name: The provided name 'maintenance' is not unique.",
+		},
+		{
+			name: "consoleBanners with an invalid severity is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", Severity: "Fatal", MessageID: "ContactSupport", ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].severity: Invalid enum parameter.",
+		},
+		{
+			name: "consoleBanners with an insecure link is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				expiry := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", MessageID: "ContactSupport", Link: &ConsoleBannerLink{Href: "http://example.com", Text: "Learn more"}, ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].link.href: The provided href 'http://example.com' is invalid.",
+		},
+		{
+			name: "consoleBanners without an expiryTime are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", MessageID: "ContactSupport"},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].expiryTime: The expiryTime must be set.",
+		},
+		{
+			name: "consoleBanners expiring before they start are not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				start := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+				expiry := start.Add(-time.Hour)
+				oc.Properties.ConsoleBanners = []ConsoleBanner{
+					{Name: "maintenance", MessageID: "ContactSupport", StartTime: &start, ExpiryTime: &expiry},
+				}
+			},
+			wantErr: "400: InvalidParameter: properties.consoleBanners[0].expiryTime: The expiryTime must be after the startTime.",
+		},
+		{
+			name: "upgradeProgress change is not allowed",
+			oc: func() *OpenShiftCluster {
+				return &OpenShiftCluster{}
+			},
+			modify: func(oc *OpenShiftCluster) {
+				oc.Properties.UpgradeProgress = "Completed"
+			},
+			wantErr: "400: PropertyChangeNotAllowed: properties.upgradeProgress: Changing property 'properties.upgradeProgress' is not allowed.",
+		},
 		{
 			name: "maintenanceTask change to blank allowed",
 			oc: func() *OpenShiftCluster {
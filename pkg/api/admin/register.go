@@ -16,5 +16,7 @@ func init() {
 		OpenShiftClusterStaticValidator: openShiftClusterStaticValidator{},
 		OpenShiftVersionConverter:       openShiftVersionConverter{},
 		OpenShiftVersionStaticValidator: openShiftVersionStaticValidator{},
+		BatchOperationConverter:         batchOperationConverter{},
+		BatchOperationStaticValidator:   batchOperationStaticValidator{},
 	}
 }
@@ -21,4 +21,15 @@ type AsyncOperation struct {
 	EndTime   *time.Time `json:"endTime,omitempty" deep:"-"`
 
 	Error *CloudErrorBody `json:"error,omitempty"`
+
+	Queue *AsyncOperationQueue `json:"queue,omitempty" deep:"-"`
+}
+
+// AsyncOperationQueue represents the position of an asyncOperation in the
+// backend queue while it is waiting to be picked up
+type AsyncOperationQueue struct {
+	Position            int  `json:"position,omitempty"`
+	SubscriptionRunning int  `json:"subscriptionRunning,omitempty"`
+	SubscriptionLimit   int  `json:"subscriptionLimit,omitempty"`
+	Throttled           bool `json:"throttled,omitempty"`
 }
@@ -0,0 +1,98 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// BatchOperation represents an admin action fanned out across many clusters
+type BatchOperation struct {
+	MissingFields
+
+	ID string `json:"id,omitempty"`
+
+	// Action is the admin action to run against each selected cluster
+	Action BatchOperationAction `json:"action,omitempty"`
+
+	// Parameters holds the action-specific parameters, for example the kind,
+	// namespace and name of the object to delete for
+	// BatchOperationActionKubernetesObjectDelete
+	Parameters map[string]string `json:"parameters,omitempty"`
+
+	Selector BatchOperationSelector `json:"selector,omitempty"`
+
+	// MaxConcurrency is the maximum number of clusters that the action runs
+	// against at once
+	MaxConcurrency int `json:"maxConcurrency,omitempty"`
+
+	// FailureBudget is the number of per-cluster failures tolerated before
+	// the remaining clusters are skipped and the batch operation fails
+	FailureBudget int `json:"failureBudget,omitempty"`
+
+	State     BatchOperationState `json:"state,omitempty"`
+	Error     string              `json:"error,omitempty"`
+	StartTime *time.Time          `json:"startTime,omitempty"`
+	EndTime   *time.Time          `json:"endTime,omitempty"`
+
+	Clusters []BatchOperationCluster `json:"clusters,omitempty"`
+}
+
+// BatchOperationAction represents an admin action supported by batch
+// operations
+type BatchOperationAction string
+
+// BatchOperationAction constants
+const (
+	BatchOperationActionApproveCSR             BatchOperationAction = "approvecsr"
+	BatchOperationActionEtcdCertificateRenew   BatchOperationAction = "etcdcertificaterenew"
+	BatchOperationActionKubernetesObjectDelete BatchOperationAction = "kubernetesobjectdelete"
+	BatchOperationActionRedeployVM             BatchOperationAction = "redeployvm"
+)
+
+// BatchOperationSelector selects the clusters a batch operation runs against.
+// All non-empty fields must match for a cluster to be selected.
+type BatchOperationSelector struct {
+	MissingFields
+
+	ResourceIDs     []string `json:"resourceIds,omitempty"`
+	SubscriptionIDs []string `json:"subscriptionIds,omitempty"`
+	Locations       []string `json:"locations,omitempty"`
+
+	// MinVersion is the inclusive lower bound of the cluster version
+	MinVersion string `json:"minVersion,omitempty"`
+
+	// MaxVersion is the exclusive upper bound of the cluster version
+	MaxVersion string `json:"maxVersion,omitempty"`
+}
+
+// BatchOperationState represents the state of a batch operation or of one of
+// its clusters
+type BatchOperationState string
+
+// BatchOperationState constants
+const (
+	BatchOperationStatePending   BatchOperationState = "Pending"
+	BatchOperationStateRunning   BatchOperationState = "Running"
+	BatchOperationStateSucceeded BatchOperationState = "Succeeded"
+	BatchOperationStateFailed    BatchOperationState = "Failed"
+	BatchOperationStateSkipped   BatchOperationState = "Skipped"
+)
+
+// IsTerminal returns true if the state will not change further
+func (s BatchOperationState) IsTerminal() bool {
+	return s == BatchOperationStateSucceeded ||
+		s == BatchOperationStateFailed ||
+		s == BatchOperationStateSkipped
+}
+
+// BatchOperationCluster records the progress of a batch operation against a
+// single cluster
+type BatchOperationCluster struct {
+	MissingFields
+
+	ResourceID string              `json:"resourceId,omitempty"`
+	State      BatchOperationState `json:"state,omitempty"`
+	Error      string              `json:"error,omitempty"`
+}
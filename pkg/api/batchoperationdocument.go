@@ -0,0 +1,41 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// BatchOperationDocuments represents batch operation documents.
+// pkg/database/cosmosdb requires its definition.
+type BatchOperationDocuments struct {
+	Count                   int                       `json:"_count,omitempty"`
+	ResourceID              string                    `json:"_rid,omitempty"`
+	BatchOperationDocuments []*BatchOperationDocument `json:"Documents,omitempty"`
+}
+
+func (c *BatchOperationDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// BatchOperationDocument represents a batch operation document.
+// pkg/database/cosmosdb requires its definition.
+type BatchOperationDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	LeaseOwner   string `json:"leaseOwner,omitempty" deep:"-"`
+	LeaseExpires int    `json:"leaseExpires,omitempty" deep:"-"`
+
+	BatchOperation *BatchOperation `json:"batchOperation,omitempty"`
+}
+
+func (c *BatchOperationDocument) String() string {
+	return encodeJSON(c)
+}
@@ -13,4 +13,28 @@ type Billing struct {
 
 	Location string `json:"location,omitempty"`
 	TenantID string `json:"tenantID,omitempty"`
+
+	// UsageIntervals records the cluster's workers over time, as observed by
+	// the billing reconciler.  The last interval is open until the workers
+	// change or the cluster is deleted.
+	UsageIntervals []BillingUsageInterval `json:"usageIntervals,omitempty"`
+}
+
+// BillingUsageInterval is a period during which a cluster ran an unchanged set
+// of workers
+type BillingUsageInterval struct {
+	MissingFields
+
+	StartTime int `json:"startTime,omitempty"`
+	EndTime   int `json:"endTime,omitempty"`
+
+	Workers []BillingWorkers `json:"workers,omitempty"`
+}
+
+// BillingWorkers counts the workers of a given VM size
+type BillingWorkers struct {
+	MissingFields
+
+	VMSize VMSize `json:"vmSize,omitempty"`
+	Count  int    `json:"count,omitempty"`
 }
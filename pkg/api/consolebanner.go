@@ -0,0 +1,37 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import "time"
+
+// ConsoleBannerSeverity selects the colours of a console banner
+type ConsoleBannerSeverity string
+
+const (
+	ConsoleBannerSeverityInfo     ConsoleBannerSeverity = "Info"
+	ConsoleBannerSeverityWarning  ConsoleBannerSeverity = "Warning"
+	ConsoleBannerSeverityCritical ConsoleBannerSeverity = "Critical"
+)
+
+// ConsoleBanner is a notification shown at the top of the cluster's web
+// console from StartTime (or immediately, if nil) until ExpiryTime.  The
+// banner's text is the operator's message identified by MessageID, translated
+// to Language (English, if empty), with its placeholders filled from the
+// cluster and Parameters.
+type ConsoleBanner struct {
+	Name       string                `json:"name,omitempty"`
+	Severity   ConsoleBannerSeverity `json:"severity,omitempty"`
+	MessageID  string                `json:"messageId,omitempty"`
+	Language   string                `json:"language,omitempty"`
+	Parameters map[string]string     `json:"parameters,omitempty"`
+	Link       *ConsoleBannerLink    `json:"link,omitempty"`
+	StartTime  *time.Time            `json:"startTime,omitempty"`
+	ExpiryTime *time.Time            `json:"expiryTime,omitempty"`
+}
+
+// ConsoleBannerLink is a link shown after the text of a console banner
+type ConsoleBannerLink struct {
+	Href string `json:"href,omitempty"`
+	Text string `json:"text,omitempty"`
+}
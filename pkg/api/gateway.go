@@ -12,4 +12,16 @@ type Gateway struct {
 
 	StorageSuffix                   string `json:"storageSuffix,omitempty"`
 	ImageRegistryStorageAccountName string `json:"imageRegistryStorageAccountName,omitempty"`
+
+	// EgressAllowList lists additional destinations which the cluster may
+	// reach through the gateway
+	EgressAllowList []GatewayEgressRule `json:"egressAllowList,omitempty"`
+}
+
+// GatewayEgressRule allows connections through the gateway to an FQDN.  An
+// FQDN starting with "*." matches any subdomain of the rest of the FQDN, but
+// not the domain itself.  If Ports is empty, only port 443 is allowed.
+type GatewayEgressRule struct {
+	FQDN  string `json:"fqdn,omitempty"`
+	Ports []int  `json:"ports,omitempty"`
 }
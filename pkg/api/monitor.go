@@ -7,5 +7,24 @@ package api
 type Monitor struct {
 	MissingFields
 
+	// Buckets is set on the master document, and lists the monitor which owns
+	// each bucket.
 	Buckets []string `json:"buckets,omitempty"`
+
+	// Zone and BucketLoads are set by each monitor on its own document, and are
+	// used by the master to balance buckets between monitors.
+	Zone        string              `json:"zone,omitempty"`
+	BucketLoads []MonitorBucketLoad `json:"bucketLoads,omitempty"`
+}
+
+// MonitorBucketLoad represents the load of monitoring a bucket
+type MonitorBucketLoad struct {
+	MissingFields
+
+	Bucket   int `json:"bucket"`
+	Clusters int `json:"clusters,omitempty"`
+
+	// CycleSeconds is the sum of the most recent monitoring cycle duration of
+	// each cluster in the bucket.
+	CycleSeconds float64 `json:"cycleSeconds,omitempty"`
 }
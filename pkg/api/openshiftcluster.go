@@ -105,6 +105,18 @@ type OpenShiftClusterProperties struct {
 	OperatorFlags   OperatorFlags `json:"operatorFlags,omitempty"`
 	OperatorVersion string        `json:"operatorVersion,omitempty"`
 
+	// UpgradeVersion is the OpenShift version which the Upgrade maintenance
+	// task upgrades the cluster to
+	UpgradeVersion string `json:"upgradeVersion,omitempty"`
+	// UpgradeProgress reports the progress of the most recent Upgrade
+	// maintenance task
+	UpgradeProgress string `json:"upgradeProgress,omitempty"`
+
+	// ConsoleBanners are published to the cluster's web console by the
+	// operator's banner controller, which only runs when the
+	// aro.banner.enabled operator flag is set
+	ConsoleBanners []ConsoleBanner `json:"consoleBanners,omitempty"`
+
 	CreatedAt time.Time `json:"createdAt,omitempty"`
 
 	// CreatedBy is the RP version (Git commit hash) that created this cluster
@@ -202,6 +214,7 @@ const (
 	MaintenanceTaskEverything MaintenanceTask = "Everything"
 	MaintenanceTaskOperator   MaintenanceTask = "OperatorUpdate"
 	MaintenanceTaskRenewCerts MaintenanceTask = "CertificatesRenewal"
+	MaintenanceTaskUpgrade    MaintenanceTask = "Upgrade"
 
 	//
 	// Maintenance tasks for updating customer maintenance signals
@@ -223,6 +236,7 @@ func (t MaintenanceTask) IsMaintenanceOngoingTask() bool {
 	result := (t == MaintenanceTaskEverything) ||
 		(t == MaintenanceTaskOperator) ||
 		(t == MaintenanceTaskRenewCerts) ||
+		(t == MaintenanceTaskUpgrade) ||
 		(t == "")
 	return result
 }
@@ -356,6 +370,7 @@ type NetworkProfile struct {
 	APIServerPrivateEndpointIP string               `json:"privateEndpointIp,omitempty"`
 	GatewayPrivateEndpointIP   string               `json:"gatewayPrivateEndpointIp,omitempty"`
 	GatewayPrivateLinkID       string               `json:"gatewayPrivateLinkId,omitempty"`
+	GatewayEgressAllowList     []GatewayEgressRule  `json:"gatewayEgressAllowList,omitempty"`
 	PreconfiguredNSG           PreconfiguredNSG     `json:"preconfiguredNSG,omitempty"`
 	LoadBalancerProfile        *LoadBalancerProfile `json:"loadBalancerProfile,omitempty"`
 }
@@ -760,4 +775,8 @@ type HiveProfile struct {
 	// of clusters that were created by Hive to avoid deleting existing
 	// ClusterDeployments.
 	CreatedByHive bool `json:"createdByHive,omitempty"`
+
+	// Shard is the Hive (AKS) shard which manages the cluster. It is assigned
+	// when the cluster is created; zero means the cluster predates sharding.
+	Shard int `json:"shard,omitempty"`
 }
@@ -40,6 +40,21 @@ type OpenShiftClusterDocument struct {
 	LeaseExpires int    `json:"leaseExpires,omitempty" deep:"-"`
 	Dequeues     int    `json:"dequeues,omitempty"`
 
+	// EnqueueTime is the time (in Unix seconds) at which the current operation
+	// was queued for the backend.  Unlike _ts, it is not changed when the
+	// document is written during the operation, so it orders the queue.
+	EnqueueTime int `json:"enqueueTime,omitempty" deep:"-"`
+
+	// CancelRequested is set by an admin to abort the current create or
+	// admin update.  The backend cancels the operation at the next
+	// heartbeat and fails it.
+	CancelRequested bool `json:"cancelRequested,omitempty"`
+
+	// CompletedSteps records the skippable backend steps which have completed
+	// during the current operation, so that a re-dequeued operation can
+	// resume rather than starting from the top.
+	CompletedSteps []string `json:"completedSteps,omitempty"`
+
 	AsyncOperationID string `json:"asyncOperationId,omitempty" deep:"-"`
 
 	OpenShiftCluster *OpenShiftCluster `json:"openShiftCluster,omitempty"`
@@ -0,0 +1,49 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// PortalAccessGrantState represents the state of a portal access grant
+type PortalAccessGrantState string
+
+// PortalAccessGrantState constants
+const (
+	PortalAccessGrantStatePending  PortalAccessGrantState = "Pending"
+	PortalAccessGrantStateApproved PortalAccessGrantState = "Approved"
+	PortalAccessGrantStateRejected PortalAccessGrantState = "Rejected"
+)
+
+// PortalAccessGrant represents a request by an SRE for elevated portal access
+// to a single cluster for a window of time, and its review by a second SRE
+type PortalAccessGrant struct {
+	MissingFields
+
+	Username string `json:"username,omitempty"`
+
+	// ResourceID is the lower case resourceID of the cluster to which access
+	// is requested
+	ResourceID string `json:"resourceId,omitempty"`
+
+	Justification string `json:"justification,omitempty"`
+
+	NotBefore time.Time `json:"notBefore,omitempty"`
+	NotAfter  time.Time `json:"notAfter,omitempty"`
+
+	State       PortalAccessGrantState `json:"state,omitempty"`
+	RequestedAt time.Time              `json:"requestedAt,omitempty"`
+
+	Reviewer   string    `json:"reviewer,omitempty"`
+	ReviewedAt time.Time `json:"reviewedAt,omitempty"`
+}
+
+// IsActive returns true if the grant has been approved and now falls within
+// its window
+func (g *PortalAccessGrant) IsActive(now time.Time) bool {
+	return g.State == PortalAccessGrantStateApproved &&
+		!now.Before(g.NotBefore) &&
+		now.Before(g.NotAfter)
+}
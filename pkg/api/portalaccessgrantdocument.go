@@ -0,0 +1,38 @@
+package api
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+// PortalAccessGrantDocuments represents portal access grant documents.
+// pkg/database/cosmosdb requires its definition.
+type PortalAccessGrantDocuments struct {
+	Count                      int                          `json:"_count,omitempty"`
+	ResourceID                 string                       `json:"_rid,omitempty"`
+	PortalAccessGrantDocuments []*PortalAccessGrantDocument `json:"Documents,omitempty"`
+}
+
+func (c *PortalAccessGrantDocuments) String() string {
+	return encodeJSON(c)
+}
+
+// PortalAccessGrantDocument represents a portal access grant document.
+// pkg/database/cosmosdb requires its definition.
+type PortalAccessGrantDocument struct {
+	MissingFields
+
+	ID          string                 `json:"id,omitempty"`
+	ResourceID  string                 `json:"_rid,omitempty"`
+	Timestamp   int                    `json:"_ts,omitempty"`
+	Self        string                 `json:"_self,omitempty"`
+	ETag        string                 `json:"_etag,omitempty" deep:"-"`
+	Attachments string                 `json:"_attachments,omitempty"`
+	TTL         int                    `json:"ttl,omitempty"`
+	LSN         int                    `json:"_lsn,omitempty"`
+	Metadata    map[string]interface{} `json:"_metadata,omitempty"`
+
+	PortalAccessGrant *PortalAccessGrant `json:"portalAccessGrant,omitempty"`
+}
+
+func (c *PortalAccessGrantDocument) String() string {
+	return encodeJSON(c)
+}
@@ -37,6 +37,16 @@ type OpenShiftVersionStaticValidator interface {
 	Static(interface{}, *OpenShiftVersion) error
 }
 
+type BatchOperationConverter interface {
+	ToExternal(*BatchOperation) interface{}
+	ToExternalList([]*BatchOperation) interface{}
+	ToInternal(interface{}, *BatchOperation)
+}
+
+type BatchOperationStaticValidator interface {
+	Static(interface{}) error
+}
+
 type SyncSetConverter interface {
 	ToExternal(*SyncSet) interface{}
 	ToExternalList([]*SyncSet) interface{}
@@ -69,6 +79,8 @@ type Version struct {
 	OpenShiftClusterAdminKubeconfigConverter OpenShiftClusterAdminKubeconfigConverter
 	OpenShiftVersionConverter                OpenShiftVersionConverter
 	OpenShiftVersionStaticValidator          OpenShiftVersionStaticValidator
+	BatchOperationConverter                  BatchOperationConverter
+	BatchOperationStaticValidator            BatchOperationStaticValidator
 	OperationList                            OperationList
 	SyncSetConverter                         SyncSetConverter
 	MachinePoolConverter                     MachinePoolConverter
@@ -0,0 +1,106 @@
+package validate
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	utilnamespace "github.com/Azure/ARO-RP/pkg/util/namespace"
+)
+
+// RxKubernetesString is weaker than Kubernetes validation, but strong enough to
+// prevent mischief
+var RxKubernetesString = regexp.MustCompile(`(?i)^[-a-z0-9.]{0,255}$`)
+
+func permittedClusterwideObjects(gvr schema.GroupVersionResource) bool {
+	permittedGroups := map[string]bool{
+		"apiserver.openshift.io":              true,
+		"aro.openshift.io":                    true,
+		"authorization.openshift.io":          true,
+		"certificates.k8s.io":                 true,
+		"config.openshift.io":                 true,
+		"console.openshift.io":                true,
+		"imageregistry.operator.openshift.io": true,
+		"machine.openshift.io":                true,
+		"machineconfiguration.openshift.io":   true,
+		"operator.openshift.io":               true,
+		"rbac.authorization.k8s.io":           true,
+		"metrics.k8s.io":                      true,
+	}
+	permittedObjects := map[string]map[string]bool{
+		"": {"nodes": true},
+	}
+	allowedResources, groupHasException := permittedObjects[gvr.Group]
+	return permittedGroups[gvr.Group] || (groupHasException && allowedResources[gvr.Resource])
+}
+
+// AdminKubernetesObjectsNonCustomer validates that the admin API may act on an
+// object, which must not be in a customer namespace
+func AdminKubernetesObjectsNonCustomer(method string, gvr schema.GroupVersionResource, namespace, name string) error {
+	if gvr.Empty() {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided resource is invalid.")
+	}
+
+	if namespace == "" && !permittedClusterwideObjects(gvr) {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to cluster-scoped object '%v' is forbidden.", gvr)
+	}
+
+	if !utilnamespace.IsOpenShiftNamespace(namespace) {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to the provided namespace '%s' is forbidden.", namespace)
+	}
+
+	return AdminKubernetesObjects(method, gvr, namespace, name)
+}
+
+// AdminKubernetesObjects validates that the admin API may act on an object
+func AdminKubernetesObjects(method string, gvr schema.GroupVersionResource, namespace, name string) error {
+	if gvr.Empty() {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided resource is invalid.")
+	}
+
+	if gvr.Resource == "secrets" ||
+		gvr.Group == "oauth.openshift.io" {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to secrets is forbidden.")
+	}
+	if method != http.MethodGet &&
+		(gvr.Group == "rbac.authorization.k8s.io" ||
+			gvr.Group == "authorization.openshift.io") {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Write access to RBAC is forbidden.")
+	}
+
+	if !RxKubernetesString.MatchString(namespace) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided namespace '%s' is invalid.", namespace)
+	}
+
+	if (method != http.MethodGet && name == "") ||
+		!RxKubernetesString.MatchString(name) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided name '%s' is invalid.", name)
+	}
+
+	return nil
+}
+
+// AdminKubernetesObjectsForceDelete validates that the admin API may force
+// delete objects of a kind
+func AdminKubernetesObjectsForceDelete(groupKind string) error {
+	if !strings.EqualFold(groupKind, "Pod") {
+		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Force deleting groupKind '%s' is forbidden.", groupKind)
+	}
+
+	return nil
+}
+
+// AdminVMName validates the name of a VM acted on by the admin API
+func AdminVMName(vmName string) error {
+	if vmName == "" || !RxKubernetesString.MatchString(vmName) {
+		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided vmName '%s' is invalid.", vmName)
+	}
+
+	return nil
+}
@@ -22,6 +22,10 @@ import (
 const (
 	maxWorkers      = 100
 	maxDequeueCount = 5
+
+	// DefaultMaxCreatesPerSubscription is the default number of clusters a
+	// single subscription may have being created at once
+	DefaultMaxCreatesPerSubscription = 10
 )
 
 type backend struct {
@@ -34,10 +38,17 @@ type backend struct {
 	dbOpenShiftClusters database.OpenShiftClusters
 	dbSubscriptions     database.Subscriptions
 	dbOpenShiftVersions database.OpenShiftVersions
+	dbBatchOperations   database.BatchOperations
+
+	aead     encryption.AEAD
+	m        metrics.Emitter
+	clusterm metrics.Emitter
+	billing  billing.Manager
 
-	aead    encryption.AEAD
-	m       metrics.Emitter
-	billing billing.Manager
+	// maxCreatesPerSubscription limits how many clusters a single
+	// subscription may have being created at once, so that one subscription
+	// can't starve the others; zero or less means no limit
+	maxCreatesPerSubscription int
 
 	mu       sync.Mutex
 	cond     *sync.Cond
@@ -46,6 +57,7 @@ type backend struct {
 
 	ocb *openShiftClusterBackend
 	sb  *subscriptionBackend
+	bob *batchOperationBackend
 }
 
 // Runnable represents a runnable object
@@ -54,18 +66,19 @@ type Runnable interface {
 }
 
 // NewBackend returns a new runnable backend
-func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (Runnable, error) {
-	b, err := newBackend(ctx, log, env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, aead, m)
+func NewBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, dbBatchOperations database.BatchOperations, aead encryption.AEAD, m metrics.Emitter, clusterm metrics.Emitter, maxCreatesPerSubscription int) (Runnable, error) {
+	b, err := newBackend(ctx, log, env, dbAsyncOperations, dbBilling, dbGateway, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, dbBatchOperations, aead, m, clusterm, maxCreatesPerSubscription)
 	if err != nil {
 		return nil, err
 	}
 
 	b.ocb = newOpenShiftClusterBackend(b)
 	b.sb = newSubscriptionBackend(b)
+	b.bob = newBatchOperationBackend(b)
 	return b, nil
 }
 
-func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, aead encryption.AEAD, m metrics.Emitter) (*backend, error) {
+func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsyncOperations database.AsyncOperations, dbBilling database.Billing, dbGateway database.Gateway, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, dbOpenShiftVersions database.OpenShiftVersions, dbBatchOperations database.BatchOperations, aead encryption.AEAD, m metrics.Emitter, clusterm metrics.Emitter, maxCreatesPerSubscription int) (*backend, error) {
 	billing, err := billing.NewManager(env, dbBilling, dbSubscriptions, log)
 	if err != nil {
 		return nil, err
@@ -81,10 +94,14 @@ func newBackend(ctx context.Context, log *logrus.Entry, env env.Interface, dbAsy
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbSubscriptions:     dbSubscriptions,
 		dbOpenShiftVersions: dbOpenShiftVersions,
+		dbBatchOperations:   dbBatchOperations,
 
-		billing: billing,
-		aead:    aead,
-		m:       m,
+		billing:  billing,
+		aead:     aead,
+		m:        m,
+		clusterm: clusterm,
+
+		maxCreatesPerSubscription: maxCreatesPerSubscription,
 	}
 	b.cond = sync.NewCond(&b.mu)
 	b.stopping.Store(false)
@@ -108,6 +125,8 @@ func (b *backend) Run(ctx context.Context, stop <-chan struct{}, done chan<- str
 		}()
 	}
 
+	go b.ocb.reportQueue(ctx, stop)
+
 	for {
 		b.mu.Lock()
 		for atomic.LoadInt32(&b.workers) >= maxWorkers && !b.stopping.Load().(bool) {
@@ -129,7 +148,12 @@ func (b *backend) Run(ctx context.Context, stop <-chan struct{}, done chan<- str
 			b.baseLog.Error(err)
 		}
 
-		if !(ocbDidWork || sbDidWork) {
+		bobDidWork, err := b.bob.try(ctx)
+		if err != nil {
+			b.baseLog.Error(err)
+		}
+
+		if !(ocbDidWork || sbDidWork || bobDidWork) {
 			<-t.C
 		}
 	}
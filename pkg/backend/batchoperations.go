@@ -0,0 +1,572 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/azure"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+// etcdCertificateRenewTimeout is how long the etcd certificate renewal of a
+// single cluster waits for the new etcd revision to be applied
+const etcdCertificateRenewTimeout = 30 * time.Minute
+
+type kubeActionsFactory func(*logrus.Entry, env.Interface, *api.OpenShiftCluster) (adminactions.KubeActions, error)
+
+type azureActionsFactory func(*logrus.Entry, env.Interface, *api.OpenShiftCluster, *api.SubscriptionDocument) (adminactions.AzureActions, error)
+
+type batchOperationBackend struct {
+	*backend
+
+	newKubeActions  kubeActionsFactory
+	newAzureActions azureActionsFactory
+
+	now func() time.Time
+}
+
+func newBatchOperationBackend(b *backend) *batchOperationBackend {
+	return &batchOperationBackend{
+		backend: b,
+
+		newKubeActions:  adminactions.NewKubeActions,
+		newAzureActions: adminactions.NewAzureActions,
+
+		now: time.Now,
+	}
+}
+
+// try tries to dequeue a BatchOperationDocument for work, and works it on a
+// new goroutine.  It returns a boolean to the caller indicating whether it
+// succeeded in dequeuing anything - if this is false, the caller should sleep
+// before calling again
+func (bob *batchOperationBackend) try(ctx context.Context) (bool, error) {
+	if bob.dbBatchOperations == nil {
+		return false, nil
+	}
+
+	doc, err := bob.dbBatchOperations.Dequeue(ctx)
+	if err != nil || doc == nil {
+		return false, err
+	}
+
+	log := bob.baseLog.WithField("batch_operation_id", doc.ID)
+
+	log.Print("dequeued")
+	atomic.AddInt32(&bob.workers, 1)
+	bob.m.EmitGauge("backend.batchoperations.workers.count", int64(atomic.LoadInt32(&bob.workers)), nil)
+
+	go func() {
+		defer recover.Panic(log)
+
+		t := time.Now()
+
+		defer func() {
+			atomic.AddInt32(&bob.workers, -1)
+			bob.m.EmitGauge("backend.batchoperations.workers.count", int64(atomic.LoadInt32(&bob.workers)), nil)
+			bob.cond.Signal()
+
+			log.WithField("duration", time.Since(t).Seconds()).Print("done")
+		}()
+
+		err := bob.handle(context.Background(), log, doc)
+		if err != nil {
+			log.Error(err)
+		}
+	}()
+
+	return true, nil
+}
+
+// handle selects the clusters of a leased batch operation if this has not
+// been done yet, and then runs its action against them.  Clusters which were
+// left running by a previous lease holder are retried.  If the backend is
+// stopping, no more clusters are started and the lease is ended so that
+// another backend carries on from here.
+func (bob *batchOperationBackend) handle(ctx context.Context, log *logrus.Entry, doc *api.BatchOperationDocument) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stop := bob.heartbeat(ctx, cancel, log, doc)
+	defer stop()
+
+	doc, err := bob.dbBatchOperations.PatchWithLease(ctx, doc.ID, func(doc *api.BatchOperationDocument) error {
+		if doc.BatchOperation.State == api.BatchOperationStatePending {
+			now := bob.now().UTC()
+			doc.BatchOperation.StartTime = &now
+		}
+		doc.BatchOperation.State = api.BatchOperationStateRunning
+
+		for i := range doc.BatchOperation.Clusters {
+			if doc.BatchOperation.Clusters[i].State == api.BatchOperationStateRunning {
+				doc.BatchOperation.Clusters[i].State = api.BatchOperationStatePending
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if doc.BatchOperation.Clusters == nil {
+		doc, err = bob.selectClusters(ctx, log, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	if !doc.BatchOperation.State.IsTerminal() {
+		log.Infof("running %s across %d clusters", doc.BatchOperation.Action, len(doc.BatchOperation.Clusters))
+
+		doc, err = bob.run(ctx, log, doc)
+		if err != nil {
+			return err
+		}
+	}
+
+	stop()
+
+	if doc.BatchOperation.State.IsTerminal() {
+		log.Infof("finished with state %s", doc.BatchOperation.State)
+	} else {
+		log.Warn("stopped before completion")
+	}
+
+	_, err = bob.dbBatchOperations.EndLease(ctx, doc.ID)
+	return err
+}
+
+// selectClusters records the clusters matched by the batch operation's
+// selector.  If the selector lists resource IDs, only those clusters are
+// considered and any which do not exist are skipped; otherwise the whole
+// fleet is.  If no clusters are matched, the batch operation fails.
+func (bob *batchOperationBackend) selectClusters(ctx context.Context, log *logrus.Entry, doc *api.BatchOperationDocument) (*api.BatchOperationDocument, error) {
+	s := &doc.BatchOperation.Selector
+	var clusters []api.BatchOperationCluster
+
+	if len(s.ResourceIDs) > 0 {
+		for _, resourceID := range s.ResourceIDs {
+			ocDoc, err := bob.dbOpenShiftClusters.Get(ctx, strings.ToLower(resourceID))
+			switch {
+			case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+				clusters = append(clusters, api.BatchOperationCluster{
+					ResourceID: resourceID,
+					State:      api.BatchOperationStateSkipped,
+					Error:      fmt.Sprintf("The Resource '%s' was not found.", resourceID),
+				})
+				continue
+			case err != nil:
+				return nil, err
+			}
+
+			if batchOperationSelects(s, ocDoc) {
+				clusters = append(clusters, api.BatchOperationCluster{
+					ResourceID: ocDoc.OpenShiftCluster.ID,
+					State:      api.BatchOperationStatePending,
+				})
+			}
+		}
+	} else {
+		i := bob.dbOpenShiftClusters.List("")
+		for {
+			docs, err := i.Next(ctx, -1)
+			if err != nil {
+				return nil, err
+			}
+			if docs == nil {
+				break
+			}
+
+			for _, ocDoc := range docs.OpenShiftClusterDocuments {
+				if batchOperationSelects(s, ocDoc) {
+					clusters = append(clusters, api.BatchOperationCluster{
+						ResourceID: ocDoc.OpenShiftCluster.ID,
+						State:      api.BatchOperationStatePending,
+					})
+				}
+			}
+		}
+	}
+
+	log.Infof("selected %d clusters", len(clusters))
+
+	return bob.dbBatchOperations.PatchWithLease(ctx, doc.ID, func(doc *api.BatchOperationDocument) error {
+		doc.BatchOperation.Clusters = clusters
+
+		for _, c := range clusters {
+			if c.State == api.BatchOperationStatePending {
+				return nil
+			}
+		}
+
+		now := bob.now().UTC()
+		doc.BatchOperation.EndTime = &now
+		doc.BatchOperation.State = api.BatchOperationStateFailed
+		doc.BatchOperation.Error = "The selector did not match any clusters."
+
+		return nil
+	})
+}
+
+// run runs the batch operation's action against its pending clusters, at most
+// MaxConcurrency at a time, until they are all done or the failure budget is
+// exceeded
+func (bob *batchOperationBackend) run(ctx context.Context, log *logrus.Entry, doc *api.BatchOperationDocument) (*api.BatchOperationDocument, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		sem      = make(chan struct{}, doc.BatchOperation.MaxConcurrency)
+		failures int
+	)
+
+	for _, c := range doc.BatchOperation.Clusters {
+		if c.State == api.BatchOperationStateFailed {
+			failures++
+		}
+	}
+
+	budgetExceeded := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return failures > doc.BatchOperation.FailureBudget
+	}
+
+	setClusterState := func(resourceID string, state api.BatchOperationState, errMsg string) error {
+		_, err := bob.dbBatchOperations.PatchWithLease(ctx, doc.ID, func(doc *api.BatchOperationDocument) error {
+			for i := range doc.BatchOperation.Clusters {
+				if doc.BatchOperation.Clusters[i].ResourceID == resourceID {
+					doc.BatchOperation.Clusters[i].State = state
+					doc.BatchOperation.Clusters[i].Error = errMsg
+				}
+			}
+			return nil
+		})
+		return err
+	}
+
+	var stopped bool
+	for _, c := range doc.BatchOperation.Clusters {
+		if c.State != api.BatchOperationStatePending {
+			continue
+		}
+
+		sem <- struct{}{}
+
+		if bob.stopping.Load().(bool) || ctx.Err() != nil {
+			stopped = true
+			<-sem
+			break
+		}
+
+		if budgetExceeded() {
+			<-sem
+			break
+		}
+
+		wg.Add(1)
+		go func(resourceID string) {
+			defer recover.Panic(log)
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			log := utillog.EnrichWithResourceID(log, resourceID)
+
+			err := setClusterState(resourceID, api.BatchOperationStateRunning, "")
+			if err != nil {
+				log.Error(err)
+				return
+			}
+
+			stopSignal := bob.emitMaintenanceSignal(resourceID)
+			err = bob.runAction(ctx, log, doc.BatchOperation, resourceID)
+			stopSignal()
+
+			if err != nil {
+				log.Errorf("%s failed: %s", doc.BatchOperation.Action, err)
+
+				mu.Lock()
+				failures++
+				mu.Unlock()
+
+				err = setClusterState(resourceID, api.BatchOperationStateFailed, err.Error())
+			} else {
+				err = setClusterState(resourceID, api.BatchOperationStateSucceeded, "")
+			}
+			if err != nil {
+				log.Error(err)
+			}
+		}(c.ResourceID)
+	}
+
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		// we lost our lease; whoever dequeues the batch operation next will
+		// carry on from here
+		return nil, err
+	}
+
+	return bob.dbBatchOperations.PatchWithLease(ctx, doc.ID, func(doc *api.BatchOperationDocument) error {
+		if stopped && failures <= doc.BatchOperation.FailureBudget {
+			// leave the remaining clusters to the next lease holder
+			return nil
+		}
+
+		now := bob.now().UTC()
+		doc.BatchOperation.EndTime = &now
+		doc.BatchOperation.State = api.BatchOperationStateSucceeded
+
+		if failures > doc.BatchOperation.FailureBudget {
+			doc.BatchOperation.State = api.BatchOperationStateFailed
+			doc.BatchOperation.Error = fmt.Sprintf("%d clusters failed, exceeding the failure budget of %d", failures, doc.BatchOperation.FailureBudget)
+
+			for i := range doc.BatchOperation.Clusters {
+				if doc.BatchOperation.Clusters[i].State == api.BatchOperationStatePending {
+					doc.BatchOperation.Clusters[i].State = api.BatchOperationStateSkipped
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// emitMaintenanceSignal emits the unplanned maintenance signal for the
+// cluster every minute until the returned function is called, as the admin
+// API does for actions run against a single cluster
+func (bob *batchOperationBackend) emitMaintenanceSignal(resourceID string) func() {
+	stop := make(chan struct{})
+
+	emit := func() {
+		bob.clusterm.EmitGauge("frontend.maintenance.unplanned", 1, map[string]string{
+			"resourceId": resourceID,
+		})
+	}
+
+	emit()
+	go func() {
+		defer recover.Panic(bob.baseLog)
+
+		t := time.NewTicker(time.Minute)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+				emit()
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	return func() { close(stop) }
+}
+
+// runAction runs the batch operation's action against a single cluster.  The
+// cluster is skipped with an error if it is no longer selected, for example
+// because it is now being deleted.
+func (bob *batchOperationBackend) runAction(ctx context.Context, log *logrus.Entry, bo *api.BatchOperation, resourceID string) error {
+	key := strings.ToLower(resourceID)
+
+	r, err := azure.ParseResourceID(key)
+	if err != nil {
+		return err
+	}
+
+	doc, err := bob.dbOpenShiftClusters.Get(ctx, key)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", r.ResourceType, r.ResourceName, r.ResourceGroup)
+	case err != nil:
+		return err
+	}
+
+	if !batchOperationSelects(&bo.Selector, doc) {
+		return fmt.Errorf("the cluster is no longer selected in provisioningState %s", doc.OpenShiftCluster.Properties.ProvisioningState)
+	}
+
+	switch bo.Action {
+	case api.BatchOperationActionApproveCSR:
+		k, err := bob.newKubeActions(log, bob.env, doc.OpenShiftCluster)
+		if err != nil {
+			return err
+		}
+
+		return k.ApproveAllCsrs(ctx)
+
+	case api.BatchOperationActionEtcdCertificateRenew:
+		k, err := bob.newKubeActions(log, bob.env, doc.OpenShiftCluster)
+		if err != nil {
+			return err
+		}
+
+		return adminactions.RenewEtcdCertificates(ctx, log, k, doc.OpenShiftCluster, etcdCertificateRenewTimeout)
+
+	case api.BatchOperationActionKubernetesObjectDelete:
+		groupKind, namespace, name := bo.Parameters["kind"], bo.Parameters["namespace"], bo.Parameters["name"]
+		force, _ := strconv.ParseBool(bo.Parameters["force"])
+
+		if force {
+			err := validate.AdminKubernetesObjectsForceDelete(groupKind)
+			if err != nil {
+				return err
+			}
+		}
+
+		k, err := bob.newKubeActions(log, bob.env, doc.OpenShiftCluster)
+		if err != nil {
+			return err
+		}
+
+		gvr, err := k.ResolveGVR(groupKind, "")
+		if err != nil {
+			return err
+		}
+
+		err = validate.AdminKubernetesObjectsNonCustomer(http.MethodDelete, gvr, namespace, name)
+		if err != nil {
+			return err
+		}
+
+		return k.KubeDelete(ctx, groupKind, namespace, name, force, nil)
+
+	case api.BatchOperationActionRedeployVM:
+		vmName := doc.OpenShiftCluster.Properties.InfraID + "-" + bo.Parameters["vmNameSuffix"]
+
+		err := validate.AdminVMName(vmName)
+		if err != nil {
+			return err
+		}
+
+		subscriptionDoc, err := bob.dbSubscriptions.Get(ctx, r.SubscriptionID)
+		if err != nil {
+			return err
+		}
+
+		a, err := bob.newAzureActions(log, bob.env, doc.OpenShiftCluster, subscriptionDoc)
+		if err != nil {
+			return err
+		}
+
+		return a.VMRedeployAndWait(ctx, vmName)
+	}
+
+	return fmt.Errorf("unsupported batch operation action %q", bo.Action)
+}
+
+func (bob *batchOperationBackend) heartbeat(ctx context.Context, cancel context.CancelFunc, log *logrus.Entry, doc *api.BatchOperationDocument) func() {
+	var stopped bool
+	stop, done := make(chan struct{}), make(chan struct{})
+
+	go func() {
+		defer recover.Panic(log)
+
+		defer close(done)
+
+		t := time.NewTicker(10 * time.Second)
+		defer t.Stop()
+
+		for {
+			select {
+			case <-t.C:
+			case <-stop:
+				return
+			}
+
+			_, err := bob.dbBatchOperations.Lease(ctx, doc.ID)
+			if err != nil {
+				log.Error(err)
+				cancel()
+				return
+			}
+		}
+	}()
+
+	return func() {
+		if !stopped {
+			close(stop)
+			<-done
+			stopped = true
+		}
+	}
+}
+
+// batchOperationSelects returns true if the cluster matches every non-empty
+// field of the selector.  Clusters which are being created or deleted, or
+// which failed to be, are never selected.
+func batchOperationSelects(s *api.BatchOperationSelector, doc *api.OpenShiftClusterDocument) bool {
+	ps := doc.OpenShiftCluster.Properties.ProvisioningState
+	fps := doc.OpenShiftCluster.Properties.FailedProvisioningState
+
+	switch {
+	case ps == api.ProvisioningStateCreating,
+		ps == api.ProvisioningStateDeleting,
+		ps == api.ProvisioningStateFailed &&
+			(fps == api.ProvisioningStateCreating ||
+				fps == api.ProvisioningStateDeleting):
+		return false
+	}
+
+	if len(s.SubscriptionIDs) > 0 {
+		r, err := azure.ParseResourceID(doc.OpenShiftCluster.ID)
+		if err != nil || !containsFold(s.SubscriptionIDs, r.SubscriptionID) {
+			return false
+		}
+	}
+
+	if len(s.Locations) > 0 && !containsFold(s.Locations, doc.OpenShiftCluster.Location) {
+		return false
+	}
+
+	if s.MinVersion != "" || s.MaxVersion != "" {
+		v, err := version.ParseVersion(doc.OpenShiftCluster.Properties.ClusterProfile.Version)
+		if err != nil {
+			return false
+		}
+
+		if s.MinVersion != "" {
+			min, err := version.ParseVersion(s.MinVersion)
+			if err != nil || v.Lt(min) {
+				return false
+			}
+		}
+
+		if s.MaxVersion != "" {
+			max, err := version.ParseVersion(s.MaxVersion)
+			if err != nil || !v.Lt(max) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func containsFold(s []string, v string) bool {
+	for _, i := range s {
+		if strings.EqualFold(i, v) {
+			return true
+		}
+	}
+	return false
+}
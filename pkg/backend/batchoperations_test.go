@@ -0,0 +1,538 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+type batchOperationTestInfra struct {
+	dbOpenShiftClusters   database.OpenShiftClusters
+	dbBatchOperations     database.BatchOperations
+	batchOperationsClient *cosmosdb.FakeBatchOperationDocumentClient
+
+	bob *batchOperationBackend
+}
+
+func newBatchOperationTestInfra(t *testing.T, now func() time.Time, k map[string]*mock_adminactions.MockKubeActions, fixture func(*testdatabase.Fixture)) *batchOperationTestInfra {
+	ti := &batchOperationTestInfra{}
+
+	ti.dbOpenShiftClusters, _ = testdatabase.NewFakeOpenShiftClusters()
+	ti.dbBatchOperations, ti.batchOperationsClient = testdatabase.NewFakeBatchOperations()
+
+	f := testdatabase.NewFixture().WithOpenShiftClusters(ti.dbOpenShiftClusters).WithBatchOperations(ti.dbBatchOperations)
+	fixture(f)
+
+	err := f.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, log := testlog.New()
+
+	b := &backend{
+		baseLog:             log,
+		dbOpenShiftClusters: ti.dbOpenShiftClusters,
+		dbBatchOperations:   ti.dbBatchOperations,
+		m:                   &noop.Noop{},
+		clusterm:            &noop.Noop{},
+	}
+	b.cond = sync.NewCond(&b.mu)
+	b.stopping.Store(false)
+
+	ti.bob = newBatchOperationBackend(b)
+	ti.bob.now = now
+	ti.bob.newKubeActions = func(log *logrus.Entry, _ env.Interface, oc *api.OpenShiftCluster) (adminactions.KubeActions, error) {
+		return k[oc.Name], nil
+	}
+
+	return ti
+}
+
+func TestBatchOperationBackendHandle(t *testing.T) {
+	ctx := context.Background()
+
+	startTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := func() time.Time { return startTime }
+
+	clusterID := func(name string) string {
+		return "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/" + name
+	}
+	names := []string{"one", "two", "three"}
+
+	type test struct {
+		name          string
+		batch         *api.BatchOperation
+		stopping      bool
+		mocks         func(map[string]*mock_adminactions.MockKubeActions)
+		wantDocuments []*api.BatchOperationDocument
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "all clusters succeed",
+			batch: &api.BatchOperation{
+				Action:         api.BatchOperationActionApproveCSR,
+				MaxConcurrency: 2,
+				State:          api.BatchOperationStatePending,
+				Clusters: []api.BatchOperationCluster{
+					{ResourceID: clusterID("one"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("two"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("three"), State: api.BatchOperationStatePending},
+				},
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				for _, name := range names {
+					k[name].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+				}
+			},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						MaxConcurrency: 2,
+						State:          api.BatchOperationStateSucceeded,
+						StartTime:      &startTime,
+						EndTime:        &startTime,
+						Clusters: []api.BatchOperationCluster{
+							{ResourceID: clusterID("one"), State: api.BatchOperationStateSucceeded},
+							{ResourceID: clusterID("two"), State: api.BatchOperationStateSucceeded},
+							{ResourceID: clusterID("three"), State: api.BatchOperationStateSucceeded},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "failures within budget",
+			batch: &api.BatchOperation{
+				Action:         api.BatchOperationActionApproveCSR,
+				MaxConcurrency: 1,
+				FailureBudget:  1,
+				State:          api.BatchOperationStatePending,
+				Clusters: []api.BatchOperationCluster{
+					{ResourceID: clusterID("one"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("two"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("three"), State: api.BatchOperationStatePending},
+				},
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				k["one"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+				k["two"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(errors.New("random error"))
+				k["three"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+			},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						MaxConcurrency: 1,
+						FailureBudget:  1,
+						State:          api.BatchOperationStateSucceeded,
+						StartTime:      &startTime,
+						EndTime:        &startTime,
+						Clusters: []api.BatchOperationCluster{
+							{ResourceID: clusterID("one"), State: api.BatchOperationStateSucceeded},
+							{ResourceID: clusterID("two"), State: api.BatchOperationStateFailed, Error: "random error"},
+							{ResourceID: clusterID("three"), State: api.BatchOperationStateSucceeded},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "failure budget exceeded",
+			batch: &api.BatchOperation{
+				Action:         api.BatchOperationActionApproveCSR,
+				MaxConcurrency: 1,
+				State:          api.BatchOperationStatePending,
+				Clusters: []api.BatchOperationCluster{
+					{ResourceID: clusterID("one"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("two"), State: api.BatchOperationStatePending},
+					{ResourceID: clusterID("three"), State: api.BatchOperationStatePending},
+				},
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				k["one"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(errors.New("random error"))
+			},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						MaxConcurrency: 1,
+						State:          api.BatchOperationStateFailed,
+						Error:          "1 clusters failed, exceeding the failure budget of 0",
+						StartTime:      &startTime,
+						EndTime:        &startTime,
+						Clusters: []api.BatchOperationCluster{
+							{ResourceID: clusterID("one"), State: api.BatchOperationStateFailed, Error: "random error"},
+							{ResourceID: clusterID("two"), State: api.BatchOperationStateSkipped},
+							{ResourceID: clusterID("three"), State: api.BatchOperationStateSkipped},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resume after losing lease",
+			batch: &api.BatchOperation{
+				Action:         api.BatchOperationActionApproveCSR,
+				MaxConcurrency: 2,
+				State:          api.BatchOperationStateRunning,
+				StartTime:      &startTime,
+				Clusters: []api.BatchOperationCluster{
+					{ResourceID: clusterID("one"), State: api.BatchOperationStateSucceeded},
+					{ResourceID: clusterID("two"), State: api.BatchOperationStateRunning},
+					{ResourceID: clusterID("three"), State: api.BatchOperationStatePending},
+				},
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				k["two"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+				k["three"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+			},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						MaxConcurrency: 2,
+						State:          api.BatchOperationStateSucceeded,
+						StartTime:      &startTime,
+						EndTime:        &startTime,
+						Clusters: []api.BatchOperationCluster{
+							{ResourceID: clusterID("one"), State: api.BatchOperationStateSucceeded},
+							{ResourceID: clusterID("two"), State: api.BatchOperationStateSucceeded},
+							{ResourceID: clusterID("three"), State: api.BatchOperationStateSucceeded},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "stop starting clusters while stopping",
+			batch: &api.BatchOperation{
+				Action:         api.BatchOperationActionApproveCSR,
+				MaxConcurrency: 2,
+				State:          api.BatchOperationStatePending,
+				Clusters: []api.BatchOperationCluster{
+					{ResourceID: clusterID("one"), State: api.BatchOperationStatePending},
+				},
+			},
+			stopping: true,
+			mocks:    func(k map[string]*mock_adminactions.MockKubeActions) {},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						MaxConcurrency: 2,
+						State:          api.BatchOperationStateRunning,
+						StartTime:      &startTime,
+						Clusters: []api.BatchOperationCluster{
+							{ResourceID: clusterID("one"), State: api.BatchOperationStatePending},
+						},
+					},
+				},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			k := map[string]*mock_adminactions.MockKubeActions{}
+			for _, name := range names {
+				k[name] = mock_adminactions.NewMockKubeActions(controller)
+			}
+			tt.mocks(k)
+
+			ti := newBatchOperationTestInfra(t, now, k, func(f *testdatabase.Fixture) {
+				for _, name := range names {
+					f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+						Key: strings.ToLower(clusterID(name)),
+						OpenShiftCluster: &api.OpenShiftCluster{
+							ID:   clusterID(name),
+							Name: name,
+						},
+					})
+				}
+				f.AddBatchOperationDocuments(&api.BatchOperationDocument{
+					BatchOperation: tt.batch,
+				})
+			})
+			ti.bob.stopping.Store(tt.stopping)
+
+			doc, err := ti.dbBatchOperations.Dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ti.bob.handle(ctx, ti.bob.baseLog, doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			checker.AddBatchOperationDocuments(tt.wantDocuments...)
+			for _, err := range checker.CheckBatchOperations(ti.batchOperationsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBatchOperationBackendSelectClusters(t *testing.T) {
+	ctx := context.Background()
+
+	startTime := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	now := func() time.Time { return startTime }
+
+	clusterID := func(subscriptionID, name string) string {
+		return "/subscriptions/" + subscriptionID + "/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/" + name
+	}
+
+	sub1 := "00000000-0000-0000-0000-000000000001"
+	sub2 := "00000000-0000-0000-0000-000000000002"
+
+	cluster := func(subscriptionID, name, location, version string, state api.ProvisioningState) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			Key: strings.ToLower(clusterID(subscriptionID, name)),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:       clusterID(subscriptionID, name),
+				Name:     name,
+				Location: location,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: state,
+					ClusterProfile: api.ClusterProfile{
+						Version: version,
+					},
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name         string
+		selector     api.BatchOperationSelector
+		mocks        func(map[string]*mock_adminactions.MockKubeActions)
+		wantState    api.BatchOperationState
+		wantError    string
+		wantClusters []api.BatchOperationCluster
+	}{
+		{
+			name: "select by subscription and version range",
+			selector: api.BatchOperationSelector{
+				SubscriptionIDs: []string{sub1},
+				MinVersion:      "4.12.0",
+				MaxVersion:      "4.14.0",
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				k["old"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+			},
+			wantState: api.BatchOperationStateSucceeded,
+			wantClusters: []api.BatchOperationCluster{
+				{ResourceID: clusterID(sub1, "old"), State: api.BatchOperationStateSucceeded},
+			},
+		},
+		{
+			name: "select by resource ID and location",
+			selector: api.BatchOperationSelector{
+				ResourceIDs: []string{clusterID(sub1, "new"), clusterID(sub2, "other"), clusterID(sub2, "missing")},
+				Locations:   []string{"WestUS"},
+			},
+			mocks: func(k map[string]*mock_adminactions.MockKubeActions) {
+				k["other"].EXPECT().ApproveAllCsrs(gomock.Any()).Return(nil)
+			},
+			wantState: api.BatchOperationStateSucceeded,
+			wantClusters: []api.BatchOperationCluster{
+				{ResourceID: clusterID(sub2, "other"), State: api.BatchOperationStateSucceeded},
+				{ResourceID: clusterID(sub2, "missing"), State: api.BatchOperationStateSkipped, Error: "The Resource '" + clusterID(sub2, "missing") + "' was not found."},
+			},
+		},
+		{
+			name: "selector matches nothing",
+			selector: api.BatchOperationSelector{
+				Locations: []string{"northeurope"},
+			},
+			wantState: api.BatchOperationStateFailed,
+			wantError: "The selector did not match any clusters.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			k := map[string]*mock_adminactions.MockKubeActions{}
+			for _, name := range []string{"old", "new", "creating", "other"} {
+				k[name] = mock_adminactions.NewMockKubeActions(controller)
+			}
+			if tt.mocks != nil {
+				tt.mocks(k)
+			}
+
+			ti := newBatchOperationTestInfra(t, now, k, func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(
+					cluster(sub1, "old", "eastus", "4.12.25", api.ProvisioningStateSucceeded),
+					cluster(sub1, "new", "eastus", "4.14.16", api.ProvisioningStateSucceeded),
+					cluster(sub1, "creating", "eastus", "4.13.0", api.ProvisioningStateCreating),
+					cluster(sub2, "other", "westus", "4.13.40", api.ProvisioningStateSucceeded),
+				)
+				f.AddBatchOperationDocuments(&api.BatchOperationDocument{
+					BatchOperation: &api.BatchOperation{
+						Action:         api.BatchOperationActionApproveCSR,
+						Selector:       tt.selector,
+						MaxConcurrency: 10,
+						State:          api.BatchOperationStatePending,
+					},
+				})
+			})
+
+			doc, err := ti.dbBatchOperations.Dequeue(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = ti.bob.handle(ctx, ti.bob.baseLog, doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			checker.AddBatchOperationDocuments(&api.BatchOperationDocument{
+				ID: "07070707-0707-0707-0707-070707070001",
+				BatchOperation: &api.BatchOperation{
+					Action:         api.BatchOperationActionApproveCSR,
+					Selector:       tt.selector,
+					MaxConcurrency: 10,
+					State:          tt.wantState,
+					Error:          tt.wantError,
+					StartTime:      &startTime,
+					EndTime:        &startTime,
+					Clusters:       tt.wantClusters,
+				},
+			})
+			for _, err := range checker.CheckBatchOperations(ti.batchOperationsClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func TestBatchOperationSelects(t *testing.T) {
+	doc := &api.OpenShiftClusterDocument{
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID:       "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName",
+			Location: "eastus",
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: api.ProvisioningStateSucceeded,
+				ClusterProfile: api.ClusterProfile{
+					Version: "4.13.40",
+				},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name     string
+		selector api.BatchOperationSelector
+		mutate   func(*api.OpenShiftClusterDocument)
+		want     bool
+	}{
+		{
+			name: "matching subscription and location",
+			selector: api.BatchOperationSelector{
+				SubscriptionIDs: []string{"00000000-0000-0000-0000-000000000000"},
+				Locations:       []string{"EastUS"},
+			},
+			want: true,
+		},
+		{
+			name: "other location",
+			selector: api.BatchOperationSelector{
+				Locations: []string{"westus"},
+			},
+		},
+		{
+			name: "minimum version is inclusive",
+			selector: api.BatchOperationSelector{
+				MinVersion: "4.13.40",
+			},
+			want: true,
+		},
+		{
+			name: "maximum version is exclusive",
+			selector: api.BatchOperationSelector{
+				MaxVersion: "4.13.40",
+			},
+		},
+		{
+			name: "unparseable cluster version",
+			selector: api.BatchOperationSelector{
+				MinVersion: "4.10.0",
+			},
+			mutate: func(doc *api.OpenShiftClusterDocument) {
+				doc.OpenShiftCluster.Properties.ClusterProfile.Version = ""
+			},
+		},
+		{
+			name: "cluster failed to delete",
+			selector: api.BatchOperationSelector{
+				Locations: []string{"eastus"},
+			},
+			mutate: func(doc *api.OpenShiftClusterDocument) {
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateFailed
+				doc.OpenShiftCluster.Properties.FailedProvisioningState = api.ProvisioningStateDeleting
+			},
+		},
+		{
+			name: "cluster failed to update",
+			selector: api.BatchOperationSelector{
+				Locations: []string{"eastus"},
+			},
+			mutate: func(doc *api.OpenShiftClusterDocument) {
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateFailed
+				doc.OpenShiftCluster.Properties.FailedProvisioningState = api.ProvisioningStateUpdating
+			},
+			want: true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &api.OpenShiftClusterDocument{
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID:         doc.OpenShiftCluster.ID,
+					Location:   doc.OpenShiftCluster.Location,
+					Properties: doc.OpenShiftCluster.Properties,
+				},
+			}
+			if tt.mutate != nil {
+				tt.mutate(doc)
+			}
+
+			got := batchOperationSelects(&tt.selector, doc)
+			if got != tt.want {
+				t.Error(got)
+			}
+		})
+	}
+}
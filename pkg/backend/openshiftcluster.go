@@ -5,9 +5,11 @@ package backend
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -27,16 +29,25 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 )
 
+// errOperationCancelled is recorded as the failure of an operation which an
+// admin has asked to cancel
+var errOperationCancelled = errors.New("operation cancelled by admin request")
+
 type openShiftClusterBackend struct {
 	*backend
 
 	newManager func(context.Context, *logrus.Entry, env.Interface, database.OpenShiftClusters, database.Gateway, database.OpenShiftVersions, encryption.AEAD, billing.Manager, *api.OpenShiftClusterDocument, *api.SubscriptionDocument, hive.ClusterManager, metrics.Emitter) (cluster.Interface, error)
+
+	subscriptionWorkersMu sync.Mutex
+	subscriptionWorkers   map[string]int
 }
 
 func newOpenShiftClusterBackend(b *backend) *openShiftClusterBackend {
 	return &openShiftClusterBackend{
 		backend:    b,
 		newManager: cluster.New,
+
+		subscriptionWorkers: map[string]int{},
 	}
 }
 
@@ -45,7 +56,7 @@ func newOpenShiftClusterBackend(b *backend) *openShiftClusterBackend {
 // succeeded in dequeuing anything - if this is false, the caller should sleep
 // before calling again
 func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
-	doc, err := ocb.dbOpenShiftClusters.Dequeue(ctx)
+	doc, err := ocb.dbOpenShiftClusters.Dequeue(ctx, ocb.maxCreatesPerSubscription)
 	if err != nil || doc == nil {
 		return false, err
 	}
@@ -61,9 +72,14 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 		return true, ocb.endLease(ctx, log, nil, doc, api.ProvisioningStateFailed, err)
 	}
 
+	if doc.CancelRequested {
+		log.Print("cancel requested before dequeue, failing")
+		return true, ocb.endLease(ctx, log, nil, doc, api.ProvisioningStateFailed, errOperationCancelled)
+	}
+
 	log.Print("dequeued")
 	atomic.AddInt32(&ocb.workers, 1)
-	ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
+	ocb.addSubscriptionWorkers(doc.PartitionKey, 1)
 
 	go func() {
 		defer recover.Panic(log)
@@ -72,7 +88,7 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 
 		defer func() {
 			atomic.AddInt32(&ocb.workers, -1)
-			ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(atomic.LoadInt32(&ocb.workers)), nil)
+			ocb.addSubscriptionWorkers(doc.PartitionKey, -1)
 			ocb.cond.Signal()
 
 			log.WithField("duration", time.Since(t).Seconds()).Print("done")
@@ -87,12 +103,32 @@ func (ocb *openShiftClusterBackend) try(ctx context.Context) (bool, error) {
 	return true, nil
 }
 
+// addSubscriptionWorkers adjusts the number of workers handling clusters in
+// the given subscription and emits the workers gauge for that subscription
+func (ocb *openShiftClusterBackend) addSubscriptionWorkers(subscriptionID string, delta int) {
+	ocb.subscriptionWorkersMu.Lock()
+	ocb.subscriptionWorkers[subscriptionID] += delta
+	workers := ocb.subscriptionWorkers[subscriptionID]
+	if workers == 0 {
+		delete(ocb.subscriptionWorkers, subscriptionID)
+	}
+	ocb.subscriptionWorkersMu.Unlock()
+
+	ocb.m.EmitGauge("backend.openshiftcluster.workers.count", int64(workers), map[string]string{
+		"subscriptionId": subscriptionID,
+	})
+}
+
 // handle is responsible for handling backend operation and lease
 func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument) error {
+	// leaseCtx outlives a cancel request so that we can still record the
+	// outcome of a cancelled operation
+	leaseCtx := ctx
 	ctx, cancel := context.WithCancel(ctx)
 	defer cancel()
 
-	stop := ocb.heartbeat(ctx, cancel, log, doc)
+	var cancelRequested atomic.Bool
+	stop := ocb.heartbeat(leaseCtx, cancel, &cancelRequested, log, doc)
 	defer stop()
 
 	r, err := azure.ParseResourceID(doc.OpenShiftCluster.ID)
@@ -118,12 +154,16 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 
 	var hr hive.ClusterManager
 	if installViaHive || adoptViaHive {
-		hiveShard := 1
-		hiveRestConfig, err := ocb.env.LiveConfig().HiveRestConfig(ctx, hiveShard)
-		if err != nil {
-			return fmt.Errorf("failed getting RESTConfig for Hive shard %d: %w", hiveShard, err)
+		if doc.OpenShiftCluster.Properties.ProvisioningState == api.ProvisioningStateCreating &&
+			doc.OpenShiftCluster.Properties.HiveProfile.Shard == 0 {
+			assigned, err := ocb.assignHiveShard(ctx, log, doc)
+			if err != nil {
+				return ocb.endLease(ctx, log, stop, doc, api.ProvisioningStateFailed, err)
+			}
+			doc = assigned
 		}
-		hr, err = hive.NewFromConfig(log, ocb.env, hiveRestConfig)
+
+		hr, err = hive.NewFromEnvForShard(ctx, log, ocb.env, hive.ShardFor(doc))
 		if err != nil {
 			return fmt.Errorf("failed creating HiveClusterManager: %w", err)
 		}
@@ -139,6 +179,9 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 		log.Print("creating")
 
 		err = m.Install(ctx)
+		if err != nil && cancelRequested.Load() {
+			return ocb.endLease(leaseCtx, log, stop, doc, api.ProvisioningStateFailed, errOperationCancelled)
+		}
 		if err != nil {
 			return ocb.endLease(ctx, log, stop, doc, api.ProvisioningStateFailed, err)
 		}
@@ -159,6 +202,9 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 		log.Printf("admin updating (type: %s)", doc.OpenShiftCluster.Properties.MaintenanceTask)
 
 		err = m.AdminUpdate(ctx)
+		if err != nil && cancelRequested.Load() {
+			return ocb.endLease(leaseCtx, log, stop, doc, api.ProvisioningStateFailed, errOperationCancelled)
+		}
 		if err != nil {
 			// Customer will continue to see the cluster in an ongoing maintenance state
 			return ocb.endLease(ctx, log, stop, doc, api.ProvisioningStateFailed, err)
@@ -206,7 +252,11 @@ func (ocb *openShiftClusterBackend) handle(ctx context.Context, log *logrus.Entr
 	return fmt.Errorf("unexpected provisioningState %q", doc.OpenShiftCluster.Properties.ProvisioningState)
 }
 
-func (ocb *openShiftClusterBackend) heartbeat(ctx context.Context, cancel context.CancelFunc, log *logrus.Entry, doc *api.OpenShiftClusterDocument) func() {
+// heartbeat renews the lease on doc until the returned function is called.  If
+// the lease is lost, or an admin requests that the operation be cancelled,
+// cancel is called; in the latter case cancelRequested is also set and the
+// lease continues to be renewed.
+func (ocb *openShiftClusterBackend) heartbeat(ctx context.Context, cancel context.CancelFunc, cancelRequested *atomic.Bool, log *logrus.Entry, doc *api.OpenShiftClusterDocument) func() {
 	var stopped bool
 	stop, done := make(chan struct{}), make(chan struct{})
 
@@ -219,13 +269,19 @@ func (ocb *openShiftClusterBackend) heartbeat(ctx context.Context, cancel contex
 		defer t.Stop()
 
 		for {
-			_, err := ocb.dbOpenShiftClusters.Lease(ctx, doc.Key)
+			leased, err := ocb.dbOpenShiftClusters.Lease(ctx, doc.Key)
 			if err != nil {
 				log.Error(err)
 				cancel()
 				return
 			}
 
+			if leased.CancelRequested && !cancelRequested.Load() {
+				log.Print("cancel requested")
+				cancelRequested.Store(true)
+				cancel()
+			}
+
 			select {
 			case <-t.C:
 			case <-stop:
@@ -247,6 +303,7 @@ func (ocb *openShiftClusterBackend) updateAsyncOperation(ctx context.Context, lo
 	if id != "" {
 		_, err := ocb.dbAsyncOperations.Patch(ctx, id, func(asyncdoc *api.AsyncOperationDocument) error {
 			asyncdoc.AsyncOperation.ProvisioningState = provisioningState
+			asyncdoc.AsyncOperation.Queue = nil
 
 			now := time.Now()
 			asyncdoc.AsyncOperation.EndTime = &now
@@ -387,3 +444,36 @@ func (ocb *openShiftClusterBackend) setNoMaintenanceState(ctx context.Context, d
 		return nil
 	})
 }
+
+// assignHiveShard selects the Hive shard which will manage a new cluster and
+// records it on the document. The shard is never changed once set.
+func (ocb *openShiftClusterBackend) assignHiveShard(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error) {
+	shards, err := ocb.env.LiveConfig().HiveShards(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	usage := map[int]int{}
+	for _, shard := range shards {
+		if shard.Draining {
+			continue
+		}
+
+		usage[shard.Index], err = ocb.dbOpenShiftClusters.CountByHiveShard(ctx, "OpenShiftClusters", shard.Index)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	shard, err := hive.SelectShard(shards, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("assigning to Hive shard %d", shard)
+
+	return ocb.dbOpenShiftClusters.PatchWithLease(ctx, doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		doc.OpenShiftCluster.Properties.HiveProfile.Shard = shard
+		return nil
+	})
+}
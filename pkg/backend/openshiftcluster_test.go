@@ -25,11 +25,13 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics/noop"
 	"github.com/Azure/ARO-RP/pkg/util/billing"
 	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/liveconfig"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	mock_cluster "github.com/Azure/ARO-RP/pkg/util/mocks/cluster"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
 	"github.com/Azure/ARO-RP/test/util/deterministicuuid"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
 	testlog "github.com/Azure/ARO-RP/test/util/log"
 	"github.com/Azure/ARO-RP/test/util/testliveconfig"
 )
@@ -286,6 +288,45 @@ func TestBackendTry(t *testing.T) {
 				manager.EXPECT().Delete(gomock.Any()).Return(nil)
 			},
 		},
+		{
+			name: "StateAdminUpdating with a cancel request fails without running the update",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key:             strings.ToLower(resourceID),
+					CancelRequested: true,
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:       resourceID,
+						Name:     "resourceName",
+						Type:     "Microsoft.RedHatOpenShift/OpenShiftClusters",
+						Location: "location",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:     api.ProvisioningStateAdminUpdating,
+							LastProvisioningState: api.ProvisioningStateSucceeded,
+							MaintenanceTask:       api.MaintenanceTaskEverything,
+						},
+					},
+				})
+				f.AddSubscriptionDocuments(&api.SubscriptionDocument{
+					ID: mockSubID,
+				})
+			},
+			checker: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key: strings.ToLower(resourceID),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID:       resourceID,
+						Name:     "resourceName",
+						Type:     "Microsoft.RedHatOpenShift/OpenShiftClusters",
+						Location: "location",
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState:    api.ProvisioningStateSucceeded,
+							LastAdminUpdateError: "operation cancelled by admin request",
+						},
+					},
+				})
+			},
+			mocks: func(manager *mock_cluster.MockInterface, dbOpenShiftClusters database.OpenShiftClusters) {},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -315,15 +356,13 @@ func TestBackendTry(t *testing.T) {
 				return manager, nil
 			}
 
-			b, err := newBackend(ctx, log, _env, nil, nil, nil, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, nil, &noop.Noop{})
+			b, err := newBackend(ctx, log, _env, nil, nil, nil, dbOpenShiftClusters, dbSubscriptions, dbOpenShiftVersions, nil, nil, &noop.Noop{}, &noop.Noop{}, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
 
-			b.ocb = &openShiftClusterBackend{
-				backend:    b,
-				newManager: createManager,
-			}
+			b.ocb = newOpenShiftClusterBackend(b)
+			b.ocb.newManager = createManager
 
 			worked, err := b.ocb.try(ctx)
 			if err != nil {
@@ -424,3 +463,123 @@ func TestAsyncOperationResultLog(t *testing.T) {
 		})
 	}
 }
+
+func TestAssignHiveShard(t *testing.T) {
+	ctx := context.Background()
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	resourceID := func(name string) string {
+		return fmt.Sprintf("/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourcegroup/providers/microsoft.redhatopenshift/openshiftclusters/%s", name)
+	}
+
+	hiveManagedDoc := func(name string, shard int) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			Key: resourceID(name),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID(name),
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateSucceeded,
+					HiveProfile: api.HiveProfile{
+						Namespace: "aro-" + name,
+						Shard:     shard,
+					},
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name      string
+		shards    []liveconfig.HiveShard
+		pending   []int
+		wantShard int
+		wantErr   string
+	}{
+		{
+			name: "assigns the shard with the most free capacity",
+			shards: []liveconfig.HiveShard{
+				{Index: 1, Capacity: 10},
+				{Index: 2, Capacity: 10},
+				{Index: 3, Capacity: 10, Draining: true},
+			},
+			wantShard: 2,
+		},
+		{
+			name: "counts clusters which are assigned a shard but not yet installed",
+			shards: []liveconfig.HiveShard{
+				{Index: 1, Capacity: 10},
+				{Index: 2, Capacity: 10},
+			},
+			pending:   []int{2, 2},
+			wantShard: 1,
+		},
+		{
+			name: "fails when no shard has capacity",
+			shards: []liveconfig.HiveShard{
+				{Index: 1, Capacity: 2},
+				{Index: 2, Capacity: 1},
+			},
+			wantErr: "no Hive shard has capacity for a new cluster",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			_env := mock_env.NewMockInterface(controller)
+			_env.EXPECT().LiveConfig().AnyTimes().Return(testliveconfig.NewTestLiveConfigWithHiveShards(false, true, tt.shards))
+
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+
+			f := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters)
+			f.AddOpenShiftClusterDocuments(
+				&api.OpenShiftClusterDocument{
+					Key: resourceID("new"),
+					OpenShiftCluster: &api.OpenShiftCluster{
+						ID: resourceID("new"),
+						Properties: api.OpenShiftClusterProperties{
+							ProvisioningState: api.ProvisioningStateCreating,
+						},
+					},
+				},
+				// clusters which predate sharding count against shard 1
+				hiveManagedDoc("legacy", 0),
+				hiveManagedDoc("one", 1),
+				hiveManagedDoc("two", 2),
+			)
+			for i, shard := range tt.pending {
+				// shards are assigned before the Hive namespace is created
+				doc := hiveManagedDoc(fmt.Sprintf("pending%d", i), shard)
+				doc.OpenShiftCluster.Properties.HiveProfile.Namespace = ""
+				f.AddOpenShiftClusterDocuments(doc)
+			}
+			err := f.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ocb := &openShiftClusterBackend{
+				backend: &backend{
+					baseLog:             log,
+					env:                 _env,
+					dbOpenShiftClusters: dbOpenShiftClusters,
+				},
+			}
+
+			doc, err = ocb.assignHiveShard(ctx, log, doc)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+			if err != nil {
+				return
+			}
+
+			if doc.OpenShiftCluster.Properties.HiveProfile.Shard != tt.wantShard {
+				t.Errorf("got shard %d, want %d", doc.OpenShiftCluster.Properties.HiveProfile.Shard, tt.wantShard)
+			}
+		})
+	}
+}
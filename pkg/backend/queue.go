@@ -0,0 +1,91 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+const queueReportInterval = 30 * time.Second
+
+// reportQueue periodically publishes the queue position of each waiting
+// cluster operation to its asyncOperation.  The queue length of each
+// subscription is emitted by database.EmitMetrics.
+func (ocb *openShiftClusterBackend) reportQueue(ctx context.Context, stop <-chan struct{}) {
+	defer recover.Panic(ocb.baseLog)
+
+	t := time.NewTicker(queueReportInterval)
+	defer t.Stop()
+
+	for {
+		err := ocb.publishQueue(ctx)
+		if err != nil {
+			ocb.baseLog.Error(err)
+		}
+
+		select {
+		case <-t.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (ocb *openShiftClusterBackend) publishQueue(ctx context.Context) error {
+	queue, err := ocb.dbOpenShiftClusters.Queue(ctx, ocb.maxCreatesPerSubscription)
+	if err != nil {
+		return err
+	}
+
+	for _, q := range queue {
+		if q.Doc.AsyncOperationID == "" {
+			continue
+		}
+
+		aq := &api.AsyncOperationQueue{
+			Position:            q.Position,
+			SubscriptionRunning: q.Running,
+			Throttled:           q.Throttled,
+		}
+		if ocb.maxCreatesPerSubscription > 0 {
+			aq.SubscriptionLimit = ocb.maxCreatesPerSubscription
+		}
+
+		err = ocb.setAsyncOperationQueue(ctx, q.Doc.AsyncOperationID, aq)
+		if err != nil {
+			ocb.baseLog.Error(err)
+		}
+	}
+
+	return nil
+}
+
+// setAsyncOperationQueue updates the queue information of an asyncOperation,
+// avoiding a write if nothing changed
+func (ocb *openShiftClusterBackend) setAsyncOperationQueue(ctx context.Context, id string, aq *api.AsyncOperationQueue) error {
+	asyncdoc, err := ocb.dbAsyncOperations.Get(ctx, id)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil
+	case err != nil:
+		return err
+	}
+
+	if asyncdoc.AsyncOperation.EndTime != nil || reflect.DeepEqual(asyncdoc.AsyncOperation.Queue, aq) {
+		return nil
+	}
+
+	_, err = ocb.dbAsyncOperations.Patch(ctx, id, func(asyncdoc *api.AsyncOperationDocument) error {
+		asyncdoc.AsyncOperation.Queue = aq
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,83 @@
+package backend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestPublishQueue(t *testing.T) {
+	ctx := context.Background()
+
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	otherSubID := "11111111-1111-1111-1111-111111111111"
+
+	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+	dbAsyncOperations, _ := testdatabase.NewFakeAsyncOperations()
+
+	f := testdatabase.NewFixture().WithOpenShiftClusters(dbOpenShiftClusters).WithAsyncOperations(dbAsyncOperations)
+	for i, c := range []struct {
+		subscriptionID string
+		name           string
+	}{
+		{mockSubID, "one"},
+		{mockSubID, "two"},
+		{otherSubID, "three"},
+	} {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key:              strings.ToLower(testdatabase.GetResourcePath(c.subscriptionID, c.name)),
+			AsyncOperationID: c.name,
+			EnqueueTime:      i + 1,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: testdatabase.GetResourcePath(c.subscriptionID, c.name),
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateCreating,
+				},
+			},
+		})
+		f.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+			ID:             c.name,
+			AsyncOperation: &api.AsyncOperation{},
+		})
+	}
+
+	err := f.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ocb := newOpenShiftClusterBackend(&backend{
+		baseLog:                   utillog.GetLogger(),
+		dbAsyncOperations:         dbAsyncOperations,
+		dbOpenShiftClusters:       dbOpenShiftClusters,
+		maxCreatesPerSubscription: 1,
+	})
+
+	err = ocb.publishQueue(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]*api.AsyncOperationQueue{
+		"one":   {Position: 1, SubscriptionLimit: 1},
+		"three": {Position: 2, SubscriptionLimit: 1},
+		"two":   {Position: 3, SubscriptionLimit: 1, Throttled: true},
+	} {
+		asyncdoc, err := dbAsyncOperations.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(asyncdoc.AsyncOperation.Queue, want) {
+			t.Errorf("%s: got %#v, want %#v", id, asyncdoc.AsyncOperation.Queue, want)
+		}
+	}
+}
@@ -127,6 +127,7 @@ func (sb *subscriptionBackend) handleDelete(ctx context.Context, log *logrus.Ent
 				case api.ProvisioningStateSucceeded,
 					api.ProvisioningStateFailed:
 					doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateDeleting
+					doc.EnqueueTime = int(time.Now().Unix())
 				default:
 					return fmt.Errorf("unexpected provisioningState %q", doc.OpenShiftCluster.Properties.ProvisioningState)
 				}
@@ -50,6 +50,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
 				"[Action initializeOperatorDeployer-fm]",
@@ -73,6 +74,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
 				"[Action initializeOperatorDeployer-fm]",
@@ -93,6 +95,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
 				"[Action initializeOperatorDeployer-fm]",
@@ -115,11 +118,12 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
-				"[Action ensureResourceGroup-fm]",
-				"[Action createOrUpdateDenyAssignment-fm]",
-				"[Action ensureServiceEndpoints-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action ensureResourceGroup-fm]]",
+				"[Skippable [Action createOrUpdateDenyAssignment-fm]]",
+				"[Skippable [Action ensureServiceEndpoints-fm]]",
 				"[Action populateRegistryStorageAccountName-fm]",
-				"[Action migrateStorageAccounts-fm]",
+				"[Skippable [Action migrateStorageAccounts-fm]]",
 				"[Action fixSSH-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
@@ -161,11 +165,12 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
-				"[Action ensureResourceGroup-fm]",
-				"[Action createOrUpdateDenyAssignment-fm]",
-				"[Action ensureServiceEndpoints-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action ensureResourceGroup-fm]]",
+				"[Skippable [Action createOrUpdateDenyAssignment-fm]]",
+				"[Skippable [Action ensureServiceEndpoints-fm]]",
 				"[Action populateRegistryStorageAccountName-fm]",
-				"[Action migrateStorageAccounts-fm]",
+				"[Skippable [Action migrateStorageAccounts-fm]]",
 				"[Action fixSSH-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
@@ -203,11 +208,12 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
-				"[Action ensureResourceGroup-fm]",
-				"[Action createOrUpdateDenyAssignment-fm]",
-				"[Action ensureServiceEndpoints-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action ensureResourceGroup-fm]]",
+				"[Skippable [Action createOrUpdateDenyAssignment-fm]]",
+				"[Skippable [Action ensureServiceEndpoints-fm]]",
 				"[Action populateRegistryStorageAccountName-fm]",
-				"[Action migrateStorageAccounts-fm]",
+				"[Skippable [Action migrateStorageAccounts-fm]]",
 				"[Action fixSSH-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
@@ -244,11 +250,12 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
-				"[Action ensureResourceGroup-fm]",
-				"[Action createOrUpdateDenyAssignment-fm]",
-				"[Action ensureServiceEndpoints-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action ensureResourceGroup-fm]]",
+				"[Skippable [Action createOrUpdateDenyAssignment-fm]]",
+				"[Skippable [Action ensureServiceEndpoints-fm]]",
 				"[Action populateRegistryStorageAccountName-fm]",
-				"[Action migrateStorageAccounts-fm]",
+				"[Skippable [Action migrateStorageAccounts-fm]]",
 				"[Action fixSSH-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
@@ -289,6 +296,7 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
 				"[Condition apiServersReady-fm, timeout 30m0s]",
@@ -300,6 +308,32 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action renewMDSDCertificate-fm]",
 			},
 		},
+		{
+			name: "Upgrade OpenShift",
+			fixture: func() (*api.OpenShiftClusterDocument, bool) {
+				doc := baseClusterDoc()
+				doc.OpenShiftCluster.Properties.ProvisioningState = api.ProvisioningStateAdminUpdating
+				doc.OpenShiftCluster.Properties.MaintenanceTask = api.MaintenanceTaskUpgrade
+				doc.OpenShiftCluster.Properties.UpgradeVersion = "4.11.44"
+				return doc, true
+			},
+			shouldRunSteps: []string{
+				"[Action initializeKubernetesClients-fm]",
+				"[Action ensureBillingRecord-fm]",
+				"[Action ensureDefaults-fm]",
+				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
+				"[Action fixInfraID-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action validateUpgrade-fm]]",
+				"[Action startVMs-fm]",
+				"[Condition apiServersReady-fm, timeout 30m0s]",
+				"[Skippable [Action upgradePreChecks-fm]]",
+				"[Skippable [Action setDesiredUpdate-fm]]",
+				"[Condition clusterVersionUpgraded-fm, timeout 3h0m0s]",
+				"[Condition machineConfigPoolsUpdated-fm, timeout 2h0m0s]",
+				"[Action finishUpgrade-fm]",
+			},
+		},
 		{
 			name: "adminUpdate() does not adopt Hive-created clusters",
 			fixture: func() (*api.OpenShiftClusterDocument, bool) {
@@ -316,11 +350,12 @@ func TestAdminUpdateSteps(t *testing.T) {
 				"[Action ensureDefaults-fm]",
 				"[AuthorizationRetryingAction fixupClusterSPObjectID-fm]",
 				"[Action fixInfraID-fm]",
-				"[Action ensureResourceGroup-fm]",
-				"[Action createOrUpdateDenyAssignment-fm]",
-				"[Action ensureServiceEndpoints-fm]",
+				"[Action ensureGatewayEgressAllowList-fm]",
+				"[Skippable [Action ensureResourceGroup-fm]]",
+				"[Skippable [Action createOrUpdateDenyAssignment-fm]]",
+				"[Skippable [Action ensureServiceEndpoints-fm]]",
 				"[Action populateRegistryStorageAccountName-fm]",
-				"[Action migrateStorageAccounts-fm]",
+				"[Skippable [Action migrateStorageAccounts-fm]]",
 				"[Action fixSSH-fm]",
 				"[Action populateDatabaseIntIP-fm]",
 				"[Action startVMs-fm]",
@@ -0,0 +1,41 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+// AdminUpdatePlan describes the steps which an admin update would run
+type AdminUpdatePlan struct {
+	MaintenanceTask api.MaintenanceTask `json:"maintenanceTask"`
+	Steps           []steps.PlannedStep `json:"steps"`
+
+	// Skipped lists the groups of steps which will not be run, and why
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// PlanAdminUpdate returns the steps which AdminUpdate would run for the
+// maintenance task set on the document, without running them or modifying
+// the document.
+func PlanAdminUpdate(doc *api.OpenShiftClusterDocument, adoptViaHive bool) *AdminUpdatePlan {
+	m := &manager{
+		doc:          doc,
+		adoptViaHive: adoptViaHive,
+	}
+
+	task := doc.OpenShiftCluster.Properties.MaintenanceTask
+	if task == "" {
+		task = api.MaintenanceTaskEverything
+	}
+
+	toRun, skipped := m.adminUpdateSteps()
+
+	return &AdminUpdatePlan{
+		MaintenanceTask: task,
+		Steps:           steps.Plan(toRun, &documentCheckpointer{m: m}),
+		Skipped:         skipped,
+	}
+}
@@ -0,0 +1,77 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	"github.com/go-test/deep"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+func TestAdminUpdateSkipped(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		task          api.MaintenanceTask
+		version       string
+		adoptViaHive  bool
+		createdByHive bool
+		wantSkipped   []string
+	}{
+		{
+			name:         "everything, adopting via Hive",
+			task:         api.MaintenanceTaskEverything,
+			version:      "4.10.0",
+			adoptViaHive: true,
+		},
+		{
+			name:        "everything, not adopting via Hive",
+			task:        api.MaintenanceTaskEverything,
+			version:     "4.10.0",
+			wantSkipped: []string{"Hive adoption: adoption by Hive is disabled"},
+		},
+		{
+			name:          "blank task, cluster created by Hive",
+			version:       "4.10.0",
+			adoptViaHive:  true,
+			createdByHive: true,
+			wantSkipped:   []string{"Hive adoption: cluster was created by Hive"},
+		},
+		{
+			name:        "operator update on an old cluster",
+			task:        api.MaintenanceTaskOperator,
+			version:     "4.6.62",
+			wantSkipped: []string{`ARO operator update: cluster version "4.6.62" is older than 4.7.0`},
+		},
+		{
+			name:    "certificate renewal",
+			task:    api.MaintenanceTaskRenewCerts,
+			version: "4.6.62",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := &api.OpenShiftClusterDocument{
+				OpenShiftCluster: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						MaintenanceTask: tt.task,
+						ClusterProfile: api.ClusterProfile{
+							Version: tt.version,
+						},
+						HiveProfile: api.HiveProfile{
+							Namespace:     "aro-00000000-0000-0000-0000-000000000000",
+							CreatedByHive: tt.createdByHive,
+						},
+					},
+				},
+			}
+
+			plan := PlanAdminUpdate(doc, tt.adoptViaHive)
+
+			for _, diff := range deep.Equal(plan.Skipped, tt.wantSkipped) {
+				t.Error(diff)
+			}
+		})
+	}
+}
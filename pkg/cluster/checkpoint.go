@@ -0,0 +1,35 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// documentCheckpointer persists step checkpoints in the cluster document, so
+// that if the backend lease is lost part way through an operation, the next
+// backend to dequeue the document can resume where this one stopped.
+type documentCheckpointer struct {
+	m *manager
+}
+
+func (cp *documentCheckpointer) IsCompleted(key string) bool {
+	for _, completed := range cp.m.doc.CompletedSteps {
+		if completed == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (cp *documentCheckpointer) SetCompleted(ctx context.Context, key string) error {
+	var err error
+	cp.m.doc, err = cp.m.db.PatchWithLease(ctx, cp.m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		doc.CompletedSteps = append(doc.CompletedSteps, key)
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,76 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestDocumentCheckpointer(t *testing.T) {
+	ctx := context.Background()
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroup/providers/microsoft.redhatopenshift/openshiftclusters/resourceName"
+
+	fakeOpenShiftClustersDatabase, _ := testdatabase.NewFakeOpenShiftClusters()
+	fixture := testdatabase.NewFixture().WithOpenShiftClusters(fakeOpenShiftClustersDatabase)
+	fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+		Key: strings.ToLower(resourceID),
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID: resourceID,
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: api.ProvisioningStateCreating,
+			},
+		},
+		CompletedSteps: []string{"0.action.createDNS"},
+	})
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clusterdoc, err := fakeOpenShiftClustersDatabase.Dequeue(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &manager{
+		log: logrus.NewEntry(logrus.StandardLogger()),
+		doc: clusterdoc,
+		db:  fakeOpenShiftClustersDatabase,
+	}
+	cp := &documentCheckpointer{m: m}
+
+	if !cp.IsCompleted("0.action.createDNS") {
+		t.Error("expected 0.action.createDNS to be completed")
+	}
+	if cp.IsCompleted("1.action.ensureResourceGroup") {
+		t.Error("expected 1.action.ensureResourceGroup not to be completed")
+	}
+
+	err = cp.SetCompleted(ctx, "1.action.ensureResourceGroup")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !cp.IsCompleted("1.action.ensureResourceGroup") {
+		t.Error("expected 1.action.ensureResourceGroup to be completed")
+	}
+
+	doc, err := fakeOpenShiftClustersDatabase.Get(ctx, strings.ToLower(resourceID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"0.action.createDNS", "1.action.ensureResourceGroup"}
+	if !reflect.DeepEqual(doc.CompletedSteps, want) {
+		t.Errorf("got %v, want %v", doc.CompletedSteps, want)
+	}
+}
@@ -75,7 +75,7 @@ func TestFixInfraID(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			clusterdoc, err := fakeOpenShiftClustersDatabase.Dequeue(ctx)
+			clusterdoc, err := fakeOpenShiftClustersDatabase.Dequeue(ctx, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
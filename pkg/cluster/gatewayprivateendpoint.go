@@ -5,7 +5,9 @@ package cluster
 
 import (
 	"context"
+	"reflect"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/util/arm"
 	"github.com/Azure/ARO-RP/pkg/util/stringutils"
 )
@@ -47,3 +49,30 @@ func (m *manager) ensureGatewayUpgrade(ctx context.Context) error {
 
 	return nil
 }
+
+// ensureGatewayEgressAllowList copies the cluster's gateway egress allow list
+// to its gateway database record, from where the gateway picks it up via the
+// change feed.
+func (m *manager) ensureGatewayEgressAllowList(ctx context.Context) error {
+	linkID := m.doc.OpenShiftCluster.Properties.NetworkProfile.GatewayPrivateLinkID
+	if linkID == "" {
+		return nil
+	}
+
+	allowList := m.doc.OpenShiftCluster.Properties.NetworkProfile.GatewayEgressAllowList
+
+	gwyDoc, err := m.dbGateway.Get(ctx, linkID)
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(gwyDoc.Gateway.EgressAllowList, allowList) {
+		return nil
+	}
+
+	_, err = m.dbGateway.Patch(ctx, linkID, func(doc *api.GatewayDocument) error {
+		doc.Gateway.EgressAllowList = allowList
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,97 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestEnsureGatewayEgressAllowList(t *testing.T) {
+	ctx := context.Background()
+
+	allowList := []api.GatewayEgressRule{
+		{FQDN: "registry.example.com"},
+		{FQDN: "*.example.com", Ports: []int{443, 8443}},
+	}
+
+	for _, tt := range []struct {
+		name          string
+		linkID        string
+		allowList     []api.GatewayEgressRule
+		wantAllowList []api.GatewayEgressRule
+	}{
+		{
+			name:          "allow list is copied to the gateway record",
+			linkID:        "1234",
+			allowList:     allowList,
+			wantAllowList: allowList,
+		},
+		{
+			name:   "allow list is removed from the gateway record",
+			linkID: "1234",
+		},
+		{
+			name:      "cluster without a gateway is left alone",
+			allowList: allowList,
+			wantAllowList: []api.GatewayEgressRule{
+				{FQDN: "old.example.com"},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbGateway, gatewayClient := testdatabase.NewFakeGateway()
+
+			fixture := testdatabase.NewFixture().WithGateway(dbGateway)
+			fixture.AddGatewayDocuments(&api.GatewayDocument{
+				ID: "1234",
+				Gateway: &api.Gateway{
+					ID: "/subscriptions/subscriptionId/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName",
+					EgressAllowList: []api.GatewayEgressRule{
+						{FQDN: "old.example.com"},
+					},
+				},
+			})
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			m := &manager{
+				doc: &api.OpenShiftClusterDocument{
+					OpenShiftCluster: &api.OpenShiftCluster{
+						Properties: api.OpenShiftClusterProperties{
+							NetworkProfile: api.NetworkProfile{
+								GatewayPrivateLinkID:   tt.linkID,
+								GatewayEgressAllowList: tt.allowList,
+							},
+						},
+					},
+				},
+				dbGateway: dbGateway,
+			}
+
+			err = m.ensureGatewayEgressAllowList(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checker := testdatabase.NewChecker()
+			checker.AddGatewayDocuments(&api.GatewayDocument{
+				ID: "1234",
+				Gateway: &api.Gateway{
+					ID:              "/subscriptions/subscriptionId/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName",
+					EgressAllowList: tt.wantAllowList,
+				},
+			})
+
+			for _, err := range checker.CheckGateways(gatewayClient) {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -43,14 +43,23 @@ func (m *manager) AdminUpdate(ctx context.Context) error {
 }
 
 func (m *manager) adminUpdate() []steps.Step {
+	toRun, _ := m.adminUpdateSteps()
+	return toRun
+}
+
+// adminUpdateSteps returns the steps which an admin update runs for the
+// current maintenance task and cluster, and explains the conditional groups of
+// steps which it skips
+func (m *manager) adminUpdateSteps() (toRun []steps.Step, skipped []string) {
 	task := m.doc.OpenShiftCluster.Properties.MaintenanceTask
 	isEverything := task == api.MaintenanceTaskEverything || task == ""
 	isOperator := task == api.MaintenanceTaskOperator
 	isRenewCerts := task == api.MaintenanceTaskRenewCerts
+	isUpgrade := task == api.MaintenanceTaskUpgrade
 
 	// Generic fix-up or setup actions that are fairly safe to always take, and
 	// don't require a running cluster
-	toRun := []steps.Step{
+	toRun = []steps.Step{
 		steps.Action(m.initializeKubernetesClients), // must be first
 		steps.Action(m.ensureBillingRecord),         // belt and braces
 		steps.Action(m.ensureDefaults),
@@ -60,15 +69,16 @@ func (m *manager) adminUpdate() []steps.Step {
 		// to advance
 		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.fixupClusterSPObjectID),
 		steps.Action(m.fixInfraID), // Old clusters lacks infraID in the database. Which makes code prone to errors.
+		steps.Action(m.ensureGatewayEgressAllowList),
 	}
 
 	if isEverything {
 		toRun = append(toRun,
-			steps.Action(m.ensureResourceGroup), // re-create RP RBAC if needed after tenant migration
-			steps.Action(m.createOrUpdateDenyAssignment),
-			steps.Action(m.ensureServiceEndpoints),
+			steps.Skippable(steps.Action(m.ensureResourceGroup)), // re-create RP RBAC if needed after tenant migration
+			steps.Skippable(steps.Action(m.createOrUpdateDenyAssignment)),
+			steps.Skippable(steps.Action(m.ensureServiceEndpoints)),
 			steps.Action(m.populateRegistryStorageAccountName), // must go before migrateStorageAccounts
-			steps.Action(m.migrateStorageAccounts),
+			steps.Skippable(steps.Action(m.migrateStorageAccounts)),
 			steps.Action(m.fixSSH),
 			// steps.Action(m.removePrivateDNSZone), // TODO(mj): re-enable once we communicate this out
 		)
@@ -80,6 +90,15 @@ func (m *manager) adminUpdate() []steps.Step {
 		)
 	}
 
+	// Refuse upgrades which aren't allowed before touching the cluster.  The
+	// checks are skipped when an upgrade is resumed, as the cluster is then
+	// expected to be mid-upgrade.
+	if isUpgrade {
+		toRun = append(toRun,
+			steps.Skippable(steps.Action(m.validateUpgrade)),
+		)
+	}
+
 	// Make sure the VMs are switched on and we have an APIServer
 	toRun = append(toRun,
 		steps.Action(m.startVMs),
@@ -135,22 +154,43 @@ func (m *manager) adminUpdate() []steps.Step {
 	}
 
 	// Update the ARO Operator
-	if (isEverything || isOperator) && m.shouldUpdateOperator() {
+	if isEverything || isOperator {
+		if m.shouldUpdateOperator() {
+			toRun = append(toRun,
+				steps.Action(m.ensureAROOperator),
+				steps.Condition(m.aroDeploymentReady, 20*time.Minute, true),
+				steps.Condition(m.ensureAROOperatorRunningDesiredVersion, 5*time.Minute, true),
+			)
+		} else {
+			skipped = append(skipped, fmt.Sprintf("ARO operator update: cluster version %q is older than %s", m.doc.OpenShiftCluster.Properties.ClusterProfile.Version, operatorCutoffVersion))
+		}
+	}
+
+	if isUpgrade {
 		toRun = append(toRun,
-			steps.Action(m.ensureAROOperator),
-			steps.Condition(m.aroDeploymentReady, 20*time.Minute, true),
-			steps.Condition(m.ensureAROOperatorRunningDesiredVersion, 5*time.Minute, true),
+			steps.Skippable(steps.Action(m.upgradePreChecks)),
+			steps.Skippable(steps.Action(m.setDesiredUpdate)),
+			steps.Condition(m.clusterVersionUpgraded, 3*time.Hour, true),
+			steps.Condition(m.machineConfigPoolsUpdated, 2*time.Hour, true),
+			steps.Action(m.finishUpgrade),
 		)
 	}
 
 	// Hive cluster adoption and reconciliation
-	if isEverything && m.adoptViaHive && !m.clusterWasCreatedByHive() {
-		toRun = append(toRun,
-			steps.Action(m.hiveCreateNamespace),
-			steps.Action(m.hiveEnsureResources),
-			steps.Condition(m.hiveClusterDeploymentReady, 5*time.Minute, false),
-			steps.Action(m.hiveResetCorrelationData),
-		)
+	if isEverything {
+		switch {
+		case !m.adoptViaHive:
+			skipped = append(skipped, "Hive adoption: adoption by Hive is disabled")
+		case m.clusterWasCreatedByHive():
+			skipped = append(skipped, "Hive adoption: cluster was created by Hive")
+		default:
+			toRun = append(toRun,
+				steps.Action(m.hiveCreateNamespace),
+				steps.Action(m.hiveEnsureResources),
+				steps.Condition(m.hiveClusterDeploymentReady, 5*time.Minute, false),
+				steps.Action(m.hiveResetCorrelationData),
+			)
+		}
 	}
 
 	// We don't run this on an operator-only deploy as PUCM scripts then cannot
@@ -161,7 +201,7 @@ func (m *manager) adminUpdate() []steps.Step {
 		)
 	}
 
-	return toRun
+	return toRun, skipped
 }
 
 func (m *manager) shouldUpdateOperator() bool {
@@ -274,23 +314,34 @@ func (m *manager) bootstrap() []steps.Step {
 		steps.Action(m.ensureStorageSuffix),
 		steps.Action(m.populateMTUSize),
 
-		steps.Action(m.createDNS),
 		steps.Action(m.initializeClusterSPClients), // must run before clusterSPObjectID
 
 		// TODO: this relies on an authorizer that isn't exposed in the manager
 		// struct, so we'll rebuild the fpAuthorizer and use the error catching
 		// to advance
 		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.clusterSPObjectID),
-		steps.Action(m.ensureResourceGroup),
-		steps.Action(m.ensureServiceEndpoints),
-		steps.Action(m.setMasterSubnetPolicies),
-		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.deployBaseResourceTemplate),
-		steps.AuthorizationRetryingAction(m.fpAuthorizer, m.attachNSGs),
+
+		// Steps only run in parallel if they don't depend on each other and
+		// don't patch the cluster document, as m.doc is not safe for
+		// concurrent use.
+		steps.Skippable(steps.Parallel(
+			steps.Action(m.createDNS),
+			steps.Action(m.ensureResourceGroup),
+			steps.Action(m.ensureServiceEndpoints),
+		)),
+		// setMasterSubnetPolicies updates the master subnet, so must not race
+		// ensureServiceEndpoints, and deployBaseResourceTemplate depends on the
+		// service endpoints
+		steps.Skippable(steps.Action(m.setMasterSubnetPolicies)),
+		steps.Skippable(steps.AuthorizationRetryingAction(m.fpAuthorizer, m.deployBaseResourceTemplate)),
+		steps.Skippable(steps.Parallel(
+			steps.AuthorizationRetryingAction(m.fpAuthorizer, m.attachNSGs),
+			steps.Action(m.createCertificates),
+		)),
 		steps.Action(m.updateAPIIPEarly),
 		steps.Action(m.createOrUpdateRouterIPEarly),
 		steps.Action(m.ensureGatewayCreate),
-		steps.Action(m.createAPIServerPrivateEndpoint),
-		steps.Action(m.createCertificates),
+		steps.Skippable(steps.Action(m.createAPIServerPrivateEndpoint)),
 	}
 
 	if m.adoptViaHive || m.installViaHive {
@@ -301,16 +352,16 @@ func (m *manager) bootstrap() []steps.Step {
 
 	if m.installViaHive {
 		s = append(s,
-			steps.Action(m.runHiveInstaller),
+			steps.Skippable(steps.Action(m.runHiveInstaller)),
 			// Give Hive 60 minutes to install the cluster, since this includes
 			// all of bootstrapping being complete
-			steps.Condition(m.hiveClusterInstallationComplete, 60*time.Minute, true),
+			steps.Skippable(steps.Condition(m.hiveClusterInstallationComplete, 60*time.Minute, true)),
 			steps.Condition(m.hiveClusterDeploymentReady, 5*time.Minute, true),
 			steps.Action(m.generateKubeconfigs),
 		)
 	} else {
 		s = append(s,
-			steps.Action(m.runPodmanInstaller),
+			steps.Skippable(steps.Action(m.runPodmanInstaller)),
 			steps.Action(m.generateKubeconfigs),
 		)
 
@@ -348,8 +399,10 @@ func (m *manager) Install(ctx context.Context) error {
 		api.InstallPhaseRemoveBootstrap: {
 			steps.Action(m.initializeKubernetesClients),
 			steps.Action(m.initializeOperatorDeployer), // depends on kube clients
-			steps.Action(m.removeBootstrap),
-			steps.Action(m.removeBootstrapIgnition),
+			steps.Skippable(steps.Parallel(
+				steps.Action(m.removeBootstrap),
+				steps.Action(m.removeBootstrapIgnition),
+			)),
 			// Occasionally, the apiserver experiences disruptions, causing the certificate configuration step to fail.
 			// This issue is currently under investigation.
 			steps.Condition(m.apiServersReady, 30*time.Minute, true),
@@ -359,9 +412,11 @@ func (m *manager) Install(ctx context.Context) error {
 			steps.Condition(m.operatorConsoleExists, 30*time.Minute, true),
 			steps.Action(m.updateConsoleBranding),
 			steps.Condition(m.operatorConsoleReady, 20*time.Minute, true),
-			steps.Action(m.disableSamples),
-			steps.Action(m.disableOperatorHubSources),
-			steps.Action(m.disableUpdates),
+			steps.Parallel(
+				steps.Action(m.disableSamples),
+				steps.Action(m.disableOperatorHubSources),
+				steps.Action(m.disableUpdates),
+			),
 			steps.Condition(m.clusterVersionReady, 30*time.Minute, true),
 			steps.Condition(m.aroDeploymentReady, 20*time.Minute, true),
 			steps.Action(m.updateClusterData),
@@ -388,20 +443,22 @@ func (m *manager) runSteps(ctx context.Context, s []steps.Step, metricsTopic str
 	var err error
 	if metricsTopic != "" {
 		var stepsTimeRun map[string]int64
-		stepsTimeRun, err = steps.Run(ctx, m.log, 10*time.Second, s, m.now)
+		startTime := time.Now()
+		stepsTimeRun, err = steps.RunWithCheckpoints(ctx, m.log, 10*time.Second, s, m.now, &documentCheckpointer{m: m})
 		if err == nil {
-			var totalInstallTime int64
 			for stepName, duration := range stepsTimeRun {
 				metricName := fmt.Sprintf("backend.openshiftcluster.%s.%s.duration.seconds", metricsTopic, stepName)
 				m.metricsEmitter.EmitGauge(metricName, duration, nil)
-				totalInstallTime += duration
 			}
 
+			// steps in a parallel group overlap, so the total is the
+			// wall-clock time of the run rather than the sum of the steps
+			totalInstallTime := int64(m.now().Sub(startTime).Seconds())
 			metricName := fmt.Sprintf("backend.openshiftcluster.%s.duration.total.seconds", metricsTopic)
 			m.metricsEmitter.EmitGauge(metricName, totalInstallTime, nil)
 		}
 	} else {
-		_, err = steps.Run(ctx, m.log, 10*time.Second, s, nil)
+		_, err = steps.RunWithCheckpoints(ctx, m.log, 10*time.Second, s, nil, &documentCheckpointer{m: m})
 	}
 	if err != nil {
 		m.gatherFailureLogs(ctx)
@@ -428,6 +485,7 @@ func (m *manager) incrInstallPhase(ctx context.Context) error {
 	var err error
 	m.doc, err = m.db.PatchWithLease(ctx, m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
 		doc.OpenShiftCluster.Properties.Install.Phase++
+		doc.CompletedSteps = nil // checkpoints are only meaningful within a phase
 		return nil
 	})
 	return err
@@ -209,7 +209,7 @@ func TestUpdateProvisionedBy(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	clusterdoc, err := openShiftClustersDatabase.Dequeue(ctx)
+	clusterdoc, err := openShiftClustersDatabase.Dequeue(ctx, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -261,7 +261,7 @@ func TestInstallationTimeMetrics(t *testing.T) {
 				steps.Action(successfulActionStep),
 			},
 			wantedMetrics: map[string]int64{
-				"backend.openshiftcluster.install.duration.total.seconds":                             4,
+				"backend.openshiftcluster.install.duration.total.seconds":                             2,
 				"backend.openshiftcluster.install.action.successfulActionStep.duration.seconds":       2,
 				"backend.openshiftcluster.install.condition.successfulConditionStep.duration.seconds": 2,
 			},
@@ -276,11 +276,27 @@ func TestInstallationTimeMetrics(t *testing.T) {
 				steps.Action(successfulActionStep),
 			},
 			wantedMetrics: map[string]int64{
-				"backend.openshiftcluster.update.duration.total.seconds":                             6,
+				"backend.openshiftcluster.update.duration.total.seconds":                             3,
 				"backend.openshiftcluster.update.action.successfulActionStep.duration.seconds":       3,
 				"backend.openshiftcluster.update.condition.successfulConditionStep.duration.seconds": 3,
 			},
 		},
+		{
+			name:         "Steps run in parallel are not counted twice in the total install time",
+			metricsTopic: "install",
+			timePerStep:  2,
+			steps: []steps.Step{
+				steps.Parallel(
+					steps.Action(successfulActionStep),
+					steps.Condition(successfulConditionStep, 30*time.Minute, true),
+				),
+			},
+			wantedMetrics: map[string]int64{
+				"backend.openshiftcluster.install.duration.total.seconds":                             2,
+				"backend.openshiftcluster.install.action.successfulActionStep.duration.seconds":       2,
+				"backend.openshiftcluster.install.condition.successfulConditionStep.duration.seconds": 2,
+			},
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			ctx := context.Background()
@@ -334,7 +350,7 @@ func TestRunHiveInstallerSetsCreatedByHiveFieldToTrueInClusterDoc(t *testing.T)
 		t.Fatal(err)
 	}
 
-	dequeuedDoc, err := openShiftClustersDatabase.Dequeue(ctx)
+	dequeuedDoc, err := openShiftClustersDatabase.Dequeue(ctx, 0)
 	if err != nil {
 		t.Fatal(err)
 	}
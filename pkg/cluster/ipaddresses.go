@@ -255,6 +255,7 @@ func (m *manager) ensureGatewayCreate(ctx context.Context) error {
 			ID:                              m.doc.OpenShiftCluster.ID,
 			StorageSuffix:                   m.doc.OpenShiftCluster.Properties.StorageSuffix,
 			ImageRegistryStorageAccountName: m.doc.OpenShiftCluster.Properties.ImageRegistryStorageAccountName,
+			EgressAllowList:                 m.doc.OpenShiftCluster.Properties.NetworkProfile.GatewayEgressAllowList,
 		},
 	})
 
@@ -156,7 +156,7 @@ func TestCreateOrUpdateRouterIPFromCluster(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			doc, err := dbOpenShiftClusters.Dequeue(ctx)
+			doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -345,7 +345,7 @@ func TestCreateOrUpdateRouterIPEarly(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			doc, err := dbOpenShiftClusters.Dequeue(ctx)
+			doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -508,7 +508,7 @@ func TestPopulateDatabaseIntIP(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			doc, err := dbOpenShiftClusters.Dequeue(ctx)
+			doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -665,7 +665,7 @@ func TestUpdateAPIIPEarly(t *testing.T) {
 				t.Fatal(err)
 			}
 
-			doc, err := dbOpenShiftClusters.Dequeue(ctx)
+			doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
 			if err != nil {
 				t.Fatal(err)
 			}
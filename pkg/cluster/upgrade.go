@@ -0,0 +1,274 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/util/ready"
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+// Upgrade progress values reported in UpgradeProgress.  While the cluster
+// version operator is working towards the new version, the message of its
+// Progressing condition is reported instead.  Customers see the upgrade through
+// the cluster's MaintenanceState, which is ongoing until the upgrade has
+// completed.
+const (
+	upgradeProgressValidated        = "Validated"
+	upgradeProgressPreChecksPassed  = "PreChecksPassed"
+	upgradeProgressUpdateRequested  = "UpdateRequested"
+	upgradeProgressMachinesUpdating = "MachineConfigPoolsUpdating"
+	upgradeProgressCompleted        = "Completed"
+)
+
+// upgradeClusterOperatorConditionsIgnore contains ClusterOperator conditions
+// which do not block an upgrade even though they are not as expected
+var upgradeClusterOperatorConditionsIgnore = map[string]map[configv1.ClusterStatusConditionType]struct{}{
+	"insights": {"Disabled": {}},
+}
+
+// upgradeVersions returns the running and the requested cluster versions
+func (m *manager) upgradeVersions() (*version.Version, *version.Version, error) {
+	current, err := version.ParseVersion(m.doc.OpenShiftCluster.Properties.ClusterProfile.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	desired, err := version.ParseVersion(m.doc.OpenShiftCluster.Properties.UpgradeVersion)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return current, desired, nil
+}
+
+// upgradeOpenShiftVersion returns the enabled OpenShiftVersion matching the
+// requested upgrade version
+func (m *manager) upgradeOpenShiftVersion(ctx context.Context) (*api.OpenShiftVersion, error) {
+	docs, err := m.dbOpenShiftVersions.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, doc := range docs.OpenShiftVersionDocuments {
+		if doc.OpenShiftVersion.Properties.Enabled &&
+			doc.OpenShiftVersion.Properties.Version == m.doc.OpenShiftCluster.Properties.UpgradeVersion {
+			return doc.OpenShiftVersion, nil
+		}
+	}
+
+	return nil, fmt.Errorf("upgrade version %q is not an enabled OpenShift version", m.doc.OpenShiftCluster.Properties.UpgradeVersion)
+}
+
+// validateUpgrade refuses upgrades to versions which are not enabled, are not
+// newer than the running version, or which skip a minor version without the
+// upgrade graph allowing it
+func (m *manager) validateUpgrade(ctx context.Context) error {
+	current, desired, err := m.upgradeVersions()
+	if err != nil {
+		return err
+	}
+
+	if !current.Lt(desired) {
+		return fmt.Errorf("upgrade version %s is not newer than the running version %s", desired, current)
+	}
+
+	if current.V[0] != desired.V[0] {
+		return fmt.Errorf("upgrade from %s to %s changes the major version", current, desired)
+	}
+
+	_, err = m.upgradeOpenShiftVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if desired.V[1] > current.V[1]+1 {
+		g, err := m.upgradeGraph(ctx, m.env.UpgradeGraphURL(), fmt.Sprintf("stable-%d.%d", desired.V[0], desired.V[1]))
+		if err != nil {
+			return err
+		}
+
+		if !g.HasEdge(current, desired) {
+			return fmt.Errorf("upgrade from %s to %s skips a minor version and is not allowed by the upgrade graph", current, desired)
+		}
+	}
+
+	return m.setUpgradeProgress(ctx, upgradeProgressValidated)
+}
+
+// upgradePreChecks refuses to start an upgrade unless all ClusterOperators
+// and MachineConfigPools are healthy
+func (m *manager) upgradePreChecks(ctx context.Context) error {
+	cos, err := m.configcli.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	var unhealthy []string
+	for _, co := range cos.Items {
+		for _, c := range co.Status.Conditions {
+			if !upgradeClusterOperatorConditionIsExpected(&co, &c) {
+				unhealthy = append(unhealthy, fmt.Sprintf("clusteroperator/%s %s=%s", co.Name, c.Type, c.Status))
+			}
+		}
+	}
+
+	mcps, err := m.mcocli.MachineconfigurationV1().MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, mcp := range mcps.Items {
+		if !machineConfigPoolIsHealthy(&mcp) {
+			unhealthy = append(unhealthy, fmt.Sprintf("machineconfigpool/%s", mcp.Name))
+		}
+	}
+
+	if len(unhealthy) > 0 {
+		sort.Strings(unhealthy)
+		return fmt.Errorf("cluster is not healthy enough to upgrade: %s", strings.Join(unhealthy, ", "))
+	}
+
+	return m.setUpgradeProgress(ctx, upgradeProgressPreChecksPassed)
+}
+
+// setDesiredUpdate asks the cluster version operator to upgrade the cluster
+func (m *manager) setDesiredUpdate(ctx context.Context) error {
+	v, err := m.upgradeOpenShiftVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		cv, err := m.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+
+		if cv.Spec.DesiredUpdate != nil && cv.Spec.DesiredUpdate.Version == v.Properties.Version {
+			return nil
+		}
+
+		cv.Spec.DesiredUpdate = &configv1.Update{
+			Version: v.Properties.Version,
+			Image:   v.Properties.OpenShiftPullspec,
+		}
+
+		_, err = m.configcli.ConfigV1().ClusterVersions().Update(ctx, cv, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.setUpgradeProgress(ctx, upgradeProgressUpdateRequested)
+}
+
+// clusterVersionUpgraded returns true once the cluster version operator has
+// completed the upgrade, reporting its progress in the meantime
+func (m *manager) clusterVersionUpgraded(ctx context.Context) (bool, error) {
+	cv, err := m.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		return false, nil
+	}
+
+	for _, h := range cv.Status.History {
+		if h.Version == m.doc.OpenShiftCluster.Properties.UpgradeVersion &&
+			h.State == configv1.CompletedUpdate {
+			return true, m.setUpgradeProgress(ctx, upgradeProgressMachinesUpdating)
+		}
+	}
+
+	for _, c := range cv.Status.Conditions {
+		if c.Type == configv1.OperatorProgressing && c.Message != "" {
+			return false, m.setUpgradeProgress(ctx, c.Message)
+		}
+	}
+
+	return false, nil
+}
+
+// machineConfigPoolsUpdated returns true once all MachineConfigPools have
+// rolled out the configuration of the new version
+func (m *manager) machineConfigPoolsUpdated(ctx context.Context) (bool, error) {
+	mcps, err := m.mcocli.MachineconfigurationV1().MachineConfigPools().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, nil
+	}
+
+	for _, mcp := range mcps.Items {
+		if !machineConfigPoolIsHealthy(&mcp) {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+// finishUpgrade records the new cluster version
+func (m *manager) finishUpgrade(ctx context.Context) error {
+	var err error
+	m.doc, err = m.db.PatchWithLease(ctx, m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		doc.OpenShiftCluster.Properties.ClusterProfile.Version = doc.OpenShiftCluster.Properties.UpgradeVersion
+		doc.OpenShiftCluster.Properties.UpgradeProgress = upgradeProgressCompleted
+		return nil
+	})
+	return err
+}
+
+// setUpgradeProgress records the progress of the upgrade in the cluster
+// document when it changes, so that it is visible through the admin API and
+// to a backend which resumes the upgrade
+func (m *manager) setUpgradeProgress(ctx context.Context, progress string) error {
+	if m.doc.OpenShiftCluster.Properties.UpgradeProgress == progress {
+		return nil
+	}
+
+	m.log.Printf("upgrade progress: %s", progress)
+
+	var err error
+	m.doc, err = m.db.PatchWithLease(ctx, m.doc.Key, func(doc *api.OpenShiftClusterDocument) error {
+		doc.OpenShiftCluster.Properties.UpgradeProgress = progress
+		return nil
+	})
+	return err
+}
+
+func upgradeClusterOperatorConditionIsExpected(co *configv1.ClusterOperator, c *configv1.ClusterOperatorStatusCondition) bool {
+	if _, ok := upgradeClusterOperatorConditionsIgnore[co.Name][c.Type]; ok {
+		return true
+	}
+
+	switch c.Type {
+	case configv1.OperatorAvailable:
+		return c.Status == configv1.ConditionTrue
+	case configv1.OperatorDegraded, configv1.OperatorProgressing:
+		return c.Status == configv1.ConditionFalse
+	case configv1.OperatorUpgradeable:
+		return c.Status != configv1.ConditionFalse
+	}
+
+	return true
+}
+
+func machineConfigPoolIsHealthy(mcp *mcv1.MachineConfigPool) bool {
+	for _, c := range mcp.Status.Conditions {
+		if c.Type == mcv1.MachineConfigPoolDegraded && c.Status == corev1.ConditionTrue {
+			return false
+		}
+	}
+
+	return ready.MachineConfigPoolIsReady(mcp)
+}
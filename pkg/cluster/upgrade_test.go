@@ -0,0 +1,381 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	configv1 "github.com/openshift/api/config/v1"
+	configfake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	mcofake "github.com/openshift/machine-config-operator/pkg/generated/clientset/versioned/fake"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/mirror"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	"github.com/Azure/ARO-RP/test/util/deterministicuuid"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+const upgradeTestResourceID = "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/resourceGroup/providers/microsoft.redhatopenshift/openshiftclusters/resourceName"
+
+func newUpgradeTestManager(t *testing.T, currentVersion, upgradeVersion string) *manager {
+	ctx := context.Background()
+
+	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+	uuidGen := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.OPENSHIFT_VERSIONS)
+	dbOpenShiftVersions, _ := testdatabase.NewFakeOpenShiftVersions(uuidGen)
+
+	fixture := testdatabase.NewFixture().
+		WithOpenShiftClusters(dbOpenShiftClusters).
+		WithOpenShiftVersions(dbOpenShiftVersions, uuidGen)
+
+	fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+		Key: strings.ToLower(upgradeTestResourceID),
+		OpenShiftCluster: &api.OpenShiftCluster{
+			ID: upgradeTestResourceID,
+			Properties: api.OpenShiftClusterProperties{
+				ProvisioningState: api.ProvisioningStateAdminUpdating,
+				MaintenanceTask:   api.MaintenanceTaskUpgrade,
+				UpgradeVersion:    upgradeVersion,
+				ClusterProfile: api.ClusterProfile{
+					Version: currentVersion,
+				},
+			},
+		},
+	})
+
+	for _, v := range []struct {
+		version string
+		enabled bool
+	}{
+		{"4.11.44", true},
+		{"4.12.25", true},
+		{"4.12.26", false},
+		{"4.13.4", true},
+	} {
+		fixture.AddOpenShiftVersionDocuments(&api.OpenShiftVersionDocument{
+			OpenShiftVersion: &api.OpenShiftVersion{
+				Properties: api.OpenShiftVersionProperties{
+					Version:           v.version,
+					OpenShiftPullspec: "quay.io/openshift-release-dev/ocp-release@sha256:" + v.version,
+					Enabled:           v.enabled,
+				},
+			},
+		})
+	}
+
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := dbOpenShiftClusters.Dequeue(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := gomock.NewController(t)
+	_env := mock_env.NewMockInterface(controller)
+	_env.EXPECT().UpgradeGraphURL().AnyTimes().Return(mirror.DefaultUpgradeGraphURL)
+
+	return &manager{
+		log:                 logrus.NewEntry(logrus.StandardLogger()),
+		env:                 _env,
+		doc:                 doc,
+		db:                  dbOpenShiftClusters,
+		dbOpenShiftVersions: dbOpenShiftVersions,
+	}
+}
+
+// assertUpgradeProgress checks that the upgrade progress has been written to
+// the cluster document in the database
+func assertUpgradeProgress(ctx context.Context, t *testing.T, m *manager, want string) {
+	t.Helper()
+
+	doc, err := m.db.Get(ctx, strings.ToLower(upgradeTestResourceID))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc.OpenShiftCluster.Properties.UpgradeProgress != want {
+		t.Errorf("got upgrade progress %q, want %q", doc.OpenShiftCluster.Properties.UpgradeProgress, want)
+	}
+}
+
+func TestValidateUpgrade(t *testing.T) {
+	ctx := context.Background()
+
+	graph := &mirror.Graph{
+		Nodes: []mirror.Node{
+			{Version: "4.11.44"},
+			{Version: "4.13.4"},
+		},
+		Edges: [][2]int{{0, 1}},
+	}
+
+	for _, tt := range []struct {
+		name           string
+		currentVersion string
+		upgradeVersion string
+		graphErr       error
+		wantErr        string
+	}{
+		{
+			name:           "z-stream upgrade is allowed",
+			currentVersion: "4.12.20",
+			upgradeVersion: "4.12.25",
+			graphErr:       errors.New("graph should not be fetched"),
+		},
+		{
+			name:           "minor upgrade is allowed",
+			currentVersion: "4.11.44",
+			upgradeVersion: "4.12.25",
+			graphErr:       errors.New("graph should not be fetched"),
+		},
+		{
+			name:           "skip-minor upgrade in the graph is allowed",
+			currentVersion: "4.11.44",
+			upgradeVersion: "4.13.4",
+		},
+		{
+			name:           "skip-minor upgrade not in the graph is refused",
+			currentVersion: "4.11.43",
+			upgradeVersion: "4.13.4",
+			wantErr:        "upgrade from 4.11.43 to 4.13.4 skips a minor version and is not allowed by the upgrade graph",
+		},
+		{
+			name:           "skip-minor upgrade fails if the graph is unavailable",
+			currentVersion: "4.11.44",
+			upgradeVersion: "4.13.4",
+			graphErr:       errors.New("unexpected status code 503"),
+			wantErr:        "unexpected status code 503",
+		},
+		{
+			name:           "downgrade is refused",
+			currentVersion: "4.13.4",
+			upgradeVersion: "4.12.25",
+			wantErr:        "upgrade version 4.12.25 is not newer than the running version 4.13.4",
+		},
+		{
+			name:           "disabled version is refused",
+			currentVersion: "4.12.25",
+			upgradeVersion: "4.12.26",
+			wantErr:        `upgrade version "4.12.26" is not an enabled OpenShift version`,
+		},
+		{
+			name:           "unknown version is refused",
+			currentVersion: "4.12.25",
+			upgradeVersion: "4.12.99",
+			wantErr:        `upgrade version "4.12.99" is not an enabled OpenShift version`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newUpgradeTestManager(t, tt.currentVersion, tt.upgradeVersion)
+			m.upgradeGraph = func(ctx context.Context, url, channel string) (*mirror.Graph, error) {
+				if url != mirror.DefaultUpgradeGraphURL || channel != "stable-"+tt.upgradeVersion[:4] {
+					t.Errorf("unexpected graph %s, channel %s", url, channel)
+				}
+				if tt.graphErr != nil {
+					return nil, tt.graphErr
+				}
+				return graph, nil
+			}
+
+			err := m.validateUpgrade(ctx)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if err == nil {
+				assertUpgradeProgress(ctx, t, m, upgradeProgressValidated)
+			}
+		})
+	}
+}
+
+func TestUpgradePreChecks(t *testing.T) {
+	ctx := context.Background()
+
+	healthyOperator := func(name string) *configv1.ClusterOperator {
+		return &configv1.ClusterOperator{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: configv1.ClusterOperatorStatus{
+				Conditions: []configv1.ClusterOperatorStatusCondition{
+					{Type: configv1.OperatorAvailable, Status: configv1.ConditionTrue},
+					{Type: configv1.OperatorDegraded, Status: configv1.ConditionFalse},
+					{Type: configv1.OperatorProgressing, Status: configv1.ConditionFalse},
+					{Type: configv1.OperatorUpgradeable, Status: configv1.ConditionTrue},
+				},
+			},
+		}
+	}
+
+	healthyPool := func(name string) *mcv1.MachineConfigPool {
+		return &mcv1.MachineConfigPool{
+			ObjectMeta: metav1.ObjectMeta{Name: name},
+			Status: mcv1.MachineConfigPoolStatus{
+				MachineCount:        3,
+				UpdatedMachineCount: 3,
+				ReadyMachineCount:   3,
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name      string
+		operators func() []*configv1.ClusterOperator
+		pools     func() []*mcv1.MachineConfigPool
+		wantErr   string
+	}{
+		{
+			name: "healthy cluster",
+			operators: func() []*configv1.ClusterOperator {
+				insights := healthyOperator("insights")
+				insights.Status.Conditions = append(insights.Status.Conditions, configv1.ClusterOperatorStatusCondition{Type: "Disabled", Status: configv1.ConditionTrue})
+				return []*configv1.ClusterOperator{healthyOperator("console"), insights}
+			},
+			pools: func() []*mcv1.MachineConfigPool {
+				return []*mcv1.MachineConfigPool{healthyPool("master"), healthyPool("worker")}
+			},
+		},
+		{
+			name: "degraded and not upgradeable operators",
+			operators: func() []*configv1.ClusterOperator {
+				console := healthyOperator("console")
+				console.Status.Conditions[1].Status = configv1.ConditionTrue
+				dns := healthyOperator("dns")
+				dns.Status.Conditions[3].Status = configv1.ConditionFalse
+				return []*configv1.ClusterOperator{console, dns}
+			},
+			pools: func() []*mcv1.MachineConfigPool {
+				return []*mcv1.MachineConfigPool{healthyPool("master")}
+			},
+			wantErr: "cluster is not healthy enough to upgrade: clusteroperator/console Degraded=True, clusteroperator/dns Upgradeable=False",
+		},
+		{
+			name: "updating and degraded pools",
+			operators: func() []*configv1.ClusterOperator {
+				return []*configv1.ClusterOperator{healthyOperator("console")}
+			},
+			pools: func() []*mcv1.MachineConfigPool {
+				master := healthyPool("master")
+				master.Status.Conditions = []mcv1.MachineConfigPoolCondition{{Type: mcv1.MachineConfigPoolDegraded, Status: corev1.ConditionTrue}}
+				worker := healthyPool("worker")
+				worker.Status.UpdatedMachineCount = 1
+				return []*mcv1.MachineConfigPool{master, worker}
+			},
+			wantErr: "cluster is not healthy enough to upgrade: machineconfigpool/master, machineconfigpool/worker",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newUpgradeTestManager(t, "4.12.20", "4.12.25")
+
+			configcli := configfake.NewSimpleClientset()
+			for _, co := range tt.operators() {
+				_, err := configcli.ConfigV1().ClusterOperators().Create(ctx, co, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			m.configcli = configcli
+
+			mcocli := mcofake.NewSimpleClientset()
+			for _, mcp := range tt.pools() {
+				_, err := mcocli.MachineconfigurationV1().MachineConfigPools().Create(ctx, mcp, metav1.CreateOptions{})
+				if err != nil {
+					t.Fatal(err)
+				}
+			}
+			m.mcocli = mcocli
+
+			err := m.upgradePreChecks(ctx)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if err == nil {
+				assertUpgradeProgress(ctx, t, m, upgradeProgressPreChecksPassed)
+			}
+		})
+	}
+}
+
+func TestUpgradeClusterVersion(t *testing.T) {
+	ctx := context.Background()
+
+	m := newUpgradeTestManager(t, "4.12.20", "4.12.25")
+	m.configcli = configfake.NewSimpleClientset(&configv1.ClusterVersion{
+		ObjectMeta: metav1.ObjectMeta{Name: "version"},
+	})
+
+	err := m.setDesiredUpdate(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cv, err := m.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cv.Spec.DesiredUpdate == nil ||
+		cv.Spec.DesiredUpdate.Version != "4.12.25" ||
+		cv.Spec.DesiredUpdate.Image != "quay.io/openshift-release-dev/ocp-release@sha256:4.12.25" {
+		t.Fatal(cv.Spec.DesiredUpdate)
+	}
+	assertUpgradeProgress(ctx, t, m, upgradeProgressUpdateRequested)
+
+	cv.Status.Conditions = []configv1.ClusterOperatorStatusCondition{
+		{Type: configv1.OperatorProgressing, Status: configv1.ConditionTrue, Message: "Working towards 4.12.25: 512 of 827 done (61% complete)"},
+	}
+	cv, err = m.configcli.ConfigV1().ClusterVersions().Update(ctx, cv, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err := m.clusterVersionUpgraded(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if done {
+		t.Error("upgrade should still be in progress")
+	}
+	assertUpgradeProgress(ctx, t, m, "Working towards 4.12.25: 512 of 827 done (61% complete)")
+
+	cv.Status.History = []configv1.UpdateHistory{
+		{Version: "4.12.25", State: configv1.CompletedUpdate},
+		{Version: "4.12.20", State: configv1.CompletedUpdate},
+	}
+	_, err = m.configcli.ConfigV1().ClusterVersions().Update(ctx, cv, metav1.UpdateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done, err = m.clusterVersionUpgraded(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !done {
+		t.Error("upgrade should be complete")
+	}
+
+	err = m.finishUpgrade(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc, err := m.db.Get(ctx, strings.ToLower(upgradeTestResourceID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.OpenShiftCluster.Properties.ClusterProfile.Version != "4.12.25" {
+		t.Error(doc.OpenShiftCluster.Properties.ClusterProfile.Version)
+	}
+	assertUpgradeProgress(ctx, t, m, upgradeProgressCompleted)
+}
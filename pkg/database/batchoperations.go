@@ -0,0 +1,184 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	BatchOperationsDequeueQuery = `SELECT * FROM BatchOperations doc WHERE doc.batchOperation.state IN ("Pending", "Running") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
+)
+
+type BatchOperationDocumentMutator func(*api.BatchOperationDocument) error
+
+type batchOperations struct {
+	c             cosmosdb.BatchOperationDocumentClient
+	uuid          string
+	uuidGenerator uuid.Generator
+}
+
+// BatchOperations is the database interface for BatchOperationDocuments
+type BatchOperations interface {
+	Create(context.Context, *api.BatchOperationDocument) (*api.BatchOperationDocument, error)
+	Get(context.Context, string) (*api.BatchOperationDocument, error)
+	ListAll(context.Context) (*api.BatchOperationDocuments, error)
+	PatchWithLease(context.Context, string, BatchOperationDocumentMutator) (*api.BatchOperationDocument, error)
+	Dequeue(context.Context) (*api.BatchOperationDocument, error)
+	Lease(context.Context, string) (*api.BatchOperationDocument, error)
+	EndLease(context.Context, string) (*api.BatchOperationDocument, error)
+	NewUUID() string
+}
+
+// NewBatchOperations returns a new BatchOperations
+func NewBatchOperations(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (BatchOperations, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	triggers := []*cosmosdb.Trigger{
+		{
+			ID:               "renewLease",
+			TriggerOperation: cosmosdb.TriggerOperationAll,
+			TriggerType:      cosmosdb.TriggerTypePre,
+			Body: `function trigger() {
+	var request = getContext().getRequest();
+	var body = request.getBody();
+	var date = new Date();
+	body["leaseExpires"] = Math.floor(date.getTime() / 1000) + 60;
+	request.setBody(body);
+}`,
+		},
+	}
+
+	triggerc := cosmosdb.NewTriggerClient(collc, collBatchOperations)
+	for _, trigger := range triggers {
+		_, err := triggerc.Create(ctx, trigger)
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+			return nil, err
+		}
+	}
+
+	documentClient := cosmosdb.NewBatchOperationDocumentClient(collc, collBatchOperations)
+	return NewBatchOperationsWithProvidedClient(documentClient, uuid.DefaultGenerator.Generate(), uuid.DefaultGenerator), nil
+}
+
+func NewBatchOperationsWithProvidedClient(client cosmosdb.BatchOperationDocumentClient, uuid string, uuidGenerator uuid.Generator) BatchOperations {
+	return &batchOperations{
+		c:             client,
+		uuid:          uuid,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *batchOperations) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *batchOperations) Create(ctx context.Context, doc *api.BatchOperationDocument) (*api.BatchOperationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Create(ctx, doc.ID, doc, nil)
+}
+
+func (c *batchOperations) Get(ctx context.Context, id string) (*api.BatchOperationDocument, error) {
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, id, id, nil)
+}
+
+func (c *batchOperations) ListAll(ctx context.Context) (*api.BatchOperationDocuments, error) {
+	return c.c.ListAll(ctx, nil)
+}
+
+func (c *batchOperations) patch(ctx context.Context, id string, f BatchOperationDocumentMutator, options *cosmosdb.Options) (*api.BatchOperationDocument, error) {
+	var doc *api.BatchOperationDocument
+
+	err := cosmosdb.RetryOnPreconditionFailed(func() (err error) {
+		doc, err = c.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		err = f(doc)
+		if err != nil {
+			return
+		}
+
+		doc, err = c.update(ctx, doc, options)
+		return
+	})
+
+	return doc, err
+}
+
+func (c *batchOperations) PatchWithLease(ctx context.Context, id string, f BatchOperationDocumentMutator) (*api.BatchOperationDocument, error) {
+	return c.patchWithLease(ctx, id, f, nil)
+}
+
+func (c *batchOperations) patchWithLease(ctx context.Context, id string, f BatchOperationDocumentMutator, options *cosmosdb.Options) (*api.BatchOperationDocument, error) {
+	return c.patch(ctx, id, func(doc *api.BatchOperationDocument) error {
+		if doc.LeaseOwner != c.uuid {
+			return fmt.Errorf("lost lease")
+		}
+
+		return f(doc)
+	}, options)
+}
+
+func (c *batchOperations) update(ctx context.Context, doc *api.BatchOperationDocument, options *cosmosdb.Options) (*api.BatchOperationDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Replace(ctx, doc.ID, doc, options)
+}
+
+func (c *batchOperations) Dequeue(ctx context.Context) (*api.BatchOperationDocument, error) {
+	i := c.c.Query("", &cosmosdb.Query{
+		Query: BatchOperationsDequeueQuery,
+	}, nil)
+
+	for {
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if docs == nil {
+			return nil, nil
+		}
+
+		for _, doc := range docs.BatchOperationDocuments {
+			doc.LeaseOwner = c.uuid
+			doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+			if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
+				continue
+			}
+			return doc, err
+		}
+	}
+}
+
+func (c *batchOperations) Lease(ctx context.Context, id string) (*api.BatchOperationDocument, error) {
+	return c.patchWithLease(ctx, id, func(doc *api.BatchOperationDocument) error {
+		return nil
+	}, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+}
+
+func (c *batchOperations) EndLease(ctx context.Context, id string) (*api.BatchOperationDocument, error) {
+	return c.patchWithLease(ctx, id, func(doc *api.BatchOperationDocument) error {
+		doc.LeaseOwner = ""
+		doc.LeaseExpires = 0
+		return nil
+	}, nil)
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 )
 
+type BillingDocumentMutator func(*api.BillingDocument) error
+
 type billing struct {
 	c cosmosdb.BillingDocumentClient
 }
@@ -22,6 +24,7 @@ type Billing interface {
 	Create(context.Context, *api.BillingDocument) (*api.BillingDocument, error)
 	Get(context.Context, string) (*api.BillingDocument, error)
 	MarkForDeletion(context.Context, string) (*api.BillingDocument, error)
+	Patch(context.Context, string, BillingDocumentMutator) (*api.BillingDocument, error)
 	UpdateLastBillingTimestamp(context.Context, string, int) (*api.BillingDocument, error)
 	List(string) cosmosdb.BillingDocumentIterator
 	ListAll(context.Context) (*api.BillingDocuments, error)
@@ -123,6 +126,12 @@ func (c *billing) patch(ctx context.Context, id string, f func(*api.BillingDocum
 	return doc, err
 }
 
+// Patch updates the billing document with the given ID, retrying if it is
+// concurrently modified
+func (c *billing) Patch(ctx context.Context, id string, f BillingDocumentMutator) (*api.BillingDocument, error) {
+	return c.patch(ctx, id, f, nil)
+}
+
 // MarkForDeletion update the deletion timestamp field in the document
 func (c *billing) MarkForDeletion(ctx context.Context, id string) (*api.BillingDocument, error) {
 	return c.patch(ctx, id, func(billingdoc *api.BillingDocument) error {
@@ -0,0 +1,66 @@
+package cosmosdb
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// PermissionTokenClient extends PermissionClient with the Cosmos DB features
+// needed to hand out least-privilege resource tokens, which the generated
+// client does not expose: scoping a permission to a single partition key and
+// choosing the lifetime of the issued token.
+type PermissionTokenClient interface {
+	PermissionClient
+	Upsert(ctx context.Context, permission *Permission, partitionKey string) (*Permission, error)
+	GetToken(ctx context.Context, permissionid string, lifetime time.Duration) (*Permission, error)
+}
+
+// scopedPermission is a Permission restricted to a partition key
+type scopedPermission struct {
+	ID                   string         `json:"id,omitempty"`
+	PermissionMode       PermissionMode `json:"permissionMode,omitempty"`
+	Resource             string         `json:"resource,omitempty"`
+	ResourcePartitionKey []string       `json:"resourcePartitionKey,omitempty"`
+}
+
+// NewPermissionTokenClient returns a new permission token client
+func NewPermissionTokenClient(userc UserClient, userid string) PermissionTokenClient {
+	return NewPermissionClient(userc, userid).(*permissionClient)
+}
+
+// Upsert creates the permission, or replaces it if it already exists.  If
+// partitionKey is not empty, the permission only grants access to documents
+// in that partition.
+func (c *permissionClient) Upsert(ctx context.Context, newpermission *Permission, partitionKey string) (permission *Permission, err error) {
+	in := &scopedPermission{
+		ID:             newpermission.ID,
+		PermissionMode: newpermission.PermissionMode,
+		Resource:       newpermission.Resource,
+	}
+	if partitionKey != "" {
+		in.ResourcePartitionKey = []string{partitionKey}
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/permissions", "permissions", c.path, http.StatusCreated, in, &permission, nil)
+	if !IsErrorStatusCode(err, http.StatusConflict) {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/permissions/"+in.ID, "permissions", c.path+"/permissions/"+in.ID, http.StatusOK, in, &permission, nil)
+	return
+}
+
+// GetToken returns the permission with a resource token valid for lifetime.
+// Cosmos DB rounds lifetime to seconds and caps it at five hours.
+func (c *permissionClient) GetToken(ctx context.Context, permissionid string, lifetime time.Duration) (permission *Permission, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Expiry-Seconds", strconv.Itoa(int(lifetime.Seconds())))
+
+	err = c.do(ctx, http.MethodGet, c.path+"/permissions/"+permissionid, "permissions", c.path+"/permissions/"+permissionid, http.StatusOK, nil, &permission, headers)
+	return
+}
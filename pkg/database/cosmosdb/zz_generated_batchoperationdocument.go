@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type batchOperationDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// BatchOperationDocumentClient is a batchOperationDocument client
+type BatchOperationDocumentClient interface {
+	Create(context.Context, string, *pkg.BatchOperationDocument, *Options) (*pkg.BatchOperationDocument, error)
+	List(*Options) BatchOperationDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.BatchOperationDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.BatchOperationDocument, error)
+	Replace(context.Context, string, *pkg.BatchOperationDocument, *Options) (*pkg.BatchOperationDocument, error)
+	Delete(context.Context, string, *pkg.BatchOperationDocument, *Options) error
+	Query(string, *Query, *Options) BatchOperationDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.BatchOperationDocuments, error)
+	ChangeFeed(*Options) BatchOperationDocumentIterator
+}
+
+type batchOperationDocumentChangeFeedIterator struct {
+	*batchOperationDocumentClient
+	continuation string
+	options      *Options
+}
+
+type batchOperationDocumentListIterator struct {
+	*batchOperationDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type batchOperationDocumentQueryIterator struct {
+	*batchOperationDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// BatchOperationDocumentIterator is a batchOperationDocument iterator
+type BatchOperationDocumentIterator interface {
+	Next(context.Context, int) (*pkg.BatchOperationDocuments, error)
+	Continuation() string
+}
+
+// BatchOperationDocumentRawIterator is a batchOperationDocument raw iterator
+type BatchOperationDocumentRawIterator interface {
+	BatchOperationDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewBatchOperationDocumentClient returns a new batchOperationDocument client
+func NewBatchOperationDocumentClient(collc CollectionClient, collid string) BatchOperationDocumentClient {
+	return &batchOperationDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *batchOperationDocumentClient) all(ctx context.Context, i BatchOperationDocumentIterator) (*pkg.BatchOperationDocuments, error) {
+	allbatchOperationDocuments := &pkg.BatchOperationDocuments{}
+
+	for {
+		batchOperationDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if batchOperationDocuments == nil {
+			break
+		}
+
+		allbatchOperationDocuments.Count += batchOperationDocuments.Count
+		allbatchOperationDocuments.ResourceID = batchOperationDocuments.ResourceID
+		allbatchOperationDocuments.BatchOperationDocuments = append(allbatchOperationDocuments.BatchOperationDocuments, batchOperationDocuments.BatchOperationDocuments...)
+	}
+
+	return allbatchOperationDocuments, nil
+}
+
+func (c *batchOperationDocumentClient) Create(ctx context.Context, partitionkey string, newbatchOperationDocument *pkg.BatchOperationDocument, options *Options) (batchOperationDocument *pkg.BatchOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newbatchOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newbatchOperationDocument, &batchOperationDocument, headers)
+	return
+}
+
+func (c *batchOperationDocumentClient) List(options *Options) BatchOperationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &batchOperationDocumentListIterator{batchOperationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *batchOperationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.BatchOperationDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *batchOperationDocumentClient) Get(ctx context.Context, partitionkey, batchOperationDocumentid string, options *Options) (batchOperationDocument *pkg.BatchOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+batchOperationDocumentid, "docs", c.path+"/docs/"+batchOperationDocumentid, http.StatusOK, nil, &batchOperationDocument, headers)
+	return
+}
+
+func (c *batchOperationDocumentClient) Replace(ctx context.Context, partitionkey string, newbatchOperationDocument *pkg.BatchOperationDocument, options *Options) (batchOperationDocument *pkg.BatchOperationDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newbatchOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newbatchOperationDocument.ID, "docs", c.path+"/docs/"+newbatchOperationDocument.ID, http.StatusOK, &newbatchOperationDocument, &batchOperationDocument, headers)
+	return
+}
+
+func (c *batchOperationDocumentClient) Delete(ctx context.Context, partitionkey string, batchOperationDocument *pkg.BatchOperationDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, batchOperationDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+batchOperationDocument.ID, "docs", c.path+"/docs/"+batchOperationDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *batchOperationDocumentClient) Query(partitionkey string, query *Query, options *Options) BatchOperationDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &batchOperationDocumentQueryIterator{batchOperationDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *batchOperationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.BatchOperationDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *batchOperationDocumentClient) ChangeFeed(options *Options) BatchOperationDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &batchOperationDocumentChangeFeedIterator{batchOperationDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *batchOperationDocumentClient) setOptions(options *Options, batchOperationDocument *pkg.BatchOperationDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if batchOperationDocument != nil && !options.NoETag {
+		if batchOperationDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", batchOperationDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *batchOperationDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (batchOperationDocuments *pkg.BatchOperationDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &batchOperationDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *batchOperationDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *batchOperationDocumentListIterator) Next(ctx context.Context, maxItemCount int) (batchOperationDocuments *pkg.BatchOperationDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &batchOperationDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *batchOperationDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *batchOperationDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (batchOperationDocuments *pkg.BatchOperationDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &batchOperationDocuments)
+	return
+}
+
+func (i *batchOperationDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *batchOperationDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakeBatchOperationDocumentTriggerHandler func(context.Context, *pkg.BatchOperationDocument) error
+type fakeBatchOperationDocumentQueryHandler func(BatchOperationDocumentClient, *Query, *Options) BatchOperationDocumentRawIterator
+
+var _ BatchOperationDocumentClient = &FakeBatchOperationDocumentClient{}
+
+// NewFakeBatchOperationDocumentClient returns a FakeBatchOperationDocumentClient
+func NewFakeBatchOperationDocumentClient(h *codec.JsonHandle) *FakeBatchOperationDocumentClient {
+	return &FakeBatchOperationDocumentClient{
+		jsonHandle:              h,
+		batchOperationDocuments: make(map[string]*pkg.BatchOperationDocument),
+		triggerHandlers:         make(map[string]fakeBatchOperationDocumentTriggerHandler),
+		queryHandlers:           make(map[string]fakeBatchOperationDocumentQueryHandler),
+	}
+}
+
+// FakeBatchOperationDocumentClient is a FakeBatchOperationDocumentClient
+type FakeBatchOperationDocumentClient struct {
+	lock                    sync.RWMutex
+	jsonHandle              *codec.JsonHandle
+	batchOperationDocuments map[string]*pkg.BatchOperationDocument
+	triggerHandlers         map[string]fakeBatchOperationDocumentTriggerHandler
+	queryHandlers           map[string]fakeBatchOperationDocumentQueryHandler
+	sorter                  func([]*pkg.BatchOperationDocument)
+	etag                    int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.BatchOperationDocument, *pkg.BatchOperationDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakeBatchOperationDocumentClient method invocation
+func (c *FakeBatchOperationDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakeBatchOperationDocumentClient) SetSorter(sorter func([]*pkg.BatchOperationDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a BatchOperationDocument
+func (c *FakeBatchOperationDocumentClient) SetConflictChecker(conflictChecker func(*pkg.BatchOperationDocument, *pkg.BatchOperationDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakeBatchOperationDocumentClient) SetTriggerHandler(triggerName string, trigger fakeBatchOperationDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakeBatchOperationDocumentClient) SetQueryHandler(queryName string, query fakeBatchOperationDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakeBatchOperationDocumentClient) deepCopy(batchOperationDocument *pkg.BatchOperationDocument) (*pkg.BatchOperationDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(batchOperationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	batchOperationDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&batchOperationDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return batchOperationDocument, nil
+}
+
+func (c *FakeBatchOperationDocumentClient) apply(ctx context.Context, partitionkey string, batchOperationDocument *pkg.BatchOperationDocument, options *Options, isCreate bool) (*pkg.BatchOperationDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	batchOperationDocument, err := c.deepCopy(batchOperationDocument) // copy now because pretriggers can mutate batchOperationDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, batchOperationDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingBatchOperationDocument, exists := c.batchOperationDocuments[batchOperationDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if batchOperationDocument.ETag != existingBatchOperationDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, batchOperationDocumentToCheck := range c.batchOperationDocuments {
+			if c.conflictChecker(batchOperationDocumentToCheck, batchOperationDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	batchOperationDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.batchOperationDocuments[batchOperationDocument.ID] = batchOperationDocument
+
+	return c.deepCopy(batchOperationDocument)
+}
+
+// Create creates a BatchOperationDocument in the database
+func (c *FakeBatchOperationDocumentClient) Create(ctx context.Context, partitionkey string, batchOperationDocument *pkg.BatchOperationDocument, options *Options) (*pkg.BatchOperationDocument, error) {
+	return c.apply(ctx, partitionkey, batchOperationDocument, options, true)
+}
+
+// Replace replaces a BatchOperationDocument in the database
+func (c *FakeBatchOperationDocumentClient) Replace(ctx context.Context, partitionkey string, batchOperationDocument *pkg.BatchOperationDocument, options *Options) (*pkg.BatchOperationDocument, error) {
+	return c.apply(ctx, partitionkey, batchOperationDocument, options, false)
+}
+
+// List returns a BatchOperationDocumentIterator to list all BatchOperationDocuments in the database
+func (c *FakeBatchOperationDocumentClient) List(*Options) BatchOperationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeBatchOperationDocumentErroringRawIterator(c.err)
+	}
+
+	batchOperationDocuments := make([]*pkg.BatchOperationDocument, 0, len(c.batchOperationDocuments))
+	for _, batchOperationDocument := range c.batchOperationDocuments {
+		batchOperationDocument, err := c.deepCopy(batchOperationDocument)
+		if err != nil {
+			return NewFakeBatchOperationDocumentErroringRawIterator(err)
+		}
+		batchOperationDocuments = append(batchOperationDocuments, batchOperationDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(batchOperationDocuments)
+	}
+
+	return NewFakeBatchOperationDocumentIterator(batchOperationDocuments, 0)
+}
+
+// ListAll lists all BatchOperationDocuments in the database
+func (c *FakeBatchOperationDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.BatchOperationDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a BatchOperationDocument from the database
+func (c *FakeBatchOperationDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.BatchOperationDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	batchOperationDocument, exists := c.batchOperationDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(batchOperationDocument)
+}
+
+// Delete deletes a BatchOperationDocument from the database
+func (c *FakeBatchOperationDocumentClient) Delete(ctx context.Context, partitionKey string, batchOperationDocument *pkg.BatchOperationDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.batchOperationDocuments[batchOperationDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.batchOperationDocuments, batchOperationDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakeBatchOperationDocumentClient) ChangeFeed(*Options) BatchOperationDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeBatchOperationDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakeBatchOperationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakeBatchOperationDocumentClient) processPreTriggers(ctx context.Context, batchOperationDocument *pkg.BatchOperationDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, batchOperationDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakeBatchOperationDocumentClient) Query(name string, query *Query, options *Options) BatchOperationDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakeBatchOperationDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakeBatchOperationDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakeBatchOperationDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.BatchOperationDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakeBatchOperationDocumentIterator(batchOperationDocuments []*pkg.BatchOperationDocument, continuation int) BatchOperationDocumentRawIterator {
+	return &fakeBatchOperationDocumentIterator{batchOperationDocuments: batchOperationDocuments, continuation: continuation}
+}
+
+type fakeBatchOperationDocumentIterator struct {
+	batchOperationDocuments []*pkg.BatchOperationDocument
+	continuation            int
+	done                    bool
+}
+
+func (i *fakeBatchOperationDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakeBatchOperationDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.BatchOperationDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var batchOperationDocuments []*pkg.BatchOperationDocument
+	if maxItemCount == -1 {
+		batchOperationDocuments = i.batchOperationDocuments[i.continuation:]
+		i.continuation = len(i.batchOperationDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.batchOperationDocuments) {
+			max = len(i.batchOperationDocuments)
+		}
+		batchOperationDocuments = i.batchOperationDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.BatchOperationDocuments{
+		BatchOperationDocuments: batchOperationDocuments,
+		Count:                   len(batchOperationDocuments),
+	}, nil
+}
+
+func (i *fakeBatchOperationDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.batchOperationDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakeBatchOperationDocumentErroringRawIterator returns a BatchOperationDocumentRawIterator which
+// whose methods return the given error
+func NewFakeBatchOperationDocumentErroringRawIterator(err error) BatchOperationDocumentRawIterator {
+	return &fakeBatchOperationDocumentErroringRawIterator{err: err}
+}
+
+type fakeBatchOperationDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakeBatchOperationDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.BatchOperationDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakeBatchOperationDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakeBatchOperationDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
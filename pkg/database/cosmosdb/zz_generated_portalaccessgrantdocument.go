@@ -0,0 +1,313 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type portalAccessGrantDocumentClient struct {
+	*databaseClient
+	path string
+}
+
+// PortalAccessGrantDocumentClient is a portalAccessGrantDocument client
+type PortalAccessGrantDocumentClient interface {
+	Create(context.Context, string, *pkg.PortalAccessGrantDocument, *Options) (*pkg.PortalAccessGrantDocument, error)
+	List(*Options) PortalAccessGrantDocumentIterator
+	ListAll(context.Context, *Options) (*pkg.PortalAccessGrantDocuments, error)
+	Get(context.Context, string, string, *Options) (*pkg.PortalAccessGrantDocument, error)
+	Replace(context.Context, string, *pkg.PortalAccessGrantDocument, *Options) (*pkg.PortalAccessGrantDocument, error)
+	Delete(context.Context, string, *pkg.PortalAccessGrantDocument, *Options) error
+	Query(string, *Query, *Options) PortalAccessGrantDocumentRawIterator
+	QueryAll(context.Context, string, *Query, *Options) (*pkg.PortalAccessGrantDocuments, error)
+	ChangeFeed(*Options) PortalAccessGrantDocumentIterator
+}
+
+type portalAccessGrantDocumentChangeFeedIterator struct {
+	*portalAccessGrantDocumentClient
+	continuation string
+	options      *Options
+}
+
+type portalAccessGrantDocumentListIterator struct {
+	*portalAccessGrantDocumentClient
+	continuation string
+	done         bool
+	options      *Options
+}
+
+type portalAccessGrantDocumentQueryIterator struct {
+	*portalAccessGrantDocumentClient
+	partitionkey string
+	query        *Query
+	continuation string
+	done         bool
+	options      *Options
+}
+
+// PortalAccessGrantDocumentIterator is a portalAccessGrantDocument iterator
+type PortalAccessGrantDocumentIterator interface {
+	Next(context.Context, int) (*pkg.PortalAccessGrantDocuments, error)
+	Continuation() string
+}
+
+// PortalAccessGrantDocumentRawIterator is a portalAccessGrantDocument raw iterator
+type PortalAccessGrantDocumentRawIterator interface {
+	PortalAccessGrantDocumentIterator
+	NextRaw(context.Context, int, interface{}) error
+}
+
+// NewPortalAccessGrantDocumentClient returns a new portalAccessGrantDocument client
+func NewPortalAccessGrantDocumentClient(collc CollectionClient, collid string) PortalAccessGrantDocumentClient {
+	return &portalAccessGrantDocumentClient{
+		databaseClient: collc.(*collectionClient).databaseClient,
+		path:           collc.(*collectionClient).path + "/colls/" + collid,
+	}
+}
+
+func (c *portalAccessGrantDocumentClient) all(ctx context.Context, i PortalAccessGrantDocumentIterator) (*pkg.PortalAccessGrantDocuments, error) {
+	allportalAccessGrantDocuments := &pkg.PortalAccessGrantDocuments{}
+
+	for {
+		portalAccessGrantDocuments, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if portalAccessGrantDocuments == nil {
+			break
+		}
+
+		allportalAccessGrantDocuments.Count += portalAccessGrantDocuments.Count
+		allportalAccessGrantDocuments.ResourceID = portalAccessGrantDocuments.ResourceID
+		allportalAccessGrantDocuments.PortalAccessGrantDocuments = append(allportalAccessGrantDocuments.PortalAccessGrantDocuments, portalAccessGrantDocuments.PortalAccessGrantDocuments...)
+	}
+
+	return allportalAccessGrantDocuments, nil
+}
+
+func (c *portalAccessGrantDocumentClient) Create(ctx context.Context, partitionkey string, newportalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) (portalAccessGrantDocument *pkg.PortalAccessGrantDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	if options == nil {
+		options = &Options{}
+	}
+	options.NoETag = true
+
+	err = c.setOptions(options, newportalAccessGrantDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPost, c.path+"/docs", "docs", c.path, http.StatusCreated, &newportalAccessGrantDocument, &portalAccessGrantDocument, headers)
+	return
+}
+
+func (c *portalAccessGrantDocumentClient) List(options *Options) PortalAccessGrantDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &portalAccessGrantDocumentListIterator{portalAccessGrantDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *portalAccessGrantDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.PortalAccessGrantDocuments, error) {
+	return c.all(ctx, c.List(options))
+}
+
+func (c *portalAccessGrantDocumentClient) Get(ctx context.Context, partitionkey, portalAccessGrantDocumentid string, options *Options) (portalAccessGrantDocument *pkg.PortalAccessGrantDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodGet, c.path+"/docs/"+portalAccessGrantDocumentid, "docs", c.path+"/docs/"+portalAccessGrantDocumentid, http.StatusOK, nil, &portalAccessGrantDocument, headers)
+	return
+}
+
+func (c *portalAccessGrantDocumentClient) Replace(ctx context.Context, partitionkey string, newportalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) (portalAccessGrantDocument *pkg.PortalAccessGrantDocument, err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, newportalAccessGrantDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodPut, c.path+"/docs/"+newportalAccessGrantDocument.ID, "docs", c.path+"/docs/"+newportalAccessGrantDocument.ID, http.StatusOK, &newportalAccessGrantDocument, &portalAccessGrantDocument, headers)
+	return
+}
+
+func (c *portalAccessGrantDocumentClient) Delete(ctx context.Context, partitionkey string, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) (err error) {
+	headers := http.Header{}
+	headers.Set("X-Ms-Documentdb-Partitionkey", `["`+partitionkey+`"]`)
+
+	err = c.setOptions(options, portalAccessGrantDocument, headers)
+	if err != nil {
+		return
+	}
+
+	err = c.do(ctx, http.MethodDelete, c.path+"/docs/"+portalAccessGrantDocument.ID, "docs", c.path+"/docs/"+portalAccessGrantDocument.ID, http.StatusNoContent, nil, nil, headers)
+	return
+}
+
+func (c *portalAccessGrantDocumentClient) Query(partitionkey string, query *Query, options *Options) PortalAccessGrantDocumentRawIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &portalAccessGrantDocumentQueryIterator{portalAccessGrantDocumentClient: c, partitionkey: partitionkey, query: query, options: options, continuation: continuation}
+}
+
+func (c *portalAccessGrantDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.PortalAccessGrantDocuments, error) {
+	return c.all(ctx, c.Query(partitionkey, query, options))
+}
+
+func (c *portalAccessGrantDocumentClient) ChangeFeed(options *Options) PortalAccessGrantDocumentIterator {
+	continuation := ""
+	if options != nil {
+		continuation = options.Continuation
+	}
+
+	return &portalAccessGrantDocumentChangeFeedIterator{portalAccessGrantDocumentClient: c, options: options, continuation: continuation}
+}
+
+func (c *portalAccessGrantDocumentClient) setOptions(options *Options, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, headers http.Header) error {
+	if options == nil {
+		return nil
+	}
+
+	if portalAccessGrantDocument != nil && !options.NoETag {
+		if portalAccessGrantDocument.ETag == "" {
+			return ErrETagRequired
+		}
+		headers.Set("If-Match", portalAccessGrantDocument.ETag)
+	}
+	if len(options.PreTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Pre-Trigger-Include", strings.Join(options.PreTriggers, ","))
+	}
+	if len(options.PostTriggers) > 0 {
+		headers.Set("X-Ms-Documentdb-Post-Trigger-Include", strings.Join(options.PostTriggers, ","))
+	}
+	if len(options.PartitionKeyRangeID) > 0 {
+		headers.Set("X-Ms-Documentdb-PartitionKeyRangeID", options.PartitionKeyRangeID)
+	}
+
+	return nil
+}
+
+func (i *portalAccessGrantDocumentChangeFeedIterator) Next(ctx context.Context, maxItemCount int) (portalAccessGrantDocuments *pkg.PortalAccessGrantDocuments, err error) {
+	headers := http.Header{}
+	headers.Set("A-IM", "Incremental feed")
+
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("If-None-Match", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &portalAccessGrantDocuments, headers)
+	if IsErrorStatusCode(err, http.StatusNotModified) {
+		err = nil
+	}
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("Etag")
+
+	return
+}
+
+func (i *portalAccessGrantDocumentChangeFeedIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *portalAccessGrantDocumentListIterator) Next(ctx context.Context, maxItemCount int) (portalAccessGrantDocuments *pkg.PortalAccessGrantDocuments, err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodGet, i.path+"/docs", "docs", i.path, http.StatusOK, nil, &portalAccessGrantDocuments, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *portalAccessGrantDocumentListIterator) Continuation() string {
+	return i.continuation
+}
+
+func (i *portalAccessGrantDocumentQueryIterator) Next(ctx context.Context, maxItemCount int) (portalAccessGrantDocuments *pkg.PortalAccessGrantDocuments, err error) {
+	err = i.NextRaw(ctx, maxItemCount, &portalAccessGrantDocuments)
+	return
+}
+
+func (i *portalAccessGrantDocumentQueryIterator) NextRaw(ctx context.Context, maxItemCount int, raw interface{}) (err error) {
+	if i.done {
+		return
+	}
+
+	headers := http.Header{}
+	headers.Set("X-Ms-Max-Item-Count", strconv.Itoa(maxItemCount))
+	headers.Set("X-Ms-Documentdb-Isquery", "True")
+	headers.Set("Content-Type", "application/query+json")
+	if i.partitionkey != "" {
+		headers.Set("X-Ms-Documentdb-Partitionkey", `["`+i.partitionkey+`"]`)
+	} else {
+		headers.Set("X-Ms-Documentdb-Query-Enablecrosspartition", "True")
+	}
+	if i.continuation != "" {
+		headers.Set("X-Ms-Continuation", i.continuation)
+	}
+
+	err = i.setOptions(i.options, nil, headers)
+	if err != nil {
+		return
+	}
+
+	err = i.do(ctx, http.MethodPost, i.path+"/docs", "docs", i.path, http.StatusOK, &i.query, &raw, headers)
+	if err != nil {
+		return
+	}
+
+	i.continuation = headers.Get("X-Ms-Continuation")
+	i.done = i.continuation == ""
+
+	return
+}
+
+func (i *portalAccessGrantDocumentQueryIterator) Continuation() string {
+	return i.continuation
+}
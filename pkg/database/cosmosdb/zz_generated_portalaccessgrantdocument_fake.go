@@ -0,0 +1,361 @@
+// Code generated by github.com/jewzaam/go-cosmosdb, DO NOT EDIT.
+
+package cosmosdb
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ugorji/go/codec"
+
+	pkg "github.com/Azure/ARO-RP/pkg/api"
+)
+
+type fakePortalAccessGrantDocumentTriggerHandler func(context.Context, *pkg.PortalAccessGrantDocument) error
+type fakePortalAccessGrantDocumentQueryHandler func(PortalAccessGrantDocumentClient, *Query, *Options) PortalAccessGrantDocumentRawIterator
+
+var _ PortalAccessGrantDocumentClient = &FakePortalAccessGrantDocumentClient{}
+
+// NewFakePortalAccessGrantDocumentClient returns a FakePortalAccessGrantDocumentClient
+func NewFakePortalAccessGrantDocumentClient(h *codec.JsonHandle) *FakePortalAccessGrantDocumentClient {
+	return &FakePortalAccessGrantDocumentClient{
+		jsonHandle:                 h,
+		portalAccessGrantDocuments: make(map[string]*pkg.PortalAccessGrantDocument),
+		triggerHandlers:            make(map[string]fakePortalAccessGrantDocumentTriggerHandler),
+		queryHandlers:              make(map[string]fakePortalAccessGrantDocumentQueryHandler),
+	}
+}
+
+// FakePortalAccessGrantDocumentClient is a FakePortalAccessGrantDocumentClient
+type FakePortalAccessGrantDocumentClient struct {
+	lock                       sync.RWMutex
+	jsonHandle                 *codec.JsonHandle
+	portalAccessGrantDocuments map[string]*pkg.PortalAccessGrantDocument
+	triggerHandlers            map[string]fakePortalAccessGrantDocumentTriggerHandler
+	queryHandlers              map[string]fakePortalAccessGrantDocumentQueryHandler
+	sorter                     func([]*pkg.PortalAccessGrantDocument)
+	etag                       int
+
+	// returns true if documents conflict
+	conflictChecker func(*pkg.PortalAccessGrantDocument, *pkg.PortalAccessGrantDocument) bool
+
+	// err, if not nil, is an error to return when attempting to communicate
+	// with this Client
+	err error
+}
+
+// SetError sets or unsets an error that will be returned on any
+// FakePortalAccessGrantDocumentClient method invocation
+func (c *FakePortalAccessGrantDocumentClient) SetError(err error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.err = err
+}
+
+// SetSorter sets or unsets a sorter function which will be used to sort values
+// returned by List() for test stability
+func (c *FakePortalAccessGrantDocumentClient) SetSorter(sorter func([]*pkg.PortalAccessGrantDocument)) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.sorter = sorter
+}
+
+// SetConflictChecker sets or unsets a function which can be used to validate
+// additional unique keys in a PortalAccessGrantDocument
+func (c *FakePortalAccessGrantDocumentClient) SetConflictChecker(conflictChecker func(*pkg.PortalAccessGrantDocument, *pkg.PortalAccessGrantDocument) bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.conflictChecker = conflictChecker
+}
+
+// SetTriggerHandler sets or unsets a trigger handler
+func (c *FakePortalAccessGrantDocumentClient) SetTriggerHandler(triggerName string, trigger fakePortalAccessGrantDocumentTriggerHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.triggerHandlers[triggerName] = trigger
+}
+
+// SetQueryHandler sets or unsets a query handler
+func (c *FakePortalAccessGrantDocumentClient) SetQueryHandler(queryName string, query fakePortalAccessGrantDocumentQueryHandler) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.queryHandlers[queryName] = query
+}
+
+func (c *FakePortalAccessGrantDocumentClient) deepCopy(portalAccessGrantDocument *pkg.PortalAccessGrantDocument) (*pkg.PortalAccessGrantDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.jsonHandle).Encode(portalAccessGrantDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	portalAccessGrantDocument = nil
+	err = codec.NewDecoderBytes(b, c.jsonHandle).Decode(&portalAccessGrantDocument)
+	if err != nil {
+		return nil, err
+	}
+
+	return portalAccessGrantDocument, nil
+}
+
+func (c *FakePortalAccessGrantDocumentClient) apply(ctx context.Context, partitionkey string, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options, isCreate bool) (*pkg.PortalAccessGrantDocument, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	portalAccessGrantDocument, err := c.deepCopy(portalAccessGrantDocument) // copy now because pretriggers can mutate portalAccessGrantDocument
+	if err != nil {
+		return nil, err
+	}
+
+	if options != nil {
+		err := c.processPreTriggers(ctx, portalAccessGrantDocument, options)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	existingPortalAccessGrantDocument, exists := c.portalAccessGrantDocuments[portalAccessGrantDocument.ID]
+	if isCreate && exists {
+		return nil, &Error{
+			StatusCode: http.StatusConflict,
+			Message:    "Entity with the specified id already exists in the system",
+		}
+	}
+	if !isCreate {
+		if !exists {
+			return nil, &Error{StatusCode: http.StatusNotFound}
+		}
+
+		if portalAccessGrantDocument.ETag != existingPortalAccessGrantDocument.ETag {
+			return nil, &Error{StatusCode: http.StatusPreconditionFailed}
+		}
+	}
+
+	if c.conflictChecker != nil {
+		for _, portalAccessGrantDocumentToCheck := range c.portalAccessGrantDocuments {
+			if c.conflictChecker(portalAccessGrantDocumentToCheck, portalAccessGrantDocument) {
+				return nil, &Error{
+					StatusCode: http.StatusConflict,
+					Message:    "Entity with the specified id already exists in the system",
+				}
+			}
+		}
+	}
+
+	portalAccessGrantDocument.ETag = fmt.Sprint(c.etag)
+	c.etag++
+
+	c.portalAccessGrantDocuments[portalAccessGrantDocument.ID] = portalAccessGrantDocument
+
+	return c.deepCopy(portalAccessGrantDocument)
+}
+
+// Create creates a PortalAccessGrantDocument in the database
+func (c *FakePortalAccessGrantDocumentClient) Create(ctx context.Context, partitionkey string, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) (*pkg.PortalAccessGrantDocument, error) {
+	return c.apply(ctx, partitionkey, portalAccessGrantDocument, options, true)
+}
+
+// Replace replaces a PortalAccessGrantDocument in the database
+func (c *FakePortalAccessGrantDocumentClient) Replace(ctx context.Context, partitionkey string, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) (*pkg.PortalAccessGrantDocument, error) {
+	return c.apply(ctx, partitionkey, portalAccessGrantDocument, options, false)
+}
+
+// List returns a PortalAccessGrantDocumentIterator to list all PortalAccessGrantDocuments in the database
+func (c *FakePortalAccessGrantDocumentClient) List(*Options) PortalAccessGrantDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePortalAccessGrantDocumentErroringRawIterator(c.err)
+	}
+
+	portalAccessGrantDocuments := make([]*pkg.PortalAccessGrantDocument, 0, len(c.portalAccessGrantDocuments))
+	for _, portalAccessGrantDocument := range c.portalAccessGrantDocuments {
+		portalAccessGrantDocument, err := c.deepCopy(portalAccessGrantDocument)
+		if err != nil {
+			return NewFakePortalAccessGrantDocumentErroringRawIterator(err)
+		}
+		portalAccessGrantDocuments = append(portalAccessGrantDocuments, portalAccessGrantDocument)
+	}
+
+	if c.sorter != nil {
+		c.sorter(portalAccessGrantDocuments)
+	}
+
+	return NewFakePortalAccessGrantDocumentIterator(portalAccessGrantDocuments, 0)
+}
+
+// ListAll lists all PortalAccessGrantDocuments in the database
+func (c *FakePortalAccessGrantDocumentClient) ListAll(ctx context.Context, options *Options) (*pkg.PortalAccessGrantDocuments, error) {
+	iter := c.List(options)
+	return iter.Next(ctx, -1)
+}
+
+// Get gets a PortalAccessGrantDocument from the database
+func (c *FakePortalAccessGrantDocumentClient) Get(ctx context.Context, partitionkey string, id string, options *Options) (*pkg.PortalAccessGrantDocument, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	portalAccessGrantDocument, exists := c.portalAccessGrantDocuments[id]
+	if !exists {
+		return nil, &Error{StatusCode: http.StatusNotFound}
+	}
+
+	return c.deepCopy(portalAccessGrantDocument)
+}
+
+// Delete deletes a PortalAccessGrantDocument from the database
+func (c *FakePortalAccessGrantDocumentClient) Delete(ctx context.Context, partitionKey string, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.err != nil {
+		return c.err
+	}
+
+	_, exists := c.portalAccessGrantDocuments[portalAccessGrantDocument.ID]
+	if !exists {
+		return &Error{StatusCode: http.StatusNotFound}
+	}
+
+	delete(c.portalAccessGrantDocuments, portalAccessGrantDocument.ID)
+	return nil
+}
+
+// ChangeFeed is unimplemented
+func (c *FakePortalAccessGrantDocumentClient) ChangeFeed(*Options) PortalAccessGrantDocumentIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePortalAccessGrantDocumentErroringRawIterator(c.err)
+	}
+
+	return NewFakePortalAccessGrantDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+func (c *FakePortalAccessGrantDocumentClient) processPreTriggers(ctx context.Context, portalAccessGrantDocument *pkg.PortalAccessGrantDocument, options *Options) error {
+	for _, triggerName := range options.PreTriggers {
+		if triggerHandler := c.triggerHandlers[triggerName]; triggerHandler != nil {
+			c.lock.Unlock()
+			err := triggerHandler(ctx, portalAccessGrantDocument)
+			c.lock.Lock()
+			if err != nil {
+				return err
+			}
+		} else {
+			return ErrNotImplemented
+		}
+	}
+
+	return nil
+}
+
+// Query calls a query handler to implement database querying
+func (c *FakePortalAccessGrantDocumentClient) Query(name string, query *Query, options *Options) PortalAccessGrantDocumentRawIterator {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	if c.err != nil {
+		return NewFakePortalAccessGrantDocumentErroringRawIterator(c.err)
+	}
+
+	if queryHandler := c.queryHandlers[query.Query]; queryHandler != nil {
+		c.lock.RUnlock()
+		i := queryHandler(c, query, options)
+		c.lock.RLock()
+		return i
+	}
+
+	return NewFakePortalAccessGrantDocumentErroringRawIterator(ErrNotImplemented)
+}
+
+// QueryAll calls a query handler to implement database querying
+func (c *FakePortalAccessGrantDocumentClient) QueryAll(ctx context.Context, partitionkey string, query *Query, options *Options) (*pkg.PortalAccessGrantDocuments, error) {
+	iter := c.Query("", query, options)
+	return iter.Next(ctx, -1)
+}
+
+func NewFakePortalAccessGrantDocumentIterator(portalAccessGrantDocuments []*pkg.PortalAccessGrantDocument, continuation int) PortalAccessGrantDocumentRawIterator {
+	return &fakePortalAccessGrantDocumentIterator{portalAccessGrantDocuments: portalAccessGrantDocuments, continuation: continuation}
+}
+
+type fakePortalAccessGrantDocumentIterator struct {
+	portalAccessGrantDocuments []*pkg.PortalAccessGrantDocument
+	continuation               int
+	done                       bool
+}
+
+func (i *fakePortalAccessGrantDocumentIterator) NextRaw(ctx context.Context, maxItemCount int, out interface{}) error {
+	return ErrNotImplemented
+}
+
+func (i *fakePortalAccessGrantDocumentIterator) Next(ctx context.Context, maxItemCount int) (*pkg.PortalAccessGrantDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+
+	var portalAccessGrantDocuments []*pkg.PortalAccessGrantDocument
+	if maxItemCount == -1 {
+		portalAccessGrantDocuments = i.portalAccessGrantDocuments[i.continuation:]
+		i.continuation = len(i.portalAccessGrantDocuments)
+		i.done = true
+	} else {
+		max := i.continuation + maxItemCount
+		if max > len(i.portalAccessGrantDocuments) {
+			max = len(i.portalAccessGrantDocuments)
+		}
+		portalAccessGrantDocuments = i.portalAccessGrantDocuments[i.continuation:max]
+		i.continuation += max
+		i.done = i.Continuation() == ""
+	}
+
+	return &pkg.PortalAccessGrantDocuments{
+		PortalAccessGrantDocuments: portalAccessGrantDocuments,
+		Count:                      len(portalAccessGrantDocuments),
+	}, nil
+}
+
+func (i *fakePortalAccessGrantDocumentIterator) Continuation() string {
+	if i.continuation >= len(i.portalAccessGrantDocuments) {
+		return ""
+	}
+	return fmt.Sprintf("%d", i.continuation)
+}
+
+// NewFakePortalAccessGrantDocumentErroringRawIterator returns a PortalAccessGrantDocumentRawIterator which
+// whose methods return the given error
+func NewFakePortalAccessGrantDocumentErroringRawIterator(err error) PortalAccessGrantDocumentRawIterator {
+	return &fakePortalAccessGrantDocumentErroringRawIterator{err: err}
+}
+
+type fakePortalAccessGrantDocumentErroringRawIterator struct {
+	err error
+}
+
+func (i *fakePortalAccessGrantDocumentErroringRawIterator) Next(ctx context.Context, maxItemCount int) (*pkg.PortalAccessGrantDocuments, error) {
+	return nil, i.err
+}
+
+func (i *fakePortalAccessGrantDocumentErroringRawIterator) NextRaw(context.Context, int, interface{}) error {
+	return i.err
+}
+
+func (i *fakePortalAccessGrantDocumentErroringRawIterator) Continuation() string {
+	return ""
+}
@@ -26,15 +26,17 @@ import (
 )
 
 const (
-	collAsyncOperations   = "AsyncOperations"
-	collBilling           = "Billing"
-	collClusterManager    = "ClusterManagerConfigurations"
-	collGateway           = "Gateway"
-	collMonitors          = "Monitors"
-	collOpenShiftClusters = "OpenShiftClusters"
-	collOpenShiftVersion  = "OpenShiftVersions"
-	collPortal            = "Portal"
-	collSubscriptions     = "Subscriptions"
+	collAsyncOperations    = "AsyncOperations"
+	collBatchOperations    = "BatchOperations"
+	collBilling            = "Billing"
+	collClusterManager     = "ClusterManagerConfigurations"
+	collGateway            = "Gateway"
+	collMonitors           = "Monitors"
+	collOpenShiftClusters  = "OpenShiftClusters"
+	collOpenShiftVersion   = "OpenShiftVersions"
+	collPortal             = "Portal"
+	collPortalAccessGrants = "PortalAccessGrants"
+	collSubscriptions      = "Subscriptions"
 )
 
 func NewDatabaseClient(log *logrus.Entry, _env env.Core, authorizer cosmosdb.Authorizer, m metrics.Emitter, aead encryption.AEAD, databaseAccountName string) (cosmosdb.DatabaseClient, error) {
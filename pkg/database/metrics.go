@@ -13,17 +13,62 @@ import (
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 )
 
-func EmitMetrics(ctx context.Context, log *logrus.Entry, dbOpenShiftClusters OpenShiftClusters, m metrics.Emitter) {
+func EmitMetrics(ctx context.Context, log *logrus.Entry, dbOpenShiftClusters OpenShiftClusters, m metrics.Emitter, maxCreatesPerSubscription int) {
 	defer recover.Panic(log)
 	t := time.NewTicker(time.Minute)
 	defer t.Stop()
 
+	// subscriptions holds the subscriptions with queued documents last time,
+	// so that zero is reported once their queue drains
+	subscriptions := map[string]struct{}{}
+
 	for range t.C {
-		i, err := dbOpenShiftClusters.QueueLength(ctx, "OpenShiftClusters")
+		lengths, err := dbOpenShiftClusters.QueueLength(ctx, maxCreatesPerSubscription)
 		if err != nil {
 			log.Error(err)
-		} else {
-			m.EmitGauge("database.openshiftclusters.queue.length", int64(i), nil)
+			continue
 		}
+
+		subscriptions = emitQueueLength(m, lengths, maxCreatesPerSubscription, subscriptions)
 	}
 }
+
+// emitQueueLength emits the length of the queue, and the length, throttled
+// creates and fair-share position of the queue of each subscription.  It
+// returns the subscriptions which had queued documents.
+func emitQueueLength(m metrics.Emitter, lengths []*SubscriptionQueueLength, maxCreatesPerSubscription int, previous map[string]struct{}) map[string]struct{} {
+	var total int
+	subscriptions := map[string]struct{}{}
+
+	for _, l := range lengths {
+		total += l.Queued
+		subscriptions[l.SubscriptionID] = struct{}{}
+
+		dims := map[string]string{
+			"subscriptionId": l.SubscriptionID,
+		}
+		m.EmitGauge("database.openshiftclusters.queue.subscription.length", int64(l.Queued), dims)
+		m.EmitGauge("database.openshiftclusters.queue.subscription.throttled", int64(l.Throttled), dims)
+		m.EmitGauge("database.openshiftclusters.queue.subscription.position", int64(l.Position), dims)
+	}
+
+	for subscriptionID := range previous {
+		if _, ok := subscriptions[subscriptionID]; ok {
+			continue
+		}
+
+		dims := map[string]string{
+			"subscriptionId": subscriptionID,
+		}
+		m.EmitGauge("database.openshiftclusters.queue.subscription.length", 0, dims)
+		m.EmitGauge("database.openshiftclusters.queue.subscription.throttled", 0, dims)
+	}
+
+	m.EmitGauge("database.openshiftclusters.queue.length", int64(total), nil)
+
+	if maxCreatesPerSubscription > 0 {
+		m.EmitGauge("database.openshiftclusters.queue.limit", int64(maxCreatesPerSubscription), nil)
+	}
+
+	return subscriptions
+}
@@ -0,0 +1,41 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestEmitQueueLength(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m := mock_metrics.NewMockEmitter(controller)
+
+	a := map[string]string{"subscriptionId": "a"}
+	b := map[string]string{"subscriptionId": "b"}
+
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.subscription.length", int64(3), a)
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.subscription.throttled", int64(1), a)
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.subscription.position", int64(1), a)
+	// b has drained since the last time
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.subscription.length", int64(0), b)
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.subscription.throttled", int64(0), b)
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.length", int64(3), nil)
+	m.EXPECT().EmitGauge("database.openshiftclusters.queue.limit", int64(2), nil)
+
+	got := emitQueueLength(m, []*SubscriptionQueueLength{
+		{SubscriptionID: "a", Queued: 3, Throttled: 1, Running: 2, Limit: 2, Position: 1},
+	}, 2, map[string]struct{}{"a": {}, "b": {}})
+
+	want := map[string]struct{}{"a": {}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
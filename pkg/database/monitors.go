@@ -26,7 +26,7 @@ type Monitors interface {
 	TryLease(context.Context) (*api.MonitorDocument, error)
 	ListBuckets(context.Context) ([]int, error)
 	ListMonitors(context.Context) (*api.MonitorDocuments, error)
-	MonitorHeartbeat(context.Context) error
+	MonitorHeartbeat(context.Context, *api.Monitor) error
 }
 
 // NewMonitors returns a new Monitors
@@ -167,10 +167,11 @@ func (c *monitors) ListMonitors(ctx context.Context) (*api.MonitorDocuments, err
 	}, nil)
 }
 
-func (c *monitors) MonitorHeartbeat(ctx context.Context) error {
+func (c *monitors) MonitorHeartbeat(ctx context.Context, monitor *api.Monitor) error {
 	doc := &api.MonitorDocument{
-		ID:  c.uuid,
-		TTL: 60,
+		ID:      c.uuid,
+		TTL:     60,
+		Monitor: monitor,
 	}
 	_, err := c.update(ctx, doc, &cosmosdb.Options{NoETag: true})
 	if err != nil && cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
@@ -7,7 +7,10 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 
@@ -17,14 +20,100 @@ import (
 )
 
 const (
-	OpenShiftClustersDequeueQuery       = `SELECT * FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
-	OpenShiftClustersQueueLengthQuery   = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
+	OpenShiftClustersQueueLengthQuery   = `SELECT doc.partitionKey, doc.openShiftCluster.properties.provisioningState FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
+	OpenShiftClustersLeasedCreatesQuery = `SELECT VALUE doc.partitionKey FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState = "Creating" AND (doc.leaseExpires ?? 0) >= GetCurrentTimestamp() / 1000`
 	OpenShiftClustersGetQuery           = `SELECT * FROM OpenShiftClusters doc WHERE doc.key = @key`
 	OpenshiftClustersPrefixQuery        = `SELECT * FROM OpenShiftClusters doc WHERE STARTSWITH(doc.key, @prefix)`
 	OpenshiftClustersClientIdQuery      = `SELECT * FROM OpenShiftClusters doc WHERE doc.clientIdKey = @clientID`
 	OpenshiftClustersResourceGroupQuery = `SELECT * FROM OpenShiftClusters doc WHERE doc.clusterResourceGroupIdKey = @resourceGroupID`
+	OpenShiftClustersHiveShardQuery     = `SELECT VALUE COUNT(1) FROM OpenShiftClusters doc WHERE ToString(doc.openShiftCluster.properties.hiveProfile.shard ?? ((doc.openShiftCluster.properties.hiveProfile.namespace ?? "") != "" ? 1 : 0)) = @shard`
+
+	// OpenShiftClustersDequeueQuery returns only the fields needed to order
+	// the queue; Dequeue reads the whole of the document which it leases.
+	OpenShiftClustersDequeueQuery = `SELECT doc.id, doc.key, doc.partitionKey, doc.enqueueTime, doc.asyncOperationId, doc._ts, doc._etag, {
+	"properties": {"provisioningState": doc.openShiftCluster.properties.provisioningState}
+} AS openShiftCluster FROM OpenShiftClusters doc WHERE doc.openShiftCluster.properties.provisioningState IN ("Creating", "Deleting", "Updating", "AdminUpdating") AND (doc.leaseExpires ?? 0) < GetCurrentTimestamp() / 1000`
+
+	// OpenShiftClustersSearchQuery returns only the fields needed to list
+	// clusters; in particular it leaves out credentials and kubeconfigs, which
+	// make up most of a document.  An empty parameter matches every cluster.
+	OpenShiftClustersSearchQuery = `SELECT doc.id, doc.key, doc.partitionKey, {
+	"id": doc.openShiftCluster.id,
+	"name": doc.openShiftCluster.name,
+	"location": doc.openShiftCluster.location,
+	"systemData": doc.openShiftCluster.systemData,
+	"properties": {
+		"provisioningState": doc.openShiftCluster.properties.provisioningState,
+		"failedProvisioningState": doc.openShiftCluster.properties.failedProvisioningState,
+		"maintenanceState": doc.openShiftCluster.properties.maintenanceState,
+		"clusterProfile": {"version": doc.openShiftCluster.properties.clusterProfile.version},
+		"createdAt": doc.openShiftCluster.properties.createdAt,
+		"provisionedBy": doc.openShiftCluster.properties.provisionedBy,
+		"hiveProfile": {"namespace": doc.openShiftCluster.properties.hiveProfile.namespace, "shard": doc.openShiftCluster.properties.hiveProfile.shard}
+	}
+} AS openShiftCluster FROM OpenShiftClusters doc WHERE
+	((@subscriptionId ?? "") = "" OR doc.partitionKey = @subscriptionId) AND
+	((@version ?? "") = "" OR STARTSWITH(doc.openShiftCluster.properties.clusterProfile.version, @version)) AND
+	((@provisioningState ?? "") = "" OR doc.openShiftCluster.properties.provisioningState = @provisioningState) AND
+	((@maintenanceState ?? "") = "" OR (doc.openShiftCluster.properties.maintenanceState ?? "") = @maintenanceState) AND
+	((@hiveShard ?? "") = "" OR ((doc.openShiftCluster.properties.hiveProfile.namespace ?? "") != "" AND ToString(doc.openShiftCluster.properties.hiveProfile.shard ?? 1) = @hiveShard)) AND
+	((@operatorFlag ?? "") = "" OR doc.openShiftCluster.properties.operatorFlags[@operatorFlag] = @operatorFlagValue) AND
+	((@createdAfter ?? "") = "" OR DateTimeToTicks(doc.openShiftCluster.properties.createdAt) >= DateTimeToTicks(@createdAfter)) AND
+	((@createdBefore ?? "") = "" OR DateTimeToTicks(doc.openShiftCluster.properties.createdAt) < DateTimeToTicks(@createdBefore))`
 )
 
+// OpenShiftClusterSearchOrderBy maps the properties which Search can order
+// by, keyed by their lower case JSON names, to their paths in the document
+var OpenShiftClusterSearchOrderBy = map[string]string{
+	"name":              "doc.openShiftCluster.name",
+	"subscription":      "doc.partitionKey",
+	"version":           "doc.openShiftCluster.properties.clusterProfile.version",
+	"provisioningstate": "doc.openShiftCluster.properties.provisioningState",
+	"maintenancestate":  "doc.openShiftCluster.properties.maintenanceState",
+	"createdat":         "doc.openShiftCluster.properties.createdAt",
+	"lastmodified":      "doc.openShiftCluster.systemData.lastModifiedAt",
+}
+
+// OpenShiftClustersSearchOrderedQuery returns OpenShiftClustersSearchQuery
+// ordered by one of the properties of OpenShiftClusterSearchOrderBy
+func OpenShiftClustersSearchOrderedQuery(orderBy string, descending bool) (string, error) {
+	path, ok := OpenShiftClusterSearchOrderBy[orderBy]
+	if !ok {
+		return "", fmt.Errorf("invalid order by %q", orderBy)
+	}
+
+	direction := "ASC"
+	if descending {
+		direction = "DESC"
+	}
+
+	return OpenShiftClustersSearchQuery + " ORDER BY " + path + " " + direction, nil
+}
+
+// OpenShiftClusterSearch filters the clusters returned by Search.  Fields
+// which are empty (or zero) match every cluster.
+type OpenShiftClusterSearch struct {
+	// SubscriptionID restricts the search to a single partition
+	SubscriptionID string
+	// Version matches clusters whose version starts with it, so "4.12"
+	// matches every 4.12.z version
+	Version           string
+	ProvisioningState api.ProvisioningState
+	MaintenanceState  api.MaintenanceState
+	// HiveShard matches Hive-managed clusters on the shard; clusters which
+	// predate sharding are on shard 1
+	HiveShard         int
+	OperatorFlag      string
+	OperatorFlagValue string
+	CreatedAfter      time.Time
+	CreatedBefore     time.Time
+
+	// OrderBy is a key of OpenShiftClusterSearchOrderBy; clusters are ordered
+	// by name if it is empty
+	OrderBy    string
+	Descending bool
+}
+
 type OpenShiftClusterDocumentMutator func(*api.OpenShiftClusterDocument) error
 
 type openShiftClusters struct {
@@ -32,13 +121,32 @@ type openShiftClusters struct {
 	collc         cosmosdb.CollectionClient
 	uuid          string
 	uuidGenerator uuid.Generator
+
+	mu sync.Mutex
+
+	// lastSubscription is the subscription of the document most recently
+	// dequeued, used to round-robin between subscriptions
+	lastSubscription string
+
+	// leasedCreates caches the number of creates running per subscription
+	// until leasedCreatesExpires, so that the backend doesn't query them on
+	// every dequeue attempt
+	leasedCreates        map[string]int
+	leasedCreatesExpires time.Time
 }
 
+// leasedCreatesCacheTTL is how long the number of creates running per
+// subscription is cached for.  Creates dequeued by this process are counted
+// straight away, so only those dequeued by other backends can push a
+// subscription over its limit, and only within this time.
+const leasedCreatesCacheTTL = 30 * time.Second
+
 // OpenShiftClusters is the database interface for OpenShiftClusterDocuments
 type OpenShiftClusters interface {
 	Create(context.Context, *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
 	Get(context.Context, string) (*api.OpenShiftClusterDocument, error)
-	QueueLength(context.Context, string) (int, error)
+	QueueLength(context.Context, int) ([]*SubscriptionQueueLength, error)
+	CountByHiveShard(context.Context, string, int) (int, error)
 	Patch(context.Context, string, OpenShiftClusterDocumentMutator) (*api.OpenShiftClusterDocument, error)
 	PatchWithLease(context.Context, string, OpenShiftClusterDocumentMutator) (*api.OpenShiftClusterDocument, error)
 	Update(context.Context, *api.OpenShiftClusterDocument) (*api.OpenShiftClusterDocument, error)
@@ -47,7 +155,9 @@ type OpenShiftClusters interface {
 	List(string) cosmosdb.OpenShiftClusterDocumentIterator
 	ListAll(context.Context) (*api.OpenShiftClusterDocuments, error)
 	ListByPrefix(string, string, string) (cosmosdb.OpenShiftClusterDocumentIterator, error)
-	Dequeue(context.Context) (*api.OpenShiftClusterDocument, error)
+	Search(*OpenShiftClusterSearch, string) (cosmosdb.OpenShiftClusterDocumentIterator, error)
+	Queue(context.Context, int) ([]*QueuedOpenShiftCluster, error)
+	Dequeue(context.Context, int) (*api.OpenShiftClusterDocument, error)
 	Lease(context.Context, string) (*api.OpenShiftClusterDocument, error)
 	EndLease(context.Context, string, api.ProvisioningState, api.ProvisioningState, *string) (*api.OpenShiftClusterDocument, error)
 	GetByClientID(ctx context.Context, partitionKey, clientID string) (*api.OpenShiftClusterDocuments, error)
@@ -152,9 +262,102 @@ func (c *openShiftClusters) Get(ctx context.Context, key string) (*api.OpenShift
 	}
 }
 
-// QueueLength returns OpenShiftClusters un-queued document count.
-// If error occurs, 0 is returned with error message
-func (c *openShiftClusters) QueueLength(ctx context.Context, collid string) (int, error) {
+// QueueLength returns the queue length of each subscription with documents
+// waiting to be dequeued, along with its position in the fair-share order and
+// the per-subscription create limit.  A limit of zero or less means no limit.
+func (c *openShiftClusters) QueueLength(ctx context.Context, maxCreatesPerSubscription int) ([]*SubscriptionQueueLength, error) {
+	var queued []queuedOpenShiftCluster
+
+	result := c.c.Query("", &cosmosdb.Query{
+		Query: OpenShiftClustersQueueLengthQuery,
+	}, nil)
+	for {
+		var data struct {
+			api.MissingFields
+			Documents []queuedOpenShiftCluster `json:"Documents,omitempty"`
+		}
+		err := result.NextRaw(ctx, -1, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		queued = append(queued, data.Documents...)
+
+		if result.Continuation() == "" {
+			break
+		}
+	}
+
+	running := map[string]int{}
+	if maxCreatesPerSubscription > 0 {
+		var err error
+		running, err = c.getLeasedCreates(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	after := c.lastSubscription
+	c.mu.Unlock()
+
+	return subscriptionQueueLengths(queued, running, maxCreatesPerSubscription, after), nil
+}
+
+// getLeasedCreates returns the number of creates running per subscription.
+// The counts are cached for leasedCreatesCacheTTL.
+func (c *openShiftClusters) getLeasedCreates(ctx context.Context) (map[string]int, error) {
+	c.mu.Lock()
+	if c.leasedCreates != nil && time.Now().Before(c.leasedCreatesExpires) {
+		leasedCreates := make(map[string]int, len(c.leasedCreates))
+		for subscription, count := range c.leasedCreates {
+			leasedCreates[subscription] = count
+		}
+		c.mu.Unlock()
+		return leasedCreates, nil
+	}
+	c.mu.Unlock()
+
+	// only the partition keys are read, as only the count per subscription
+	// is needed
+	leasedCreates := map[string]int{}
+	result := c.c.Query("", &cosmosdb.Query{
+		Query: OpenShiftClustersLeasedCreatesQuery,
+	}, nil)
+	for {
+		var data struct {
+			api.MissingFields
+			Documents []string `json:"Documents,omitempty"`
+		}
+		err := result.NextRaw(ctx, -1, &data)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, partitionKey := range data.Documents {
+			leasedCreates[partitionKey]++
+		}
+
+		if result.Continuation() == "" {
+			break
+		}
+	}
+
+	c.mu.Lock()
+	c.leasedCreates = make(map[string]int, len(leasedCreates))
+	for subscription, count := range leasedCreates {
+		c.leasedCreates[subscription] = count
+	}
+	c.leasedCreatesExpires = time.Now().Add(leasedCreatesCacheTTL)
+	c.mu.Unlock()
+
+	return leasedCreates, nil
+}
+
+// CountByHiveShard returns the number of clusters assigned to the given Hive
+// shard, including clusters whose installation has not yet reached Hive.
+// Hive-managed clusters which predate sharding are counted against shard 1.
+func (c *openShiftClusters) CountByHiveShard(ctx context.Context, collid string, shard int) (int, error) {
 	partitions, err := c.collc.PartitionKeyRanges(ctx, collid)
 	if err != nil {
 		return 0, err
@@ -163,12 +366,17 @@ func (c *openShiftClusters) QueueLength(ctx context.Context, collid string) (int
 	var countTotal int
 	for _, r := range partitions.PartitionKeyRanges {
 		result := c.c.Query("", &cosmosdb.Query{
-			Query: OpenShiftClustersQueueLengthQuery,
+			Query: OpenShiftClustersHiveShardQuery,
+			Parameters: []cosmosdb.Parameter{
+				{
+					Name:  "@shard",
+					Value: strconv.Itoa(shard),
+				},
+			},
 		}, &cosmosdb.Options{
 			PartitionKeyRangeID: r.ID,
 		})
-		// because we aggregate count we don't expect pagination in this query result,
-		// so we gonna call Next() only once per partition.
+		// as with QueueLength, the aggregate count is returned in a single page
 		var data struct {
 			api.MissingFields
 			Document []int `json:"Documents,omitempty"`
@@ -274,30 +482,123 @@ func (c *openShiftClusters) ListByPrefix(subscriptionID, prefix, continuation st
 	), nil
 }
 
-func (c *openShiftClusters) Dequeue(ctx context.Context) (*api.OpenShiftClusterDocument, error) {
-	i := c.c.Query("", &cosmosdb.Query{
+// Search returns an iterator over the clusters which match s, in the order
+// which s asks for, starting at continuation.  The documents returned only
+// hold the fields selected by OpenShiftClustersSearchQuery.
+func (c *openShiftClusters) Search(s *OpenShiftClusterSearch, continuation string) (cosmosdb.OpenShiftClusterDocumentIterator, error) {
+	orderBy := s.OrderBy
+	if orderBy == "" {
+		orderBy = "name"
+	}
+
+	query, err := OpenShiftClustersSearchOrderedQuery(orderBy, s.Descending)
+	if err != nil {
+		return nil, err
+	}
+
+	var hiveShard, createdAfter, createdBefore string
+	if s.HiveShard != 0 {
+		hiveShard = strconv.Itoa(s.HiveShard)
+	}
+	if !s.CreatedAfter.IsZero() {
+		createdAfter = s.CreatedAfter.UTC().Format(time.RFC3339Nano)
+	}
+	if !s.CreatedBefore.IsZero() {
+		createdBefore = s.CreatedBefore.UTC().Format(time.RFC3339Nano)
+	}
+
+	// the subscription is also passed as the partition key, so that a search
+	// within a subscription reads a single partition
+	subscriptionID := strings.ToLower(s.SubscriptionID)
+
+	return c.c.Query(subscriptionID, &cosmosdb.Query{
+		Query: query,
+		Parameters: []cosmosdb.Parameter{
+			{Name: "@subscriptionId", Value: subscriptionID},
+			{Name: "@version", Value: s.Version},
+			{Name: "@provisioningState", Value: string(s.ProvisioningState)},
+			{Name: "@maintenanceState", Value: string(s.MaintenanceState)},
+			{Name: "@hiveShard", Value: hiveShard},
+			{Name: "@operatorFlag", Value: s.OperatorFlag},
+			{Name: "@operatorFlagValue", Value: s.OperatorFlagValue},
+			{Name: "@createdAfter", Value: createdAfter},
+			{Name: "@createdBefore", Value: createdBefore},
+		},
+	}, &cosmosdb.Options{Continuation: continuation}), nil
+}
+
+// Queue returns the documents waiting to be dequeued in fair-share order: round
+// robin by subscription, with no more than maxCreatesPerSubscription creates
+// running per subscription at once.  A limit of zero or less means no limit.
+// The documents returned only hold the fields selected by
+// OpenShiftClustersDequeueQuery.
+func (c *openShiftClusters) Queue(ctx context.Context, maxCreatesPerSubscription int) ([]*QueuedOpenShiftCluster, error) {
+	docs, err := c.c.QueryAll(ctx, "", &cosmosdb.Query{
 		Query: OpenShiftClustersDequeueQuery,
 	}, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		docs, err := i.Next(ctx, -1)
+	running := map[string]int{}
+	if maxCreatesPerSubscription > 0 {
+		running, err = c.getLeasedCreates(ctx)
 		if err != nil {
 			return nil, err
 		}
-		if docs == nil {
-			return nil, nil
+	}
+
+	c.mu.Lock()
+	after := c.lastSubscription
+	c.mu.Unlock()
+
+	return fairShareQueue(docs.OpenShiftClusterDocuments, running, maxCreatesPerSubscription, after), nil
+}
+
+// Dequeue leases the first document in the fair-share order which is not
+// throttled by maxCreatesPerSubscription
+func (c *openShiftClusters) Dequeue(ctx context.Context, maxCreatesPerSubscription int) (*api.OpenShiftClusterDocument, error) {
+	queue, err := c.Queue(ctx, maxCreatesPerSubscription)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, q := range queue {
+		if q.Throttled {
+			continue
+		}
+
+		// the whole document is only read for the one being leased; if it
+		// has changed since it was queried, someone else got there first
+		doc, err := c.c.Get(ctx, q.Doc.PartitionKey, q.Doc.ID, nil)
+		if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if doc.ETag != q.Doc.ETag {
+			continue
 		}
 
-		for _, doc := range docs.OpenShiftClusterDocuments {
-			doc.LeaseOwner = c.uuid
-			doc.Dequeues++
-			doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
-			if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
-				continue
+		doc.LeaseOwner = c.uuid
+		doc.Dequeues++
+		doc, err = c.update(ctx, doc, &cosmosdb.Options{PreTriggers: []string{"renewLease"}})
+		if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) { // someone else got there first
+			continue
+		}
+		if err == nil {
+			c.mu.Lock()
+			c.lastSubscription = doc.PartitionKey
+			if c.leasedCreates != nil && doc.OpenShiftCluster.Properties.ProvisioningState == api.ProvisioningStateCreating {
+				c.leasedCreates[doc.PartitionKey]++
 			}
-			return doc, err
+			c.mu.Unlock()
 		}
+		return doc, err
 	}
+
+	return nil, nil
 }
 
 func (c *openShiftClusters) Lease(ctx context.Context, key string) (*api.OpenShiftClusterDocument, error) {
@@ -328,6 +629,9 @@ func (c *openShiftClusters) EndLease(ctx context.Context, key string, provisioni
 			doc.CorrelationData = nil
 			doc.OpenShiftCluster.Properties.LastProvisioningState = ""
 			doc.AsyncOperationID = ""
+			doc.EnqueueTime = 0
+			doc.CompletedSteps = nil
+			doc.CancelRequested = false
 		}
 
 		return nil
@@ -0,0 +1,184 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sort"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// QueuedOpenShiftCluster is an OpenShiftClusterDocument waiting to be dequeued
+type QueuedOpenShiftCluster struct {
+	Doc *api.OpenShiftClusterDocument
+
+	// Position is the 1-based position of the document in the fair-share
+	// order
+	Position int
+
+	// Running is the number of clusters in the same subscription which are
+	// currently being created
+	Running int
+
+	// Throttled is true if the document is a create which is held back
+	// because its subscription has reached the per-subscription limit
+	Throttled bool
+}
+
+// fairShareQueue orders queued documents round-robin by subscription,
+// starting with the first subscription after the given one.  Within a
+// subscription, documents are kept oldest first.  Creates beyond
+// maxCreatesPerSubscription (including those already running) are marked as
+// throttled; a limit of zero or less means no limit.
+func fairShareQueue(docs []*api.OpenShiftClusterDocument, running map[string]int, maxCreatesPerSubscription int, after string) []*QueuedOpenShiftCluster {
+	bySubscription := map[string][]*api.OpenShiftClusterDocument{}
+	for _, doc := range docs {
+		bySubscription[doc.PartitionKey] = append(bySubscription[doc.PartitionKey], doc)
+	}
+
+	subscriptions := make([]string, 0, len(bySubscription))
+	for subscription, docs := range bySubscription {
+		subscriptions = append(subscriptions, subscription)
+
+		sort.SliceStable(docs, func(i, j int) bool {
+			if enqueueTime(docs[i]) != enqueueTime(docs[j]) {
+				return enqueueTime(docs[i]) < enqueueTime(docs[j])
+			}
+			return docs[i].Key < docs[j].Key
+		})
+	}
+	subscriptions = roundRobin(subscriptions, after)
+
+	creates := map[string]int{}
+	for subscription := range bySubscription {
+		creates[subscription] = running[subscription]
+	}
+
+	queue := make([]*QueuedOpenShiftCluster, 0, len(docs))
+	for round := 0; len(queue) < len(docs); round++ {
+		for _, subscription := range subscriptions {
+			if round >= len(bySubscription[subscription]) {
+				continue
+			}
+
+			doc := bySubscription[subscription][round]
+			q := &QueuedOpenShiftCluster{
+				Doc:      doc,
+				Position: len(queue) + 1,
+				Running:  running[subscription],
+			}
+
+			if doc.OpenShiftCluster.Properties.ProvisioningState == api.ProvisioningStateCreating {
+				if maxCreatesPerSubscription > 0 && creates[subscription] >= maxCreatesPerSubscription {
+					q.Throttled = true
+				}
+				creates[subscription]++
+			}
+
+			queue = append(queue, q)
+		}
+	}
+
+	return queue
+}
+
+// enqueueTime returns the time at which the document was queued.  Documents
+// queued before EnqueueTime was introduced fall back to _ts.
+func enqueueTime(doc *api.OpenShiftClusterDocument) int {
+	if doc.EnqueueTime != 0 {
+		return doc.EnqueueTime
+	}
+	return doc.Timestamp
+}
+
+// roundRobin sorts the subscriptions and rotates them so that the
+// subscription after the given one goes first
+func roundRobin(subscriptions []string, after string) []string {
+	sort.Strings(subscriptions)
+
+	start := sort.SearchStrings(subscriptions, after)
+	if start < len(subscriptions) && subscriptions[start] == after {
+		start++
+	}
+	return append(append([]string{}, subscriptions[start:]...), subscriptions[:start]...)
+}
+
+// SubscriptionQueueLength is the length of the queue of a subscription
+type SubscriptionQueueLength struct {
+	SubscriptionID string
+
+	// Queued is the number of documents of the subscription waiting to be
+	// dequeued, and Throttled the number of those which are creates held
+	// back by the per-subscription limit
+	Queued    int
+	Throttled int
+
+	// Running is the number of clusters in the subscription which are
+	// currently being created, and Limit the per-subscription create limit,
+	// or zero if there is none
+	Running int
+	Limit   int
+
+	// Position is the 1-based position of the subscription in the round
+	// robin order, i.e. the fair-share position of its next document
+	Position int
+}
+
+// queuedOpenShiftCluster is the projection of a queued document read by
+// QueueLength
+type queuedOpenShiftCluster struct {
+	PartitionKey      string                `json:"partitionKey,omitempty"`
+	ProvisioningState api.ProvisioningState `json:"provisioningState,omitempty"`
+}
+
+// subscriptionQueueLengths returns the queue length of each subscription with
+// queued documents, in round robin order starting with the first subscription
+// after the given one
+func subscriptionQueueLengths(queued []queuedOpenShiftCluster, running map[string]int, maxCreatesPerSubscription int, after string) []*SubscriptionQueueLength {
+	bySubscription := map[string]*SubscriptionQueueLength{}
+	creates := map[string]int{}
+	for _, q := range queued {
+		l := bySubscription[q.PartitionKey]
+		if l == nil {
+			l = &SubscriptionQueueLength{
+				SubscriptionID: q.PartitionKey,
+				Running:        running[q.PartitionKey],
+			}
+			if maxCreatesPerSubscription > 0 {
+				l.Limit = maxCreatesPerSubscription
+			}
+			bySubscription[q.PartitionKey] = l
+		}
+
+		l.Queued++
+		if q.ProvisioningState == api.ProvisioningStateCreating {
+			creates[q.PartitionKey]++
+		}
+	}
+
+	subscriptions := make([]string, 0, len(bySubscription))
+	for subscription := range bySubscription {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	lengths := make([]*SubscriptionQueueLength, 0, len(subscriptions))
+	for i, subscription := range roundRobin(subscriptions, after) {
+		l := bySubscription[subscription]
+		l.Position = i + 1
+
+		if l.Limit > 0 {
+			free := l.Limit - l.Running
+			if free < 0 {
+				free = 0
+			}
+			if creates[subscription] > free {
+				l.Throttled = creates[subscription] - free
+			}
+		}
+
+		lengths = append(lengths, l)
+	}
+
+	return lengths
+}
@@ -0,0 +1,264 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func TestFairShareQueue(t *testing.T) {
+	doc := func(subscription, name string, timestamp int, state api.ProvisioningState) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			Key:          subscription + "/" + name,
+			PartitionKey: subscription,
+			Timestamp:    timestamp,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: state,
+				},
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		docs    []*api.OpenShiftClusterDocument
+		running map[string]int
+		max     int
+		after   string
+		want    []string
+	}{
+		{
+			name: "empty",
+			want: []string{},
+		},
+		{
+			name: "round robin by subscription, oldest first",
+			docs: []*api.OpenShiftClusterDocument{
+				doc("a", "3", 3, api.ProvisioningStateCreating),
+				doc("a", "1", 1, api.ProvisioningStateCreating),
+				doc("a", "2", 2, api.ProvisioningStateCreating),
+				doc("b", "1", 4, api.ProvisioningStateUpdating),
+				doc("c", "1", 5, api.ProvisioningStateDeleting),
+			},
+			want: []string{
+				"1 a/1 running=0 throttled=false",
+				"2 b/1 running=0 throttled=false",
+				"3 c/1 running=0 throttled=false",
+				"4 a/2 running=0 throttled=false",
+				"5 a/3 running=0 throttled=false",
+			},
+		},
+		{
+			name: "starts after the last subscription dequeued",
+			docs: []*api.OpenShiftClusterDocument{
+				doc("a", "1", 1, api.ProvisioningStateCreating),
+				doc("b", "1", 2, api.ProvisioningStateCreating),
+				doc("c", "1", 3, api.ProvisioningStateCreating),
+			},
+			after: "a",
+			want: []string{
+				"1 b/1 running=0 throttled=false",
+				"2 c/1 running=0 throttled=false",
+				"3 a/1 running=0 throttled=false",
+			},
+		},
+		{
+			name: "creates beyond the limit are throttled, other operations are not",
+			docs: []*api.OpenShiftClusterDocument{
+				doc("a", "1", 1, api.ProvisioningStateCreating),
+				doc("a", "2", 2, api.ProvisioningStateDeleting),
+				doc("a", "3", 3, api.ProvisioningStateCreating),
+				doc("b", "1", 4, api.ProvisioningStateCreating),
+			},
+			running: map[string]int{"a": 1},
+			max:     2,
+			want: []string{
+				"1 a/1 running=1 throttled=false",
+				"2 b/1 running=0 throttled=false",
+				"3 a/2 running=1 throttled=false",
+				"4 a/3 running=1 throttled=true",
+			},
+		},
+		{
+			name: "ordered by enqueue time rather than last modification",
+			docs: []*api.OpenShiftClusterDocument{
+				// a/1 was queued first, but was written since when it was
+				// handed over between backends
+				func() *api.OpenShiftClusterDocument {
+					d := doc("a", "1", 10, api.ProvisioningStateCreating)
+					d.EnqueueTime = 1
+					return d
+				}(),
+				func() *api.OpenShiftClusterDocument {
+					d := doc("a", "2", 3, api.ProvisioningStateCreating)
+					d.EnqueueTime = 2
+					return d
+				}(),
+			},
+			running: map[string]int{"a": 1},
+			max:     2,
+			want: []string{
+				"1 a/1 running=1 throttled=false",
+				"2 a/2 running=1 throttled=true",
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := fairShareQueue(tt.docs, tt.running, tt.max, tt.after)
+
+			got := make([]string, 0, len(queue))
+			for _, q := range queue {
+				got = append(got, fmt.Sprintf("%d %s running=%d throttled=%t", q.Position, q.Doc.Key, q.Running, q.Throttled))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSubscriptionQueueLengths(t *testing.T) {
+	queued := func(subscription string, state api.ProvisioningState) queuedOpenShiftCluster {
+		return queuedOpenShiftCluster{
+			PartitionKey:      subscription,
+			ProvisioningState: state,
+		}
+	}
+
+	for _, tt := range []struct {
+		name    string
+		queued  []queuedOpenShiftCluster
+		running map[string]int
+		max     int
+		after   string
+		want    []*SubscriptionQueueLength
+	}{
+		{
+			name: "empty",
+			want: []*SubscriptionQueueLength{},
+		},
+		{
+			name: "no limit",
+			queued: []queuedOpenShiftCluster{
+				queued("a", api.ProvisioningStateCreating),
+				queued("b", api.ProvisioningStateCreating),
+				queued("a", api.ProvisioningStateCreating),
+			},
+			want: []*SubscriptionQueueLength{
+				{SubscriptionID: "a", Queued: 2, Position: 1},
+				{SubscriptionID: "b", Queued: 1, Position: 2},
+			},
+		},
+		{
+			name: "creates beyond the limit are throttled, other operations are not",
+			queued: []queuedOpenShiftCluster{
+				queued("a", api.ProvisioningStateCreating),
+				queued("a", api.ProvisioningStateDeleting),
+				queued("a", api.ProvisioningStateCreating),
+				queued("a", api.ProvisioningStateCreating),
+				queued("b", api.ProvisioningStateCreating),
+				queued("c", api.ProvisioningStateCreating),
+			},
+			running: map[string]int{"a": 1, "c": 3},
+			max:     2,
+			after:   "a",
+			want: []*SubscriptionQueueLength{
+				{SubscriptionID: "b", Queued: 1, Limit: 2, Position: 1},
+				{SubscriptionID: "c", Queued: 1, Throttled: 1, Running: 3, Limit: 2, Position: 2},
+				{SubscriptionID: "a", Queued: 4, Throttled: 2, Running: 1, Limit: 2, Position: 3},
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := subscriptionQueueLengths(tt.queued, tt.running, tt.max, tt.after)
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDequeueReadsLeasedDocument(t *testing.T) {
+	ctx := context.Background()
+
+	client := cosmosdb.NewFakeOpenShiftClusterDocumentClient(&codec.JsonHandle{})
+	client.SetTriggerHandler("renewLease", func(context.Context, *api.OpenShiftClusterDocument) error { return nil })
+
+	for i, name := range []string{"changed", "queued"} {
+		_, err := client.Create(ctx, "sub", &api.OpenShiftClusterDocument{
+			ID:           name,
+			Key:          "sub/" + name,
+			PartitionKey: "sub",
+			EnqueueTime:  i + 1,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateCreating,
+					KubeadminPassword: "secret",
+				},
+			},
+		}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the query only returns the projected fields; the document which is
+	// queued first has since been changed by someone else
+	client.SetQueryHandler(OpenShiftClustersDequeueQuery, func(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+		docs, err := client.ListAll(ctx, nil)
+		if err != nil {
+			return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+		}
+
+		var projected []*api.OpenShiftClusterDocument
+		for _, doc := range docs.OpenShiftClusterDocuments {
+			etag := doc.ETag
+			if doc.ID == "changed" {
+				etag = "stale"
+			}
+
+			projected = append(projected, &api.OpenShiftClusterDocument{
+				ID:           doc.ID,
+				Key:          doc.Key,
+				PartitionKey: doc.PartitionKey,
+				EnqueueTime:  doc.EnqueueTime,
+				ETag:         etag,
+				OpenShiftCluster: &api.OpenShiftCluster{
+					Properties: api.OpenShiftClusterProperties{
+						ProvisioningState: doc.OpenShiftCluster.Properties.ProvisioningState,
+					},
+				},
+			})
+		}
+		return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(projected, 0)
+	})
+
+	c := NewOpenShiftClustersWithProvidedClient(client, nil, "backend", nil)
+
+	doc, err := c.Dequeue(ctx, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if doc == nil || doc.ID != "queued" {
+		t.Fatalf("got %v, want the document queued", doc)
+	}
+	if doc.LeaseOwner != "backend" {
+		t.Errorf("got lease owner %q, want %q", doc.LeaseOwner, "backend")
+	}
+	if doc.OpenShiftCluster.Properties.KubeadminPassword != "secret" {
+		t.Error("leased document was not read in full")
+	}
+}
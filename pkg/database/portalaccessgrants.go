@@ -0,0 +1,130 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	PortalAccessGrantsListApprovedQuery = `SELECT * FROM PortalAccessGrants doc WHERE doc.portalAccessGrant.state = "Approved" AND doc.portalAccessGrant.username = @username AND doc.portalAccessGrant.resourceId = @resourceId`
+)
+
+type portalAccessGrants struct {
+	c             cosmosdb.PortalAccessGrantDocumentClient
+	uuidGenerator uuid.Generator
+}
+
+// PortalAccessGrants is the database interface for PortalAccessGrantDocuments
+type PortalAccessGrants interface {
+	Create(context.Context, *api.PortalAccessGrantDocument) (*api.PortalAccessGrantDocument, error)
+	Get(context.Context, string) (*api.PortalAccessGrantDocument, error)
+	Patch(context.Context, string, func(*api.PortalAccessGrantDocument) error) (*api.PortalAccessGrantDocument, error)
+	ListAll(context.Context) (*api.PortalAccessGrantDocuments, error)
+	GetActive(context.Context, string, string, time.Time) (*api.PortalAccessGrantDocument, error)
+	NewUUID() string
+}
+
+// NewPortalAccessGrants returns a new PortalAccessGrants
+func NewPortalAccessGrants(ctx context.Context, dbc cosmosdb.DatabaseClient, dbName string) (PortalAccessGrants, error) {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	documentClient := cosmosdb.NewPortalAccessGrantDocumentClient(collc, collPortalAccessGrants)
+	return NewPortalAccessGrantsWithProvidedClient(documentClient, uuid.DefaultGenerator), nil
+}
+
+func NewPortalAccessGrantsWithProvidedClient(client cosmosdb.PortalAccessGrantDocumentClient, uuidGenerator uuid.Generator) PortalAccessGrants {
+	return &portalAccessGrants{
+		c:             client,
+		uuidGenerator: uuidGenerator,
+	}
+}
+
+func (c *portalAccessGrants) NewUUID() string {
+	return c.uuidGenerator.Generate()
+}
+
+func (c *portalAccessGrants) Create(ctx context.Context, doc *api.PortalAccessGrantDocument) (*api.PortalAccessGrantDocument, error) {
+	if doc.ID != strings.ToLower(doc.ID) {
+		return nil, fmt.Errorf("id %q is not lower case", doc.ID)
+	}
+
+	return c.c.Create(ctx, doc.ID, doc, nil)
+}
+
+func (c *portalAccessGrants) Get(ctx context.Context, id string) (*api.PortalAccessGrantDocument, error) {
+	if id != strings.ToLower(id) {
+		return nil, fmt.Errorf("id %q is not lower case", id)
+	}
+
+	return c.c.Get(ctx, id, id, nil)
+}
+
+func (c *portalAccessGrants) Patch(ctx context.Context, id string, f func(*api.PortalAccessGrantDocument) error) (*api.PortalAccessGrantDocument, error) {
+	var doc *api.PortalAccessGrantDocument
+
+	err := cosmosdb.RetryOnPreconditionFailed(func() (err error) {
+		doc, err = c.Get(ctx, id)
+		if err != nil {
+			return
+		}
+
+		err = f(doc)
+		if err != nil {
+			return
+		}
+
+		doc, err = c.c.Replace(ctx, doc.ID, doc, nil)
+		return
+	})
+
+	return doc, err
+}
+
+func (c *portalAccessGrants) ListAll(ctx context.Context) (*api.PortalAccessGrantDocuments, error) {
+	return c.c.ListAll(ctx, nil)
+}
+
+// GetActive returns the approved grant for username's access to the cluster
+// with the given lower case resourceID whose window contains now, or nil if
+// there is none.  If several grants are active, the one ending last is
+// returned.
+func (c *portalAccessGrants) GetActive(ctx context.Context, username, resourceID string, now time.Time) (*api.PortalAccessGrantDocument, error) {
+	docs, err := c.c.QueryAll(ctx, "", &cosmosdb.Query{
+		Query: PortalAccessGrantsListApprovedQuery,
+		Parameters: []cosmosdb.Parameter{
+			{
+				Name:  "@username",
+				Value: username,
+			},
+			{
+				Name:  "@resourceId",
+				Value: resourceID,
+			},
+		},
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var active *api.PortalAccessGrantDocument
+	for _, doc := range docs.PortalAccessGrantDocuments {
+		if !doc.PortalAccessGrant.IsActive(now) {
+			continue
+		}
+
+		if active == nil || doc.PortalAccessGrant.NotAfter.After(active.PortalAccessGrant.NotAfter) {
+			active = doc
+		}
+	}
+
+	return active, nil
+}
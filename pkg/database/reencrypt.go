@@ -0,0 +1,276 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// Reencrypter re-seals the encrypted fields of every document with the current
+// encryption key, so that older key versions can be retired.
+type Reencrypter struct {
+	log  *logrus.Entry
+	m    metrics.Emitter
+	aead *encryption.Recorder
+
+	openShiftClusters            cosmosdb.OpenShiftClusterDocumentClient
+	clusterManagerConfigurations cosmosdb.ClusterManagerConfigurationDocumentClient
+	asyncOperations              cosmosdb.AsyncOperationDocumentClient
+}
+
+// ReencryptReport summarises a re-encryption run
+type ReencryptReport struct {
+	// SealKeyVersion is the key version all documents were re-sealed with
+	SealKeyVersion string
+
+	// Scanned and Resealed count the documents read and rewritten
+	Scanned  int
+	Resealed int
+
+	// InUse counts the encrypted values still sealed with each key version
+	// once the run completed
+	InUse map[string]int
+
+	// SafeToDelete lists the key versions no stored value depends on
+	SafeToDelete []string
+}
+
+// reencryptPage is a page of documents, each represented by a function which
+// writes it back
+type reencryptPage []func(context.Context) error
+
+type reencryptCollection struct {
+	name string
+	list func() func(context.Context) (reencryptPage, error)
+}
+
+// NewReencrypter returns a new Reencrypter.  dbc must have been created with
+// aead, which the Reencrypter uses to find out which key versions the
+// documents it reads depend on.
+func NewReencrypter(log *logrus.Entry, dbc cosmosdb.DatabaseClient, dbName string, aead *encryption.Recorder, m metrics.Emitter) *Reencrypter {
+	collc := cosmosdb.NewCollectionClient(dbc, dbName)
+
+	return NewReencrypterWithProvidedClients(log, aead, m,
+		cosmosdb.NewOpenShiftClusterDocumentClient(collc, collOpenShiftClusters),
+		cosmosdb.NewClusterManagerConfigurationDocumentClient(collc, collClusterManager),
+		cosmosdb.NewAsyncOperationDocumentClient(collc, collAsyncOperations),
+	)
+}
+
+func NewReencrypterWithProvidedClients(log *logrus.Entry, aead *encryption.Recorder, m metrics.Emitter, openShiftClusters cosmosdb.OpenShiftClusterDocumentClient, clusterManagerConfigurations cosmosdb.ClusterManagerConfigurationDocumentClient, asyncOperations cosmosdb.AsyncOperationDocumentClient) *Reencrypter {
+	return &Reencrypter{
+		log:  log,
+		m:    m,
+		aead: aead,
+
+		openShiftClusters:            openShiftClusters,
+		clusterManagerConfigurations: clusterManagerConfigurations,
+		asyncOperations:              asyncOperations,
+	}
+}
+
+// Run re-seals every document which holds a value sealed with an old key
+// version, then reads every document again to find out which key versions are
+// still in use.  Documents concurrently modified by another writer are
+// skipped; the verification pass reports them if they still depend on an old
+// key.
+func (r *Reencrypter) Run(ctx context.Context) (*ReencryptReport, error) {
+	report := &ReencryptReport{
+		SealKeyVersion: r.aead.SealKeyVersion(),
+		InUse:          map[string]int{},
+	}
+
+	r.log.Printf("re-sealing documents with key version %s", report.SealKeyVersion)
+
+	for _, c := range r.collections() {
+		err := r.reseal(ctx, c, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, c := range r.collections() {
+		err := r.verify(ctx, c, report)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	keyVersions := r.aead.KeyVersions()
+	sort.Strings(keyVersions)
+
+	for _, keyVersion := range keyVersions {
+		r.m.EmitGauge("reencrypt.keyversion.values", int64(report.InUse[keyVersion]), map[string]string{
+			"keyVersion": keyVersion,
+		})
+
+		switch {
+		case keyVersion == report.SealKeyVersion:
+		case report.InUse[keyVersion] > 0:
+			r.log.Printf("key version %s is still used by %d values", keyVersion, report.InUse[keyVersion])
+		default:
+			r.log.Printf("key version %s is safe to delete", keyVersion)
+			report.SafeToDelete = append(report.SafeToDelete, keyVersion)
+		}
+	}
+
+	return report, nil
+}
+
+func (r *Reencrypter) reseal(ctx context.Context, c *reencryptCollection, report *ReencryptReport) error {
+	var scanned, resealed int
+
+	next := c.list()
+	for {
+		r.aead.Reset()
+		page, err := next(ctx)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			break
+		}
+
+		scanned += len(page)
+
+		if !r.onlySealKeyVersion(r.aead.Reset()) {
+			for _, replace := range page {
+				err = replace(ctx)
+				if cosmosdb.IsErrorStatusCode(err, http.StatusPreconditionFailed) ||
+					cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+					continue
+				}
+				if err != nil {
+					return err
+				}
+
+				resealed++
+			}
+		}
+
+		r.log.Printf("%s: scanned %d documents, re-sealed %d", c.name, scanned, resealed)
+	}
+
+	r.m.EmitGauge("reencrypt.documents", int64(scanned), map[string]string{
+		"collection": c.name,
+		"state":      "scanned",
+	})
+	r.m.EmitGauge("reencrypt.documents", int64(resealed), map[string]string{
+		"collection": c.name,
+		"state":      "resealed",
+	})
+
+	report.Scanned += scanned
+	report.Resealed += resealed
+
+	return nil
+}
+
+func (r *Reencrypter) verify(ctx context.Context, c *reencryptCollection, report *ReencryptReport) error {
+	next := c.list()
+	for {
+		r.aead.Reset()
+		page, err := next(ctx)
+		if err != nil {
+			return err
+		}
+		if page == nil {
+			return nil
+		}
+
+		for keyVersion, n := range r.aead.Reset() {
+			report.InUse[keyVersion] += n
+		}
+	}
+}
+
+func (r *Reencrypter) onlySealKeyVersion(opened map[string]int) bool {
+	for keyVersion := range opened {
+		if keyVersion != r.aead.SealKeyVersion() {
+			return false
+		}
+	}
+	return true
+}
+
+func (r *Reencrypter) collections() []*reencryptCollection {
+	return []*reencryptCollection{
+		{
+			name: collOpenShiftClusters,
+			list: func() func(context.Context) (reencryptPage, error) {
+				i := r.openShiftClusters.List(nil)
+				return func(ctx context.Context) (reencryptPage, error) {
+					docs, err := i.Next(ctx, -1)
+					if err != nil || docs == nil {
+						return nil, err
+					}
+
+					page := make(reencryptPage, 0, len(docs.OpenShiftClusterDocuments))
+					for _, doc := range docs.OpenShiftClusterDocuments {
+						doc := doc
+						page = append(page, func(ctx context.Context) error {
+							_, err := r.openShiftClusters.Replace(ctx, doc.PartitionKey, doc, nil)
+							return err
+						})
+					}
+					return page, nil
+				}
+			},
+		},
+		{
+			name: collClusterManager,
+			list: func() func(context.Context) (reencryptPage, error) {
+				i := r.clusterManagerConfigurations.List(nil)
+				return func(ctx context.Context) (reencryptPage, error) {
+					docs, err := i.Next(ctx, -1)
+					if err != nil || docs == nil {
+						return nil, err
+					}
+
+					page := make(reencryptPage, 0, len(docs.ClusterManagerConfigurationDocuments))
+					for _, doc := range docs.ClusterManagerConfigurationDocuments {
+						doc := doc
+						page = append(page, func(ctx context.Context) error {
+							_, err := r.clusterManagerConfigurations.Replace(ctx, doc.PartitionKey, doc, nil)
+							return err
+						})
+					}
+					return page, nil
+				}
+			},
+		},
+		{
+			// asynchronous operations hold a copy of the cluster taken when
+			// the operation ended, including its secrets
+			name: collAsyncOperations,
+			list: func() func(context.Context) (reencryptPage, error) {
+				i := r.asyncOperations.List(nil)
+				return func(ctx context.Context) (reencryptPage, error) {
+					docs, err := i.Next(ctx, -1)
+					if err != nil || docs == nil {
+						return nil, err
+					}
+
+					page := make(reencryptPage, 0, len(docs.AsyncOperationDocuments))
+					for _, doc := range docs.AsyncOperationDocuments {
+						doc := doc
+						page = append(page, func(ctx context.Context) error {
+							_, err := r.asyncOperations.Replace(ctx, doc.ID, doc, nil)
+							return err
+						})
+					}
+					return page, nil
+				}
+			},
+		},
+	}
+}
@@ -0,0 +1,238 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ugorji/go/codec"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+)
+
+// versionedAEAD "seals" values by prefixing them with the sealing key version
+type versionedAEAD struct {
+	seal string
+}
+
+func (a *versionedAEAD) Open(input []byte) ([]byte, error) {
+	b, _, err := a.OpenWithKeyVersion(input)
+	return b, err
+}
+
+func (a *versionedAEAD) OpenWithKeyVersion(input []byte) ([]byte, string, error) {
+	keyVersion, b, found := strings.Cut(string(input), ":")
+	if !found {
+		return nil, "", errors.New("invalid input")
+	}
+	return []byte(b), keyVersion, nil
+}
+
+func (a *versionedAEAD) Seal(input []byte) ([]byte, error) {
+	return []byte(a.seal + ":" + string(input)), nil
+}
+
+func (a *versionedAEAD) SealKeyVersion() string {
+	return a.seal
+}
+
+func (a *versionedAEAD) KeyVersions() []string {
+	return []string{"key/1", "key/2"}
+}
+
+// rawOpenShiftClusters stores documents encoded, so that encrypted values keep
+// the key version they were sealed with
+type rawOpenShiftClusters struct {
+	cosmosdb.OpenShiftClusterDocumentClient
+
+	h    *codec.JsonHandle
+	docs map[string][]byte
+}
+
+func (c *rawOpenShiftClusters) List(*cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentIterator {
+	return &rawOpenShiftClustersIterator{c: c}
+}
+
+func (c *rawOpenShiftClusters) Replace(ctx context.Context, partitionKey string, doc *api.OpenShiftClusterDocument, options *cosmosdb.Options) (*api.OpenShiftClusterDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.h).Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.docs[doc.ID] = b
+	return doc, nil
+}
+
+type rawOpenShiftClustersIterator struct {
+	c    *rawOpenShiftClusters
+	done bool
+}
+
+func (i *rawOpenShiftClustersIterator) Next(ctx context.Context, maxItemCount int) (*api.OpenShiftClusterDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+	i.done = true
+
+	docs := &api.OpenShiftClusterDocuments{}
+	for _, b := range i.c.docs {
+		var doc *api.OpenShiftClusterDocument
+		err := codec.NewDecoderBytes(b, i.c.h).Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+		docs.OpenShiftClusterDocuments = append(docs.OpenShiftClusterDocuments, doc)
+	}
+	docs.Count = len(docs.OpenShiftClusterDocuments)
+
+	return docs, nil
+}
+
+func (i *rawOpenShiftClustersIterator) Continuation() string {
+	return ""
+}
+
+// rawAsyncOperations stores documents encoded, like rawOpenShiftClusters
+type rawAsyncOperations struct {
+	cosmosdb.AsyncOperationDocumentClient
+
+	h    *codec.JsonHandle
+	docs map[string][]byte
+}
+
+func (c *rawAsyncOperations) List(*cosmosdb.Options) cosmosdb.AsyncOperationDocumentIterator {
+	return &rawAsyncOperationsIterator{c: c}
+}
+
+func (c *rawAsyncOperations) Replace(ctx context.Context, partitionKey string, doc *api.AsyncOperationDocument, options *cosmosdb.Options) (*api.AsyncOperationDocument, error) {
+	var b []byte
+	err := codec.NewEncoderBytes(&b, c.h).Encode(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	c.docs[doc.ID] = b
+	return doc, nil
+}
+
+type rawAsyncOperationsIterator struct {
+	c    *rawAsyncOperations
+	done bool
+}
+
+func (i *rawAsyncOperationsIterator) Next(ctx context.Context, maxItemCount int) (*api.AsyncOperationDocuments, error) {
+	if i.done {
+		return nil, nil
+	}
+	i.done = true
+
+	docs := &api.AsyncOperationDocuments{}
+	for _, b := range i.c.docs {
+		var doc *api.AsyncOperationDocument
+		err := codec.NewDecoderBytes(b, i.c.h).Decode(&doc)
+		if err != nil {
+			return nil, err
+		}
+		docs.AsyncOperationDocuments = append(docs.AsyncOperationDocuments, doc)
+	}
+	docs.Count = len(docs.AsyncOperationDocuments)
+
+	return docs, nil
+}
+
+func (i *rawAsyncOperationsIterator) Continuation() string {
+	return ""
+}
+
+func TestReencrypter(t *testing.T) {
+	ctx := context.Background()
+
+	oldh, err := NewJSONHandle(&versionedAEAD{seal: "key/1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recorder := encryption.NewRecorder(&versionedAEAD{seal: "key/2"})
+	h, err := NewJSONHandle(recorder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	openShiftClusters := &rawOpenShiftClusters{h: oldh, docs: map[string][]byte{}}
+	for _, doc := range []*api.OpenShiftClusterDocument{
+		{
+			ID: "sealed",
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					ClusterProfile:  api.ClusterProfile{PullSecret: "pullsecret"},
+					AdminKubeconfig: api.SecureBytes("kubeconfig"),
+				},
+			},
+		},
+		{
+			ID:               "unsealed",
+			OpenShiftCluster: &api.OpenShiftCluster{},
+		},
+	} {
+		_, err = openShiftClusters.Replace(ctx, "", doc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	openShiftClusters.h = h
+
+	asyncOperations := &rawAsyncOperations{h: oldh, docs: map[string][]byte{}}
+	_, err = asyncOperations.Replace(ctx, "operation", &api.AsyncOperationDocument{
+		ID:             "operation",
+		AsyncOperation: &api.AsyncOperation{},
+		OpenShiftCluster: &api.OpenShiftCluster{
+			Properties: api.OpenShiftClusterProperties{
+				ClusterProfile: api.ClusterProfile{PullSecret: "pullsecret"},
+			},
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	asyncOperations.h = h
+
+	r := NewReencrypterWithProvidedClients(utillog.GetLogger(), recorder, &noop.Noop{},
+		openShiftClusters, cosmosdb.NewFakeClusterManagerConfigurationDocumentClient(h), asyncOperations)
+
+	report, err := r.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := &ReencryptReport{
+		SealKeyVersion: "key/2",
+		Scanned:        3,
+		Resealed:       3,
+		InUse:          map[string]int{"key/2": 3},
+		SafeToDelete:   []string{"key/1"},
+	}
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %#v, want %#v", report, want)
+	}
+
+	// a second run has nothing left to do
+	report, err = r.Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want.Resealed = 0
+	if !reflect.DeepEqual(report, want) {
+		t.Errorf("got %#v, want %#v", report, want)
+	}
+}
@@ -6,29 +6,32 @@ package dbtoken
 import (
 	"context"
 	"net/http"
-	"os"
 
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 )
 
-func ConfigurePermissions(ctx context.Context, dbid string, userc cosmosdb.UserClient) error {
-	gateway := os.Getenv("AZURE_GATEWAY_SERVICE_PRINCIPAL_ID")
+// ConfigurePermissions creates a Cosmos DB user for each identity in the
+// policy and creates or updates its permissions to match the policy.
+func ConfigurePermissions(ctx context.Context, dbid string, userc cosmosdb.UserClient, policy *Policy) error {
+	for _, identity := range policy.Identities {
+		_, err := userc.Create(ctx, &cosmosdb.User{
+			ID: identity.ObjectID,
+		})
+		if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
+			return err
+		}
 
-	_, err := userc.Create(ctx, &cosmosdb.User{
-		ID: gateway,
-	})
-	if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
-		return err
-	}
-
-	permc := cosmosdb.NewPermissionClient(userc, gateway)
-	_, err = permc.Create(ctx, &cosmosdb.Permission{
-		ID:             "gateway",
-		PermissionMode: cosmosdb.PermissionModeRead,
-		Resource:       "dbs/" + dbid + "/colls/Gateway",
-	})
-	if err != nil && !cosmosdb.IsErrorStatusCode(err, http.StatusConflict) {
-		return err
+		permc := cosmosdb.NewPermissionTokenClient(userc, identity.ObjectID)
+		for _, perm := range identity.Permissions {
+			_, err = permc.Upsert(ctx, &cosmosdb.Permission{
+				ID:             perm.Name,
+				PermissionMode: perm.Mode.permissionMode(),
+				Resource:       resource(dbid, perm.Collection),
+			}, perm.PartitionKey)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
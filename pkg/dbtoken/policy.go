@@ -0,0 +1,191 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	// DefaultTokenLifetime matches the Cosmos DB default resource token
+	// lifetime
+	DefaultTokenLifetime = time.Hour
+
+	// maxTokenLifetime is the longest lifetime Cosmos DB will issue a resource
+	// token for
+	maxTokenLifetime = 5 * time.Hour
+)
+
+// PolicyMode is the access a policy permission grants on its collection
+type PolicyMode string
+
+const (
+	PolicyModeRead  PolicyMode = "read"
+	PolicyModeWrite PolicyMode = "write"
+)
+
+// Policy declares which Cosmos DB permissions each AAD identity may be issued
+// tokens for.  Identities not listed in the policy are refused.
+type Policy struct {
+	// TokenLifetimeSeconds is the lifetime of issued tokens unless overridden
+	// by a permission.  If zero, DefaultTokenLifetime is used.
+	TokenLifetimeSeconds int `json:"tokenLifetimeSeconds,omitempty"`
+
+	Identities []PolicyIdentity `json:"identities,omitempty"`
+}
+
+// PolicyIdentity grants permissions to the AAD identity with the given object
+// ID
+type PolicyIdentity struct {
+	ObjectID    string             `json:"objectId,omitempty"`
+	Permissions []PolicyPermission `json:"permissions,omitempty"`
+}
+
+// PolicyPermission is a named permission on a collection, optionally
+// restricted to a single partition key
+type PolicyPermission struct {
+	Name                 string     `json:"name,omitempty"`
+	Collection           string     `json:"collection,omitempty"`
+	PartitionKey         string     `json:"partitionKey,omitempty"`
+	Mode                 PolicyMode `json:"mode,omitempty"`
+	TokenLifetimeSeconds int        `json:"tokenLifetimeSeconds,omitempty"`
+}
+
+// DefaultPolicy returns the policy used when none is configured: the gateway
+// may read the Gateway collection.
+func DefaultPolicy(gatewayObjectID string) *Policy {
+	return &Policy{
+		Identities: []PolicyIdentity{
+			{
+				ObjectID: gatewayObjectID,
+				Permissions: []PolicyPermission{
+					{
+						Name:       "gateway",
+						Collection: "Gateway",
+						Mode:       PolicyModeRead,
+					},
+				},
+			},
+		},
+	}
+}
+
+// ParsePolicy parses and validates a JSON encoded policy
+func ParsePolicy(b []byte) (*Policy, error) {
+	var p *Policy
+	err := json.Unmarshal(b, &p)
+	if err != nil {
+		return nil, err
+	}
+
+	if p == nil {
+		return nil, fmt.Errorf("policy must not be empty")
+	}
+
+	return p, p.Validate()
+}
+
+// Validate checks the policy is well formed
+func (p *Policy) Validate() error {
+	err := validateLifetime("tokenLifetimeSeconds", p.TokenLifetimeSeconds)
+	if err != nil {
+		return err
+	}
+
+	objectIDs := map[string]struct{}{}
+	for i, identity := range p.Identities {
+		path := fmt.Sprintf("identities[%d]", i)
+
+		if !uuid.IsValid(identity.ObjectID) {
+			return fmt.Errorf("%s.objectId: invalid object ID %q", path, identity.ObjectID)
+		}
+		if _, ok := objectIDs[strings.ToLower(identity.ObjectID)]; ok {
+			return fmt.Errorf("%s.objectId: duplicate object ID %q", path, identity.ObjectID)
+		}
+		objectIDs[strings.ToLower(identity.ObjectID)] = struct{}{}
+
+		names := map[string]struct{}{}
+		for j, perm := range identity.Permissions {
+			path := fmt.Sprintf("%s.permissions[%d]", path, j)
+
+			if !rxValidPermission.MatchString(perm.Name) {
+				return fmt.Errorf("%s.name: invalid name %q", path, perm.Name)
+			}
+			if _, ok := names[perm.Name]; ok {
+				return fmt.Errorf("%s.name: duplicate name %q", path, perm.Name)
+			}
+			names[perm.Name] = struct{}{}
+
+			if perm.Collection == "" || strings.ContainsAny(perm.Collection, "/\\?#") {
+				return fmt.Errorf("%s.collection: invalid collection %q", path, perm.Collection)
+			}
+
+			switch perm.Mode {
+			case PolicyModeRead, PolicyModeWrite:
+			default:
+				return fmt.Errorf("%s.mode: invalid mode %q", path, perm.Mode)
+			}
+
+			err = validateLifetime(path+".tokenLifetimeSeconds", perm.TokenLifetimeSeconds)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateLifetime(path string, seconds int) error {
+	if seconds < 0 || time.Duration(seconds)*time.Second > maxTokenLifetime {
+		return fmt.Errorf("%s: lifetime must be between 0 and %d seconds", path, int(maxTokenLifetime.Seconds()))
+	}
+	return nil
+}
+
+// permission returns the named permission granted to objectID, if any
+func (p *Policy) permission(objectID, name string) (*PolicyPermission, bool) {
+	for _, identity := range p.Identities {
+		if !strings.EqualFold(identity.ObjectID, objectID) {
+			continue
+		}
+
+		for i := range identity.Permissions {
+			if identity.Permissions[i].Name == name {
+				return &identity.Permissions[i], true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// tokenLifetime returns the lifetime of tokens issued for perm
+func (p *Policy) tokenLifetime(perm *PolicyPermission) time.Duration {
+	switch {
+	case perm.TokenLifetimeSeconds != 0:
+		return time.Duration(perm.TokenLifetimeSeconds) * time.Second
+	case p.TokenLifetimeSeconds != 0:
+		return time.Duration(p.TokenLifetimeSeconds) * time.Second
+	default:
+		return DefaultTokenLifetime
+	}
+}
+
+func (m PolicyMode) permissionMode() cosmosdb.PermissionMode {
+	if m == PolicyModeWrite {
+		return cosmosdb.PermissionModeAll
+	}
+	return cosmosdb.PermissionModeRead
+}
+
+func resource(dbid, collection string) string {
+	return "dbs/" + dbid + "/colls/" + collection
+}
@@ -0,0 +1,102 @@
+package dbtoken
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestParsePolicy(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		policy  string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			policy: `{"tokenLifetimeSeconds": 900, "identities": [{"objectId": "00000000-0000-0000-0000-000000000000", "permissions": [
+				{"name": "gateway", "collection": "Gateway", "mode": "read"},
+				{"name": "portal", "collection": "Portal", "partitionKey": "portal", "mode": "write", "tokenLifetimeSeconds": 300}
+			]}]}`,
+		},
+		{
+			name:    "empty",
+			policy:  `null`,
+			wantErr: "policy must not be empty",
+		},
+		{
+			name:    "invalid object ID",
+			policy:  `{"identities": [{"objectId": "gateway"}]}`,
+			wantErr: `identities[0].objectId: invalid object ID "gateway"`,
+		},
+		{
+			name:    "duplicate object ID",
+			policy:  `{"identities": [{"objectId": "00000000-0000-0000-0000-000000000000"}, {"objectId": "00000000-0000-0000-0000-000000000000"}]}`,
+			wantErr: `identities[1].objectId: duplicate object ID "00000000-0000-0000-0000-000000000000"`,
+		},
+		{
+			name:    "invalid name",
+			policy:  `{"identities": [{"objectId": "00000000-0000-0000-0000-000000000000", "permissions": [{"name": "Gateway!", "collection": "Gateway", "mode": "read"}]}]}`,
+			wantErr: `identities[0].permissions[0].name: invalid name "Gateway!"`,
+		},
+		{
+			name:    "invalid collection",
+			policy:  `{"identities": [{"objectId": "00000000-0000-0000-0000-000000000000", "permissions": [{"name": "gateway", "collection": "Gateway/docs", "mode": "read"}]}]}`,
+			wantErr: `identities[0].permissions[0].collection: invalid collection "Gateway/docs"`,
+		},
+		{
+			name:    "invalid mode",
+			policy:  `{"identities": [{"objectId": "00000000-0000-0000-0000-000000000000", "permissions": [{"name": "gateway", "collection": "Gateway", "mode": "admin"}]}]}`,
+			wantErr: `identities[0].permissions[0].mode: invalid mode "admin"`,
+		},
+		{
+			name:    "lifetime too long",
+			policy:  `{"tokenLifetimeSeconds": 86400}`,
+			wantErr: "tokenLifetimeSeconds: lifetime must be between 0 and 18000 seconds",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePolicy([]byte(tt.policy))
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestPolicyTokenLifetime(t *testing.T) {
+	p := &Policy{
+		Identities: []PolicyIdentity{
+			{
+				ObjectID: "00000000-0000-0000-0000-000000000000",
+				Permissions: []PolicyPermission{
+					{Name: "default", Collection: "Gateway", Mode: PolicyModeRead},
+					{Name: "short", Collection: "Gateway", Mode: PolicyModeRead, TokenLifetimeSeconds: 60},
+				},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		name                 string
+		tokenLifetimeSeconds int
+		want                 time.Duration
+	}{
+		{name: "default", want: DefaultTokenLifetime},
+		{name: "default", tokenLifetimeSeconds: 600, want: 10 * time.Minute},
+		{name: "short", tokenLifetimeSeconds: 600, want: time.Minute},
+	} {
+		p.TokenLifetimeSeconds = tt.tokenLifetimeSeconds
+
+		perm, ok := p.permission("00000000-0000-0000-0000-000000000000", tt.name)
+		if !ok {
+			t.Fatal(tt.name)
+		}
+
+		if got := p.tokenLifetime(perm); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/metrics"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
 )
@@ -38,9 +39,11 @@ type server struct {
 	env                     env.Core
 	log                     *logrus.Entry
 	accessLog               *logrus.Entry
+	auditLog                *logrus.Entry
 	l                       net.Listener
 	verifier                oidc.Verifier
-	permissionClientFactory func(userid string) cosmosdb.PermissionClient
+	policy                  *Policy
+	permissionClientFactory func(userid string) cosmosdb.PermissionTokenClient
 	m                       metrics.Emitter
 }
 
@@ -49,11 +52,13 @@ func NewServer(
 	env env.Core,
 	log *logrus.Entry,
 	accessLog *logrus.Entry,
+	auditLog *logrus.Entry,
 	l net.Listener,
 	servingKey *rsa.PrivateKey,
 	servingCerts []*x509.Certificate,
 	verifier oidc.Verifier,
 	userc cosmosdb.UserClient,
+	policy *Policy,
 	m metrics.Emitter,
 ) (Server, error) {
 	config := &tls.Config{
@@ -79,10 +84,12 @@ func NewServer(
 		env:       env,
 		log:       log,
 		accessLog: accessLog,
+		auditLog:  auditLog,
 		l:         tls.NewListener(l, config),
 		verifier:  verifier,
-		permissionClientFactory: func(userid string) cosmosdb.PermissionClient {
-			return cosmosdb.NewPermissionClient(userc, userid)
+		policy:    policy,
+		permissionClientFactory: func(userid string) cosmosdb.PermissionTokenClient {
+			return cosmosdb.NewPermissionTokenClient(userc, userid)
 		},
 		m: m,
 	}, nil
@@ -145,6 +152,8 @@ func (s *server) authenticate(h http.Handler) http.Handler {
 	})
 }
 
+// token issues a resource token for the requested permission, provided the
+// policy grants it to the caller.  Every issuance or refusal is audit logged.
 func (s *server) token(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -155,11 +164,21 @@ func (s *server) token(w http.ResponseWriter, r *http.Request) {
 	}
 
 	username, _ := ctx.Value(middleware.ContextKeyUsername).(string)
+
+	perm, ok := s.policy.permission(username, permission)
+	if !ok {
+		s.audit(r, username, permission, nil, 0, audit.ResultTypeFail, "permission not granted by policy")
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return
+	}
+
+	lifetime := s.policy.tokenLifetime(perm)
 	permc := s.permissionClientFactory(username)
 
-	perm, err := permc.Get(ctx, permission)
+	p, err := permc.GetToken(ctx, permission, lifetime)
 	if err != nil {
 		s.log.Error(err)
+		s.audit(r, username, permission, perm, lifetime, audit.ResultTypeFail, "permission lookup failed")
 		if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
 			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
 		} else {
@@ -168,12 +187,58 @@ func (s *server) token(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	s.audit(r, username, permission, perm, lifetime, audit.ResultTypeSuccess, "token issued")
+
 	w.Header().Set("Content-Type", "application/json")
 
 	e := json.NewEncoder(w)
 	e.SetIndent("", "    ")
 
 	_ = e.Encode(&tokenResponse{
-		Token: perm.Token,
+		Token: p.Token,
 	})
 }
+
+func (s *server) audit(r *http.Request, username, permission string, perm *PolicyPermission, lifetime time.Duration, resultType, description string) {
+	fields := logrus.Fields{
+		audit.MetadataCreatedTime:     time.Now().UTC().Format(time.RFC3339),
+		audit.MetadataLogKind:         audit.IFXAuditLogKind,
+		audit.MetadataSource:          audit.SourceDBToken,
+		audit.EnvKeyAppID:             audit.SourceDBToken,
+		audit.EnvKeyCloudRole:         audit.CloudRoleRP,
+		audit.PayloadKeyCategory:      audit.CategoryAuthorization,
+		audit.PayloadKeyOperationName: "issue token " + permission,
+		audit.PayloadKeyCallerIdentities: []audit.CallerIdentity{
+			{
+				CallerIdentityType:  audit.CallerIdentityTypeObjectID,
+				CallerIdentityValue: username,
+				CallerIPAddress:     r.RemoteAddr,
+			},
+		},
+		audit.PayloadKeyResult: audit.Result{
+			ResultType:        resultType,
+			ResultDescription: description,
+		},
+		"permission": permission,
+	}
+
+	if s.env != nil {
+		fields[audit.EnvKeyEnvironment] = s.env.Environment().Name
+		fields[audit.EnvKeyHostname] = s.env.Hostname()
+		fields[audit.EnvKeyLocation] = s.env.Location()
+	}
+
+	if perm != nil {
+		fields[audit.PayloadKeyTargetResources] = []audit.TargetResource{
+			{
+				TargetResourceType: "collection",
+				TargetResourceName: perm.Collection,
+			},
+		}
+		fields["partitionKey"] = perm.PartitionKey
+		fields["mode"] = perm.Mode
+		fields["tokenLifetime"] = lifetime.Seconds()
+	}
+
+	s.auditLog.WithFields(fields).Info(audit.DefaultLogMessage)
+}
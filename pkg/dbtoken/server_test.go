@@ -10,14 +10,17 @@ import (
 	"net/http"
 	"net/url"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/sirupsen/logrus"
 
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/util/log/audit"
 	mock_cosmosdb "github.com/Azure/ARO-RP/pkg/util/mocks/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/util/oidc"
 	"github.com/Azure/ARO-RP/test/util/listener"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
 )
 
 func TestServer(t *testing.T) {
@@ -25,10 +28,11 @@ func TestServer(t *testing.T) {
 
 	for _, tt := range []struct {
 		name                    string
-		permissionClientFactory func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient
+		permissionClientFactory func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionTokenClient
 		req                     *http.Request
 		wantStatusCode          int
 		wantToken               string
+		wantAuditResult         string
 	}{
 		{
 			name: "GET /random returns 404",
@@ -142,12 +146,46 @@ func TestServer(t *testing.T) {
 			},
 			wantStatusCode: http.StatusBadRequest,
 		},
+		{
+			name: "POST /token?permission=other returns 403 (not granted by policy)",
+			req: &http.Request{
+				Method: http.MethodPost,
+				URL: &url.URL{
+					Scheme:   "http",
+					Host:     "localhost",
+					Path:     "/token",
+					RawQuery: "permission=other",
+				},
+				Header: http.Header{
+					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
+				},
+			},
+			wantStatusCode:  http.StatusForbidden,
+			wantAuditResult: audit.ResultTypeFail,
+		},
+		{
+			name: "POST /token?permission=perm returns 403 (identity not in policy)",
+			req: &http.Request{
+				Method: http.MethodPost,
+				URL: &url.URL{
+					Scheme:   "http",
+					Host:     "localhost",
+					Path:     "/token",
+					RawQuery: "permission=perm",
+				},
+				Header: http.Header{
+					"Authorization": []string{`Bearer {"sub": "11111111-1111-1111-1111-111111111111"}`},
+				},
+			},
+			wantStatusCode:  http.StatusForbidden,
+			wantAuditResult: audit.ResultTypeFail,
+		},
 		{
 			name: "POST /token?permission=notexist returns 400",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "notexist").Return(nil, &cosmosdb.Error{StatusCode: http.StatusNotFound})
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionTokenClient {
+				return func(userid string) cosmosdb.PermissionTokenClient {
+					permc := mock_cosmosdb.NewMockPermissionTokenClient(controller)
+					permc.EXPECT().GetToken(gomock.Any(), "notexist", time.Hour).Return(nil, &cosmosdb.Error{StatusCode: http.StatusNotFound})
 					return permc
 				}
 			},
@@ -163,14 +201,15 @@ func TestServer(t *testing.T) {
 					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
 				},
 			},
-			wantStatusCode: http.StatusBadRequest,
+			wantStatusCode:  http.StatusBadRequest,
+			wantAuditResult: audit.ResultTypeFail,
 		},
 		{
 			name: "POST /token?permission=perm and database error returns 500",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(nil, errors.New("sad database"))
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionTokenClient {
+				return func(userid string) cosmosdb.PermissionTokenClient {
+					permc := mock_cosmosdb.NewMockPermissionTokenClient(controller)
+					permc.EXPECT().GetToken(gomock.Any(), "perm", 10*time.Minute).Return(nil, errors.New("sad database"))
 					return permc
 				}
 			},
@@ -186,14 +225,15 @@ func TestServer(t *testing.T) {
 					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
 				},
 			},
-			wantStatusCode: http.StatusInternalServerError,
+			wantStatusCode:  http.StatusInternalServerError,
+			wantAuditResult: audit.ResultTypeFail,
 		},
 		{
 			name: "get /token?permission=perm returns 405",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(&cosmosdb.Permission{
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionTokenClient {
+				return func(userid string) cosmosdb.PermissionTokenClient {
+					permc := mock_cosmosdb.NewMockPermissionTokenClient(controller)
+					permc.EXPECT().GetToken(gomock.Any(), "perm", 10*time.Minute).Return(&cosmosdb.Permission{
 						Token: "token",
 					}, nil)
 					return permc
@@ -215,10 +255,10 @@ func TestServer(t *testing.T) {
 		},
 		{
 			name: "POST /token?permission=perm returns 200",
-			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionClient {
-				return func(userid string) cosmosdb.PermissionClient {
-					permc := mock_cosmosdb.NewMockPermissionClient(controller)
-					permc.EXPECT().Get(gomock.Any(), "perm").Return(&cosmosdb.Permission{
+			permissionClientFactory: func(controller *gomock.Controller) func(userid string) cosmosdb.PermissionTokenClient {
+				return func(userid string) cosmosdb.PermissionTokenClient {
+					permc := mock_cosmosdb.NewMockPermissionTokenClient(controller)
+					permc.EXPECT().GetToken(gomock.Any(), "perm", 10*time.Minute).Return(&cosmosdb.Permission{
 						Token: "token",
 					}, nil)
 					return permc
@@ -236,8 +276,9 @@ func TestServer(t *testing.T) {
 					"Authorization": []string{`Bearer {"sub": "00000000-0000-0000-0000-000000000000"}`},
 				},
 			},
-			wantStatusCode: http.StatusOK,
-			wantToken:      "token",
+			wantStatusCode:  http.StatusOK,
+			wantToken:       "token",
+			wantAuditResult: audit.ResultTypeSuccess,
 		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
@@ -247,11 +288,25 @@ func TestServer(t *testing.T) {
 			l := listener.NewListener()
 			defer l.Close()
 
+			auditHook, auditLog := testlog.New()
+
 			s := &server{
 				log:       logrus.NewEntry(logrus.StandardLogger()),
 				accessLog: logrus.NewEntry(logrus.StandardLogger()),
+				auditLog:  auditLog,
 				l:         l,
 				verifier:  &oidc.NoopVerifier{},
+				policy: &Policy{
+					Identities: []PolicyIdentity{
+						{
+							ObjectID: "00000000-0000-0000-0000-000000000000",
+							Permissions: []PolicyPermission{
+								{Name: "perm", Collection: "Gateway", Mode: PolicyModeRead, TokenLifetimeSeconds: 600},
+								{Name: "notexist", Collection: "Gateway", Mode: PolicyModeRead},
+							},
+						},
+					},
+				},
 			}
 
 			if tt.permissionClientFactory != nil {
@@ -278,6 +333,17 @@ func TestServer(t *testing.T) {
 				t.Error(resp.StatusCode)
 			}
 
+			switch entries := auditHook.AllEntries(); {
+			case tt.wantAuditResult == "" && len(entries) != 0:
+				t.Errorf("unexpected audit entries %v", entries)
+			case tt.wantAuditResult != "" && len(entries) != 1:
+				t.Errorf("got %d audit entries, want 1", len(entries))
+			case tt.wantAuditResult != "":
+				if result := entries[0].Data[audit.PayloadKeyResult].(audit.Result); result.ResultType != tt.wantAuditResult {
+					t.Error(result)
+				}
+			}
+
 			if tt.wantToken == "" {
 				return
 			}
@@ -50,6 +50,7 @@ const (
 	PortalServerClientSecretName     = "portal-client"
 	PortalServerSessionKeySecretName = "portal-session-key"
 	PortalServerSSHKeySecretName     = "portal-sshkey"
+	PortalRecordingKeySecretName     = "portal-recording-key"
 	ClusterKeyvaultSuffix            = "-cls"
 	DBTokenKeyvaultSuffix            = "-dbt"
 	GatewayKeyvaultSuffix            = "-gwy"
@@ -94,6 +95,7 @@ type Interface interface {
 	ACRDomain() string
 	AROOperatorImage() string
 	LiveConfig() liveconfig.Manager
+	UpgradeGraphURL() string
 
 	// VMSku returns SKU for a given vm size. Note that this
 	// returns a pointer to partly populated object.
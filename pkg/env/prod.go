@@ -21,6 +21,7 @@ import (
 	"github.com/jongio/azidext/go/azidext"
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/mirror"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient/mgmt/compute"
 	"github.com/Azure/ARO-RP/pkg/util/clientauthorizer"
@@ -343,6 +344,16 @@ func (p *prod) GatewayResourceGroup() string {
 	return os.Getenv("GATEWAY_RESOURCEGROUP")
 }
 
+// UpgradeGraphURL returns the Cincinnati endpoint which upgrades are checked
+// against, defaulting to the production endpoint
+func (p *prod) UpgradeGraphURL() string {
+	if url := os.Getenv("UPGRADE_GRAPH_URL"); url != "" {
+		return url
+	}
+
+	return mirror.DefaultUpgradeGraphURL
+}
+
 func (p *prod) ServiceKeyvault() keyvault.Manager {
 	return p.serviceKeyvault
 }
@@ -0,0 +1,89 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/admin"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+func (f *frontend) getAdminBatchOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminBatchOperation(ctx, chi.URLParam(r, "batchOperationId"))
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _getAdminBatchOperation(ctx context.Context, id string) ([]byte, error) {
+	converter := f.apis[admin.APIVersion].BatchOperationConverter
+
+	if !uuid.IsValid(id) {
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The batch operation '%s' was not found.", id)
+	}
+
+	doc, err := f.dbBatchOperations.Get(ctx, id)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The batch operation '%s' was not found.", id)
+	case err != nil:
+		return nil, err
+	}
+
+	return json.MarshalIndent(converter.ToExternal(doc.BatchOperation), "", "    ")
+}
+
+func (f *frontend) getAdminBatchOperations(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._getAdminBatchOperations(ctx, api.BatchOperationState(r.URL.Query().Get("state")))
+
+	adminReply(log, w, nil, b, err)
+}
+
+// _getAdminBatchOperations lists batch operations, newest first, optionally
+// filtered by state
+func (f *frontend) _getAdminBatchOperations(ctx context.Context, state api.BatchOperationState) ([]byte, error) {
+	converter := f.apis[admin.APIVersion].BatchOperationConverter
+
+	docs, err := f.dbBatchOperations.ListAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var bos []*api.BatchOperation
+	if docs != nil {
+		for _, doc := range docs.BatchOperationDocuments {
+			if state == "" || strings.EqualFold(string(doc.BatchOperation.State), string(state)) {
+				bos = append(bos, doc.BatchOperation)
+			}
+		}
+	}
+
+	sort.SliceStable(bos, func(i, j int) bool {
+		switch {
+		case bos[i].StartTime == nil:
+			return bos[j].StartTime != nil
+		case bos[j].StartTime == nil:
+			return false
+		}
+		return bos[i].StartTime.After(*bos[j].StartTime)
+	})
+
+	return json.MarshalIndent(converter.ToExternalList(bos), "", "    ")
+}
@@ -0,0 +1,75 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/admin"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+// defaultBatchOperationConcurrency is used when a batch operation does not
+// specify MaxConcurrency
+const defaultBatchOperationConcurrency = 10
+
+func (f *frontend) postAdminBatchOperation(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+
+	b, err := f._postAdminBatchOperation(ctx, log, r)
+	if err == nil {
+		err = statusCodeError(http.StatusCreated)
+	}
+
+	adminReply(log, w, nil, b, err)
+}
+
+func (f *frontend) _postAdminBatchOperation(ctx context.Context, log *logrus.Entry, r *http.Request) ([]byte, error) {
+	converter := f.apis[admin.APIVersion].BatchOperationConverter
+	staticValidator := f.apis[admin.APIVersion].BatchOperationStaticValidator
+
+	body := r.Context().Value(middleware.ContextKeyBody).([]byte)
+	if len(body) == 0 || !json.Valid(body) {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content was invalid and could not be deserialized.")
+	}
+
+	var ext *admin.BatchOperation
+	err := json.Unmarshal(body, &ext)
+	if err != nil {
+		return nil, api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidRequestContent, "", "The request content could not be deserialized: "+err.Error())
+	}
+
+	err = staticValidator.Static(ext)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := &api.BatchOperationDocument{
+		ID:             f.dbBatchOperations.NewUUID(),
+		BatchOperation: &api.BatchOperation{},
+	}
+
+	converter.ToInternal(ext, doc.BatchOperation)
+
+	doc.BatchOperation.ID = doc.ID
+	doc.BatchOperation.State = api.BatchOperationStatePending
+	if doc.BatchOperation.MaxConcurrency == 0 {
+		doc.BatchOperation.MaxConcurrency = defaultBatchOperationConcurrency
+	}
+
+	doc, err = f.dbBatchOperations.Create(ctx, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Infof("created batch operation %s: %s", doc.ID, doc.BatchOperation.Action)
+
+	return json.MarshalIndent(converter.ToExternal(doc.BatchOperation), "", "    ")
+}
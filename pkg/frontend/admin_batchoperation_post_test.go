@@ -0,0 +1,165 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/admin"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+)
+
+func TestPostAdminBatchOperation(t *testing.T) {
+	ctx := context.Background()
+
+	clusterID := func(subscriptionID, name string) string {
+		return "/subscriptions/" + subscriptionID + "/resourceGroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/" + name
+	}
+
+	sub1 := "00000000-0000-0000-0000-000000000001"
+	sub2 := "00000000-0000-0000-0000-000000000002"
+
+	type test struct {
+		name           string
+		body           *admin.BatchOperation
+		wantStatusCode int
+		wantResponse   *admin.BatchOperation
+		wantError      string
+		wantDocuments  []*api.BatchOperationDocument
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "selector by subscription and version range",
+			body: &admin.BatchOperation{
+				Properties: admin.BatchOperationProperties{
+					Action: admin.BatchOperationActionApproveCSR,
+					Selector: admin.BatchOperationSelector{
+						SubscriptionIDs: []string{sub1},
+						MinVersion:      "4.12.0",
+						MaxVersion:      "4.14.0",
+					},
+					FailureBudget: 1,
+				},
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &admin.BatchOperation{
+				ID: "07070707-0707-0707-0707-070707070001",
+				Properties: admin.BatchOperationProperties{
+					Action: admin.BatchOperationActionApproveCSR,
+					Selector: admin.BatchOperationSelector{
+						SubscriptionIDs: []string{sub1},
+						MinVersion:      "4.12.0",
+						MaxVersion:      "4.14.0",
+					},
+					MaxConcurrency: 10,
+					FailureBudget:  1,
+					State:          admin.BatchOperationStatePending,
+				},
+			},
+			wantDocuments: []*api.BatchOperationDocument{
+				{
+					ID: "07070707-0707-0707-0707-070707070001",
+					BatchOperation: &api.BatchOperation{
+						ID:     "07070707-0707-0707-0707-070707070001",
+						Action: api.BatchOperationActionApproveCSR,
+						Selector: api.BatchOperationSelector{
+							SubscriptionIDs: []string{sub1},
+							MinVersion:      "4.12.0",
+							MaxVersion:      "4.14.0",
+						},
+						MaxConcurrency: 10,
+						FailureBudget:  1,
+						State:          api.BatchOperationStatePending,
+					},
+				},
+			},
+		},
+		{
+			name: "selector by resource ID and location",
+			body: &admin.BatchOperation{
+				Properties: admin.BatchOperationProperties{
+					Action: admin.BatchOperationActionRedeployVM,
+					Parameters: map[string]string{
+						"vmNameSuffix": "master-0",
+					},
+					Selector: admin.BatchOperationSelector{
+						ResourceIDs: []string{clusterID(sub1, "new"), clusterID(sub2, "other")},
+						Locations:   []string{"WestUS"},
+					},
+					MaxConcurrency: 1,
+				},
+			},
+			wantStatusCode: http.StatusCreated,
+			wantResponse: &admin.BatchOperation{
+				ID: "07070707-0707-0707-0707-070707070001",
+				Properties: admin.BatchOperationProperties{
+					Action: admin.BatchOperationActionRedeployVM,
+					Parameters: map[string]string{
+						"vmNameSuffix": "master-0",
+					},
+					Selector: admin.BatchOperationSelector{
+						ResourceIDs: []string{clusterID(sub1, "new"), clusterID(sub2, "other")},
+						Locations:   []string{"WestUS"},
+					},
+					MaxConcurrency: 1,
+					State:          admin.BatchOperationStatePending,
+				},
+			},
+		},
+		{
+			name: "invalid action",
+			body: &admin.BatchOperation{
+				Properties: admin.BatchOperationProperties{
+					Action: "reboot",
+					Selector: admin.BatchOperationSelector{
+						SubscriptionIDs: []string{sub1},
+					},
+				},
+			},
+			wantStatusCode: http.StatusBadRequest,
+			wantError:      "400: InvalidParameter: properties.action: The provided action 'reboot' is invalid.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithBatchOperations()
+			defer ti.done()
+
+			err := ti.buildFixtures(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, nil, nil, nil, nil, nil, ti.batchOperationsDatabase, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost, "https://server/admin/batchoperations",
+				http.Header{
+					"Content-Type": []string{"application/json"},
+				}, tt.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantDocuments != nil {
+				ti.checker.AddBatchOperationDocuments(tt.wantDocuments...)
+				for _, err := range ti.checker.CheckBatchOperations(ti.batchOperationsClient) {
+					t.Error(err)
+				}
+			}
+		})
+	}
+}
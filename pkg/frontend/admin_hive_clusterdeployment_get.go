@@ -14,13 +14,14 @@ import (
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/hive"
 )
 
 func (f *frontend) getAdminHiveClusterDeployment(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
 	resourceID := strings.TrimPrefix(filepath.Dir(r.URL.Path), "/admin")
-	b, err := f._getAdminHiveClusterDeployment(ctx, resourceID)
+	b, err := f._getAdminHiveClusterDeployment(ctx, log, resourceID)
 
 	if cloudErr, ok := err.(*api.CloudError); ok {
 		api.WriteCloudError(w, cloudErr)
@@ -30,7 +31,7 @@ func (f *frontend) getAdminHiveClusterDeployment(w http.ResponseWriter, r *http.
 	adminReply(log, w, nil, b, err)
 }
 
-func (f *frontend) _getAdminHiveClusterDeployment(ctx context.Context, resourceID string) ([]byte, error) {
+func (f *frontend) _getAdminHiveClusterDeployment(ctx context.Context, log *logrus.Entry, resourceID string) ([]byte, error) {
 	// we have to check if the frontend has a valid clustermanager since hive is not everywhere.
 	if f.hiveClusterManager == nil {
 		return nil, api.NewCloudError(http.StatusInternalServerError, api.CloudErrorCodeInternalServerError, "", "hive is not enabled")
@@ -45,7 +46,17 @@ func (f *frontend) _getAdminHiveClusterDeployment(ctx context.Context, resourceI
 		return nil, api.NewCloudError(http.StatusNoContent, api.CloudErrorCodeResourceNotFound, "", "cluster is not managed by hive")
 	}
 
-	cd, err := f.hiveClusterManager.GetClusterDeployment(ctx, doc)
+	// the frontend's clustermanager talks to the default shard; clusters
+	// assigned elsewhere need a clustermanager for their own shard.
+	hiveClusterManager := f.hiveClusterManager
+	if shard := hive.ShardFor(doc); shard != hive.DefaultShard {
+		hiveClusterManager, err = hive.NewFromEnvForShard(ctx, log, f.env, shard)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	cd, err := hiveClusterManager.GetClusterDeployment(ctx, doc)
 	if err != nil {
 		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "cluster deployment not found")
 	}
@@ -90,16 +90,16 @@ func Test_getAdminHiveClusterDeployment(t *testing.T) {
 				clusterManager := mock_hive.NewMockClusterManager(controller)
 				clusterManager.EXPECT().GetClusterDeployment(gomock.Any(), gomock.Any()).Return(&clusterDeployment, nil).Times(tt.expectedGetClusterDeploymentCallCount)
 				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase,
-					ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, clusterManager, nil, nil, nil)
+					ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, clusterManager, nil, nil, nil)
 			} else {
 				f, err = NewFrontend(ctx, ti.audit, ti.log, _env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase,
-					ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+					ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			}
 
 			if err != nil {
 				t.Fatal(err)
 			}
-			hiveClusterDeployment, err := f._getAdminHiveClusterDeployment(ctx, strings.ToLower(tt.resourceID))
+			hiveClusterDeployment, err := f._getAdminHiveClusterDeployment(ctx, ti.log, strings.ToLower(tt.resourceID))
 			cloudErr, isCloudErr := err.(*api.CloudError)
 			if tt.wantError != "" && isCloudErr && cloudErr != nil {
 				if tt.wantError != cloudErr.Error() {
@@ -0,0 +1,126 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/cluster"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	"github.com/Azure/ARO-RP/test/util/testliveconfig"
+)
+
+func TestAdminOpenShiftClusterAdminUpdatePlan(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+
+	resourceID := testdatabase.GetResourcePath(mockSubID, "resourceName")
+
+	fixture := func(f *testdatabase.Fixture) {
+		f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+			Key: strings.ToLower(resourceID),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: api.ProvisioningStateSucceeded,
+					ClusterProfile: api.ClusterProfile{
+						Version: "4.6.62",
+					},
+				},
+			},
+		})
+	}
+
+	for _, tt := range []struct {
+		name            string
+		maintenanceTask string
+		fixture         func(f *testdatabase.Fixture)
+		wantStatusCode  int
+		wantResponse    *cluster.AdminUpdatePlan
+		wantError       string
+	}{
+		{
+			name:            "operator update on an old cluster",
+			maintenanceTask: "OperatorUpdate",
+			fixture:         fixture,
+			wantStatusCode:  http.StatusOK,
+			wantResponse: &cluster.AdminUpdatePlan{
+				MaintenanceTask: api.MaintenanceTaskOperator,
+				Steps: []steps.PlannedStep{
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).initializeKubernetesClients-fm]"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).ensureBillingRecord-fm]"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).ensureDefaults-fm]"},
+					{Step: "[AuthorizationRetryingAction github.com/Azure/ARO-RP/pkg/cluster.(*manager).fixupClusterSPObjectID-fm]", Timeout: "10m0s"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).fixInfraID-fm]"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).ensureGatewayEgressAllowList-fm]"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).startVMs-fm]"},
+					{Step: "[Condition github.com/Azure/ARO-RP/pkg/cluster.(*manager).apiServersReady-fm, timeout 30m0s]", Timeout: "30m0s"},
+					{Step: "[Action github.com/Azure/ARO-RP/pkg/cluster.(*manager).initializeOperatorDeployer-fm]"},
+				},
+				Skipped: []string{
+					`ARO operator update: cluster version "4.6.62" is older than 4.7.0`,
+				},
+			},
+		},
+		{
+			name:            "non-running maintenance task",
+			maintenanceTask: "Pending",
+			fixture:         fixture,
+			wantStatusCode:  http.StatusBadRequest,
+			wantError:       "400: InvalidParameter: maintenanceTask: The provided maintenanceTask 'Pending' does not run any admin update steps.",
+		},
+		{
+			name:           "cluster not found",
+			fixture:        func(f *testdatabase.Fixture) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters()
+			defer ti.done()
+
+			_env := ti.env.(*mock_env.MockInterface)
+			_env.EXPECT().LiveConfig().AnyTimes().Return(testliveconfig.NewTestLiveConfig(false, false, false))
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodGet,
+				fmt.Sprintf("https://server/admin%s/adminupdateplan?maintenanceTask=%s", resourceID, tt.maintenanceTask),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			// the plan must never modify the cluster
+			doc, err := ti.openShiftClustersDatabase.Get(ctx, strings.ToLower(resourceID))
+			if err == nil && doc.OpenShiftCluster.Properties.MaintenanceTask != "" {
+				t.Errorf("unexpected maintenanceTask %q", doc.OpenShiftCluster.Properties.MaintenanceTask)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+)
+
+func (f *frontend) postAdminOpenShiftClusterCancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+	err := f._postAdminOpenShiftClusterCancel(ctx, r)
+	adminReply(log, w, nil, nil, err)
+}
+
+// _postAdminOpenShiftClusterCancel asks the backend to abort an in-flight
+// create or admin update.  The backend notices the request at its next lease
+// heartbeat, cancels the running operation and fails it.
+func (f *frontend) _postAdminOpenShiftClusterCancel(ctx context.Context, r *http.Request) error {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	_, err := f.dbOpenShiftClusters.Patch(ctx, resourceID, func(doc *api.OpenShiftClusterDocument) error {
+		switch doc.OpenShiftCluster.Properties.ProvisioningState {
+		case api.ProvisioningStateCreating, api.ProvisioningStateAdminUpdating:
+		default:
+			return api.NewCloudError(http.StatusConflict, api.CloudErrorCodeRequestNotAllowed, "", "Only a create or admin update in progress can be cancelled, but the cluster is in provisioningState '%s'.", doc.OpenShiftCluster.Properties.ProvisioningState)
+		}
+
+		doc.CancelRequested = true
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		return api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resType, resName, resGroupName)
+	}
+	return err
+}
@@ -0,0 +1,118 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+func TestAdminCancel(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+
+	ctx := context.Background()
+
+	clusterDoc := func(provisioningState api.ProvisioningState, cancelRequested bool) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			Key:             strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resourceName")),
+			CancelRequested: cancelRequested,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: testdatabase.GetResourcePath(mockSubID, "resourceName"),
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: provisioningState,
+				},
+			},
+		}
+	}
+
+	type test struct {
+		name           string
+		fixture        func(*testdatabase.Fixture)
+		wantDocuments  func(*testdatabase.Checker)
+		wantStatusCode int
+		wantError      string
+	}
+
+	for _, tt := range []*test{
+		{
+			name: "create in progress",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateCreating, false))
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateCreating, true))
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "admin update in progress",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateAdminUpdating, false))
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateAdminUpdating, true))
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "no operation in progress",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateSucceeded, false))
+			},
+			wantDocuments: func(c *testdatabase.Checker) {
+				c.AddOpenShiftClusterDocuments(clusterDoc(api.ProvisioningStateSucceeded, false))
+			},
+			wantStatusCode: http.StatusConflict,
+			wantError:      "409: RequestNotAllowed: : Only a create or admin update in progress can be cancelled, but the cluster is in provisioningState 'Succeeded'.",
+		},
+		{
+			name:           "cluster not found",
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/resourcename' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters()
+			defer ti.done()
+
+			err := ti.buildFixtures(tt.fixture)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(http.MethodPost,
+				"https://server/admin"+testdatabase.GetResourcePath(mockSubID, "resourceName")+"/cancel",
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, nil)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantDocuments != nil {
+				tt.wantDocuments(ti.checker)
+			}
+			errs := ti.checker.CheckOpenShiftClusters(ti.openShiftClustersClient)
+			for _, err := range errs {
+				t.Error(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+// postAdminOpenShiftClusterDiagnostics asks the operator to gather a new
+// diagnostics bundle by annotating the Cluster resource with a new request
+func (f *frontend) postAdminOpenShiftClusterDiagnostics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	err := f._postAdminOpenShiftClusterDiagnostics(ctx, r, log)
+
+	adminReply(log, w, nil, nil, err)
+}
+
+func (f *frontend) _postAdminOpenShiftClusterDiagnostics(ctx context.Context, r *http.Request, log *logrus.Entry) error {
+	k, err := f.diagnosticsKubeActions(ctx, r, log)
+	if err != nil {
+		return err
+	}
+
+	b, err := k.KubeGet(ctx, "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName)
+	if err != nil {
+		return err
+	}
+
+	cluster := &unstructured.Unstructured{}
+	err = cluster.UnmarshalJSON(b)
+	if err != nil {
+		return err
+	}
+
+	annotations := cluster.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[operator.DiagnosticsRequestAnnotation] = time.Now().UTC().Format(time.RFC3339Nano)
+	cluster.SetAnnotations(annotations)
+
+	return k.KubeCreateOrUpdate(ctx, cluster)
+}
+
+// getAdminOpenShiftClusterDiagnostics returns the tar.gz diagnostics bundle
+// gathered by the operator for the latest request
+func (f *frontend) getAdminOpenShiftClusterDiagnostics(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	log := ctx.Value(middleware.ContextKeyLog).(*logrus.Entry)
+	r.URL.Path = filepath.Dir(r.URL.Path)
+
+	b, err := f._getAdminOpenShiftClusterDiagnostics(ctx, r, log)
+	if err != nil {
+		adminReply(log, w, nil, nil, err)
+		return
+	}
+
+	// the bundle is binary, so is written as is rather than through
+	// adminReply, which appends a newline
+	w.Header().Set("Content-Type", "application/gzip")
+	_, _ = w.Write(b)
+}
+
+func (f *frontend) _getAdminOpenShiftClusterDiagnostics(ctx context.Context, r *http.Request, log *logrus.Entry) ([]byte, error) {
+	k, err := f.diagnosticsKubeActions(ctx, r, log)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := k.KubeGet(ctx, "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName)
+	if err != nil {
+		return nil, err
+	}
+
+	var cluster *arov1alpha1.Cluster
+	err = json.Unmarshal(b, &cluster)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID := cluster.Annotations[operator.DiagnosticsRequestAnnotation]
+	if requestID == "" {
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "A diagnostics bundle has not been requested for this cluster.")
+	}
+
+	b, err = k.KubeGet(ctx, "Secret", operator.Namespace, operator.DiagnosticsSecretName)
+	if kerrors.IsNotFound(err) {
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The diagnostics bundle has not been gathered yet.")
+	} else if err != nil {
+		return nil, err
+	}
+
+	var secret *corev1.Secret
+	err = json.Unmarshal(b, &secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if secret.Annotations[operator.DiagnosticsRequestAnnotation] != requestID {
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeNotFound, "", "The diagnostics bundle has not been gathered yet.")
+	}
+
+	return secret.Data[operator.DiagnosticsSecretKey], nil
+}
+
+func (f *frontend) diagnosticsKubeActions(ctx context.Context, r *http.Request, log *logrus.Entry) (adminactions.KubeActions, error) {
+	resType, resName, resGroupName := chi.URLParam(r, "resourceType"), chi.URLParam(r, "resourceName"), chi.URLParam(r, "resourceGroupName")
+
+	resourceID := strings.TrimPrefix(r.URL.Path, "/admin")
+
+	doc, err := f.dbOpenShiftClusters.Get(ctx, resourceID)
+	switch {
+	case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+		return nil, api.NewCloudError(http.StatusNotFound, api.CloudErrorCodeResourceNotFound, "", "The Resource '%s/%s' under resource group '%s' was not found.", resType, resName, resGroupName)
+	case err != nil:
+		return nil, err
+	}
+
+	return f.kubeActionsFactory(log, f.env, doc.OpenShiftCluster)
+}
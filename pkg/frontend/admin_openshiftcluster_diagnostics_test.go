@@ -0,0 +1,238 @@
+package frontend
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/frontend/adminactions"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	mock_adminactions "github.com/Azure/ARO-RP/pkg/util/mocks/adminactions"
+)
+
+func TestAdminOpenShiftClusterDiagnostics(t *testing.T) {
+	mockSubID := "00000000-0000-0000-0000-000000000000"
+	mockTenantID := "00000000-0000-0000-0000-000000000000"
+	ctx := context.Background()
+
+	resourceID := fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/resourceName", mockSubID)
+
+	annotations := func(requestID string) map[string]string {
+		if requestID == "" {
+			return nil
+		}
+		return map[string]string{
+			operator.DiagnosticsRequestAnnotation: requestID,
+		}
+	}
+
+	cluster := func(requestID string) []byte {
+		b, err := json.Marshal(&arov1alpha1.Cluster{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: arov1alpha1.GroupVersion.String(),
+				Kind:       "Cluster",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        arov1alpha1.SingletonClusterName,
+				Annotations: annotations(requestID),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	secret := func(requestID string) []byte {
+		b, err := json.Marshal(&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        operator.DiagnosticsSecretName,
+				Namespace:   operator.Namespace,
+				Annotations: annotations(requestID),
+			},
+			Data: map[string][]byte{
+				operator.DiagnosticsSecretKey: []byte("bundle"),
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b
+	}
+
+	type test struct {
+		name                    string
+		method                  string
+		resourceID              string
+		mocks                   func(*mock_adminactions.MockKubeActions)
+		wantStatusCode          int
+		wantResponse            []byte
+		wantResponseContentType string
+		wantError               string
+	}
+
+	for _, tt := range []*test{
+		{
+			name:       "request",
+			method:     http.MethodPost,
+			resourceID: resourceID,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName).
+					Return(cluster(""), nil)
+				k.EXPECT().
+					KubeCreateOrUpdate(gomock.Any(), gomock.Any()).
+					DoAndReturn(func(ctx context.Context, o *unstructured.Unstructured) error {
+						if o.GetName() != arov1alpha1.SingletonClusterName {
+							return fmt.Errorf("unexpected object %s", o.GetName())
+						}
+						if o.GetAnnotations()[operator.DiagnosticsRequestAnnotation] == "" {
+							return fmt.Errorf("diagnostics request annotation not set")
+						}
+						return nil
+					})
+			},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:       "gathered",
+			method:     http.MethodGet,
+			resourceID: resourceID,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName).
+					Return(cluster("1"), nil)
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Secret", operator.Namespace, operator.DiagnosticsSecretName).
+					Return(secret("1"), nil)
+			},
+			wantStatusCode:          http.StatusOK,
+			wantResponse:            []byte("bundle"),
+			wantResponseContentType: "application/gzip",
+		},
+		{
+			name:       "not requested",
+			method:     http.MethodGet,
+			resourceID: resourceID,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName).
+					Return(cluster(""), nil)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : A diagnostics bundle has not been requested for this cluster.",
+		},
+		{
+			name:       "not gathered",
+			method:     http.MethodGet,
+			resourceID: resourceID,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName).
+					Return(cluster("1"), nil)
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Secret", operator.Namespace, operator.DiagnosticsSecretName).
+					Return(nil, kerrors.NewNotFound(schema.GroupResource{Resource: "secrets"}, operator.DiagnosticsSecretName))
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The diagnostics bundle has not been gathered yet.",
+		},
+		{
+			name:       "gathered for an older request",
+			method:     http.MethodGet,
+			resourceID: resourceID,
+			mocks: func(k *mock_adminactions.MockKubeActions) {
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Cluster.aro.openshift.io", "", arov1alpha1.SingletonClusterName).
+					Return(cluster("2"), nil)
+				k.EXPECT().
+					KubeGet(gomock.Any(), "Secret", operator.Namespace, operator.DiagnosticsSecretName).
+					Return(secret("1"), nil)
+			},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: NotFound: : The diagnostics bundle has not been gathered yet.",
+		},
+		{
+			name:           "cluster not found",
+			method:         http.MethodGet,
+			resourceID:     fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/missing", mockSubID),
+			mocks:          func(k *mock_adminactions.MockKubeActions) {},
+			wantStatusCode: http.StatusNotFound,
+			wantError:      "404: ResourceNotFound: : The Resource 'openshiftclusters/missing' under resource group 'resourcegroup' was not found.",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ti := newTestInfra(t).WithOpenShiftClusters().WithSubscriptions()
+			defer ti.done()
+
+			k := mock_adminactions.NewMockKubeActions(ti.controller)
+			tt.mocks(k)
+
+			ti.fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: strings.ToLower(resourceID),
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID:   resourceID,
+					Name: "resourceName",
+					Type: "Microsoft.RedHatOpenShift/openshiftClusters",
+				},
+			})
+			ti.fixture.AddSubscriptionDocuments(&api.SubscriptionDocument{
+				ID: mockSubID,
+				Subscription: &api.Subscription{
+					State: api.SubscriptionStateRegistered,
+					Properties: &api.SubscriptionProperties{
+						TenantID: mockTenantID,
+					},
+				},
+			})
+
+			err := ti.buildFixtures(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, func(*logrus.Entry, env.Interface, *api.OpenShiftCluster) (adminactions.KubeActions, error) {
+				return k, nil
+			}, nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			go f.Run(ctx, nil, nil)
+
+			resp, b, err := ti.request(tt.method,
+				fmt.Sprintf("https://server/admin%s/diagnostics", tt.resourceID),
+				nil, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = validateResponse(resp, b, tt.wantStatusCode, tt.wantError, tt.wantResponse)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if tt.wantResponseContentType != "" && resp.Header.Get("Content-Type") != tt.wantResponseContentType {
+				t.Errorf("got Content-Type %q, want %q", resp.Header.Get("Content-Type"), tt.wantResponseContentType)
+			}
+		})
+	}
+}
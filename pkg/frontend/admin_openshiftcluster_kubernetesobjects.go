@@ -16,6 +16,7 @@ import (
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/frontend/middleware"
 )
@@ -65,12 +66,12 @@ func (f *frontend) _getAdminKubernetesObjects(ctx context.Context, r *http.Reque
 	}
 
 	if !unrestricted {
-		err = validateAdminKubernetesObjectsNonCustomer(r.Method, gvr, namespace, name)
+		err = validate.AdminKubernetesObjectsNonCustomer(r.Method, gvr, namespace, name)
 		if err != nil {
 			return nil, err
 		}
 	}
-	err = validateAdminKubernetesObjects(r.Method, gvr, namespace, name)
+	err = validate.AdminKubernetesObjects(r.Method, gvr, namespace, name)
 	if err != nil {
 		return nil, err
 	}
@@ -98,7 +99,7 @@ func (f *frontend) _deleteAdminKubernetesObjects(ctx context.Context, r *http.Re
 	force := strings.EqualFold(r.URL.Query().Get("force"), "true")
 
 	if force {
-		err := validateAdminKubernetesObjectsForceDelete(groupKind)
+		err := validate.AdminKubernetesObjectsForceDelete(groupKind)
 		if err != nil {
 			return err
 		}
@@ -124,7 +125,7 @@ func (f *frontend) _deleteAdminKubernetesObjects(ctx context.Context, r *http.Re
 		return err
 	}
 
-	err = validateAdminKubernetesObjectsNonCustomer(r.Method, gvr, namespace, name)
+	err = validate.AdminKubernetesObjectsNonCustomer(r.Method, gvr, namespace, name)
 	if err != nil {
 		return err
 	}
@@ -178,7 +179,7 @@ func (f *frontend) _postAdminKubernetesObjects(ctx context.Context, r *http.Requ
 		return err
 	}
 
-	err = validateAdminKubernetesObjectsNonCustomer(r.Method, gvr, obj.GetNamespace(), obj.GetName())
+	err = validate.AdminKubernetesObjectsNonCustomer(r.Method, gvr, obj.GetNamespace(), obj.GetName())
 	if err != nil {
 		return err
 	}
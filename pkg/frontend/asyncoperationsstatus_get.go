@@ -59,6 +59,12 @@ func (f *frontend) _getAsyncOperationsStatus(ctx context.Context, r *http.Reques
 		asyncdoc.AsyncOperation.Error = nil
 	}
 
+	// the queue position is only meaningful until the backend picks up the
+	// operation
+	if doc == nil || doc.AsyncOperationID != operationId || doc.LeaseOwner != "" {
+		asyncdoc.AsyncOperation.Queue = nil
+	}
+
 	asyncdoc.AsyncOperation.MissingFields = api.MissingFields{}
 	asyncdoc.AsyncOperation.InitialProvisioningState = ""
 
@@ -101,6 +101,78 @@ func TestGetAsyncOperationsStatus(t *testing.T) {
 				StartTime:         mockOpStartTime,
 			},
 		},
+		{
+			name: "operation is waiting in the backend queue",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					ID:                  mockOpID,
+					OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resource1")),
+					AsyncOperation: &api.AsyncOperation{
+						ID:                       "fakeoppath",
+						Name:                     mockOpID,
+						InitialProvisioningState: api.ProvisioningStateCreating,
+						ProvisioningState:        api.ProvisioningStateCreating,
+						StartTime:                mockOpStartTime,
+						Queue: &api.AsyncOperationQueue{
+							Position:            3,
+							SubscriptionRunning: 10,
+							SubscriptionLimit:   10,
+							Throttled:           true,
+						},
+					},
+				})
+
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key:              strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resource1")),
+					AsyncOperationID: mockOpID,
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &api.AsyncOperation{
+				ID:                "fakeoppath",
+				Name:              mockOpID,
+				ProvisioningState: api.ProvisioningStateCreating,
+				StartTime:         mockOpStartTime,
+				Queue: &api.AsyncOperationQueue{
+					Position:            3,
+					SubscriptionRunning: 10,
+					SubscriptionLimit:   10,
+					Throttled:           true,
+				},
+			},
+		},
+		{
+			name: "operation has been picked up by the backend",
+			fixture: func(f *testdatabase.Fixture) {
+				f.AddAsyncOperationDocuments(&api.AsyncOperationDocument{
+					ID:                  mockOpID,
+					OpenShiftClusterKey: strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resource1")),
+					AsyncOperation: &api.AsyncOperation{
+						ID:                       "fakeoppath",
+						Name:                     mockOpID,
+						InitialProvisioningState: api.ProvisioningStateCreating,
+						ProvisioningState:        api.ProvisioningStateCreating,
+						StartTime:                mockOpStartTime,
+						Queue: &api.AsyncOperationQueue{
+							Position: 1,
+						},
+					},
+				})
+
+				f.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+					Key:              strings.ToLower(testdatabase.GetResourcePath(mockSubID, "resource1")),
+					AsyncOperationID: mockOpID,
+					LeaseOwner:       "backend",
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantResponse: &api.AsyncOperation{
+				ID:                "fakeoppath",
+				Name:              mockOpID,
+				ProvisioningState: api.ProvisioningStateCreating,
+				StartTime:         mockOpStartTime,
+			},
+		},
 		{
 			name:           "operation not found in db",
 			wantStatusCode: http.StatusNotFound,
@@ -183,7 +255,7 @@ func TestGetAsyncOperationsStatus(t *testing.T) {
 				ti.asyncOperationsClient.SetError(tt.dbError)
 			}
 
-			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
+			f, err := NewFrontend(ctx, ti.audit, ti.log, ti.env, ti.asyncOperationsDatabase, ti.clusterManagerDatabase, ti.openShiftClustersDatabase, ti.subscriptionsDatabase, nil, nil, api.APIs, &noop.Noop{}, &noop.Noop{}, nil, nil, nil, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -62,6 +62,7 @@ type frontend struct {
 	dbOpenShiftClusters           database.OpenShiftClusters
 	dbSubscriptions               database.Subscriptions
 	dbOpenShiftVersions           database.OpenShiftVersions
+	dbBatchOperations             database.BatchOperations
 
 	defaultOcpVersion  string // always enabled
 	enabledOcpVersions map[string]*api.OpenShiftVersion
@@ -117,6 +118,7 @@ func NewFrontend(ctx context.Context,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbSubscriptions database.Subscriptions,
 	dbOpenShiftVersions database.OpenShiftVersions,
+	dbBatchOperations database.BatchOperations,
 	apis map[string]*api.Version,
 	m metrics.Emitter,
 	clusterm metrics.Emitter,
@@ -153,6 +155,7 @@ func NewFrontend(ctx context.Context,
 		dbOpenShiftClusters:           dbOpenShiftClusters,
 		dbSubscriptions:               dbSubscriptions,
 		dbOpenShiftVersions:           dbOpenShiftVersions,
+		dbBatchOperations:             dbBatchOperations,
 		apis:                          apis,
 		m:                             middleware.MetricsMiddleware{Emitter: m},
 		maintenanceMiddleware:         middleware.MaintenanceMiddleware{Emitter: clusterm},
@@ -290,6 +293,12 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 		})
 		r.Get("/supportedvmsizes", f.supportedvmsizes)
 
+		r.Route("/batchoperations", func(r chi.Router) {
+			r.Get("/", f.getAdminBatchOperations)
+			r.Post("/", f.postAdminBatchOperation)
+			r.Get("/{batchOperationId}", f.getAdminBatchOperation)
+		})
+
 		r.Route("/subscriptions/{subscriptionId}", func(r chi.Router) {
 			r.Route("/resourcegroups/{resourceGroupName}/providers/{resourceProviderNamespace}/{resourceType}/{resourceName}", func(r chi.Router) {
 				// Etcd recovery
@@ -319,6 +328,15 @@ func (f *frontend) chiAuthenticatedRoutes(router chi.Router) {
 
 				r.Get("/skus", f.getAdminOpenShiftClusterVMResizeOptions)
 
+				r.Get("/adminupdateplan", f.getAdminOpenShiftClusterAdminUpdatePlan)
+
+				r.Get("/guardrails", f.getAdminOpenShiftClusterGuardrails)
+
+				r.Get("/diagnostics", f.getAdminOpenShiftClusterDiagnostics)
+				r.Post("/diagnostics", f.postAdminOpenShiftClusterDiagnostics)
+
+				r.Post("/cancel", f.postAdminOpenShiftClusterCancel)
+
 				// We don't emit unplanned maintenance signal for resize since it is only used for planned maintenance
 				r.Post("/resize", f.postAdminOpenShiftClusterVMResize)
 
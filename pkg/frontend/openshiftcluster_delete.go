@@ -60,6 +60,7 @@ func (f *frontend) _deleteOpenShiftCluster(ctx context.Context, r *http.Request,
 	if err != nil {
 		return err
 	}
+	doc.EnqueueTime = int(f.now().Unix())
 
 	u, err := url.Parse(r.Header.Get("Referer"))
 	if err != nil {
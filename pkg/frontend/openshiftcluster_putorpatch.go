@@ -207,6 +207,7 @@ func (f *frontend) _putOrPatchOpenShiftCluster(ctx context.Context, log *logrus.
 	if err != nil {
 		return nil, err
 	}
+	doc.EnqueueTime = int(f.now().Unix())
 
 	u, err := url.Parse(referer)
 	if err != nil {
@@ -325,6 +326,10 @@ func adminUpdateProvisioningState(doc *api.OpenShiftClusterDocument) {
 		doc.OpenShiftCluster.Properties.LastAdminUpdateError = ""
 		doc.Dequeues = 0
 
+		if doc.OpenShiftCluster.Properties.MaintenanceTask == api.MaintenanceTaskUpgrade {
+			doc.OpenShiftCluster.Properties.UpgradeProgress = ""
+		}
+
 		// Set the maintenance to ongoing so we emit the appropriate signal to customerss
 		if doc.OpenShiftCluster.Properties.MaintenanceState == api.MaintenanceStatePending {
 			doc.OpenShiftCluster.Properties.MaintenanceState = api.MaintenanceStatePlanned
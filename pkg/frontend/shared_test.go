@@ -80,6 +80,8 @@ type testInfra struct {
 	subscriptionsDatabase     database.Subscriptions
 	openShiftVersionsClient   *cosmosdb.FakeOpenShiftVersionDocumentClient
 	openShiftVersionsDatabase database.OpenShiftVersions
+	batchOperationsClient     *cosmosdb.FakeBatchOperationDocumentClient
+	batchOperationsDatabase   database.BatchOperations
 }
 
 func newTestInfra(t *testing.T) *testInfra {
@@ -185,6 +187,12 @@ func (ti *testInfra) WithClusterManagerConfigurations() *testInfra {
 	return ti
 }
 
+func (ti *testInfra) WithBatchOperations() *testInfra {
+	ti.batchOperationsDatabase, ti.batchOperationsClient = testdatabase.NewFakeBatchOperations()
+	ti.fixture.WithBatchOperations(ti.batchOperationsDatabase)
+	return ti
+}
+
 func (ti *testInfra) done() {
 	ti.controller.Finish()
 	ti.cli.CloseIdleConnections()
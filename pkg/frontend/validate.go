@@ -10,7 +10,6 @@ import (
 	"strings"
 
 	"github.com/Azure/go-autorest/autorest/azure"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/validate"
@@ -74,97 +73,12 @@ func (f *frontend) validateOpenShiftUniqueKey(ctx context.Context, doc *api.Open
 	return api.NewCloudError(http.StatusInternalServerError, api.CloudErrorCodeInternalServerError, "", "Internal server error.")
 }
 
-// rxKubernetesString is weaker than Kubernetes validation, but strong enough to
-// prevent mischief
-var rxKubernetesString = regexp.MustCompile(`(?i)^[-a-z0-9.]{0,255}$`)
-
-func validatePermittedClusterwideObjects(gvr schema.GroupVersionResource) bool {
-	permittedGroups := map[string]bool{
-		"apiserver.openshift.io":              true,
-		"aro.openshift.io":                    true,
-		"authorization.openshift.io":          true,
-		"certificates.k8s.io":                 true,
-		"config.openshift.io":                 true,
-		"console.openshift.io":                true,
-		"imageregistry.operator.openshift.io": true,
-		"machine.openshift.io":                true,
-		"machineconfiguration.openshift.io":   true,
-		"operator.openshift.io":               true,
-		"rbac.authorization.k8s.io":           true,
-		"metrics.k8s.io":                      true,
-	}
-	permittedObjects := map[string]map[string]bool{
-		"": {"nodes": true},
-	}
-	allowedResources, groupHasException := permittedObjects[gvr.Group]
-	return permittedGroups[gvr.Group] || (groupHasException && allowedResources[gvr.Resource])
-}
-
-func validateAdminKubernetesObjectsNonCustomer(method string, gvr schema.GroupVersionResource, namespace, name string) error {
-	if gvr.Empty() {
-		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided resource is invalid.")
-	}
-
-	if namespace == "" && !validatePermittedClusterwideObjects(gvr) {
-		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to cluster-scoped object '%v' is forbidden.", gvr)
-	}
-
-	if !utilnamespace.IsOpenShiftNamespace(namespace) {
-		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to the provided namespace '%s' is forbidden.", namespace)
-	}
-
-	return validateAdminKubernetesObjects(method, gvr, namespace, name)
-}
-
-func validateAdminKubernetesObjects(method string, gvr schema.GroupVersionResource, namespace, name string) error {
-	if gvr.Empty() {
-		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided resource is invalid.")
-	}
-
-	if gvr.Resource == "secrets" ||
-		gvr.Group == "oauth.openshift.io" {
-		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to secrets is forbidden.")
-	}
-	if method != http.MethodGet &&
-		(gvr.Group == "rbac.authorization.k8s.io" ||
-			gvr.Group == "authorization.openshift.io") {
-		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Write access to RBAC is forbidden.")
-	}
-
-	if !rxKubernetesString.MatchString(namespace) {
-		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided namespace '%s' is invalid.", namespace)
-	}
-
-	if (method != http.MethodGet && name == "") ||
-		!rxKubernetesString.MatchString(name) {
-		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided name '%s' is invalid.", name)
-	}
-
-	return nil
-}
-
-func validateAdminKubernetesObjectsForceDelete(groupKind string) error {
-	if !strings.EqualFold(groupKind, "Pod") {
-		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Force deleting groupKind '%s' is forbidden.", groupKind)
-	}
-
-	return nil
-}
-
-func validateAdminVMName(vmName string) error {
-	if vmName == "" || !rxKubernetesString.MatchString(vmName) {
-		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided vmName '%s' is invalid.", vmName)
-	}
-
-	return nil
-}
-
 func validateAdminKubernetesPodLogs(namespace, podName, containerName string) error {
-	if podName == "" || !rxKubernetesString.MatchString(podName) {
+	if podName == "" || !validate.RxKubernetesString.MatchString(podName) {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided pod name '%s' is invalid.", podName)
 	}
 
-	if namespace == "" || !rxKubernetesString.MatchString(namespace) {
+	if namespace == "" || !validate.RxKubernetesString.MatchString(namespace) {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided namespace '%s' is invalid.", namespace)
 	}
 	// Checking if the namespace is an OpenShift namespace not a customer workload namespace.
@@ -172,7 +86,7 @@ func validateAdminKubernetesPodLogs(namespace, podName, containerName string) er
 		return api.NewCloudError(http.StatusForbidden, api.CloudErrorCodeForbidden, "", "Access to the provided namespace '%s' is forbidden.", namespace)
 	}
 
-	if containerName == "" || !rxKubernetesString.MatchString(containerName) {
+	if containerName == "" || !validate.RxKubernetesString.MatchString(containerName) {
 		return api.NewCloudError(http.StatusBadRequest, api.CloudErrorCodeInvalidParameter, "", "The provided container name '%s' is invalid.", containerName)
 	}
 	return nil
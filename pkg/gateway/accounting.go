@@ -0,0 +1,284 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+const ruleConnectionLimit = "connectionlimit"
+
+// traffic is the traffic accounted since the last snapshot.  Bytes are
+// accounted as they flow, while connections and their duration are accounted
+// once they close.
+type traffic struct {
+	connections int64
+	duration    time.Duration
+	sent        int64
+	received    int64
+}
+
+// flow is an open connection.  Its byte counters are updated as data is copied
+// and are flushed into the traffic totals at every snapshot, so that long-lived
+// connections are accounted for before they close.
+type flow struct {
+	clusterResourceID string
+	destination       string
+	start             time.Time
+
+	sent     int64
+	received int64
+
+	// flushedSent and flushedReceived are protected by connections.mu
+	flushedSent     int64
+	flushedReceived int64
+}
+
+// connections tracks the number of open connections per cluster and the
+// traffic per cluster and per destination.  It is used both to enforce the
+// per-cluster connection cap and to attribute gateway load to individual
+// clusters and destinations.
+type connections struct {
+	mu            sync.Mutex
+	max           int
+	byCluster     map[string]int64
+	flows         map[*flow]struct{}
+	traffic       map[string]traffic
+	byDestination map[string]traffic
+	idleTimeout   time.Duration
+}
+
+func newConnections(max int, idleTimeout time.Duration) *connections {
+	return &connections{
+		max:           max,
+		byCluster:     map[string]int64{},
+		flows:         map[*flow]struct{}{},
+		traffic:       map[string]traffic{},
+		byDestination: map[string]traffic{},
+		idleTimeout:   idleTimeout,
+	}
+}
+
+// acquire reserves a connection slot for the cluster.  It returns false if the
+// cluster is already at its connection cap.  Each successful acquire must be
+// paired with a release.
+func (c *connections) acquire(clusterResourceID string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.max > 0 && c.byCluster[clusterResourceID] >= int64(c.max) {
+		return false
+	}
+
+	c.byCluster[clusterResourceID]++
+
+	return true
+}
+
+func (c *connections) release(clusterResourceID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.byCluster[clusterResourceID]--
+}
+
+// open starts accounting the traffic of a connection.  Each open must be
+// paired with a close.
+func (c *connections) open(clusterResourceID, destination string) *flow {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	f := &flow{
+		clusterResourceID: clusterResourceID,
+		destination:       destination,
+		start:             time.Now(),
+	}
+	c.flows[f] = struct{}{}
+
+	return f
+}
+
+// close adds the remaining traffic of a closed connection to the totals.
+func (c *connections) close(f *flow) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.flows, f)
+	c.flush(f, 1, time.Since(f.start))
+}
+
+// flush adds the bytes copied on f since it was last flushed, along with the
+// given connection count and duration, to the totals.  c.mu must be held.
+func (c *connections) flush(f *flow, connections int64, duration time.Duration) {
+	sent, received := atomic.LoadInt64(&f.sent), atomic.LoadInt64(&f.received)
+	sent, f.flushedSent = sent-f.flushedSent, sent
+	received, f.flushedReceived = received-f.flushedReceived, received
+
+	if connections == 0 && sent == 0 && received == 0 {
+		return
+	}
+
+	for _, totals := range []struct {
+		m   map[string]traffic
+		key string
+	}{
+		{m: c.traffic, key: f.clusterResourceID},
+		{m: c.byDestination, key: f.destination},
+	} {
+		t := totals.m[totals.key]
+		t.connections += connections
+		t.duration += duration
+		t.sent += sent
+		t.received += received
+		totals.m[totals.key] = t
+	}
+}
+
+// snapshot returns the current open connection counts and the traffic per
+// cluster and per destination since the last snapshot, which is then reset.
+// Open connection counts which have dropped to zero are returned once, so that
+// a zero gauge is emitted, and are then forgotten.
+func (c *connections) snapshot() (map[string]int64, map[string]traffic, map[string]traffic) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	byCluster := make(map[string]int64, len(c.byCluster))
+	for k, v := range c.byCluster {
+		byCluster[k] = v
+		if v == 0 {
+			delete(c.byCluster, k)
+		}
+	}
+
+	for f := range c.flows {
+		c.flush(f, 0, 0)
+	}
+
+	t, byDestination := c.traffic, c.byDestination
+	c.traffic, c.byDestination = map[string]traffic{}, map[string]traffic{}
+
+	return byCluster, t, byDestination
+}
+
+// destination returns the destination by which the traffic of a connection to
+// host, allowed by rule, is accounted.  Hosts on the static allow list are
+// accounted individually; the others, such as the clusters' storage accounts,
+// are not bounded, so they are accounted by the rule which allowed them.
+func destination(host, rule string) string {
+	if rule == ruleStatic {
+		return strings.ToLower(host)
+	}
+	return rule
+}
+
+// activityReader counts the bytes read through it and records the time of the
+// last read so that idle connections can be detected.
+type activityReader struct {
+	r            io.Reader
+	n            *int64
+	lastActivity *int64
+}
+
+func (a *activityReader) Read(b []byte) (int, error) {
+	n, err := a.r.Read(b)
+	if n > 0 {
+		atomic.AddInt64(a.n, int64(n))
+		atomic.StoreInt64(a.lastActivity, time.Now().UnixNano())
+	}
+	return n, err
+}
+
+// tunnel copies data in both directions between the cluster side of a
+// connection (read from r, written to c1) and the destination (c2), counting
+// the bytes sent and received by the cluster on f.  It returns once both
+// directions are done, or once no data has flowed in either direction for the
+// configured idle timeout.
+func (g *gateway) tunnel(f *flow, r io.Reader, c1, c2 net.Conn) {
+	lastActivity := time.Now().UnixNano()
+	done := make(chan struct{})
+	defer close(done)
+
+	if g.connections.idleTimeout > 0 {
+		go func() {
+			defer recover.Panic(g.log)
+			g.closeWhenIdle(done, &lastActivity, c1, c2)
+		}()
+	}
+
+	ch := make(chan struct{})
+
+	go func() {
+		defer recover.Panic(g.log)
+		defer close(ch)
+		defer closeWrite(c1)
+
+		_, _ = io.Copy(c1, &activityReader{r: c2, n: &f.received, lastActivity: &lastActivity})
+	}()
+
+	func() {
+		defer closeWrite(c2)
+
+		_, _ = io.Copy(c2, &activityReader{r: r, n: &f.sent, lastActivity: &lastActivity})
+	}()
+
+	<-ch
+}
+
+// closeWhenIdle closes both connections once lastActivity is older than the
+// idle timeout, which unblocks the copies in tunnel.
+func (g *gateway) closeWhenIdle(done <-chan struct{}, lastActivity *int64, c1, c2 net.Conn) {
+	interval := g.connections.idleTimeout / 4
+	if interval < time.Second {
+		interval = time.Second
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-t.C:
+			if time.Since(time.Unix(0, atomic.LoadInt64(lastActivity))) >= g.connections.idleTimeout {
+				_ = c1.Close()
+				_ = c2.Close()
+				return
+			}
+		}
+	}
+}
+
+// closeWrite half-closes c if the underlying connection supports it.
+func closeWrite(c net.Conn) {
+	if pc, ok := c.(*proxyproto.Conn); ok {
+		c = pc.Raw()
+	}
+
+	if cw, ok := c.(interface{ CloseWrite() error }); ok {
+		_ = cw.CloseWrite()
+	}
+}
+
+// recordConnection logs a finished connection and adds the rest of its traffic
+// to the totals, which are emitted periodically by emitMetrics.
+func (g *gateway) recordConnection(log *logrus.Entry, f *flow) {
+	g.connections.close(f)
+
+	log.WithFields(logrus.Fields{
+		"bytesSent":     atomic.LoadInt64(&f.sent),
+		"bytesReceived": atomic.LoadInt64(&f.received),
+		"duration":      time.Since(f.start).Seconds(),
+	}).Print("connection closed")
+}
@@ -0,0 +1,168 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"io"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/util/log"
+)
+
+func TestConnections(t *testing.T) {
+	c := newConnections(2, 0)
+
+	for _, tt := range []struct {
+		clusterResourceID string
+		want              bool
+	}{
+		{clusterResourceID: "a", want: true},
+		{clusterResourceID: "a", want: true},
+		{clusterResourceID: "a", want: false},
+		{clusterResourceID: "b", want: true},
+		{clusterResourceID: "c", want: true},
+	} {
+		if got := c.acquire(tt.clusterResourceID); got != tt.want {
+			t.Errorf("acquire(%s): got %v, want %v", tt.clusterResourceID, got, tt.want)
+		}
+	}
+
+	c.release("a")
+	c.release("c")
+
+	closed := c.open("a", "static.example.com")
+	closed.sent, closed.received = 5, 3
+	c.close(closed)
+
+	// the bytes of open connections are accounted before they close
+	open := c.open("a", "storage")
+	open.sent, open.received = 1, 2
+
+	byCluster, byClusterTraffic, byDestinationTraffic := c.snapshot()
+	if !reflect.DeepEqual(byCluster, map[string]int64{"a": 1, "b": 1, "c": 0}) {
+		t.Error(byCluster)
+	}
+	if byClusterTraffic["a"].connections != 1 || byClusterTraffic["a"].sent != 6 || byClusterTraffic["a"].received != 5 {
+		t.Error(byClusterTraffic)
+	}
+	if !reflect.DeepEqual(byDestinationTraffic["storage"], traffic{sent: 1, received: 2}) ||
+		byDestinationTraffic["static.example.com"].connections != 1 || byDestinationTraffic["static.example.com"].sent != 5 {
+		t.Error(byDestinationTraffic)
+	}
+
+	// entries which reached zero are only reported once, traffic is reset
+	// and only bytes copied since the last snapshot are accounted
+	open.sent = 4
+	byCluster, byClusterTraffic, byDestinationTraffic = c.snapshot()
+	if !reflect.DeepEqual(byCluster, map[string]int64{"a": 1, "b": 1}) {
+		t.Error(byCluster)
+	}
+	if !reflect.DeepEqual(byClusterTraffic, map[string]traffic{"a": {sent: 3}}) {
+		t.Error(byClusterTraffic)
+	}
+	if !reflect.DeepEqual(byDestinationTraffic, map[string]traffic{"storage": {sent: 3}}) {
+		t.Error(byDestinationTraffic)
+	}
+
+	byCluster, byClusterTraffic, byDestinationTraffic = c.snapshot()
+	if len(byClusterTraffic) != 0 || len(byDestinationTraffic) != 0 {
+		t.Error(byClusterTraffic, byDestinationTraffic)
+	}
+
+	c.close(open)
+	_, byClusterTraffic, _ = c.snapshot()
+	if byClusterTraffic["a"].connections != 1 || byClusterTraffic["a"].sent != 0 {
+		t.Error(byClusterTraffic)
+	}
+
+	if !c.acquire("a") {
+		t.Error("expected connection to be allowed after release")
+	}
+}
+
+func TestTunnel(t *testing.T) {
+	for _, tt := range []struct {
+		name         string
+		idleTimeout  time.Duration
+		closeClient  bool
+		wantSent     int64
+		wantReceived int64
+	}{
+		{
+			name:         "bytes are counted in both directions",
+			closeClient:  true,
+			wantSent:     5,
+			wantReceived: 3,
+		},
+		{
+			name:         "idle connections are closed",
+			idleTimeout:  time.Second,
+			wantSent:     5,
+			wantReceived: 3,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			g := &gateway{
+				log:         log.GetLogger(),
+				connections: newConnections(0, tt.idleTimeout),
+			}
+
+			client, c1 := net.Pipe()
+			c2, server := net.Pipe()
+
+			go func() {
+				_, _ = client.Write([]byte("hello"))
+				_, _ = io.ReadFull(client, make([]byte, 3))
+				if tt.closeClient {
+					client.Close()
+				}
+			}()
+
+			go func() {
+				_, _ = io.ReadFull(server, make([]byte, 5))
+				_, _ = server.Write([]byte("hey"))
+				if tt.closeClient {
+					server.Close()
+				}
+			}()
+
+			f := g.connections.open("cluster", "destination")
+
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				g.tunnel(f, c1, c1, c2)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(10 * time.Second):
+				t.Fatal("tunnel did not return")
+			}
+
+			if f.sent != tt.wantSent || f.received != tt.wantReceived {
+				t.Errorf("got sent %d received %d, want sent %d received %d", f.sent, f.received, tt.wantSent, tt.wantReceived)
+			}
+		})
+	}
+}
+
+func TestDestination(t *testing.T) {
+	for _, tt := range []struct {
+		host string
+		rule string
+		want string
+	}{
+		{host: "Management.Azure.com", rule: ruleStatic, want: "management.azure.com"},
+		{host: "cluster1234.blob.core.windows.net", rule: ruleStorage, want: ruleStorage},
+		{host: "api.example.com", rule: "egress:*.example.com:443", want: "egress:*.example.com:443"},
+	} {
+		if got := destination(tt.host, tt.rule); got != tt.want {
+			t.Errorf("destination(%s, %s): got %q, want %q", tt.host, tt.rule, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package gateway
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+)
+
+// Names of the rules which led to a gateway decision, as recorded in the
+// access log.  Egress allow list rules are recorded as "egress:<fqdn>:<ports>".
+const (
+	ruleStatic  = "static"
+	ruleStorage = "storage"
+	ruleDefault = "default"
+)
+
+// egressRuleMatchesHost returns true if host matches the FQDN of the rule.  A
+// rule FQDN "*.example.com" matches "a.example.com" and "a.b.example.com" but
+// not "example.com".
+func egressRuleMatchesHost(r api.GatewayEgressRule, host string) bool {
+	fqdn := strings.ToLower(r.FQDN)
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+
+	if host == "" {
+		return false
+	}
+
+	if strings.HasPrefix(fqdn, "*.") {
+		return strings.HasSuffix(host, fqdn[1:])
+	}
+
+	return host == fqdn
+}
+
+// egressRuleAllowsPort returns true if the rule allows connections to port.
+// Rules without ports only allow 443.
+func egressRuleAllowsPort(r api.GatewayEgressRule, port string) bool {
+	if len(r.Ports) == 0 {
+		return port == "443"
+	}
+
+	for _, p := range r.Ports {
+		if strconv.Itoa(p) == port {
+			return true
+		}
+	}
+
+	return false
+}
+
+func egressRuleString(r api.GatewayEgressRule) string {
+	ports := []string{"443"}
+	if len(r.Ports) > 0 {
+		ports = make([]string, 0, len(r.Ports))
+		for _, p := range r.Ports {
+			ports = append(ports, strconv.Itoa(p))
+		}
+	}
+
+	return "egress:" + strings.ToLower(r.FQDN) + ":" + strings.Join(ports, ",")
+}
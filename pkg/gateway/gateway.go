@@ -74,6 +74,8 @@ type gateway struct {
 	m                metrics.Emitter
 	httpConnections  int64
 	httpsConnections int64
+
+	connections *connections
 }
 
 type contextKey int
@@ -90,7 +92,11 @@ const SocketSize = 65536
 
 // TODO: may one day want to limit gateway readiness on # active connections
 
-func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.Entry, dbGateway database.Gateway, httpsl, httpl, httpHealthl net.Listener, acrResourceID, gatewayDomains string, m metrics.Emitter) (Runnable, error) {
+// NewGateway returns a new gateway.  maxConnectionsPerCluster caps the number of
+// concurrent connections any one cluster may hold open and idleTimeout closes
+// connections on which no data has flowed for that long; zero disables either
+// limit.
+func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.Entry, dbGateway database.Gateway, httpsl, httpl, httpHealthl net.Listener, acrResourceID, gatewayDomains string, maxConnectionsPerCluster int, idleTimeout time.Duration, m metrics.Emitter) (Runnable, error) {
 	var domains []string
 	if gatewayDomains != "" {
 		domains = strings.Split(gatewayDomains, ",")
@@ -172,6 +178,8 @@ func NewGateway(ctx context.Context, env env.Core, baseLog, accessLog *logrus.En
 
 		allowList: allowList,
 		m:         m,
+
+		connections: newConnections(maxConnectionsPerCluster, idleTimeout),
 	}
 
 	panicMiddleware := middleware.Panic(baseLog)
@@ -10,13 +10,16 @@ import (
 	"time"
 
 	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
 
-	"github.com/Azure/ARO-RP/pkg/proxy"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+	utilnet "github.com/Azure/ARO-RP/pkg/util/net"
 )
 
 // handleConnect handles incoming HTTP proxy HTTPS CONNECT requests.  The Host
 // header will indicate where the incoming connection wants to be connected to.
+// Unlike proxy.Proxy, the connection is tunnelled by the gateway itself so that
+// its traffic can be accounted to the cluster and its idle timeout enforced.
 func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -33,7 +36,7 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	clusterResourceID, isAllowed, err := g.isAllowed(conn, host)
+	clusterResourceID, isAllowed, rule, err := g.isAllowed(conn, host, port)
 	if err != nil {
 		g.log.Error(err)
 		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
@@ -41,9 +44,13 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log := utillog.EnrichWithResourceID(g.accessLog, clusterResourceID)
-	log = log.WithField("hostname", host)
+	log = log.WithFields(logrus.Fields{
+		"hostname": host,
+		"port":     port,
+		"rule":     rule,
+	})
 
-	if !isAllowed || port != "443" {
+	if !isAllowed {
 		log.Print("access denied")
 		g.m.EmitGauge("gateway.connections", 1, map[string]string{
 			"protocol": "http",
@@ -53,6 +60,17 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !g.connections.acquire(clusterResourceID) {
+		log.WithField("rule", ruleConnectionLimit).Print("access denied")
+		g.m.EmitGauge("gateway.connections", 1, map[string]string{
+			"protocol": "http",
+			"action":   "limited",
+		})
+		http.Error(w, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
+	}
+	defer g.connections.release(clusterResourceID)
+
 	log.Print("access allowed")
 	g.m.EmitGauge("gateway.connections", 1, map[string]string{
 		"protocol": "http",
@@ -62,7 +80,33 @@ func (g *gateway) handleConnect(w http.ResponseWriter, r *http.Request) {
 	atomic.AddInt64(&g.httpConnections, 1)
 	defer atomic.AddInt64(&g.httpConnections, -1)
 
-	proxy.Proxy(g.log, w, r, SocketSize)
+	c2, err := utilnet.Dial("tcp", r.Host, SocketSize)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	defer c2.Close()
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "hijacking not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+
+	c1, buf, err := hijacker.Hijack()
+	if err != nil {
+		g.log.Error(err)
+		return
+	}
+
+	defer c1.Close()
+
+	f := g.connections.open(clusterResourceID, destination(host, rule))
+	g.tunnel(f, buf, c1, c2)
+	g.recordConnection(log, f)
 }
 
 func (g *gateway) checkReady(w http.ResponseWriter, r *http.Request) {
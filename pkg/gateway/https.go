@@ -7,11 +7,11 @@ import (
 	"context"
 	"crypto/tls"
 	"errors"
-	"io"
 	"net"
 	"sync/atomic"
 
 	"github.com/pires/go-proxyproto"
+	"github.com/sirupsen/logrus"
 
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	utilnet "github.com/Azure/ARO-RP/pkg/util/net"
@@ -58,14 +58,17 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 	}
 
 	// 2. Determine if we allow the connection.
-	clusterResourceID, isAllowed, err := g.isAllowed(conn, serverName)
+	clusterResourceID, isAllowed, rule, err := g.isAllowed(conn, serverName, "443")
 	if err != nil {
 		g.log.Error(err)
 		return
 	}
 
 	log := utillog.EnrichWithResourceID(g.accessLog, clusterResourceID)
-	log = log.WithField("hostname", serverName)
+	log = log.WithFields(logrus.Fields{
+		"hostname": serverName,
+		"rule":     rule,
+	})
 
 	if !isAllowed {
 		log.Print("access denied")
@@ -76,6 +79,16 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 		return
 	}
 
+	if !g.connections.acquire(clusterResourceID) {
+		log.WithField("rule", ruleConnectionLimit).Print("access denied")
+		g.m.EmitGauge("gateway.connections", 1, map[string]string{
+			"protocol": "https",
+			"action":   "limited",
+		})
+		return
+	}
+	defer g.connections.release(clusterResourceID)
+
 	log.Print("access allowed")
 	g.m.EmitGauge("gateway.connections", 1, map[string]string{
 		"protocol": "https",
@@ -92,26 +105,9 @@ func (g *gateway) handleHTTPS(ctx context.Context, _c net.Conn) {
 	}
 
 	defer c2.Close()
-	ch := make(chan struct{})
 
 	// 4. Proxy c1<->c2.
-	go func() {
-		defer recover.Panic(g.log)
-		defer close(ch)
-		defer func() {
-			_ = conn.Raw().(*net.TCPConn).CloseWrite()
-		}()
-
-		_, _ = io.Copy(c1, c2)
-	}()
-
-	func() {
-		defer func() {
-			_ = c2.(*net.TCPConn).CloseWrite()
-		}()
-
-		_, _ = io.Copy(c2, c1)
-	}()
-
-	<-ch
+	f := g.connections.open(clusterResourceID, destination(serverName, rule))
+	g.tunnel(f, c1, conn, c2)
+	g.recordConnection(log, f)
 }
@@ -22,28 +22,29 @@ const (
 // header injected on the front of the TCP stream by PLS.  It uses this to do a
 // lookup of the gateway collection record in the in-memory cache (this is
 // populated by the Cosmos DB change feed).  It then makes a decision about
-// whether to allow the connection based on a static allow list and the
-// additional hostnames in the gateway record. It returns the cluster ID and
-// deny/allow decision.
-func (g *gateway) isAllowed(conn *proxyproto.Conn, host string) (string, bool, error) {
+// whether to allow the connection based on a static allow list, the
+// additional hostnames in the gateway record and the gateway record's egress
+// allow list. It returns the cluster ID, deny/allow decision and the rule
+// which led to the decision.
+func (g *gateway) isAllowed(conn *proxyproto.Conn, host, port string) (string, bool, string, error) {
 	linkID, err := linkID(conn)
 	if err != nil {
-		return "", false, err
+		return "", false, "", err
 	}
 
-	return g.gatewayVerification(host, linkID)
+	return g.gatewayVerification(host, port, linkID)
 }
 
-func (g *gateway) gatewayVerification(host, linkID string) (string, bool, error) {
+func (g *gateway) gatewayVerification(host, port, linkID string) (string, bool, string, error) {
 	g.mu.RLock()
 	gateway := g.gateways[linkID]
 	g.mu.RUnlock()
 
 	if gateway == nil {
-		return "", false, fmt.Errorf("gateway record not found for linkID %s", linkID)
+		return "", false, "", fmt.Errorf("gateway record not found for linkID %s", linkID)
 	}
 	if gateway.Deleting {
-		return gateway.ID, false, fmt.Errorf("gateway for linkId %s is being deleted", linkID)
+		return gateway.ID, false, "", fmt.Errorf("gateway for linkId %s is being deleted", linkID)
 	}
 
 	// Emit a gauge for the linkID if the host is empty
@@ -54,14 +55,25 @@ func (g *gateway) gatewayVerification(host, linkID string) (string, bool, error)
 		})
 	}
 
+	// the static allow list and the cluster's storage accounts are only
+	// reachable on 443
 	if _, found := g.allowList[strings.ToLower(host)]; found {
-		return gateway.ID, true, nil
+		return gateway.ID, port == "443", ruleStatic, nil
 	}
 
-	return gateway.ID,
-		strings.EqualFold(host, gateway.ImageRegistryStorageAccountName+".blob."+g.env.Environment().StorageEndpointSuffix) ||
-			strings.EqualFold(host, "cluster"+gateway.StorageSuffix+".blob."+g.env.Environment().StorageEndpointSuffix),
-		nil
+	if strings.EqualFold(host, gateway.ImageRegistryStorageAccountName+".blob."+g.env.Environment().StorageEndpointSuffix) ||
+		strings.EqualFold(host, "cluster"+gateway.StorageSuffix+".blob."+g.env.Environment().StorageEndpointSuffix) {
+		return gateway.ID, port == "443", ruleStorage, nil
+	}
+
+	// the first egress rule matching the host decides
+	for _, r := range gateway.EgressAllowList {
+		if egressRuleMatchesHost(r, host) {
+			return gateway.ID, egressRuleAllowsPort(r, port), egressRuleString(r), nil
+		}
+	}
+
+	return gateway.ID, false, ruleDefault, nil
 }
 
 // linkID retrieves the private endpoint link ID from the haproxy binary
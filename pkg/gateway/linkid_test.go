@@ -20,9 +20,11 @@ func TestGatewayVerification(t *testing.T) {
 	for _, tt := range []struct {
 		name          string
 		host          string
+		port          string
 		idParam       string
 		wantId        string
 		wantIsAllowed bool
+		wantRule      string
 		wantErr       string
 		deleting      bool
 		allowList     map[string]struct{}
@@ -33,6 +35,7 @@ func TestGatewayVerification(t *testing.T) {
 			idParam:       "1",
 			wantId:        "1",
 			wantIsAllowed: true,
+			wantRule:      "storage",
 		},
 		{
 			name:          "accepted id=2",
@@ -40,6 +43,7 @@ func TestGatewayVerification(t *testing.T) {
 			idParam:       "2",
 			wantId:        "2",
 			wantIsAllowed: true,
+			wantRule:      "storage",
 		},
 		{
 			name:          "accepted allowlist",
@@ -47,14 +51,68 @@ func TestGatewayVerification(t *testing.T) {
 			idParam:       "2",
 			wantId:        "2",
 			wantIsAllowed: true,
+			wantRule:      "static",
 			allowList:     map[string]struct{}{"redhat.com": {}},
 		},
+		{
+			name:          "allowlist on another port denied",
+			host:          "redhat.com",
+			port:          "80",
+			idParam:       "2",
+			wantId:        "2",
+			wantIsAllowed: false,
+			wantRule:      "static",
+			allowList:     map[string]struct{}{"redhat.com": {}},
+		},
+		{
+			name:          "accepted egress rule",
+			host:          "registry.example.com",
+			idParam:       "egress",
+			wantId:        "egress",
+			wantIsAllowed: true,
+			wantRule:      "egress:registry.example.com:443",
+		},
+		{
+			name:          "accepted egress wildcard rule on listed port",
+			host:          "a.b.mirror.example.com",
+			port:          "8443",
+			idParam:       "egress",
+			wantId:        "egress",
+			wantIsAllowed: true,
+			wantRule:      "egress:*.mirror.example.com:443,8443",
+		},
+		{
+			name:          "egress wildcard rule on unlisted port denied",
+			host:          "a.mirror.example.com",
+			port:          "22",
+			idParam:       "egress",
+			wantId:        "egress",
+			wantIsAllowed: false,
+			wantRule:      "egress:*.mirror.example.com:443,8443",
+		},
+		{
+			name:          "egress wildcard rule does not match its own domain",
+			host:          "mirror.example.com",
+			idParam:       "egress",
+			wantId:        "egress",
+			wantIsAllowed: false,
+			wantRule:      "default",
+		},
+		{
+			name:          "egress rule of another cluster denied",
+			host:          "registry.example.com",
+			idParam:       "1",
+			wantId:        "1",
+			wantIsAllowed: false,
+			wantRule:      "default",
+		},
 		{
 			name:          "middle part not valid",
 			host:          "account1.notblob.storageEndpointSuffix",
 			idParam:       "1",
 			wantId:        "1",
 			wantIsAllowed: false,
+			wantRule:      "default",
 		},
 		{
 			name:          "suffix not valid",
@@ -62,6 +120,7 @@ func TestGatewayVerification(t *testing.T) {
 			idParam:       "1",
 			wantId:        "1",
 			wantIsAllowed: false,
+			wantRule:      "default",
 		},
 		{
 			name:          "no gateway",
@@ -76,6 +135,7 @@ func TestGatewayVerification(t *testing.T) {
 			idParam:       "1",
 			wantId:        "1",
 			wantIsAllowed: false,
+			wantRule:      "default",
 		},
 		{
 			name:     "gateway deleting",
@@ -93,6 +153,10 @@ func TestGatewayVerification(t *testing.T) {
 				"1":        {ID: "1", StorageSuffix: "suffix-1", ImageRegistryStorageAccountName: "account1"},
 				"2":        {ID: "2", StorageSuffix: "suffix-2", ImageRegistryStorageAccountName: "account2"},
 				"deleting": {ID: "deleting", StorageSuffix: "suffix-5", ImageRegistryStorageAccountName: "account5", Deleting: true},
+				"egress": {ID: "egress", StorageSuffix: "suffix-6", ImageRegistryStorageAccountName: "account6", EgressAllowList: []api.GatewayEgressRule{
+					{FQDN: "Registry.example.com"},
+					{FQDN: "*.mirror.example.com", Ports: []int{443, 8443}},
+				}},
 			}
 
 			mockCore := mock_env.NewMockCore(mockController)
@@ -118,7 +182,12 @@ func TestGatewayVerification(t *testing.T) {
 				allowList: tt.allowList,
 			}
 
-			gatewayID, isAllowed, err := gateway.gatewayVerification(tt.host, tt.idParam)
+			port := tt.port
+			if port == "" {
+				port = "443"
+			}
+
+			gatewayID, isAllowed, rule, err := gateway.gatewayVerification(tt.host, port, tt.idParam)
 
 			if gatewayID != tt.wantId {
 				t.Error(gatewayID)
@@ -128,6 +197,10 @@ func TestGatewayVerification(t *testing.T) {
 				t.Error(isAllowed)
 			}
 
+			if rule != tt.wantRule {
+				t.Error(rule)
+			}
+
 			utilerror.AssertErrorMessage(t, err, tt.wantErr)
 		})
 	}
@@ -30,6 +30,53 @@ func (g *gateway) _emitMetrics() {
 		"protocol": "https",
 	})
 
+	byCluster, byClusterTraffic, byDestinationTraffic := g.connections.snapshot()
+	for clusterResourceID, n := range byCluster {
+		g.m.EmitGauge("gateway.connections.cluster.open", n, map[string]string{
+			"resourceId": clusterResourceID,
+		})
+	}
+
+	for clusterResourceID, t := range byClusterTraffic {
+		g.m.EmitGauge("gateway.connections.cluster.closed", t.connections, map[string]string{
+			"resourceId": clusterResourceID,
+		})
+
+		g.m.EmitGauge("gateway.connections.cluster.duration", t.duration.Milliseconds(), map[string]string{
+			"resourceId": clusterResourceID,
+		})
+
+		g.m.EmitGauge("gateway.connections.cluster.bytes", t.sent, map[string]string{
+			"resourceId": clusterResourceID,
+			"direction":  "sent",
+		})
+
+		g.m.EmitGauge("gateway.connections.cluster.bytes", t.received, map[string]string{
+			"resourceId": clusterResourceID,
+			"direction":  "received",
+		})
+	}
+
+	for destination, t := range byDestinationTraffic {
+		g.m.EmitGauge("gateway.connections.destination.closed", t.connections, map[string]string{
+			"destination": destination,
+		})
+
+		g.m.EmitGauge("gateway.connections.destination.duration", t.duration.Milliseconds(), map[string]string{
+			"destination": destination,
+		})
+
+		g.m.EmitGauge("gateway.connections.destination.bytes", t.sent, map[string]string{
+			"destination": destination,
+			"direction":   "sent",
+		})
+
+		g.m.EmitGauge("gateway.connections.destination.bytes", t.received, map[string]string{
+			"destination": destination,
+			"direction":   "received",
+		})
+	}
+
 	if lastChangefeed, ok := g.lastChangefeed.Load().(time.Time); ok {
 		g.m.EmitGauge("gateway.lastchangefeed", lastChangefeed.Unix(), nil)
 	}
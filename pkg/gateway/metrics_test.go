@@ -19,12 +19,22 @@ func TestEmitMetrics(t *testing.T) {
 		name               string
 		httpConnections    int64
 		httpsConnections   int64
+		connections        []string
+		traffic            map[string]traffic
+		destinations       map[string]traffic
 		lastChangefeedTime time.Time
 	}{
 		{
-			name:               "1 http connection 1 https connection no lastChangefeed",
-			httpConnections:    1,
-			httpsConnections:   1,
+			name:             "1 http connection 1 https connection no lastChangefeed",
+			httpConnections:  1,
+			httpsConnections: 1,
+			connections:      []string{"cluster"},
+			traffic: map[string]traffic{
+				"cluster": {connections: 2, duration: 1500 * time.Millisecond, sent: 5, received: 3},
+			},
+			destinations: map[string]traffic{
+				"management.azure.com": {connections: 2, duration: 1500 * time.Millisecond, sent: 5, received: 3},
+			},
 			lastChangefeedTime: testStartTime,
 		},
 		{
@@ -44,6 +54,28 @@ func TestEmitMetrics(t *testing.T) {
 				m:                mock_metrics,
 				httpConnections:  tt.httpConnections,
 				httpsConnections: tt.httpsConnections,
+				connections:      newConnections(0, 0),
+			}
+
+			for _, clusterResourceID := range tt.connections {
+				gateway.connections.acquire(clusterResourceID)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.cluster.open", int64(1), map[string]string{"resourceId": clusterResourceID}).Times(1)
+			}
+
+			for clusterResourceID, tr := range tt.traffic {
+				gateway.connections.traffic[clusterResourceID] = tr
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.cluster.closed", tr.connections, map[string]string{"resourceId": clusterResourceID}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.cluster.duration", tr.duration.Milliseconds(), map[string]string{"resourceId": clusterResourceID}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.cluster.bytes", tr.sent, map[string]string{"resourceId": clusterResourceID, "direction": "sent"}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.cluster.bytes", tr.received, map[string]string{"resourceId": clusterResourceID, "direction": "received"}).Times(1)
+			}
+
+			for destination, tr := range tt.destinations {
+				gateway.connections.byDestination[destination] = tr
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.destination.closed", tr.connections, map[string]string{"destination": destination}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.destination.duration", tr.duration.Milliseconds(), map[string]string{"destination": destination}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.destination.bytes", tr.sent, map[string]string{"destination": destination, "direction": "sent"}).Times(1)
+				mock_metrics.EXPECT().EmitGauge("gateway.connections.destination.bytes", tr.received, map[string]string{"destination": destination, "direction": "received"}).Times(1)
 			}
 
 			if !tt.lastChangefeedTime.Equal(testStartTime) {
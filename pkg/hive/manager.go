@@ -6,7 +6,6 @@ package hive
 import (
 	"context"
 	"errors"
-	"fmt"
 	"sort"
 
 	hivev1 "github.com/openshift/hive/apis/hive/v1"
@@ -71,12 +70,7 @@ func NewFromEnv(ctx context.Context, log *logrus.Entry, env env.Interface) (Clus
 		log.Infof("hive is disabled, skipping creation of ClusterManager")
 		return nil, nil
 	}
-	hiveShard := 1
-	hiveRestConfig, err := env.LiveConfig().HiveRestConfig(ctx, hiveShard)
-	if err != nil {
-		return nil, fmt.Errorf("failed getting RESTConfig for Hive shard %d: %w", hiveShard, err)
-	}
-	return NewFromConfig(log, env, hiveRestConfig)
+	return NewFromEnvForShard(ctx, log, env, DefaultShard)
 }
 
 // NewFromConfig creates a ClusterManager.
@@ -0,0 +1,86 @@
+package hive
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/util/liveconfig"
+)
+
+// DefaultShard is the Hive shard used by clusters which were created before
+// shard assignment was introduced.
+const DefaultShard = 1
+
+// ShardFor returns the Hive shard which manages the cluster.
+func ShardFor(doc *api.OpenShiftClusterDocument) int {
+	if doc.OpenShiftCluster.Properties.HiveProfile.Shard == 0 {
+		return DefaultShard
+	}
+	return doc.OpenShiftCluster.Properties.HiveProfile.Shard
+}
+
+// SelectShard returns the shard to which a new cluster should be assigned,
+// given the number of clusters currently assigned to each shard. Draining
+// shards and shards at capacity are never selected. Of the remaining shards,
+// the one with the most free capacity is chosen, with shards of unbounded
+// capacity ranked by their usage alone. Ties go to the lowest index.
+func SelectShard(shards []liveconfig.HiveShard, usage map[int]int) (int, error) {
+	selected := 0
+	var selectedUnbounded bool
+	var selectedFree, selectedUsage int
+
+	for _, shard := range shards {
+		if shard.Draining {
+			continue
+		}
+
+		used := usage[shard.Index]
+		unbounded := shard.Capacity == 0
+		free := shard.Capacity - used
+		if !unbounded && free <= 0 {
+			continue
+		}
+
+		var better bool
+		switch {
+		case selected == 0:
+			better = true
+		case unbounded != selectedUnbounded:
+			better = unbounded
+		case unbounded:
+			better = used < selectedUsage
+		default:
+			better = free > selectedFree
+		}
+
+		if better {
+			selected = shard.Index
+			selectedUnbounded = unbounded
+			selectedFree = free
+			selectedUsage = used
+		}
+	}
+
+	if selected == 0 {
+		return 0, errors.New("no Hive shard has capacity for a new cluster")
+	}
+
+	return selected, nil
+}
+
+// NewFromEnvForShard returns a ClusterManager for the given Hive shard.
+func NewFromEnvForShard(ctx context.Context, log *logrus.Entry, env env.Interface, shard int) (ClusterManager, error) {
+	hiveRestConfig, err := env.LiveConfig().HiveRestConfig(ctx, shard)
+	if err != nil {
+		return nil, fmt.Errorf("failed getting RESTConfig for Hive shard %d: %w", shard, err)
+	}
+	return NewFromConfig(log, env, hiveRestConfig)
+}
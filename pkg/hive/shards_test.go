@@ -0,0 +1,65 @@
+package hive
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/util/liveconfig"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestSelectShard(t *testing.T) {
+	for _, tt := range []struct {
+		name    string
+		shards  []liveconfig.HiveShard
+		usage   map[int]int
+		want    int
+		wantErr string
+	}{
+		{
+			name:   "single shard",
+			shards: []liveconfig.HiveShard{{Index: 1}},
+			usage:  map[int]int{1: 500},
+			want:   1,
+		},
+		{
+			name:   "most free capacity wins",
+			shards: []liveconfig.HiveShard{{Index: 1, Capacity: 100}, {Index: 2, Capacity: 100}, {Index: 3, Capacity: 50}},
+			usage:  map[int]int{1: 60, 2: 30, 3: 0},
+			want:   2,
+		},
+		{
+			name:   "unbounded shards preferred, least used first",
+			shards: []liveconfig.HiveShard{{Index: 1, Capacity: 1000}, {Index: 2}, {Index: 3}},
+			usage:  map[int]int{1: 0, 2: 20, 3: 10},
+			want:   3,
+		},
+		{
+			name:   "ties go to the lowest index",
+			shards: []liveconfig.HiveShard{{Index: 1}, {Index: 2}},
+			want:   1,
+		},
+		{
+			name:   "draining and full shards are skipped",
+			shards: []liveconfig.HiveShard{{Index: 1, Draining: true}, {Index: 2, Capacity: 10}, {Index: 3, Capacity: 20}},
+			usage:  map[int]int{2: 5, 3: 20},
+			want:   2,
+		},
+		{
+			name:    "no shard available",
+			shards:  []liveconfig.HiveShard{{Index: 1, Draining: true}, {Index: 2, Capacity: 10}},
+			usage:   map[int]int{2: 10},
+			wantErr: "no Hive shard has capacity for a new cluster",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectShard(tt.shards, tt.usage)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+			if got != tt.want {
+				t.Errorf("got shard %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,11 +4,14 @@ package mirror
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"mime"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/Azure/ARO-RP/pkg/util/version"
 )
@@ -19,15 +22,42 @@ type Node struct {
 	Metadata map[string]interface{} `json:"metadata,omitempty"`
 }
 
-// AddFromGraph adds all nodes whose version is of the form x.y.z (no suffix)
-// and >= min
-func AddFromGraph(min *version.Version) ([]Node, error) {
-	req, err := http.NewRequest(http.MethodGet, "https://amd64.ocp.releases.ci.openshift.org/graph", nil)
+// Graph is the Cincinnati upgrade graph.  Each edge is a pair of indexes into
+// Nodes, from the version being upgraded to the version it may upgrade to
+type Graph struct {
+	Nodes []Node   `json:"nodes,omitempty"`
+	Edges [][2]int `json:"edges,omitempty"`
+}
+
+// DefaultUpgradeGraphURL is the production Cincinnati endpoint, which serves
+// the upgrade graph of a release channel
+const DefaultUpgradeGraphURL = "https://api.openshift.com/api/upgrades_info/v1/graph"
+
+// ciGraphURL is the Cincinnati endpoint of CI releases, whose payloads are
+// mirrored
+const ciGraphURL = "https://amd64.ocp.releases.ci.openshift.org/graph"
+
+// graphTimeout bounds how long fetching a graph may take
+const graphTimeout = 30 * time.Second
+
+// GetGraph fetches the Cincinnati upgrade graph from url.  If channel is set,
+// only the graph of that release channel is fetched
+func GetGraph(ctx context.Context, url, channel string) (*Graph, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	resp, err := http.DefaultClient.Do(req)
+	if channel != "" {
+		q := req.URL.Query()
+		q.Set("channel", channel)
+		req.URL.RawQuery = q.Encode()
+	}
+	req.Header.Set("Accept", "application/json")
+
+	c := &http.Client{Timeout: graphTimeout}
+
+	resp, err := c.Do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -42,20 +72,51 @@ func AddFromGraph(min *version.Version) ([]Node, error) {
 		return nil, err
 	}
 
-	if mediaType != "application/vnd.redhat.cincinnati.graph+json" {
+	if mediaType != "application/vnd.redhat.cincinnati.graph+json" &&
+		mediaType != "application/json" {
 		return nil, fmt.Errorf("unexpected content type %q", resp.Header.Get("Content-Type"))
 	}
 
-	var g *struct {
-		Nodes []Node   `json:"nodes,omitempty"`
-		Edges [][2]int `json:"edges,omitempty"`
-	}
+	var g *Graph
 
 	err = json.NewDecoder(resp.Body).Decode(&g)
 	if err != nil {
 		return nil, err
 	}
 
+	if g == nil || len(g.Nodes) == 0 {
+		return nil, errors.New("upgrade graph is empty")
+	}
+
+	return g, nil
+}
+
+// HasEdge returns true if the graph contains a direct upgrade edge from one
+// version to the other
+func (g *Graph) HasEdge(from, to *version.Version) bool {
+	for _, edge := range g.Edges {
+		if edge[0] < 0 || edge[0] >= len(g.Nodes) ||
+			edge[1] < 0 || edge[1] >= len(g.Nodes) {
+			continue
+		}
+
+		if strings.EqualFold(g.Nodes[edge[0]].Version, from.String()) &&
+			strings.EqualFold(g.Nodes[edge[1]].Version, to.String()) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddFromGraph adds all nodes whose version is of the form x.y.z (no suffix)
+// and >= min
+func AddFromGraph(min *version.Version) ([]Node, error) {
+	g, err := GetGraph(context.Background(), ciGraphURL, "")
+	if err != nil {
+		return nil, err
+	}
+
 	releases := make([]Node, 0, len(g.Nodes))
 	for _, node := range g.Nodes {
 		vsn, err := version.ParseVersion(node.Version)
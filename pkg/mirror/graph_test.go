@@ -0,0 +1,77 @@
+package mirror
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/util/version"
+)
+
+func TestGetGraph(t *testing.T) {
+	ctx := context.Background()
+
+	for _, tt := range []struct {
+		name        string
+		contentType string
+		body        string
+		wantErr     string
+	}{
+		{
+			name:        "production content type",
+			contentType: "application/json",
+		},
+		{
+			name:        "cincinnati content type",
+			contentType: "application/vnd.redhat.cincinnati.graph+json",
+		},
+		{
+			name:        "null graph",
+			contentType: "application/json",
+			body:        "null",
+			wantErr:     "upgrade graph is empty",
+		},
+		{
+			name:        "graph without nodes",
+			contentType: "application/json",
+			body:        `{"nodes":[],"edges":[]}`,
+			wantErr:     "upgrade graph is empty",
+		},
+		{
+			name:        "unexpected content type",
+			contentType: "text/html",
+			wantErr:     `unexpected content type "text/html"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Query().Get("channel") != "stable-4.13" {
+					t.Errorf("unexpected channel %q", r.URL.Query().Get("channel"))
+				}
+
+				body := tt.body
+				if body == "" {
+					body = `{"nodes":[{"version":"4.12.25"},{"version":"4.13.4"}],"edges":[[0,1]]}`
+				}
+
+				w.Header().Set("Content-Type", tt.contentType)
+				_, _ = w.Write([]byte(body))
+			}))
+			defer s.Close()
+
+			g, err := GetGraph(ctx, s.URL, "stable-4.13")
+			if err != nil && err.Error() != tt.wantErr ||
+				err == nil && tt.wantErr != "" {
+				t.Fatal(err)
+			}
+
+			if err == nil && !g.HasEdge(version.NewVersion(4, 12, 25), version.NewVersion(4, 13, 4)) {
+				t.Error(g)
+			}
+		})
+	}
+}
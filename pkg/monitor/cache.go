@@ -5,6 +5,7 @@ package monitor
 
 import (
 	"math/rand"
+	"sort"
 	"time"
 
 	"github.com/Azure/ARO-RP/pkg/api"
@@ -13,6 +14,10 @@ import (
 type cacheDoc struct {
 	doc  *api.OpenShiftClusterDocument
 	stop chan<- struct{}
+
+	// lastCycle is how long the most recent monitoring cycle of the cluster
+	// took
+	lastCycle time.Duration
 }
 
 // deleteDoc deletes the given document from mon.docs, signalling the associated
@@ -70,3 +75,41 @@ func (mon *monitor) fixDoc(doc *api.OpenShiftClusterDocument) {
 		go mon.worker(ch, delay, doc.ID)
 	}
 }
+
+// setLastCycle records how long the most recent monitoring cycle of the given
+// document took.
+func (mon *monitor) setLastCycle(id string, d time.Duration) {
+	mon.mu.Lock()
+	defer mon.mu.Unlock()
+
+	if v := mon.docs[id]; v != nil {
+		v.lastCycle = d
+	}
+}
+
+// bucketLoads returns the monitoring load of each bucket owned by us, to be
+// reported to the master for balancing.
+func (mon *monitor) bucketLoads() []api.MonitorBucketLoad {
+	mon.mu.RLock()
+	defer mon.mu.RUnlock()
+
+	loads := make(map[int]*api.MonitorBucketLoad, len(mon.buckets))
+	for i := range mon.buckets {
+		loads[i] = &api.MonitorBucketLoad{Bucket: i}
+	}
+
+	for _, v := range mon.docs {
+		if l := loads[v.doc.Bucket]; l != nil {
+			l.Clusters++
+			l.CycleSeconds += v.lastCycle.Seconds()
+		}
+	}
+
+	bucketLoads := make([]api.MonitorBucketLoad, 0, len(loads))
+	for _, l := range loads {
+		bucketLoads = append(bucketLoads, *l)
+	}
+	sort.Slice(bucketLoads, func(i, j int) bool { return bucketLoads[i].Bucket < bucketLoads[j].Bucket })
+
+	return bucketLoads
+}
@@ -5,6 +5,7 @@ package cluster
 
 import (
 	"context"
+	"sync"
 
 	configv1 "github.com/openshift/api/config/v1"
 	appsv1 "k8s.io/api/apps/v1"
@@ -16,46 +17,60 @@ import (
 
 // Anything that caches a List is an anti-pattern because of the potential
 // memory usage.  Don't add caches here: work to remove them.
+//
+// Collectors run concurrently.  Each cached value has its own lock, held while
+// it is populated: concurrent collectors don't issue the same List twice, and
+// only wait for Lists which they need themselves.
 
-func (mon *Monitor) getClusterVersion(ctx context.Context) (*configv1.ClusterVersion, error) {
-	if mon.cache.cv != nil {
-		return mon.cache.cv, nil
+// cached is a value which is fetched at most once per monitor run
+type cached[T any] struct {
+	mu    sync.Mutex
+	value T
+	ok    bool
+}
+
+// get returns the cached value, calling fetch if it isn't cached yet.  Errors
+// aren't cached, so a later caller fetches the value again
+func (c *cached[T]) get(fetch func() (T, error)) (T, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.ok {
+		return c.value, nil
 	}
 
-	var err error
-	mon.cache.cv, err = mon.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
-	return mon.cache.cv, err
+	value, err := fetch()
+	if err != nil {
+		return value, err
+	}
+
+	c.value, c.ok = value, true
+	return c.value, nil
+}
+
+func (mon *Monitor) getClusterVersion(ctx context.Context) (*configv1.ClusterVersion, error) {
+	return mon.cache.cv.get(func() (*configv1.ClusterVersion, error) {
+		return mon.configcli.ConfigV1().ClusterVersions().Get(ctx, "version", metav1.GetOptions{})
+	})
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listClusterOperators(ctx context.Context) (*configv1.ClusterOperatorList, error) {
-	if mon.cache.cos != nil {
-		return mon.cache.cos, nil
-	}
-
-	var err error
-	mon.cache.cos, err = mon.configcli.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
-	return mon.cache.cos, err
+	return mon.cache.cos.get(func() (*configv1.ClusterOperatorList, error) {
+		return mon.configcli.ConfigV1().ClusterOperators().List(ctx, metav1.ListOptions{})
+	})
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listNodes(ctx context.Context) (*corev1.NodeList, error) {
-	if mon.cache.ns != nil {
-		return mon.cache.ns, nil
-	}
-
-	var err error
-	mon.cache.ns, err = mon.cli.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
-	return mon.cache.ns, err
+	return mon.cache.ns.get(func() (*corev1.NodeList, error) {
+		return mon.cli.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	})
 }
 
 // TODO: remove this function and paginate
 func (mon *Monitor) listARODeployments(ctx context.Context) (*appsv1.DeploymentList, error) {
-	if mon.cache.arodl != nil {
-		return mon.cache.arodl, nil
-	}
-
-	var err error
-	mon.cache.arodl, err = mon.cli.AppsV1().Deployments(pkgoperator.Namespace).List(ctx, metav1.ListOptions{})
-	return mon.cache.arodl, err
+	return mon.cache.arodl.get(func() (*appsv1.DeploymentList, error) {
+		return mon.cli.AppsV1().Deployments(pkgoperator.Namespace).List(ctx, metav1.ListOptions{})
+	})
 }
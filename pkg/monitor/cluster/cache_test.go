@@ -0,0 +1,77 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestCached(t *testing.T) {
+	t.Run("concurrent callers fetch once", func(t *testing.T) {
+		var c cached[int]
+		var mu sync.Mutex
+		var fetches int
+
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				v, err := c.get(func() (int, error) {
+					mu.Lock()
+					defer mu.Unlock()
+					fetches++
+					return 42, nil
+				})
+				if err != nil || v != 42 {
+					t.Error(v, err)
+				}
+			}()
+		}
+		wg.Wait()
+
+		if fetches != 1 {
+			t.Error(fetches)
+		}
+	})
+
+	t.Run("errors are not cached", func(t *testing.T) {
+		var c cached[int]
+
+		_, err := c.get(func() (int, error) { return 0, errors.New("failed") })
+		if err == nil {
+			t.Fatal("expected error")
+		}
+
+		v, err := c.get(func() (int, error) { return 42, nil })
+		if err != nil || v != 42 {
+			t.Error(v, err)
+		}
+	})
+
+	t.Run("a slow fetch doesn't block other values", func(t *testing.T) {
+		var slow, fast cached[int]
+		release := make(chan struct{})
+		started := make(chan struct{})
+
+		go func() {
+			_, _ = slow.get(func() (int, error) {
+				close(started)
+				<-release
+				return 1, nil
+			})
+		}()
+		<-started
+
+		v, err := fast.get(func() (int, error) { return 2, nil })
+		if err != nil || v != 2 {
+			t.Error(v, err)
+		}
+
+		close(release)
+	})
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"net/http"
 	"sync"
+	"time"
 
 	"github.com/Azure/go-autorest/autorest/azure"
 	configv1 "github.com/openshift/api/config/v1"
@@ -36,6 +37,7 @@ var _ monitoring.Monitor = (*Monitor)(nil)
 type Monitor struct {
 	log       *logrus.Entry
 	hourlyRun bool
+	schedule  *Schedule
 
 	oc   *api.OpenShiftCluster
 	dims map[string]string
@@ -53,17 +55,17 @@ type Monitor struct {
 
 	// access below only via the helper functions in cache.go
 	cache struct {
-		cos   *configv1.ClusterOperatorList
-		cs    *arov1alpha1.ClusterList
-		cv    *configv1.ClusterVersion
-		ns    *corev1.NodeList
-		arodl *appsv1.DeploymentList
+		cos   cached[*configv1.ClusterOperatorList]
+		cs    cached[*arov1alpha1.ClusterList]
+		cv    cached[*configv1.ClusterVersion]
+		ns    cached[*corev1.NodeList]
+		arodl cached[*appsv1.DeploymentList]
 	}
 
 	wg *sync.WaitGroup
 }
 
-func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, hourlyRun bool, wg *sync.WaitGroup) (*Monitor, error) {
+func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftCluster, m metrics.Emitter, hiveRestConfig *rest.Config, hourlyRun bool, schedule *Schedule, wg *sync.WaitGroup) (*Monitor, error) {
 	r, err := azure.ParseResourceID(oc.ID)
 	if err != nil {
 		return nil, err
@@ -122,6 +124,7 @@ func NewMonitor(log *logrus.Entry, restConfig *rest.Config, oc *api.OpenShiftClu
 	return &Monitor{
 		log:       log,
 		hourlyRun: hourlyRun,
+		schedule:  schedule,
 
 		oc:   oc,
 		dims: dims,
@@ -159,70 +162,33 @@ func getHiveClientSet(hiveRestConfig *rest.Config) (client.Client, error) {
 	return hiveclientset, nil
 }
 
-// Monitor checks the API server health of a cluster
+// Monitor checks the API server health of a cluster, then runs the
+// registered collectors which apply to the result
 func (mon *Monitor) Monitor(ctx context.Context) (errs []error) {
 	defer mon.wg.Done()
 
 	mon.log.Debug("monitoring")
 
-	if mon.hourlyRun {
-		mon.emitGauge("cluster.provisioning", 1, map[string]string{
-			"provisioningState":       mon.oc.Properties.ProvisioningState.String(),
-			"failedProvisioningState": mon.oc.Properties.FailedProvisioningState.String(),
-		})
-	}
-
 	//this API server healthz check must be first, our geneva monitor relies on this metric to always be emitted.
 	statusCode, err := mon.emitAPIServerHealthzCode(ctx)
 	if err != nil {
 		errs = append(errs, err)
 		mon.emitFailureToGatherMetric(steps.FriendlyName(mon.emitAPIServerHealthzCode), err)
 	}
-	// If API is not returning 200, fallback to checking ping and short circuit the rest of the checks
-	if statusCode != http.StatusOK {
-		err := mon.emitAPIServerPingCode(ctx)
-		if err != nil {
-			errs = append(errs, err)
-			mon.emitFailureToGatherMetric(steps.FriendlyName(mon.emitAPIServerPingCode), err)
-		}
-		return
-	}
-	for _, f := range []func(context.Context) error{
-		mon.emitAroOperatorHeartbeat,
-		mon.emitAroOperatorConditions,
-		mon.emitNSGReconciliation,
-		mon.emitClusterOperatorConditions,
-		mon.emitClusterOperatorVersions,
-		mon.emitClusterVersionConditions,
-		mon.emitClusterVersions,
-		mon.emitDaemonsetStatuses,
-		mon.emitDeploymentStatuses,
-		mon.emitMachineConfigPoolConditions,
-		mon.emitMachineConfigPoolUnmanagedNodeCounts,
-		mon.emitNodeConditions,
-		mon.emitPodConditions,
-		mon.emitDebugPodsCount,
-		mon.detectQuotaFailure,
-		mon.emitReplicasetStatuses,
-		mon.emitStatefulsetStatuses,
-		mon.emitJobConditions,
-		mon.emitSummary,
-		mon.emitHiveRegistrationStatus,
-		mon.emitOperatorFlagsAndSupportBanner,
-		mon.emitMaintenanceState,
-		mon.emitCertificateExpirationStatuses,
-		mon.emitEtcdCertificateExpiry,
-		mon.emitPrometheusAlerts, // at the end for now because it's the slowest/least reliable
-	} {
-		err = f(ctx)
-		if err != nil {
-			errs = append(errs, err)
-			mon.emitFailureToGatherMetric(steps.FriendlyName(f), err)
-			// keep going
+
+	// If API is not returning 200, only the collectors which expect this
+	// (e.g. falling back to checking ping) are run
+	healthy := statusCode == http.StatusOK
+	now := time.Now()
+
+	var due []*collector
+	for _, c := range collectors {
+		if c.shouldRun(mon, healthy, now) {
+			due = append(due, c)
 		}
 	}
 
-	return
+	return append(errs, mon.runCollectors(ctx, due)...)
 }
 
 func (mon *Monitor) emitFailureToGatherMetric(friendlyFuncName string, err error) {
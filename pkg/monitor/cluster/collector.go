@@ -0,0 +1,152 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+	"github.com/Azure/ARO-RP/pkg/util/steps"
+)
+
+// maxConcurrentCollectors bounds how many collectors run at once against a
+// single cluster, to avoid overwhelming its API server.
+const maxConcurrentCollectors = 5
+
+// scheduleTolerance allows for jitter in the monitoring period, so that a
+// collector with an interval of N minutes is not pushed back to N+1 minutes
+// because the worker ticked slightly early.
+const scheduleTolerance = 10 * time.Second
+
+// healthzDependency describes which API server healthz results a collector
+// should run for.
+type healthzDependency int
+
+const (
+	// healthzOK collectors only run when /healthz returns 200. This is the
+	// default.
+	healthzOK healthzDependency = iota
+	// healthzNotOK collectors only run when /healthz does not return 200.
+	healthzNotOK
+	// healthzAny collectors run regardless of the /healthz result.
+	healthzAny
+)
+
+// collector is a single check run by the Monitor.
+type collector struct {
+	f func(*Monitor, context.Context) error
+
+	// interval is the minimum time between runs of the collector for a
+	// cluster. Zero means every monitoring period.
+	interval time.Duration
+
+	// timeout bounds a single run of the collector. Zero means the collector
+	// is only bounded by the Monitor's context.
+	timeout time.Duration
+
+	// hourlyOnly collectors only run on the first monitoring period of each
+	// hour.
+	hourlyOnly bool
+
+	healthz healthzDependency
+}
+
+func (c *collector) name() string {
+	return steps.FriendlyName(c.f)
+}
+
+func (c *collector) shouldRun(mon *Monitor, healthy bool, now time.Time) bool {
+	switch c.healthz {
+	case healthzOK:
+		if !healthy {
+			return false
+		}
+	case healthzNotOK:
+		if healthy {
+			return false
+		}
+	}
+
+	if c.hourlyOnly && !mon.hourlyRun {
+		return false
+	}
+
+	return mon.schedule.due(c, now)
+}
+
+// Schedule records when each collector last ran against a cluster, so that
+// collectors with an interval are skipped in between. A Schedule should be
+// kept for the lifetime of a cluster's monitoring worker; a nil Schedule runs
+// every collector every time.
+type Schedule struct {
+	mu      sync.Mutex
+	lastRun map[string]time.Time
+}
+
+func NewSchedule() *Schedule {
+	return &Schedule{
+		lastRun: map[string]time.Time{},
+	}
+}
+
+// due returns whether the collector should run at now, and if so records it
+// as having run.
+func (s *Schedule) due(c *collector, now time.Time) bool {
+	if s == nil || c.interval == 0 {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if last, ok := s.lastRun[c.name()]; ok && now.Sub(last) < c.interval-scheduleTolerance {
+		return false
+	}
+
+	s.lastRun[c.name()] = now
+	return true
+}
+
+// runCollectors runs the given collectors concurrently, at most
+// maxConcurrentCollectors at a time, and returns any errors they encounter.
+func (mon *Monitor) runCollectors(ctx context.Context, collectors []*collector) (errs []error) {
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentCollectors)
+	)
+
+	for _, c := range collectors {
+		wg.Add(1)
+		go func(c *collector) {
+			defer wg.Done()
+			defer recover.Panic(mon.log)
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			ctx := ctx
+			if c.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, c.timeout)
+				defer cancel()
+			}
+
+			err := c.f(mon, ctx)
+			if err != nil {
+				mon.emitFailureToGatherMetric(c.name(), err)
+
+				mu.Lock()
+				errs = append(errs, err)
+				mu.Unlock()
+			}
+		}(c)
+	}
+
+	wg.Wait()
+
+	return errs
+}
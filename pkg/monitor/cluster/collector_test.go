@@ -0,0 +1,172 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestCollectorShouldRun(t *testing.T) {
+	now := time.Now()
+
+	for _, tt := range []struct {
+		name      string
+		c         *collector
+		healthy   bool
+		hourlyRun bool
+		want      bool
+	}{
+		{
+			name:    "default collector runs when healthy",
+			c:       &collector{},
+			healthy: true,
+			want:    true,
+		},
+		{
+			name: "default collector skipped when unhealthy",
+			c:    &collector{},
+		},
+		{
+			name:    "healthzNotOK collector skipped when healthy",
+			c:       &collector{healthz: healthzNotOK},
+			healthy: true,
+		},
+		{
+			name: "healthzNotOK collector runs when unhealthy",
+			c:    &collector{healthz: healthzNotOK},
+			want: true,
+		},
+		{
+			name: "healthzAny collector runs when unhealthy",
+			c:    &collector{healthz: healthzAny},
+			want: true,
+		},
+		{
+			name:    "hourly collector skipped outside hourly run",
+			c:       &collector{hourlyOnly: true},
+			healthy: true,
+		},
+		{
+			name:      "hourly collector runs on hourly run",
+			c:         &collector{hourlyOnly: true},
+			healthy:   true,
+			hourlyRun: true,
+			want:      true,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.c.f = func(*Monitor, context.Context) error { return nil }
+			mon := &Monitor{
+				hourlyRun: tt.hourlyRun,
+			}
+
+			got := tt.c.shouldRun(mon, tt.healthy, now)
+			if got != tt.want {
+				t.Error(got)
+			}
+		})
+	}
+}
+
+func TestScheduleDue(t *testing.T) {
+	now := time.Now()
+	c := &collector{
+		f:        func(*Monitor, context.Context) error { return nil },
+		interval: 5 * time.Minute,
+	}
+
+	s := NewSchedule()
+	for _, tt := range []struct {
+		at   time.Duration
+		want bool
+	}{
+		{at: 0, want: true},
+		{at: time.Minute},
+		{at: 4 * time.Minute},
+		// the worker may tick slightly early
+		{at: 5*time.Minute - time.Second, want: true},
+		{at: 6 * time.Minute},
+		{at: 10 * time.Minute, want: true},
+	} {
+		got := s.due(c, now.Add(tt.at))
+		if got != tt.want {
+			t.Errorf("at %s: got %v, want %v", tt.at, got, tt.want)
+		}
+	}
+
+	var nilSchedule *Schedule
+	if !nilSchedule.due(c, now) {
+		t.Error("nil schedule should always be due")
+	}
+}
+
+func TestRunCollectors(t *testing.T) {
+	ctx := context.Background()
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m := mock_metrics.NewMockEmitter(controller)
+
+	var mu sync.Mutex
+	var running, maxRunning int
+	track := func(*Monitor, context.Context) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return nil
+	}
+
+	collectors := []*collector{
+		{
+			f: func(*Monitor, context.Context) error {
+				return errors.New("failed")
+			},
+		},
+		{
+			f: func(mon *Monitor, ctx context.Context) error {
+				<-ctx.Done()
+				return ctx.Err()
+			},
+			timeout: time.Millisecond,
+		},
+	}
+	for i := 0; i < 3*maxConcurrentCollectors; i++ {
+		collectors = append(collectors, &collector{f: track})
+	}
+
+	mon := &Monitor{
+		log: utillog.GetLogger(),
+		m:   m,
+	}
+
+	m.EXPECT().EmitGauge("monitor.clustererrors", int64(1), gomock.Any()).Times(2)
+
+	errs := mon.runCollectors(ctx, collectors)
+	if len(errs) != 2 {
+		t.Errorf("got %d errors, want 2: %v", len(errs), errs)
+	}
+
+	if maxRunning > maxConcurrentCollectors {
+		t.Errorf("%d collectors ran concurrently, want at most %d", maxRunning, maxConcurrentCollectors)
+	}
+}
@@ -0,0 +1,55 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+)
+
+// collectors is the registry of checks run by the Monitor after the API
+// server healthz check. They run concurrently, so they must not depend on
+// each other; shared lookups should go through the helpers in cache.go. To add
+// a check, implement it in its own file and add it here.
+var collectors = []*collector{
+	{f: (*Monitor).emitProvisioningState, hourlyOnly: true, healthz: healthzAny},
+	{f: (*Monitor).emitAPIServerPingCode, healthz: healthzNotOK},
+	{f: (*Monitor).emitAroOperatorHeartbeat},
+	{f: (*Monitor).emitAroOperatorConditions},
+	{f: (*Monitor).emitNSGReconciliation},
+	{f: (*Monitor).emitGuardrailsViolations},
+	{f: (*Monitor).emitInternetChecker},
+	{f: (*Monitor).emitClusterOperatorConditions},
+	{f: (*Monitor).emitClusterOperatorVersions},
+	{f: (*Monitor).emitClusterVersionConditions},
+	{f: (*Monitor).emitClusterVersions},
+	{f: (*Monitor).emitDaemonsetStatuses},
+	{f: (*Monitor).emitDeploymentStatuses},
+	{f: (*Monitor).emitMachineConfigPoolConditions},
+	{f: (*Monitor).emitMachineConfigPoolUnmanagedNodeCounts},
+	{f: (*Monitor).emitNodeConditions},
+	{f: (*Monitor).emitPodConditions},
+	{f: (*Monitor).emitDebugPodsCount},
+	{f: (*Monitor).detectQuotaFailure},
+	{f: (*Monitor).emitReplicasetStatuses},
+	{f: (*Monitor).emitStatefulsetStatuses},
+	{f: (*Monitor).emitJobConditions},
+	{f: (*Monitor).emitSummary, hourlyOnly: true},
+	{f: (*Monitor).emitHiveRegistrationStatus},
+	{f: (*Monitor).emitOperatorFlagsAndSupportBanner},
+	{f: (*Monitor).emitMaintenanceState},
+	{f: (*Monitor).emitCertificateExpirationStatuses},
+	{f: (*Monitor).emitEtcdCertificateExpiry},
+	// the slowest and least reliable collector: bound it so that it cannot
+	// use up the whole monitoring period
+	{f: (*Monitor).emitPrometheusAlerts, timeout: 30 * time.Second},
+}
+
+func (mon *Monitor) emitProvisioningState(ctx context.Context) error {
+	mon.emitGauge("cluster.provisioning", 1, map[string]string{
+		"provisioningState":       mon.oc.Properties.ProvisioningState.String(),
+		"failedProvisioningState": mon.oc.Properties.FailedProvisioningState.String(),
+	})
+	return nil
+}
@@ -0,0 +1,56 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+const (
+	guardrailsAuditViolationsMetricsTopic     = "guardrails.violations.audit"
+	guardrailsAdmissionViolationsMetricsTopic = "guardrails.violations.admission"
+)
+
+// emitGuardrailsViolations emits the violations of each guardrails constraint
+// as summarised by the operator in the cluster status
+func (mon *Monitor) emitGuardrailsViolations(ctx context.Context) error {
+	cluster, err := mon.arocli.AroV1alpha1().Clusters().Get(ctx, arov1alpha1.SingletonClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if cluster.Status.Guardrails == nil {
+		return nil
+	}
+
+	for _, c := range cluster.Status.Guardrails.Constraints {
+		dims := map[string]string{
+			"constraint":        c.Name,
+			"kind":              c.Kind,
+			"enforcementAction": c.EnforcementAction,
+		}
+
+		mon.emitGauge(guardrailsAuditViolationsMetricsTopic, int64(c.AuditViolations), dims)
+		mon.emitGauge(guardrailsAdmissionViolationsMetricsTopic, int64(c.AdmissionViolations), dims)
+
+		if mon.hourlyRun && (c.AuditViolations > 0 || c.AdmissionViolations > 0) {
+			mon.log.WithFields(logrus.Fields{
+				"metric":              "guardrails.violations",
+				"constraint":          c.Name,
+				"kind":                c.Kind,
+				"enforcementAction":   c.EnforcementAction,
+				"auditTimestamp":      c.AuditTimestamp,
+				"auditViolations":     c.AuditViolations,
+				"admissionViolations": c.AdmissionViolations,
+			}).Print()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	arofake "github.com/Azure/ARO-RP/pkg/operator/clientset/versioned/fake"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestEmitGuardrailsViolations(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		guardrails *arov1alpha1.GuardrailsStatus
+		mocks      func(*mock_metrics.MockEmitter)
+	}{
+		{
+			name: "no status",
+		},
+		{
+			name: "violations are emitted per constraint",
+			guardrails: &arov1alpha1.GuardrailsStatus{
+				Constraints: []arov1alpha1.GuardrailsConstraintStatus{
+					{
+						Name:                "aro-machines-deny",
+						Kind:                "ARODenyLabels",
+						EnforcementAction:   "deny",
+						AuditViolations:     2,
+						AdmissionViolations: 5,
+					},
+					{
+						Name:              "aro-pull-secret-deny",
+						Kind:              "ARODenyPullSecret",
+						EnforcementAction: "dryrun",
+					},
+				},
+			},
+			mocks: func(m *mock_metrics.MockEmitter) {
+				machines := map[string]string{
+					"constraint":        "aro-machines-deny",
+					"kind":              "ARODenyLabels",
+					"enforcementAction": "deny",
+				}
+				pullSecret := map[string]string{
+					"constraint":        "aro-pull-secret-deny",
+					"kind":              "ARODenyPullSecret",
+					"enforcementAction": "dryrun",
+				}
+				m.EXPECT().EmitGauge(guardrailsAuditViolationsMetricsTopic, int64(2), machines)
+				m.EXPECT().EmitGauge(guardrailsAdmissionViolationsMetricsTopic, int64(5), machines)
+				m.EXPECT().EmitGauge(guardrailsAuditViolationsMetricsTopic, int64(0), pullSecret)
+				m.EXPECT().EmitGauge(guardrailsAdmissionViolationsMetricsTopic, int64(0), pullSecret)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			cluster := &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Status: arov1alpha1.ClusterStatus{
+					Guardrails: tt.guardrails,
+				},
+			}
+
+			m := mock_metrics.NewMockEmitter(controller)
+			if tt.mocks != nil {
+				tt.mocks(m)
+			}
+
+			mon := &Monitor{
+				arocli: arofake.NewSimpleClientset(cluster),
+				m:      m,
+			}
+
+			err := mon.emitGuardrailsViolations(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+const (
+	internetCheckerReachableMetricsTopic        = "internetchecker.reachable"
+	internetCheckerLatencyMetricsTopic          = "internetchecker.latency"
+	internetCheckerLatencyHistogramMetricsTopic = "internetchecker.latency.histogram"
+	internetCheckerSLOBreachesMetricsTopic      = "internetchecker.latency.slo.breaches"
+)
+
+// emitInternetChecker emits the reachability and latency of each internet
+// checker target as recorded by the operator in the cluster status
+func (mon *Monitor) emitInternetChecker(ctx context.Context) error {
+	cluster, err := mon.arocli.AroV1alpha1().Clusters().Get(ctx, arov1alpha1.SingletonClusterName, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	for _, s := range cluster.Status.InternetChecker {
+		dims := map[string]string{
+			"role":   s.Role,
+			"target": s.Name,
+			"mode":   string(s.Mode),
+		}
+
+		var reachable int64
+		if s.Reachable {
+			reachable = 1
+		}
+
+		mon.emitGauge(internetCheckerReachableMetricsTopic, reachable, map[string]string{
+			"role":        s.Role,
+			"target":      s.Name,
+			"mode":        string(s.Mode),
+			"failedPhase": string(s.FailedPhase),
+			"proxied":     strconv.FormatBool(s.Proxied),
+		})
+
+		if s.Reachable {
+			mon.emitGauge(internetCheckerLatencyMetricsTopic, s.LatencyMilliseconds, dims)
+		}
+
+		// the operator counts the latencies in each bucket separately; they
+		// are emitted cumulatively, as for Prometheus histograms, so that the
+		// count of a bucket is that of all latencies up to its le bound
+		var cumulative int64
+		for i, b := range s.LatencyHistogram {
+			cumulative += int64(b.Count)
+
+			le := "+Inf"
+			if i < len(s.LatencyHistogram)-1 {
+				le = strconv.FormatInt(b.LeMilliseconds, 10)
+			}
+
+			mon.emitGauge(internetCheckerLatencyHistogramMetricsTopic, cumulative, map[string]string{
+				"role":   s.Role,
+				"target": s.Name,
+				"mode":   string(s.Mode),
+				"le":     le,
+			})
+		}
+
+		mon.emitGauge(internetCheckerSLOBreachesMetricsTopic, int64(s.LatencySLOBreaches), dims)
+
+		if mon.hourlyRun && !s.Reachable {
+			mon.log.WithFields(logrus.Fields{
+				"metric":             internetCheckerReachableMetricsTopic,
+				"role":               s.Role,
+				"target":             s.Name,
+				"mode":               s.Mode,
+				"failedPhase":        s.FailedPhase,
+				"statusCode":         s.StatusCode,
+				"proxied":            s.Proxied,
+				"lastTransitionTime": s.LastTransitionTime,
+				"message":            s.Message,
+			}).Print()
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,132 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	arofake "github.com/Azure/ARO-RP/pkg/operator/clientset/versioned/fake"
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+)
+
+func TestEmitInternetChecker(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		statuses []arov1alpha1.InternetCheckerTargetStatus
+		mocks    func(*mock_metrics.MockEmitter)
+	}{
+		{
+			name: "no status",
+		},
+		{
+			name: "results are emitted per target",
+			statuses: []arov1alpha1.InternetCheckerTargetStatus{
+				{
+					Role:                "master",
+					Name:                "acr",
+					Mode:                arov1alpha1.InternetCheckerModeHTTP,
+					Reachable:           true,
+					StatusCode:          200,
+					Proxied:             true,
+					LatencyMilliseconds: 80,
+					LatencyHistogram: []arov1alpha1.InternetCheckerLatencyBucket{
+						{LeMilliseconds: 50, Count: 2},
+						{LeMilliseconds: 100, Count: 1},
+						{Count: 1},
+					},
+					LatencySLOBreaches: 1,
+				},
+				{
+					Role:        "worker",
+					Name:        "arm",
+					Mode:        arov1alpha1.InternetCheckerModeTCP,
+					FailedPhase: arov1alpha1.InternetCheckerPhaseConnect,
+					Message:     "connection refused",
+				},
+			},
+			mocks: func(m *mock_metrics.MockEmitter) {
+				acr := map[string]string{
+					"role":   "master",
+					"target": "acr",
+					"mode":   "HTTP",
+				}
+				arm := map[string]string{
+					"role":   "worker",
+					"target": "arm",
+					"mode":   "TCP",
+				}
+				m.EXPECT().EmitGauge(internetCheckerReachableMetricsTopic, int64(1), map[string]string{
+					"role":        "master",
+					"target":      "acr",
+					"mode":        "HTTP",
+					"failedPhase": "",
+					"proxied":     "true",
+				})
+				m.EXPECT().EmitGauge(internetCheckerLatencyMetricsTopic, int64(80), acr)
+				// buckets are emitted cumulatively
+				m.EXPECT().EmitGauge(internetCheckerLatencyHistogramMetricsTopic, int64(2), map[string]string{
+					"role":   "master",
+					"target": "acr",
+					"mode":   "HTTP",
+					"le":     "50",
+				})
+				m.EXPECT().EmitGauge(internetCheckerLatencyHistogramMetricsTopic, int64(3), map[string]string{
+					"role":   "master",
+					"target": "acr",
+					"mode":   "HTTP",
+					"le":     "100",
+				})
+				m.EXPECT().EmitGauge(internetCheckerLatencyHistogramMetricsTopic, int64(4), map[string]string{
+					"role":   "master",
+					"target": "acr",
+					"mode":   "HTTP",
+					"le":     "+Inf",
+				})
+				m.EXPECT().EmitGauge(internetCheckerSLOBreachesMetricsTopic, int64(1), acr)
+				m.EXPECT().EmitGauge(internetCheckerReachableMetricsTopic, int64(0), map[string]string{
+					"role":        "worker",
+					"target":      "arm",
+					"mode":        "TCP",
+					"failedPhase": "Connect",
+					"proxied":     "false",
+				})
+				m.EXPECT().EmitGauge(internetCheckerSLOBreachesMetricsTopic, int64(0), arm)
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			cluster := &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Status: arov1alpha1.ClusterStatus{
+					InternetChecker: tt.statuses,
+				},
+			}
+
+			m := mock_metrics.NewMockEmitter(controller)
+			if tt.mocks != nil {
+				tt.mocks(m)
+			}
+
+			mon := &Monitor{
+				arocli: arofake.NewSimpleClientset(cluster),
+				m:      m,
+			}
+
+			err := mon.emitInternetChecker(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
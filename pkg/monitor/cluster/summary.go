@@ -16,10 +16,6 @@ const (
 // emitSummary emits joined metric to be able to report better on all clusters
 // state in single dashboard
 func (mon *Monitor) emitSummary(ctx context.Context) error {
-	if !mon.hourlyRun {
-		return nil
-	}
-
 	cv, err := mon.getClusterVersion(ctx)
 	if err != nil {
 		return err
@@ -5,6 +5,8 @@ package monitor
 
 import (
 	"context"
+	"math"
+	"sort"
 
 	"github.com/Azure/ARO-RP/pkg/api"
 )
@@ -37,12 +39,9 @@ func (mon *monitor) master(ctx context.Context) error {
 			return err
 		}
 
-		var monitors []string
+		var monitors []*api.MonitorDocument
 		if docs != nil {
-			monitors = make([]string, 0, len(docs.MonitorDocuments))
-			for _, doc := range docs.MonitorDocuments {
-				monitors = append(monitors, doc.ID)
-			}
+			monitors = docs.MonitorDocuments
 		}
 
 		mon.balance(monitors, doc)
@@ -55,8 +54,9 @@ func (mon *monitor) master(ctx context.Context) error {
 	return err
 }
 
-// balance shares out buckets over a slice of registered monitors
-func (mon *monitor) balance(monitors []string, doc *api.MonitorDocument) {
+// balance shares out buckets over a slice of registered monitors, weighting
+// each bucket by the monitoring load reported for it
+func (mon *monitor) balance(monitors []*api.MonitorDocument, doc *api.MonitorDocument) {
 	// initialise doc.Monitor
 	if doc.Monitor == nil {
 		doc.Monitor = &api.Monitor{}
@@ -71,54 +71,163 @@ func (mon *monitor) balance(monitors []string, doc *api.MonitorDocument) {
 		doc.Monitor.Buckets = doc.Monitor.Buckets[:mon.bucketCount]
 	}
 
-	var unallocated []int
-	m := make(map[string][]int, len(monitors)) // map of monitor to list of buckets it owns
+	weights := bucketWeights(monitors, mon.bucketCount)
+
+	load := make(map[string]float64, len(monitors)) // map of monitor to the weight of buckets it owns
+	zones := make(map[string]string, len(monitors)) // map of monitor to its availability zone
 	for _, monitor := range monitors {
-		m[monitor] = nil
+		load[monitor.ID] = 0
+		if monitor.Monitor != nil {
+			zones[monitor.ID] = monitor.Monitor.Zone
+		}
 	}
 
-	var target int // target number of buckets per monitor
-	if len(monitors) > 0 {
-		target = mon.bucketCount / len(monitors)
-		if mon.bucketCount%len(monitors) != 0 {
-			target++
+	// limit is the most load which a monitor may keep of its current buckets.
+	// It is set hysteresis above the fair share, so that small changes in load
+	// or the monitors coming and going during a rolling deploy don't cause
+	// buckets to flap between monitors.  It is rounded up to a whole number of
+	// average buckets: with equal weights, a monitor keeps bucketCount /
+	// len(monitors) buckets, rounded up.
+	var limit float64
+	if len(monitors) > 0 && mon.bucketCount > 0 {
+		var total float64
+		for _, weight := range weights {
+			total += weight
 		}
+		average := total / float64(mon.bucketCount)
+		limit = math.Ceil(total/float64(len(monitors))*(1+mon.balanceHysteresis)/average) * average
 	}
 
-	// load the current bucket allocations into the map
-	for i, monitor := range doc.Monitor.Buckets {
-		if buckets, found := m[monitor]; found && len(buckets) < target {
-			// if the current bucket is allocated to a known monitor and doesn't
-			// take its number of buckets above the target, keep it there...
-			m[monitor] = append(m[monitor], i)
+	// load the current bucket allocations, heaviest first so that a monitor
+	// keeps its heaviest buckets
+	buckets := make([]int, len(doc.Monitor.Buckets))
+	for i := range buckets {
+		buckets[i] = i
+	}
+	sort.SliceStable(buckets, func(i, j int) bool {
+		return weights[buckets[i]] > weights[buckets[j]]
+	})
+
+	var unallocated []int
+	for _, i := range buckets {
+		monitor := doc.Monitor.Buckets[i]
+		if l, found := load[monitor]; found && (l == 0 || l+weights[i] <= limit) {
+			// if the current bucket is allocated to a known monitor which
+			// stays within the limit with it, keep it there.  A monitor always
+			// keeps one bucket, even if it weighs more than the limit...
+			load[monitor] += weights[i]
 		} else {
 			// ...otherwise we'll reallocate it below
 			unallocated = append(unallocated, i)
 		}
 	}
 
-	// reallocate all unallocated buckets, appending to the least loaded monitor
-	if len(monitors) > 0 {
-		for _, i := range unallocated {
-			var leastMonitor string
-			for monitor := range m {
-				if leastMonitor == "" ||
-					len(m[monitor]) < len(m[leastMonitor]) {
-					leastMonitor = monitor
-				}
+	// reallocate all unallocated buckets, heaviest first, to the least loaded
+	// monitor in the least loaded zone
+	sort.SliceStable(unallocated, func(i, j int) bool {
+		return weights[unallocated[i]] > weights[unallocated[j]]
+	})
+
+	for _, i := range unallocated {
+		monitor := leastLoadedMonitor(load, zones) // "" if there are no known monitors
+		doc.Monitor.Buckets[i] = monitor
+		if monitor != "" {
+			load[monitor] += weights[i]
+		}
+	}
+}
+
+// bucketWeights returns the weight of each bucket: one, plus the number of
+// seconds per cycle spent monitoring its clusters.  With no load reported, all
+// buckets weigh the same.  Load which hasn't been reported yet is estimated
+// from the average of the reports which have.
+func bucketWeights(monitors []*api.MonitorDocument, bucketCount int) []float64 {
+	loads := make([]*api.MonitorBucketLoad, bucketCount)
+	for _, monitor := range monitors {
+		if monitor.Monitor == nil {
+			continue
+		}
+
+		for i := range monitor.Monitor.BucketLoads {
+			l := &monitor.Monitor.BucketLoads[i]
+			if l.Bucket < 0 || l.Bucket >= bucketCount {
+				continue
 			}
 
-			m[leastMonitor] = append(m[leastMonitor], i)
+			// while a bucket is moving, both monitors may report it
+			if loads[l.Bucket] == nil || l.CycleSeconds > loads[l.Bucket].CycleSeconds {
+				loads[l.Bucket] = l
+			}
 		}
 	}
 
-	// write the updated bucket allocations back to the document
-	for _, i := range unallocated {
-		doc.Monitor.Buckets[i] = "" // should only happen if there are no known monitors
+	var reported, seconds float64
+	var clusters int
+	for _, l := range loads {
+		if l != nil {
+			reported++
+			if l.CycleSeconds > 0 {
+				seconds += l.CycleSeconds
+				clusters += l.Clusters
+			}
+		}
+	}
+
+	var secondsPerBucket, secondsPerCluster float64
+	if reported > 0 {
+		secondsPerBucket = seconds / reported
+	}
+	if clusters > 0 {
+		secondsPerCluster = seconds / float64(clusters)
+	}
+
+	weights := make([]float64, bucketCount)
+	for i, l := range loads {
+		switch {
+		case l == nil:
+			weights[i] = 1 + secondsPerBucket
+		case l.CycleSeconds == 0:
+			weights[i] = 1 + float64(l.Clusters)*secondsPerCluster
+		default:
+			weights[i] = 1 + l.CycleSeconds
+		}
+	}
+
+	return weights
+}
+
+// leastLoadedMonitor returns the least loaded monitor in the zone with the
+// least load per monitor, so that the loss of a zone affects as few clusters
+// as possible.  Ties are broken by name so that the result is deterministic.
+func leastLoadedMonitor(load map[string]float64, zones map[string]string) string {
+	monitors := make([]string, 0, len(load))
+	for monitor := range load {
+		monitors = append(monitors, monitor)
+	}
+	sort.Strings(monitors)
+
+	zoneLoad := map[string]float64{}
+	zoneSize := map[string]int{}
+	for _, monitor := range monitors {
+		zoneLoad[zones[monitor]] += load[monitor]
+		zoneSize[zones[monitor]]++
+	}
+
+	var leastZone string
+	for i, monitor := range monitors {
+		zone := zones[monitor]
+		if i == 0 || zoneLoad[zone]/float64(zoneSize[zone]) < zoneLoad[leastZone]/float64(zoneSize[leastZone]) {
+			leastZone = zone
+		}
 	}
-	for monitor, buckets := range m {
-		for _, i := range buckets {
-			doc.Monitor.Buckets[i] = monitor
+
+	var leastMonitor string
+	for _, monitor := range monitors {
+		if zones[monitor] == leastZone &&
+			(leastMonitor == "" || load[monitor] < load[leastMonitor]) {
+			leastMonitor = monitor
 		}
 	}
+
+	return leastMonitor
 }
@@ -170,7 +170,12 @@ func TestBalance(t *testing.T) {
 
 			doc := tt.doc()
 
-			mon.balance(tt.monitors, doc)
+			var monitors []*api.MonitorDocument
+			for _, monitor := range tt.monitors {
+				monitors = append(monitors, &api.MonitorDocument{ID: monitor})
+			}
+
+			mon.balance(monitors, doc)
 
 			if doc.Monitor == nil {
 				t.Fatal(doc.Monitor)
@@ -184,3 +189,198 @@ func TestBalance(t *testing.T) {
 		})
 	}
 }
+
+func TestBalanceWeighted(t *testing.T) {
+	for _, tt := range []struct {
+		name       string
+		monitors   []*api.MonitorDocument
+		buckets    []string
+		hysteresis float64
+		want       []string
+	}{
+		{
+			name: "heavy bucket is balanced against several light ones",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 10, CycleSeconds: 59},
+							{Bucket: 1, Clusters: 1, CycleSeconds: 9},
+						},
+					},
+				},
+				{
+					ID: "two",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 2, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 3, Clusters: 1, CycleSeconds: 9},
+						},
+					},
+				},
+			},
+			buckets: []string{"one", "one", "two", "two"},
+			// weights are 60, 10, 10, 10: one keeps bucket 0, which is
+			// heavier than the limit of 45, and gives up bucket 1
+			want: []string{"one", "two", "two", "two"},
+		},
+		{
+			name: "slightly overloaded allocation is rebalanced without hysteresis",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 1, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 2, Clusters: 1, CycleSeconds: 3},
+						},
+					},
+				},
+				{
+					ID: "two",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 3, Clusters: 1, CycleSeconds: 7},
+						},
+					},
+				},
+			},
+			buckets: []string{"one", "one", "one", "two"},
+			// weights are 10, 10, 4, 8: one's fair share is 16, so it keeps
+			// buckets 0 and 2 and gives up bucket 1
+			want: []string{"one", "two", "one", "two"},
+		},
+		{
+			name: "hysteresis keeps a slightly overloaded allocation",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 1, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 2, Clusters: 1, CycleSeconds: 3},
+						},
+					},
+				},
+				{
+					ID: "two",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 3, Clusters: 1, CycleSeconds: 7},
+						},
+					},
+				},
+			},
+			buckets:    []string{"one", "one", "one", "two"},
+			hysteresis: 0.3,
+			want:       []string{"one", "one", "one", "two"},
+		},
+		{
+			name: "unreported buckets are estimated from reported ones",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 3, CycleSeconds: 29},
+							{Bucket: 1, Clusters: 3},
+						},
+					},
+				},
+				{
+					ID: "two",
+				},
+			},
+			buckets: []string{"one", "one", "", ""},
+			// weights are 30, 30, 15.5, 15.5: bucket 1 is estimated from its
+			// clusters, buckets 2 and 3 from the average reported bucket
+			want: []string{"one", "two", "one", "two"},
+		},
+		{
+			name: "bucket taking a monitor exactly to the limit is kept",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 1, Clusters: 1, CycleSeconds: 3},
+							{Bucket: 2, Clusters: 1, CycleSeconds: 3},
+							{Bucket: 3, Clusters: 1, CycleSeconds: 3},
+						},
+					},
+				},
+				{
+					ID: "two",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 1, CycleSeconds: 11},
+						},
+					},
+				},
+			},
+			buckets: []string{"two", "one", "one", "one"},
+			// weights are 12, 4, 4, 4: the limit is 12
+			want: []string{"two", "one", "one", "one"},
+		},
+		{
+			name: "bucket taking a monitor over the limit is moved",
+			monitors: []*api.MonitorDocument{
+				{
+					ID: "one",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 0, Clusters: 1, CycleSeconds: 9},
+							{Bucket: 1, Clusters: 1, CycleSeconds: 3},
+						},
+					},
+				},
+				{
+					ID: "two",
+					Monitor: &api.Monitor{
+						BucketLoads: []api.MonitorBucketLoad{
+							{Bucket: 2, Clusters: 1, CycleSeconds: 3},
+							{Bucket: 3, Clusters: 1, CycleSeconds: 3},
+						},
+					},
+				},
+			},
+			buckets: []string{"one", "one", "two", "two"},
+			// weights are 10, 4, 4, 4: the limit is 11, which one is under
+			// with bucket 0 but over with bucket 1 too
+			want: []string{"one", "two", "two", "two"},
+		},
+		{
+			name: "buckets are spread across zones",
+			monitors: []*api.MonitorDocument{
+				{ID: "one", Monitor: &api.Monitor{Zone: "1"}},
+				{ID: "two", Monitor: &api.Monitor{Zone: "1"}},
+				{ID: "three", Monitor: &api.Monitor{Zone: "2"}},
+			},
+			buckets: []string{"", "", "", "", "", ""},
+			// zone 1 has two monitors, so takes two thirds of the buckets
+			want: []string{"one", "three", "two", "one", "three", "two"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			mon := &monitor{
+				bucketCount:       len(tt.buckets),
+				balanceHysteresis: tt.hysteresis,
+			}
+
+			doc := &api.MonitorDocument{
+				Monitor: &api.Monitor{
+					Buckets: tt.buckets,
+				},
+			}
+
+			mon.balance(tt.monitors, doc)
+
+			if !reflect.DeepEqual(doc.Monitor.Buckets, tt.want) {
+				t.Error(doc.Monitor.Buckets)
+			}
+		})
+	}
+}
@@ -39,9 +39,10 @@ type monitor struct {
 	subs     map[string]*api.SubscriptionDocument
 	env      env.Interface
 
-	isMaster    bool
-	bucketCount int
-	buckets     map[int]struct{}
+	isMaster          bool
+	bucketCount       int
+	buckets           map[int]struct{}
+	balanceHysteresis float64
 
 	lastBucketlist atomic.Value //time.Time
 	lastChangefeed atomic.Value //time.Time
@@ -56,7 +57,7 @@ type Runnable interface {
 	Run(context.Context) error
 }
 
-func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager, e env.Interface) Runnable {
+func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Monitors, dbOpenShiftClusters database.OpenShiftClusters, dbSubscriptions database.Subscriptions, m, clusterm metrics.Emitter, liveConfig liveconfig.Manager, e env.Interface, balanceHysteresis float64) Runnable {
 	return &monitor{
 		baseLog: log,
 		dialer:  dialer,
@@ -71,8 +72,9 @@ func NewMonitor(log *logrus.Entry, dialer proxy.Dialer, dbMonitors database.Moni
 		subs:     map[string]*api.SubscriptionDocument{},
 		env:      e,
 
-		bucketCount: bucket.Buckets,
-		buckets:     map[int]struct{}{},
+		bucketCount:       bucket.Buckets,
+		buckets:           map[int]struct{}{},
+		balanceHysteresis: balanceHysteresis,
 
 		startTime: time.Now(),
 
@@ -100,7 +102,10 @@ func (mon *monitor) Run(ctx context.Context) error {
 
 	for {
 		// register ourself as a monitor
-		err = mon.dbMonitors.MonitorHeartbeat(ctx)
+		err = mon.dbMonitors.MonitorHeartbeat(ctx, &api.Monitor{
+			Zone:        mon.env.Zone(),
+			BucketLoads: mon.bucketLoads(),
+		})
 		if err != nil {
 			mon.baseLog.Error(err)
 		}
@@ -15,6 +15,7 @@ import (
 	"k8s.io/client-go/rest"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/hive"
 	"github.com/Azure/ARO-RP/pkg/monitor/azure/nsg"
 	"github.com/Azure/ARO-RP/pkg/monitor/cluster"
 	"github.com/Azure/ARO-RP/pkg/monitor/dimension"
@@ -115,10 +116,7 @@ func (mon *monitor) changefeed(ctx context.Context, baseLog *logrus.Entry, stop
 							fps == api.ProvisioningStateDeleting):
 					mon.deleteDoc(doc)
 				default:
-					// in the future we will have the shard index set on the api.OpenShiftClusterDocument
-					// but for now we simply select Hive (AKS) shard 1
-					// e.g. shard := mon.hiveShardConfigs[doc.shardIndex]
-					shard := 1
+					shard := hive.ShardFor(doc)
 
 					_, exists := mon.getHiveShardConfig(shard)
 					if !exists {
@@ -203,6 +201,7 @@ func (mon *monitor) worker(stop <-chan struct{}, delay time.Duration, id string)
 	defer t.Stop()
 
 	h := time.Now().Hour()
+	schedule := cluster.NewSchedule()
 
 out:
 	for {
@@ -221,7 +220,9 @@ out:
 		// cached metrics in the remaining minutes
 
 		if sub != nil && sub.Subscription != nil && sub.Subscription.State != api.SubscriptionStateSuspended && sub.Subscription.State != api.SubscriptionStateWarned {
-			mon.workOne(context.Background(), log, v.doc, sub, newh != h, nsgMonitoringTicker)
+			start := time.Now()
+			mon.workOne(context.Background(), log, v.doc, sub, newh != h, schedule, nsgMonitoringTicker)
+			mon.setLastCycle(id, time.Since(start))
 		}
 
 		select {
@@ -237,7 +238,7 @@ out:
 }
 
 // workOne checks the API server health of a cluster
-func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, sub *api.SubscriptionDocument, hourlyRun bool, nsgMonTicker *time.Ticker) {
+func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.OpenShiftClusterDocument, sub *api.SubscriptionDocument, hourlyRun bool, schedule *cluster.Schedule, nsgMonTicker *time.Ticker) {
 	ctx, cancel := context.WithTimeout(ctx, 50*time.Second)
 	defer cancel()
 
@@ -247,9 +248,7 @@ func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.Ope
 		return
 	}
 
-	// once sharding is implemented, we will have the shard set on the api.OpenShiftClusterDocument
-	// e.g. shard := mon.hiveShardConfigs[doc.shardIndex]
-	shard := 1
+	shard := hive.ShardFor(doc)
 	hiveRestConfig, exists := mon.getHiveShardConfig(shard)
 	if !exists {
 		log.Warnf("no hiveShardConfigs set for shard %d", shard)
@@ -266,7 +265,7 @@ func (mon *monitor) workOne(ctx context.Context, log *logrus.Entry, doc *api.Ope
 
 	nsgMon := nsg.NewMonitor(log, doc.OpenShiftCluster, mon.env, sub.ID, sub.Subscription.Properties.TenantID, mon.clusterm, dims, &wg, nsgMonTicker.C)
 
-	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, hourlyRun, &wg)
+	c, err := cluster.NewMonitor(log, restConfig, doc.OpenShiftCluster, mon.clusterm, hiveRestConfig, hourlyRun, schedule, &wg)
 	if err != nil {
 		log.Error(err)
 		mon.m.EmitGauge("monitor.cluster.failedworker", 1, map[string]string{
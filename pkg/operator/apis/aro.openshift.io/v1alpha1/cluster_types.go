@@ -68,7 +68,59 @@ type GenevaLoggingSpec struct {
 }
 
 type InternetCheckerSpec struct {
-	URLs []string `json:"urls,omitempty"`
+	// URLs are checked as HTTP targets accepting any response.  They are
+	// only checked if Targets is empty.
+	URLs    []string                `json:"urls,omitempty"`
+	Targets []InternetCheckerTarget `json:"targets,omitempty"`
+}
+
+// InternetCheckerMode is the way in which an internet checker target is
+// checked
+// +kubebuilder:validation:Enum=HTTP;TCP;DNS
+type InternetCheckerMode string
+
+const (
+	// InternetCheckerModeHTTP sends a HEAD request to the URL, through the
+	// cluster-wide proxy if one is configured
+	InternetCheckerModeHTTP InternetCheckerMode = "HTTP"
+	// InternetCheckerModeTCP resolves the host and connects to the port, or
+	// opens a tunnel to it through the cluster-wide proxy if that applies
+	InternetCheckerModeTCP InternetCheckerMode = "TCP"
+	// InternetCheckerModeDNS only resolves the host.  It is not checked if the
+	// cluster-wide proxy applies to the host.
+	InternetCheckerModeDNS InternetCheckerMode = "DNS"
+)
+
+// InternetCheckerPhase is the phase of a check in which it failed
+type InternetCheckerPhase string
+
+const (
+	InternetCheckerPhaseDNS     InternetCheckerPhase = "DNS"
+	InternetCheckerPhaseConnect InternetCheckerPhase = "Connect"
+	InternetCheckerPhaseTLS     InternetCheckerPhase = "TLS"
+	InternetCheckerPhaseHTTP    InternetCheckerPhase = "HTTP"
+)
+
+// InternetCheckerTarget is an endpoint which the cluster must be able to reach
+type InternetCheckerTarget struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+	// Mode defaults to HTTP
+	Mode InternetCheckerMode `json:"mode,omitempty"`
+
+	// URL is checked by HTTP targets
+	URL string `json:"url,omitempty"`
+	// ExpectedStatusCodes are the response status codes which pass an HTTP
+	// check.  Any response passes if empty.
+	ExpectedStatusCodes []int `json:"expectedStatusCodes,omitempty"`
+
+	// Host is checked by TCP and DNS targets, and Port by TCP targets
+	Host string `json:"host,omitempty"`
+	Port int    `json:"port,omitempty"`
+
+	// LatencySLO is the latency within which checks of the target should
+	// pass.  Slower checks still pass, but are counted in the status.
+	LatencySLO *metav1.Duration `json:"latencySLO,omitempty"`
 }
 
 type OperatorFlags map[string]string
@@ -114,6 +166,7 @@ type ClusterSpec struct {
 	GatewayDomains           []string            `json:"gatewayDomains,omitempty"`
 	GatewayPrivateEndpointIP string              `json:"gatewayPrivateEndpointIP,omitempty"`
 	Banner                   Banner              `json:"banner,omitempty"`
+	ConsoleBanners           []ConsoleBanner     `json:"consoleBanners,omitempty"`
 	ServiceSubnets           []string            `json:"serviceSubnets,omitempty"`
 
 	// OperatorFlags defines feature gates for the ARO Operator
@@ -125,11 +178,116 @@ type Banner struct {
 	Content BannerContent `json:"content,omitempty"`
 }
 
+// ConsoleBannerSeverity selects the colours of a ConsoleBanner
+type ConsoleBannerSeverity string
+
+const (
+	ConsoleBannerSeverityInfo     ConsoleBannerSeverity = "Info"
+	ConsoleBannerSeverityWarning  ConsoleBannerSeverity = "Warning"
+	ConsoleBannerSeverityCritical ConsoleBannerSeverity = "Critical"
+)
+
+// ConsoleBanner defines a banner published by the RP to the console between
+// StartTime and ExpiryTime.  MessageID selects one of the operator's messages
+// and Language its translation.
+type ConsoleBanner struct {
+	Name       string                `json:"name"`
+	Severity   ConsoleBannerSeverity `json:"severity,omitempty"`
+	MessageID  string                `json:"messageId"`
+	Language   string                `json:"language,omitempty"`
+	Parameters map[string]string     `json:"parameters,omitempty"`
+	Link       *ConsoleBannerLink    `json:"link,omitempty"`
+	StartTime  *metav1.Time          `json:"startTime,omitempty"`
+	ExpiryTime *metav1.Time          `json:"expiryTime,omitempty"`
+}
+
+// ConsoleBannerLink defines a link shown after the text of a ConsoleBanner
+type ConsoleBannerLink struct {
+	Href string `json:"href"`
+	Text string `json:"text"`
+}
+
 // ClusterStatus defines the observed state of Cluster
 type ClusterStatus struct {
 	OperatorVersion   string                         `json:"operatorVersion,omitempty"`
 	Conditions        []operatorv1.OperatorCondition `json:"conditions,omitempty"`
 	RedHatKeysPresent []string                       `json:"redHatKeysPresent,omitempty"`
+	Guardrails        *GuardrailsStatus              `json:"guardrails,omitempty"`
+	InternetChecker   []InternetCheckerTargetStatus  `json:"internetChecker,omitempty"`
+}
+
+// InternetCheckerTargetStatus is the result of the last check of an internet
+// checker target by the operator running with a role
+type InternetCheckerTargetStatus struct {
+	Role      string              `json:"role"`
+	Name      string              `json:"name"`
+	Mode      InternetCheckerMode `json:"mode"`
+	Reachable bool                `json:"reachable"`
+
+	// LastTransitionTime is the time at which Reachable last changed
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+
+	// FailedPhase and Message describe why the check failed
+	FailedPhase InternetCheckerPhase `json:"failedPhase,omitempty"`
+	Message     string               `json:"message,omitempty"`
+
+	// StatusCode is the response status code of the last HTTP check, and
+	// Proxied is set if the last check went through the cluster-wide proxy
+	StatusCode int  `json:"statusCode,omitempty"`
+	Proxied    bool `json:"proxied,omitempty"`
+
+	// LatencyMilliseconds is the latency of the last check, if it passed
+	LatencyMilliseconds int64 `json:"latencyMilliseconds,omitempty"`
+
+	// LatencyHistogram counts the latencies of the recent checks which passed,
+	// and LatencySLOBreaches those of them which were slower than the
+	// target's LatencySLO
+	LatencyHistogram   []InternetCheckerLatencyBucket `json:"latencyHistogram,omitempty"`
+	LatencySLOBreaches int                            `json:"latencySLOBreaches,omitempty"`
+}
+
+// InternetCheckerLatencyBucket counts the latencies greater than the previous
+// bucket's upper bound and at most LeMilliseconds.  The last bucket has no
+// upper bound.
+type InternetCheckerLatencyBucket struct {
+	LeMilliseconds int64 `json:"leMilliseconds,omitempty"`
+	Count          int   `json:"count"`
+}
+
+// GuardrailsStatus summarises the violations of the guardrails constraints
+// reported by Gatekeeper
+type GuardrailsStatus struct {
+	LastUpdateTime metav1.Time                  `json:"lastUpdateTime,omitempty"`
+	Constraints    []GuardrailsConstraintStatus `json:"constraints,omitempty"`
+	Rollouts       []GuardrailsRolloutStatus    `json:"rollouts,omitempty"`
+}
+
+// GuardrailsRolloutStatus records when the rollout of a guardrails constraint
+// started.  The constraint is deployed in dryrun until it has soaked for the
+// soak period since then, and in deny after that.
+type GuardrailsRolloutStatus struct {
+	Name      string      `json:"name"`
+	Kind      string      `json:"kind"`
+	StartTime metav1.Time `json:"startTime"`
+}
+
+// GuardrailsConstraintStatus summarises the violations of a single guardrails
+// constraint
+type GuardrailsConstraintStatus struct {
+	Name              string `json:"name"`
+	Kind              string `json:"kind"`
+	EnforcementAction string `json:"enforcementAction,omitempty"`
+
+	// AuditTimestamp is the time of Gatekeeper's last audit of the
+	// constraint, and AuditViolations the number of existing objects which
+	// violated it
+	AuditTimestamp  string `json:"auditTimestamp,omitempty"`
+	AuditViolations int    `json:"auditViolations"`
+
+	// AdmissionViolations is the number of admission requests in the last
+	// hour which violated the constraint.  They were denied if the
+	// enforcement action is deny.
+	AdmissionViolations int `json:"admissionViolations"`
 }
 
 // Cluster is the Schema for the clusters API
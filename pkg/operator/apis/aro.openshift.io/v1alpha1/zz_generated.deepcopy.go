@@ -7,6 +7,7 @@ package v1alpha1
 
 import (
 	v1 "github.com/openshift/api/operator/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -95,6 +96,13 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 		copy(*out, *in)
 	}
 	out.Banner = in.Banner
+	if in.ConsoleBanners != nil {
+		in, out := &in.ConsoleBanners, &out.ConsoleBanners
+		*out = make([]ConsoleBanner, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.ServiceSubnets != nil {
 		in, out := &in.ServiceSubnets, &out.ServiceSubnets
 		*out = make([]string, len(*in))
@@ -134,6 +142,18 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = new(GuardrailsStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.InternetChecker != nil {
+		in, out := &in.InternetChecker, &out.InternetChecker
+		*out = make([]InternetCheckerTargetStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterStatus.
@@ -146,6 +166,56 @@ func (in *ClusterStatus) DeepCopy() *ClusterStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleBanner) DeepCopyInto(out *ConsoleBanner) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Link != nil {
+		in, out := &in.Link, &out.Link
+		*out = new(ConsoleBannerLink)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ExpiryTime != nil {
+		in, out := &in.ExpiryTime, &out.ExpiryTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleBanner.
+func (in *ConsoleBanner) DeepCopy() *ConsoleBanner {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleBanner)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ConsoleBannerLink) DeepCopyInto(out *ConsoleBannerLink) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ConsoleBannerLink.
+func (in *ConsoleBannerLink) DeepCopy() *ConsoleBannerLink {
+	if in == nil {
+		return nil
+	}
+	out := new(ConsoleBannerLink)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *GenevaLoggingSpec) DeepCopyInto(out *GenevaLoggingSpec) {
 	*out = *in
@@ -161,6 +231,80 @@ func (in *GenevaLoggingSpec) DeepCopy() *GenevaLoggingSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailsConstraintStatus) DeepCopyInto(out *GuardrailsConstraintStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailsConstraintStatus.
+func (in *GuardrailsConstraintStatus) DeepCopy() *GuardrailsConstraintStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailsConstraintStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailsRolloutStatus) DeepCopyInto(out *GuardrailsRolloutStatus) {
+	*out = *in
+	in.StartTime.DeepCopyInto(&out.StartTime)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailsRolloutStatus.
+func (in *GuardrailsRolloutStatus) DeepCopy() *GuardrailsRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailsRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailsStatus) DeepCopyInto(out *GuardrailsStatus) {
+	*out = *in
+	in.LastUpdateTime.DeepCopyInto(&out.LastUpdateTime)
+	if in.Constraints != nil {
+		in, out := &in.Constraints, &out.Constraints
+		*out = make([]GuardrailsConstraintStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollouts != nil {
+		in, out := &in.Rollouts, &out.Rollouts
+		*out = make([]GuardrailsRolloutStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailsStatus.
+func (in *GuardrailsStatus) DeepCopy() *GuardrailsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternetCheckerLatencyBucket) DeepCopyInto(out *InternetCheckerLatencyBucket) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternetCheckerLatencyBucket.
+func (in *InternetCheckerLatencyBucket) DeepCopy() *InternetCheckerLatencyBucket {
+	if in == nil {
+		return nil
+	}
+	out := new(InternetCheckerLatencyBucket)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *InternetCheckerSpec) DeepCopyInto(out *InternetCheckerSpec) {
 	*out = *in
@@ -169,6 +313,13 @@ func (in *InternetCheckerSpec) DeepCopyInto(out *InternetCheckerSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]InternetCheckerTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternetCheckerSpec.
@@ -181,6 +332,52 @@ func (in *InternetCheckerSpec) DeepCopy() *InternetCheckerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternetCheckerTarget) DeepCopyInto(out *InternetCheckerTarget) {
+	*out = *in
+	if in.ExpectedStatusCodes != nil {
+		in, out := &in.ExpectedStatusCodes, &out.ExpectedStatusCodes
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	if in.LatencySLO != nil {
+		in, out := &in.LatencySLO, &out.LatencySLO
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternetCheckerTarget.
+func (in *InternetCheckerTarget) DeepCopy() *InternetCheckerTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(InternetCheckerTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InternetCheckerTargetStatus) DeepCopyInto(out *InternetCheckerTargetStatus) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	if in.LatencyHistogram != nil {
+		in, out := &in.LatencyHistogram, &out.LatencyHistogram
+		*out = make([]InternetCheckerLatencyBucket, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InternetCheckerTargetStatus.
+func (in *InternetCheckerTargetStatus) DeepCopy() *InternetCheckerTargetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InternetCheckerTargetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in OperatorFlags) DeepCopyInto(out *OperatorFlags) {
 	{
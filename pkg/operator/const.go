@@ -9,4 +9,12 @@ const (
 
 	Namespace  = "openshift-azure-operator"
 	SecretName = "cluster"
+
+	// DiagnosticsRequestAnnotation on the Cluster singleton asks the operator
+	// to gather a diagnostics bundle.  The bundle is written to the
+	// DiagnosticsSecretName secret, which records the request it was gathered
+	// for in the same annotation.
+	DiagnosticsRequestAnnotation = "aro.openshift.io/diagnostics-request"
+	DiagnosticsSecretName        = "aro-diagnostics"
+	DiagnosticsSecretKey         = "diagnostics.tar.gz"
 )
@@ -5,6 +5,7 @@ package banner
 
 import (
 	"context"
+	"time"
 
 	consolev1 "github.com/openshift/api/console/v1"
 	"github.com/sirupsen/logrus"
@@ -30,6 +31,8 @@ type Reconciler struct {
 	log *logrus.Entry
 
 	client client.Client
+
+	now func() time.Time
 }
 
 // NewReconciler creates a new Reconciler
@@ -37,6 +40,8 @@ func NewReconciler(log *logrus.Entry, client client.Client) *Reconciler {
 	return &Reconciler{
 		log:    log,
 		client: client,
+
+		now: time.Now,
 	}
 }
 
@@ -54,7 +59,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 	}
 
 	r.log.Debug("running")
-	return reconcile.Result{}, r.reconcileBanner(ctx, instance)
+	err = r.reconcileBanner(ctx, instance)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	return r.reconcileConsoleBanners(ctx, instance)
 }
 
 // SetupWithManager creates the controller
@@ -64,7 +74,8 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 	})
 
 	aroBannerPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
-		return o.GetName() == BannerName
+		_, isConsoleBanner := o.GetLabels()[consoleBannerLabel]
+		return o.GetName() == BannerName || isConsoleBanner
 	})
 
 	return ctrl.NewControllerManagedBy(mgr).
@@ -5,9 +5,11 @@ package banner
 
 import (
 	"context"
+	"reflect"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	consolev1 "github.com/openshift/api/console/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -154,6 +156,7 @@ func TestBannerReconcile(t *testing.T) {
 			r := Reconciler{
 				log:    utillog.GetLogger(),
 				client: clientFake,
+				now:    time.Now,
 			}
 
 			// function under test
@@ -181,3 +184,138 @@ func TestBannerReconcile(t *testing.T) {
 		})
 	}
 }
+
+func TestConsoleBannersReconcile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	mtime := func(d time.Duration) *metav1.Time {
+		t := metav1.NewTime(now.Add(d))
+		return &t
+	}
+
+	instance := &arov1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: arov1alpha1.SingletonClusterName,
+		},
+		Spec: arov1alpha1.ClusterSpec{
+			ResourceID: "FAKE_RESOURCE_ID",
+			OperatorFlags: arov1alpha1.OperatorFlags{
+				operator.BannerEnabled: "true",
+			},
+			ConsoleBanners: []arov1alpha1.ConsoleBanner{
+				{
+					Name:      "maintenance",
+					Severity:  arov1alpha1.ConsoleBannerSeverityWarning,
+					MessageID: "ContactSupport",
+					Link: &arov1alpha1.ConsoleBannerLink{
+						Href: "https://learn.microsoft.com/azure/openshift/",
+						Text: "Learn more",
+					},
+					StartTime:  mtime(-time.Hour),
+					ExpiryTime: mtime(3 * time.Hour),
+				},
+				{
+					Name:       "support",
+					MessageID:  "ContactSupport",
+					ExpiryTime: mtime(2 * time.Hour),
+				},
+				{
+					Name:       "unsupported-language",
+					MessageID:  "ContactSupport",
+					Language:   "de",
+					ExpiryTime: mtime(2 * time.Hour),
+				},
+				{
+					Name:       "later",
+					MessageID:  "ContactSupport",
+					StartTime:  mtime(time.Hour),
+					ExpiryTime: mtime(4 * time.Hour),
+				},
+				{
+					Name:       "expired",
+					MessageID:  "ContactSupport",
+					ExpiryTime: mtime(-time.Minute),
+				},
+				{
+					Name:       "unknown",
+					MessageID:  "Unknown",
+					ExpiryTime: mtime(time.Hour),
+				},
+			},
+		},
+	}
+
+	stale := &consolev1.ConsoleNotification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: consoleBannerNamePrefix + "expired",
+			Labels: map[string]string{
+				consoleBannerLabel: "expired",
+			},
+		},
+	}
+
+	old := &consolev1.ConsoleNotification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: consoleBannerNamePrefix + "support",
+			Labels: map[string]string{
+				consoleBannerLabel: "support",
+			},
+		},
+		Spec: consolev1.ConsoleNotificationSpec{
+			Text: "OLD BANNER TEXT",
+		},
+	}
+
+	clientFake := fake.NewClientBuilder().WithObjects(instance, stale, old).Build()
+
+	r := Reconciler{
+		log:    utillog.GetLogger(),
+		client: clientFake,
+		now:    func() time.Time { return now },
+	}
+
+	// function under test
+	result, err := r.Reconcile(ctx, ctrl.Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.RequeueAfter != time.Hour {
+		t.Errorf("got requeueAfter %s, want %s", result.RequeueAfter, time.Hour)
+	}
+
+	notifications := &consolev1.ConsoleNotificationList{}
+	err = clientFake.List(ctx, notifications)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := map[string]consolev1.ConsoleNotificationSpec{}
+	for _, n := range notifications.Items {
+		got[n.Name] = n.Spec
+	}
+
+	want := map[string]consolev1.ConsoleNotificationSpec{
+		"openshift-aro-sre-maintenance": {
+			Text:            "We have noticed an issue regarding your cluster requiring an action on your part. Please contact support with your cluster resource ID: FAKE_RESOURCE_ID",
+			Location:        consolev1.BannerTop,
+			Color:           "#000",
+			BackgroundColor: "#f0ab00",
+			Link: &consolev1.Link{
+				Href: "https://learn.microsoft.com/azure/openshift/",
+				Text: "Learn more",
+			},
+		},
+		"openshift-aro-sre-support": {
+			Text:            "We have noticed an issue regarding your cluster requiring an action on your part. Please contact support with your cluster resource ID: FAKE_RESOURCE_ID",
+			Location:        consolev1.BannerTop,
+			Color:           "#fff",
+			BackgroundColor: "#06c",
+		},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v", got)
+	}
+}
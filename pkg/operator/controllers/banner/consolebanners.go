@@ -0,0 +1,149 @@
+package banner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+
+	consolev1 "github.com/openshift/api/console/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/util/consolebanner"
+)
+
+// reconcileConsoleBanners publishes a ConsoleNotification for each of the
+// console banners which is due, and removes those of banners which have
+// expired or been removed.  It requeues for the next banner start or expiry.
+func (r *Reconciler) reconcileConsoleBanners(ctx context.Context, instance *arov1alpha1.Cluster) (ctrl.Result, error) {
+	now := r.now()
+
+	var requeueAfter time.Duration
+	requeueAt := func(t time.Time) {
+		if d := t.Sub(now); requeueAfter == 0 || d < requeueAfter {
+			requeueAfter = d
+		}
+	}
+
+	wanted := []*consolev1.ConsoleNotification{}
+	names := map[string]struct{}{}
+	for i := range instance.Spec.ConsoleBanners {
+		banner := &instance.Spec.ConsoleBanners[i]
+
+		if banner.ExpiryTime != nil && !now.Before(banner.ExpiryTime.Time) {
+			continue
+		}
+		if banner.StartTime != nil && now.Before(banner.StartTime.Time) {
+			requeueAt(banner.StartTime.Time)
+			continue
+		}
+		if banner.ExpiryTime != nil {
+			requeueAt(banner.ExpiryTime.Time)
+		}
+
+		notification, err := newConsoleBanner(instance, banner)
+		if err != nil {
+			// don't let one bad banner hold up the others
+			r.log.Warnf("skipping console banner %q: %s", banner.Name, err)
+			continue
+		}
+
+		wanted = append(wanted, notification)
+		names[notification.Name] = struct{}{}
+	}
+
+	existing := &consolev1.ConsoleNotificationList{}
+	err := r.client.List(ctx, existing, client.HasLabels{consoleBannerLabel})
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	for i := range existing.Items {
+		if _, ok := names[existing.Items[i].Name]; ok {
+			continue
+		}
+
+		r.log.Infof("removing console banner %q", existing.Items[i].Labels[consoleBannerLabel])
+		err = r.client.Delete(ctx, &existing.Items[i])
+		if err != nil && !kerrors.IsNotFound(err) {
+			return ctrl.Result{}, err
+		}
+	}
+
+	for _, notification := range wanted {
+		err = r.createOrUpdateConsoleBanner(ctx, notification)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
+}
+
+func (r *Reconciler) createOrUpdateConsoleBanner(ctx context.Context, notification *consolev1.ConsoleNotification) error {
+	old := &consolev1.ConsoleNotification{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: notification.Name}, old)
+	if kerrors.IsNotFound(err) {
+		r.log.Infof("publishing console banner %q", notification.Labels[consoleBannerLabel])
+		return r.client.Create(ctx, notification)
+	} else if err != nil {
+		return err
+	}
+
+	old.Labels = notification.Labels
+	old.Spec = notification.Spec
+	return r.client.Update(ctx, old)
+}
+
+func newConsoleBanner(instance *arov1alpha1.Cluster, banner *arov1alpha1.ConsoleBanner) (*consolev1.ConsoleNotification, error) {
+	data := &consolebanner.Data{
+		ResourceID: instance.Spec.ResourceID,
+		Parameters: banner.Parameters,
+	}
+	if banner.StartTime != nil {
+		data.StartTime = banner.StartTime.UTC().Format(time.RFC1123)
+	}
+	if banner.ExpiryTime != nil {
+		data.ExpiryTime = banner.ExpiryTime.UTC().Format(time.RFC1123)
+	}
+
+	text, err := consolebanner.Render(banner.MessageID, banner.Language, data)
+	if err != nil {
+		return nil, err
+	}
+
+	colors, ok := consoleBannerColors[banner.Severity]
+	if !ok {
+		colors = consoleBannerColors[arov1alpha1.ConsoleBannerSeverityInfo]
+	}
+
+	notification := &consolev1.ConsoleNotification{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: consoleBannerNamePrefix + banner.Name,
+			Labels: map[string]string{
+				consoleBannerLabel: banner.Name,
+			},
+		},
+		Spec: consolev1.ConsoleNotificationSpec{
+			Text:            text,
+			Location:        consolev1.BannerTop,
+			Color:           colors.color,
+			BackgroundColor: colors.backgroundColor,
+		},
+	}
+
+	if banner.Link != nil {
+		notification.Spec.Link = &consolev1.Link{
+			Href: banner.Link.Href,
+			Text: banner.Link.Text,
+		}
+	}
+
+	return notification, nil
+}
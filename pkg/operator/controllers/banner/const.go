@@ -3,8 +3,33 @@ package banner
 // Copyright (c) Microsoft Corporation.
 // Licensed under the Apache License 2.0.
 
+import (
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
 const (
 	BannerName = "openshift-aro-sre"
 	//Banner messages are approved by PM, don't modify the messages without re-approval
 	TextContactSupport = "We have noticed an issue regarding your cluster requiring an action on your part. Please contact support with your cluster resource ID: %s"
 )
+
+const (
+	// consoleBannerLabel is set on the ConsoleNotifications published for
+	// Spec.ConsoleBanners, with the banner's name as its value
+	consoleBannerLabel = "aro.openshift.io/console-banner"
+
+	consoleBannerNamePrefix = BannerName + "-"
+)
+
+type consoleBannerColor struct {
+	color           string
+	backgroundColor string
+}
+
+// consoleBannerColors are the text and background colours of banners, by
+// severity
+var consoleBannerColors = map[arov1alpha1.ConsoleBannerSeverity]consoleBannerColor{
+	arov1alpha1.ConsoleBannerSeverityInfo:     {color: "#fff", backgroundColor: "#06c"},
+	arov1alpha1.ConsoleBannerSeverityWarning:  {color: "#000", backgroundColor: "#f0ab00"},
+	arov1alpha1.ConsoleBannerSeverityCritical: {color: "#fff", backgroundColor: "#c9190b"},
+}
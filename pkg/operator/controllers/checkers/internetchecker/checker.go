@@ -4,26 +4,70 @@ package internetchecker
 // Licensed under the Apache License 2.0.
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
+	"encoding/base64"
 	"fmt"
+	"net"
 	"net/http"
-	"strings"
+	"net/http/httptrace"
+	"net/url"
+	"strconv"
+	"sync"
 	"time"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 )
 
 type simpleHTTPClient interface {
 	Do(req *http.Request) (*http.Response, error)
 }
 
+type resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+type dialer interface {
+	DialContext(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// proxyFunc returns the proxy through which a request should be sent, or nil
+type proxyFunc func(*url.URL) (*url.URL, error)
+
 type internetChecker interface {
-	Check(URLs []string) error
+	Check(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult
+}
+
+// checkResult is the result of checking a target
+type checkResult struct {
+	target arov1alpha1.InternetCheckerTarget
+
+	// err and phase are set if the check failed
+	err   error
+	phase arov1alpha1.InternetCheckerPhase
+
+	statusCode int
+	proxied    bool
+
+	// latency is set if the check passed
+	latency time.Duration
+
+	// skipped is set if the target cannot be checked on this cluster
+	skipped bool
 }
 
+type contextKey int
+
+const contextKeyProxy contextKey = iota
+
 // checker evaluates our capability to create new
 // connections to given internet endpoints.
 type checker struct {
 	checkTimeout time.Duration
 	httpClient   simpleHTTPClient
+	resolver     resolver
+	dialer       dialer
 }
 
 func newInternetChecker() *checker {
@@ -44,67 +88,300 @@ func newInternetChecker() *checker {
 				// 2. We *want* to evaluate our capability to successfully create
 				// *new* connections to internet endpoints anyway.
 				DisableKeepAlives: true,
+				Proxy:             proxyFromContext,
 			},
 		},
+		resolver: net.DefaultResolver,
+		dialer:   &net.Dialer{},
 	}
 }
-func (r *checker) Check(URLs []string) error {
-	ch := make(chan error)
-	checkCount := 0
-	for _, url := range URLs {
-		checkCount++
-		go func(urlToCheck string) {
-			ch <- r.checkWithRetry(urlToCheck)
-		}(url)
-	}
 
-	errsAll := []string{}
-	for i := 0; i < checkCount; i++ {
-		if err := <-ch; err != nil {
-			errsAll = append(errsAll, err.Error())
-		}
-	}
-	if len(errsAll) != 0 {
-		// TODO: Consider replacing with multi error wrapping with Go 1.20: https://github.com/golang/go/issues/53435#issuecomment-1320343377
-		return fmt.Errorf(strings.Join(errsAll, "\n"))
+// proxyFromContext returns the proxy for a request from the proxyFunc stored
+// in its context by checkHTTP
+func proxyFromContext(req *http.Request) (*url.URL, error) {
+	proxy, _ := req.Context().Value(contextKeyProxy).(proxyFunc)
+	if proxy == nil {
+		return nil, nil
 	}
 
-	return nil
+	return proxy(req.URL)
 }
 
-// checkWithRetry checks the URL, retrying a failed query a few times
-func (r *checker) checkWithRetry(url string) error {
-	var err error
+// Check checks the targets in parallel, returning their results in the same
+// order
+func (r *checker) Check(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult {
+	results := make([]*checkResult, len(targets))
+
+	var wg sync.WaitGroup
+	for i := range targets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i] = r.checkWithRetry(&targets[i], proxy)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
 
+// checkWithRetry checks the target, retrying a failed check a few times
+func (r *checker) checkWithRetry(target *arov1alpha1.InternetCheckerTarget, proxy proxyFunc) *checkResult {
+	var result *checkResult
 	for i := 0; i < 6; i++ {
-		err = r.checkOnce(url, r.checkTimeout/6)
-		if err == nil {
-			return nil
+		result = r.checkOnce(target, proxy, r.checkTimeout/6)
+		if result.err == nil {
+			return result
 		}
 	}
-
-	return err
+	return result
 }
 
-// checkOnce checks a given url.  The check both times out after a given timeout
-// *and* will wait for the timeout if it fails, so that we don't hit endpoints
-// too much.
-func (r *checker) checkOnce(url string, timeout time.Duration) error {
+// checkOnce checks a given target.  The check both times out after a given
+// timeout *and* will wait for the timeout if it fails, so that we don't hit
+// endpoints too much.
+func (r *checker) checkOnce(target *arov1alpha1.InternetCheckerTarget, proxy proxyFunc, timeout time.Duration) *checkResult {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
-	if err != nil {
+	result := &checkResult{
+		target: *target,
+	}
+
+	start := time.Now()
+
+	switch target.Mode {
+	case arov1alpha1.InternetCheckerModeHTTP, "":
+		r.checkHTTP(ctx, target, proxy, result)
+	case arov1alpha1.InternetCheckerModeTCP:
+		r.checkTCP(ctx, target, proxy, result)
+	case arov1alpha1.InternetCheckerModeDNS:
+		r.checkDNS(ctx, target, proxy, result)
+	default:
+		result.err = fmt.Errorf("unsupported mode %q", target.Mode)
+	}
+
+	if result.skipped {
+		return result
+	}
+
+	if result.err != nil {
 		<-ctx.Done()
-		return err
+		return result
+	}
+
+	result.latency = time.Since(start)
+	return result
+}
+
+func (r *checker) checkHTTP(ctx context.Context, target *arov1alpha1.InternetCheckerTarget, proxy proxyFunc, result *checkResult) {
+	// record the phase which each request reaches, so that we can tell in
+	// which one it failed.  When the request is proxied, the DNS and Connect
+	// phases are those of the connection to the proxy.
+	var mu sync.Mutex
+	phase := arov1alpha1.InternetCheckerPhaseHTTP
+	setPhase := func(p arov1alpha1.InternetCheckerPhase) {
+		mu.Lock()
+		defer mu.Unlock()
+		phase = p
+	}
+
+	ctx = httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		DNSStart:          func(httptrace.DNSStartInfo) { setPhase(arov1alpha1.InternetCheckerPhaseDNS) },
+		ConnectStart:      func(string, string) { setPhase(arov1alpha1.InternetCheckerPhaseConnect) },
+		TLSHandshakeStart: func() { setPhase(arov1alpha1.InternetCheckerPhaseTLS) },
+		WroteHeaders:      func() { setPhase(arov1alpha1.InternetCheckerPhaseHTTP) },
+	})
+	ctx = context.WithValue(ctx, contextKeyProxy, proxy)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, target.URL, nil)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseHTTP
+		return
+	}
+
+	if proxy != nil {
+		proxyURL, err := proxy(req.URL)
+		if err != nil {
+			result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseHTTP
+			return
+		}
+		result.proxied = proxyURL != nil
 	}
 
 	resp, err := r.httpClient.Do(req)
 	if err != nil {
-		<-ctx.Done()
-		return fmt.Errorf("%s: %s", url, err)
+		mu.Lock()
+		result.err, result.phase = err, phase
+		mu.Unlock()
+		return
 	}
+	resp.Body.Close()
+
+	result.statusCode = resp.StatusCode
 
+	if len(target.ExpectedStatusCodes) == 0 {
+		return
+	}
+	for _, code := range target.ExpectedStatusCodes {
+		if resp.StatusCode == code {
+			return
+		}
+	}
+
+	result.err = fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	result.phase = arov1alpha1.InternetCheckerPhaseHTTP
+}
+
+// checkTCP connects to the target.  If the cluster-wide proxy applies to the
+// target, it asks the proxy to open a tunnel to the target instead, as direct
+// connections are typically blocked on such clusters.
+func (r *checker) checkTCP(ctx context.Context, target *arov1alpha1.InternetCheckerTarget, proxy proxyFunc, result *checkResult) {
+	address := net.JoinHostPort(target.Host, strconv.Itoa(target.Port))
+
+	proxyURL, err := tcpProxyURL(address, proxy)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseConnect
+		return
+	}
+
+	if proxyURL != nil {
+		result.proxied = true
+		r.checkTCPThroughProxy(ctx, address, proxyURL, result)
+		return
+	}
+
+	addrs, err := r.lookupHost(ctx, target.Host)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseDNS
+		return
+	}
+
+	conn, err := r.dialer.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], strconv.Itoa(target.Port)))
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseConnect
+		return
+	}
+	conn.Close()
+}
+
+// checkTCPThroughProxy opens a tunnel to address through the proxy with a
+// CONNECT request.  The DNS, Connect and TLS phases are those of the
+// connection to the proxy, and the HTTP phase is that of the CONNECT request.
+func (r *checker) checkTCPThroughProxy(ctx context.Context, address string, proxyURL *url.URL, result *checkResult) {
+	port := proxyURL.Port()
+	if port == "" {
+		port = "80"
+		if proxyURL.Scheme == "https" {
+			port = "443"
+		}
+	}
+
+	addrs, err := r.lookupHost(ctx, proxyURL.Hostname())
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseDNS
+		return
+	}
+
+	conn, err := r.dialer.DialContext(ctx, "tcp", net.JoinHostPort(addrs[0], port))
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseConnect
+		return
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		err = conn.SetDeadline(deadline)
+		if err != nil {
+			result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseConnect
+			return
+		}
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		err = tlsConn.HandshakeContext(ctx)
+		if err != nil {
+			result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseTLS
+			return
+		}
+		conn = tlsConn
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: address},
+		Host:   address,
+		Header: http.Header{},
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(proxyURL.User.Username()+":"+password)))
+	}
+
+	err = req.Write(conn)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseHTTP
+		return
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseHTTP
+		return
+	}
 	resp.Body.Close()
-	return nil
+
+	result.statusCode = resp.StatusCode
+	if resp.StatusCode != http.StatusOK {
+		result.err = fmt.Errorf("proxy returned status code %d", resp.StatusCode)
+		result.phase = arov1alpha1.InternetCheckerPhaseHTTP
+	}
+}
+
+// checkDNS resolves the target.  If the cluster-wide proxy applies to the
+// target, the target is skipped: clients on such clusters reach it through
+// the proxy, so whether it resolves from the cluster doesn't matter.
+func (r *checker) checkDNS(ctx context.Context, target *arov1alpha1.InternetCheckerTarget, proxy proxyFunc, result *checkResult) {
+	proxyURL, err := tcpProxyURL(target.Host, proxy)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseDNS
+		return
+	}
+
+	if proxyURL != nil {
+		result.skipped = true
+		return
+	}
+
+	_, err = r.lookupHost(ctx, target.Host)
+	if err != nil {
+		result.err, result.phase = err, arov1alpha1.InternetCheckerPhaseDNS
+	}
+}
+
+// tcpProxyURL returns the proxy through which a connection to address should
+// be tunnelled, or nil
+func tcpProxyURL(address string, proxy proxyFunc) (*url.URL, error) {
+	if proxy == nil {
+		return nil, nil
+	}
+
+	return proxy(&url.URL{Scheme: "https", Host: address})
+}
+
+func (r *checker) lookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	addrs, err := r.resolver.LookupHost(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no addresses found for %s", host)
+	}
+
+	return addrs, nil
 }
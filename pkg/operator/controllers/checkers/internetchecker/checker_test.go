@@ -4,22 +4,26 @@ package internetchecker
 // Licensed under the Apache License 2.0.
 
 import (
+	"bufio"
 	"context"
+	"errors"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
 	"os"
 	"syscall"
 	"testing"
 	"time"
 
-	operatorv1 "github.com/openshift/api/operator/v1"
-
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
 type fakeResponse struct {
+	// phase is the last phase which the request reaches
+	phase        arov1alpha1.InternetCheckerPhase
 	httpResponse *http.Response
 	err          error
 }
@@ -31,9 +35,90 @@ type testClient struct {
 func (c *testClient) Do(req *http.Request) (*http.Response, error) {
 	response := c.responses[0]
 	c.responses = c.responses[1:]
+
+	trace := httptrace.ContextClientTrace(req.Context())
+	if trace != nil {
+		switch response.phase {
+		case arov1alpha1.InternetCheckerPhaseDNS:
+			trace.DNSStart(httptrace.DNSStartInfo{})
+		case arov1alpha1.InternetCheckerPhaseConnect:
+			trace.DNSStart(httptrace.DNSStartInfo{})
+			trace.ConnectStart("tcp", "")
+		case arov1alpha1.InternetCheckerPhaseTLS:
+			trace.DNSStart(httptrace.DNSStartInfo{})
+			trace.ConnectStart("tcp", "")
+			trace.TLSHandshakeStart()
+		default:
+			trace.DNSStart(httptrace.DNSStartInfo{})
+			trace.ConnectStart("tcp", "")
+			trace.TLSHandshakeStart()
+			trace.WroteHeaders()
+		}
+	}
+
 	return response.httpResponse, response.err
 }
 
+type testResolver map[string][]string
+
+func (r testResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	addrs, ok := r[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addrs, nil
+}
+
+// testDialer accepts connections to its addresses, passing the server end of
+// each to the address's handler, if any
+type testDialer map[string]func(net.Conn)
+
+func (d testDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	handler, ok := d[address]
+	if !ok {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}
+	}
+	client, server := net.Pipe()
+	go func() {
+		defer server.Close()
+		if handler != nil {
+			handler(server)
+		}
+	}()
+	return client, nil
+}
+
+// testProxy returns a handler which answers CONNECT requests to the allowed
+// addresses and forbids all others
+func testProxy(allowed ...string) func(net.Conn) {
+	return func(conn net.Conn) {
+		req, err := http.ReadRequest(bufio.NewReader(conn))
+		if err != nil {
+			return
+		}
+
+		statusCode := http.StatusForbidden
+		for _, address := range allowed {
+			if req.Method == http.MethodConnect && req.Host == address {
+				statusCode = http.StatusOK
+			}
+		}
+
+		resp := &http.Response{
+			StatusCode: statusCode,
+			ProtoMajor: 1,
+			ProtoMinor: 1,
+		}
+		_ = resp.Write(conn)
+	}
+}
+
+func proxyTo(proxyURL string) proxyFunc {
+	return func(*url.URL) (*url.URL, error) {
+		return url.Parse(proxyURL)
+	}
+}
+
 const urltocheck = "https://not-used-in-test.io"
 
 // simulated responses
@@ -53,49 +138,234 @@ var (
 	}
 
 	networkUnreach = &fakeResponse{
+		phase: arov1alpha1.InternetCheckerPhaseConnect,
 		err: &url.Error{
+			Op:  "Head",
 			URL: urltocheck,
 			Err: &net.OpError{
+				Op:  "dial",
+				Net: "tcp",
 				Err: os.NewSyscallError("socket", syscall.ENETUNREACH),
 			},
 		},
 	}
 
+	noSuchHost = &fakeResponse{
+		phase: arov1alpha1.InternetCheckerPhaseDNS,
+		err:   errors.New("no such host"),
+	}
+
+	badCertificate = &fakeResponse{
+		phase: arov1alpha1.InternetCheckerPhaseTLS,
+		err:   errors.New("x509: certificate signed by unknown authority"),
+	}
+
 	timedoutReq = &fakeResponse{err: context.DeadlineExceeded}
 )
 
 func TestCheck(t *testing.T) {
 	var testCases = []struct {
-		name          string
-		responses     []*fakeResponse
-		wantErr       string
-		wantCondition operatorv1.ConditionStatus
+		name           string
+		target         arov1alpha1.InternetCheckerTarget
+		proxy          proxyFunc
+		responses      []*fakeResponse
+		wantErr        string
+		wantPhase      arov1alpha1.InternetCheckerPhase
+		wantStatusCode int
+		wantProxied    bool
+		wantSkipped    bool
 	}{
 		{
-			name:          "200 OK",
-			responses:     []*fakeResponse{okResp},
-			wantCondition: operatorv1.ConditionTrue,
+			name:           "200 OK",
+			responses:      []*fakeResponse{okResp},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "bad request",
+			responses:      []*fakeResponse{badReq},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "eventual 200 OK",
+			responses:      []*fakeResponse{networkUnreach, timedoutReq, okResp},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name:           "eventual bad request",
+			responses:      []*fakeResponse{timedoutReq, networkUnreach, badReq},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:      "timedout request",
+			responses: []*fakeResponse{networkUnreach, timedoutReq, timedoutReq, timedoutReq, timedoutReq, timedoutReq},
+			wantErr:   "context deadline exceeded",
+			wantPhase: arov1alpha1.InternetCheckerPhaseHTTP,
+		},
+		{
+			name:      "DNS failure",
+			responses: []*fakeResponse{noSuchHost, noSuchHost, noSuchHost, noSuchHost, noSuchHost, noSuchHost},
+			wantErr:   "no such host",
+			wantPhase: arov1alpha1.InternetCheckerPhaseDNS,
+		},
+		{
+			name:      "connect failure",
+			responses: []*fakeResponse{networkUnreach, networkUnreach, networkUnreach, networkUnreach, networkUnreach, networkUnreach},
+			wantErr:   "Head \"https://not-used-in-test.io\": dial tcp: socket: network is unreachable",
+			wantPhase: arov1alpha1.InternetCheckerPhaseConnect,
+		},
+		{
+			name:      "TLS failure",
+			responses: []*fakeResponse{badCertificate, badCertificate, badCertificate, badCertificate, badCertificate, badCertificate},
+			wantErr:   "x509: certificate signed by unknown authority",
+			wantPhase: arov1alpha1.InternetCheckerPhaseTLS,
+		},
+		{
+			name: "expected status code",
+			target: arov1alpha1.InternetCheckerTarget{
+				ExpectedStatusCodes: []int{http.StatusOK, http.StatusBadRequest},
+			},
+			responses:      []*fakeResponse{badReq},
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name: "unexpected status code",
+			target: arov1alpha1.InternetCheckerTarget{
+				ExpectedStatusCodes: []int{http.StatusOK},
+			},
+			responses:      []*fakeResponse{badReq, badReq, badReq, badReq, badReq, badReq},
+			wantErr:        "unexpected status code 400",
+			wantPhase:      arov1alpha1.InternetCheckerPhaseHTTP,
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "proxied",
+			proxy:          proxyTo("http://proxy.io:3128"),
+			responses:      []*fakeResponse{okResp},
+			wantStatusCode: http.StatusOK,
+			wantProxied:    true,
+		},
+		{
+			name: "not proxied",
+			proxy: func(*url.URL) (*url.URL, error) {
+				return nil, nil
+			},
+			responses:      []*fakeResponse{okResp},
+			wantStatusCode: http.StatusOK,
+		},
+		{
+			name: "TCP",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "reachable.io",
+				Port: 443,
+			},
 		},
 		{
-			name:          "bad request",
-			responses:     []*fakeResponse{badReq},
-			wantCondition: operatorv1.ConditionTrue,
+			name: "TCP to an IP",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "192.0.2.2",
+				Port: 443,
+			},
 		},
 		{
-			name:          "eventual 200 OK",
-			responses:     []*fakeResponse{networkUnreach, timedoutReq, okResp},
-			wantCondition: operatorv1.ConditionTrue,
+			name: "TCP DNS failure",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "unknown.io",
+				Port: 443,
+			},
+			wantErr:   "lookup unknown.io: no such host",
+			wantPhase: arov1alpha1.InternetCheckerPhaseDNS,
 		},
 		{
-			name:          "eventual bad request",
-			responses:     []*fakeResponse{timedoutReq, networkUnreach, badReq},
-			wantCondition: operatorv1.ConditionTrue,
+			name: "TCP connect failure",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "reachable.io",
+				Port: 8443,
+			},
+			wantErr:   "dial tcp: connect: connection refused",
+			wantPhase: arov1alpha1.InternetCheckerPhaseConnect,
 		},
 		{
-			name:          "timedout request",
-			responses:     []*fakeResponse{networkUnreach, timedoutReq, timedoutReq, timedoutReq, timedoutReq, timedoutReq},
-			wantErr:       "https://not-used-in-test.io: context deadline exceeded",
-			wantCondition: operatorv1.ConditionFalse,
+			name: "TCP through proxy",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "unresolvable.io",
+				Port: 443,
+			},
+			proxy:          proxyTo("http://proxy.io:3128"),
+			wantStatusCode: http.StatusOK,
+			wantProxied:    true,
+		},
+		{
+			name: "TCP through proxy refused",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "forbidden.io",
+				Port: 443,
+			},
+			proxy:          proxyTo("http://proxy.io:3128"),
+			wantErr:        "proxy returned status code 403",
+			wantPhase:      arov1alpha1.InternetCheckerPhaseHTTP,
+			wantStatusCode: http.StatusForbidden,
+			wantProxied:    true,
+		},
+		{
+			name: "TCP to unreachable proxy",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "unresolvable.io",
+				Port: 443,
+			},
+			proxy:       proxyTo("http://proxy.io:8080"),
+			wantErr:     "dial tcp: connect: connection refused",
+			wantPhase:   arov1alpha1.InternetCheckerPhaseConnect,
+			wantProxied: true,
+		},
+		{
+			name: "TCP not proxied",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeTCP,
+				Host: "reachable.io",
+				Port: 443,
+			},
+			proxy: func(*url.URL) (*url.URL, error) {
+				return nil, nil
+			},
+		},
+		{
+			name: "DNS",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeDNS,
+				Host: "reachable.io",
+			},
+		},
+		{
+			name: "DNS failure",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeDNS,
+				Host: "unknown.io",
+			},
+			wantErr:   "lookup unknown.io: no such host",
+			wantPhase: arov1alpha1.InternetCheckerPhaseDNS,
+		},
+		{
+			name: "DNS through proxy",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: arov1alpha1.InternetCheckerModeDNS,
+				Host: "unresolvable.io",
+			},
+			proxy:       proxyTo("http://proxy.io:3128"),
+			wantSkipped: true,
+		},
+		{
+			name: "unsupported mode",
+			target: arov1alpha1.InternetCheckerTarget{
+				Mode: "ICMP",
+			},
+			wantErr: `unsupported mode "ICMP"`,
 		},
 	}
 
@@ -104,9 +374,46 @@ func TestCheck(t *testing.T) {
 			r := &checker{
 				checkTimeout: 100 * time.Millisecond,
 				httpClient:   &testClient{responses: test.responses},
+				resolver: testResolver{
+					"reachable.io": {"192.0.2.1"},
+					"proxy.io":     {"192.0.2.3"},
+				},
+				dialer: testDialer{
+					"192.0.2.1:443":  nil,
+					"192.0.2.2:443":  nil,
+					"192.0.2.3:3128": testProxy("unresolvable.io:443"),
+				},
+			}
+
+			target := test.target
+			target.Name = test.name
+			if target.Mode == "" {
+				target.URL = urltocheck
+			}
+
+			results := r.Check([]arov1alpha1.InternetCheckerTarget{target}, test.proxy)
+			if len(results) != 1 {
+				t.Fatalf("got %d results, want 1", len(results))
+			}
+			result := results[0]
+
+			utilerror.AssertErrorMessage(t, result.err, test.wantErr)
+
+			if result.target.Name != test.name {
+				t.Errorf("got target %q, want %q", result.target.Name, test.name)
+			}
+			if result.phase != test.wantPhase {
+				t.Errorf("got phase %q, want %q", result.phase, test.wantPhase)
+			}
+			if result.statusCode != test.wantStatusCode {
+				t.Errorf("got status code %d, want %d", result.statusCode, test.wantStatusCode)
+			}
+			if result.proxied != test.wantProxied {
+				t.Errorf("got proxied %t, want %t", result.proxied, test.wantProxied)
+			}
+			if result.skipped != test.wantSkipped {
+				t.Errorf("got skipped %t, want %t", result.skipped, test.wantSkipped)
 			}
-			err := r.Check([]string{urltocheck})
-			utilerror.AssertErrorMessage(t, err, test.wantErr)
 		})
 	}
 }
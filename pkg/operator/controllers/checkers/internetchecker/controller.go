@@ -26,9 +26,14 @@ import (
 // from the annotation below.
 // +kubebuilder:rbac:groups=aro.openshift.io,resources=clusters,verbs=get;list;watch
 // +kubebuilder:rbac:groups=aro.openshift.io,resources=clusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=config.openshift.io,resources=proxies,verbs=get;list;watch
 
 const (
 	ControllerName = "InternetChecker"
+
+	// failedCheckRequeueInterval is how soon the targets are checked again
+	// after a check failed
+	failedCheckRequeueInterval = 5 * time.Minute
 )
 
 // Reconciler runs a number of checkers
@@ -39,6 +44,12 @@ type Reconciler struct {
 	checker internetChecker
 
 	client client.Client
+
+	// latencies are the latencies of the recent checks of each target which
+	// passed, by target name
+	latencies map[string][]time.Duration
+
+	now func() time.Time
 }
 
 func NewReconciler(log *logrus.Entry, client client.Client, role string) *Reconciler {
@@ -49,6 +60,10 @@ func NewReconciler(log *logrus.Entry, client client.Client, role string) *Reconc
 		checker: newInternetChecker(),
 
 		client: client,
+
+		latencies: map[string][]time.Duration{},
+
+		now: time.Now,
 	}
 }
 
@@ -66,7 +81,20 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 	}
 
 	r.log.Debug("running")
-	checkErr := r.checker.Check(instance.Spec.InternetChecker.URLs)
+
+	proxy, err := r.getProxyFunc(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	results := r.checker.Check(getTargets(&instance.Spec.InternetChecker), proxy)
+
+	err = r.setStatus(ctx, r.targetStatuses(results))
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	checkErr := checkError(results)
 	condition := r.condition(checkErr)
 
 	err = conditions.SetCondition(ctx, r.client, condition, r.role)
@@ -74,14 +102,24 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 		return reconcile.Result{}, err
 	}
 
-	// We always requeue here:
-	// * Either immediately (with rate limiting) based on the error
-	//   when checkErr != nil.
-	// * Or based on RequeueAfter when err == nil.
-	return reconcile.Result{RequeueAfter: time.Hour}, checkErr
+	// We always requeue on a fixed interval, sooner if a check failed.  The
+	// failure is reported through the condition and the status rather than
+	// returned, as the rate limited requeue of an error would check the
+	// targets far more often than we want to.
+	if checkErr != nil {
+		r.log.Warn(checkErr)
+		return reconcile.Result{RequeueAfter: failedCheckRequeueInterval}, nil
+	}
+
+	return reconcile.Result{RequeueAfter: time.Hour}, nil
 }
 
 func (r *Reconciler) reconcileDisabled(ctx context.Context) (ctrl.Result, error) {
+	err := r.setStatus(ctx, nil)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
 	condition := &operatorv1.OperatorCondition{
 		Type:   r.conditionType(),
 		Status: operatorv1.ConditionUnknown,
@@ -126,8 +164,10 @@ func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
 		return o.GetName() == arov1alpha1.SingletonClusterName
 	})
 
+	// Our own status updates must not trigger reconciles, otherwise we would
+	// check the targets continuously.  We requeue periodically instead.
 	builder := ctrl.NewControllerManagedBy(mgr).
-		For(&arov1alpha1.Cluster{}, builder.WithPredicates(aroClusterPredicate))
+		For(&arov1alpha1.Cluster{}, builder.WithPredicates(aroClusterPredicate, predicate.GenerationChangedPredicate{}))
 
 	return builder.Named(ControllerName).Complete(r)
 }
@@ -25,15 +25,22 @@ import (
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
-type fakeChecker func(URLs []string) error
+type fakeChecker func(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult
 
-func (fc fakeChecker) Check(URLs []string) error {
-	return fc(URLs)
+func (fc fakeChecker) Check(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult {
+	return fc(targets, proxy)
 }
 
 func TestReconcile(t *testing.T) {
 	ctx := context.Background()
 	urlsToCheck := []string{"https://fake-url-for-test-only.xyz"}
+	targetsToCheck := []arov1alpha1.InternetCheckerTarget{
+		{
+			Name: "https://fake-url-for-test-only.xyz",
+			Mode: arov1alpha1.InternetCheckerModeHTTP,
+			URL:  "https://fake-url-for-test-only.xyz",
+		},
+	}
 
 	tests := []struct {
 		name               string
@@ -52,8 +59,7 @@ func TestReconcile(t *testing.T) {
 			name:             "error making a request",
 			checkerReturnErr: errors.New("fake error from checker"),
 			wantCondition:    operatorv1.ConditionFalse,
-			wantErr:          "fake error from checker",
-			wantResult:       reconcile.Result{RequeueAfter: time.Hour},
+			wantResult:       reconcile.Result{RequeueAfter: failedCheckRequeueInterval},
 		},
 		{
 			name:               "controller disabled",
@@ -94,14 +100,25 @@ func TestReconcile(t *testing.T) {
 					r := &Reconciler{
 						log:  utillog.GetLogger(),
 						role: testRole,
-						checker: fakeChecker(func(URLs []string) error {
-							if !reflect.DeepEqual(urlsToCheck, URLs) {
-								t.Error(cmp.Diff(urlsToCheck, URLs))
+						checker: fakeChecker(func(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult {
+							if !reflect.DeepEqual(targetsToCheck, targets) {
+								t.Error(cmp.Diff(targetsToCheck, targets))
 							}
 
-							return tt.checkerReturnErr
+							result := &checkResult{
+								target:  targets[0],
+								latency: 10 * time.Millisecond,
+							}
+							if tt.checkerReturnErr != nil {
+								result.err = tt.checkerReturnErr
+								result.phase = arov1alpha1.InternetCheckerPhaseConnect
+							}
+
+							return []*checkResult{result}
 						}),
-						client: clientFake,
+						client:    clientFake,
+						latencies: map[string][]time.Duration{},
+						now:       time.Now,
 					}
 
 					result, err := r.Reconcile(ctx, ctrl.Request{})
@@ -128,8 +145,209 @@ func TestReconcile(t *testing.T) {
 					if condition.Status != tt.wantCondition {
 						t.Error(condition.Status)
 					}
+
+					wantStatuses := 1
+					if tt.controllerDisabled {
+						wantStatuses = 0
+					}
+					if len(instance.Status.InternetChecker) != wantStatuses {
+						t.Errorf("got %d target statuses, want %d", len(instance.Status.InternetChecker), wantStatuses)
+					}
 				})
 			}
 		})
 	}
 }
+
+func TestReconcileTargetStatuses(t *testing.T) {
+	ctx := context.Background()
+
+	// the fake client round trips the status through JSON, which truncates
+	// times to seconds in the local time zone
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC).Local()
+
+	targets := []arov1alpha1.InternetCheckerTarget{
+		{
+			Name:       "acr",
+			Mode:       arov1alpha1.InternetCheckerModeHTTP,
+			URL:        "https://arosvc.azurecr.io/",
+			LatencySLO: &metav1.Duration{Duration: 200 * time.Millisecond},
+		},
+		{
+			Name: "dns",
+			Mode: arov1alpha1.InternetCheckerModeDNS,
+			Host: "example.com",
+		},
+	}
+
+	masterStatus := arov1alpha1.InternetCheckerTargetStatus{
+		Role:      operator.RoleMaster,
+		Name:      "acr",
+		Mode:      arov1alpha1.InternetCheckerModeHTTP,
+		Reachable: true,
+	}
+
+	instance := &arov1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: arov1alpha1.SingletonClusterName,
+		},
+		Spec: arov1alpha1.ClusterSpec{
+			InternetChecker: arov1alpha1.InternetCheckerSpec{
+				URLs:    []string{"https://ignored.io/"},
+				Targets: targets,
+			},
+			OperatorFlags: arov1alpha1.OperatorFlags{
+				operator.CheckerEnabled: operator.FlagTrue,
+			},
+		},
+		Status: arov1alpha1.ClusterStatus{
+			InternetChecker: []arov1alpha1.InternetCheckerTargetStatus{
+				masterStatus,
+				{
+					Role: operator.RoleWorker,
+					Name: "removed",
+					Mode: arov1alpha1.InternetCheckerModeHTTP,
+				},
+			},
+		},
+	}
+
+	// the acr latencies of successive reconciles; the last check fails
+	acrLatencies := []time.Duration{30 * time.Millisecond, 300 * time.Millisecond, 3 * time.Second}
+
+	// each reconcile happens an hour after the previous one
+	var reconciles int
+	r := &Reconciler{
+		log:  utillog.GetLogger(),
+		role: operator.RoleWorker,
+		checker: fakeChecker(func(targets []arov1alpha1.InternetCheckerTarget, proxy proxyFunc) []*checkResult {
+			if !reflect.DeepEqual(instance.Spec.InternetChecker.Targets, targets) {
+				t.Error(cmp.Diff(instance.Spec.InternetChecker.Targets, targets))
+			}
+
+			acr := &checkResult{
+				target:     targets[0],
+				statusCode: 200,
+				proxied:    true,
+				latency:    acrLatencies[reconciles],
+			}
+			if reconciles == len(acrLatencies)-1 {
+				acr.err = errors.New("connection refused")
+				acr.phase = arov1alpha1.InternetCheckerPhaseConnect
+				acr.latency = 0
+			}
+
+			now = now.Add(time.Hour)
+			reconciles++
+
+			return []*checkResult{
+				acr,
+				{
+					target:  targets[1],
+					latency: time.Millisecond,
+				},
+			}
+		}),
+		client:    fake.NewClientBuilder().WithObjects(instance).Build(),
+		latencies: map[string][]time.Duration{},
+		now:       func() time.Time { return now },
+	}
+
+	start := now
+
+	for range acrLatencies {
+		_, err := r.Reconcile(ctx, ctrl.Request{})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	histogram := func(counts ...int) []arov1alpha1.InternetCheckerLatencyBucket {
+		buckets := []arov1alpha1.InternetCheckerLatencyBucket{
+			{LeMilliseconds: 50},
+			{LeMilliseconds: 100},
+			{LeMilliseconds: 250},
+			{LeMilliseconds: 500},
+			{LeMilliseconds: 1000},
+			{LeMilliseconds: 2500},
+			{LeMilliseconds: 5000},
+			{},
+		}
+		for i := range counts {
+			buckets[i].Count = counts[i]
+		}
+		return buckets
+	}
+
+	want := []arov1alpha1.InternetCheckerTargetStatus{
+		masterStatus,
+		{
+			Role:               operator.RoleWorker,
+			Name:               "acr",
+			Mode:               arov1alpha1.InternetCheckerModeHTTP,
+			LastTransitionTime: metav1.NewTime(start.Add(3 * time.Hour)),
+			FailedPhase:        arov1alpha1.InternetCheckerPhaseConnect,
+			Message:            "connection refused",
+			StatusCode:         200,
+			Proxied:            true,
+			LatencyHistogram:   histogram(1, 0, 0, 1),
+			LatencySLOBreaches: 1,
+		},
+		{
+			Role:                operator.RoleWorker,
+			Name:                "dns",
+			Mode:                arov1alpha1.InternetCheckerModeDNS,
+			LastTransitionTime:  metav1.NewTime(start.Add(time.Hour)),
+			Reachable:           true,
+			LatencyMilliseconds: 1,
+			LatencyHistogram:    histogram(3),
+		},
+	}
+
+	if !reflect.DeepEqual(want, instance.Status.InternetChecker) {
+		t.Error(cmp.Diff(want, instance.Status.InternetChecker))
+	}
+
+	// unchanged statuses are not written
+	resourceVersion := instance.ResourceVersion
+	err = r.setStatus(ctx, instance.Status.InternetChecker[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if instance.ResourceVersion != resourceVersion {
+		t.Errorf("got resourceVersion %s, want %s", instance.ResourceVersion, resourceVersion)
+	}
+
+	// disabling the checker removes our statuses but not those of the other
+	// role
+	instance.Spec.OperatorFlags[operator.CheckerEnabled] = operator.FlagFalse
+	err = r.client.Update(ctx, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.Reconcile(ctx, ctrl.Request{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual([]arov1alpha1.InternetCheckerTargetStatus{masterStatus}, instance.Status.InternetChecker) {
+		t.Error(cmp.Diff([]arov1alpha1.InternetCheckerTargetStatus{masterStatus}, instance.Status.InternetChecker))
+	}
+}
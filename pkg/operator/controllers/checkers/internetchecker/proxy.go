@@ -0,0 +1,90 @@
+package internetchecker
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+// newProxyFunc returns a proxyFunc which honours the cluster-wide proxy
+// settings, or nil if no proxy is configured.  It follows the usual
+// HTTP_PROXY, HTTPS_PROXY and NO_PROXY semantics.
+func newProxyFunc(proxy *configv1.Proxy) (proxyFunc, error) {
+	if proxy == nil || (proxy.Status.HTTPProxy == "" && proxy.Status.HTTPSProxy == "") {
+		return nil, nil
+	}
+
+	var httpProxy, httpsProxy *url.URL
+	var err error
+
+	if proxy.Status.HTTPProxy != "" {
+		httpProxy, err = url.Parse(proxy.Status.HTTPProxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if proxy.Status.HTTPSProxy != "" {
+		httpsProxy, err = url.Parse(proxy.Status.HTTPSProxy)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	noProxy := []string{}
+	for _, entry := range strings.Split(proxy.Status.NoProxy, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" {
+			noProxy = append(noProxy, entry)
+		}
+	}
+
+	return func(u *url.URL) (*url.URL, error) {
+		if useProxy(u.Hostname(), noProxy) {
+			switch u.Scheme {
+			case "https":
+				return httpsProxy, nil
+			case "http":
+				return httpProxy, nil
+			}
+		}
+
+		return nil, nil
+	}, nil
+}
+
+// useProxy returns false if host matches an entry of noProxy.  Entries are
+// "*", IP addresses, CIDRs, or domains which also match their subdomains.
+func useProxy(host string, noProxy []string) bool {
+	host = strings.ToLower(host)
+	ip := net.ParseIP(host)
+
+	for _, entry := range noProxy {
+		switch {
+		case entry == "*":
+			return false
+
+		case ip != nil:
+			if _, cidr, err := net.ParseCIDR(entry); err == nil {
+				if cidr.Contains(ip) {
+					return false
+				}
+			} else if entryIP := net.ParseIP(entry); entryIP != nil && entryIP.Equal(ip) {
+				return false
+			}
+
+		default:
+			domain := strings.TrimPrefix(entry, "*")
+			if host == strings.TrimPrefix(domain, ".") || strings.HasSuffix(host, "."+strings.TrimPrefix(domain, ".")) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,69 @@
+package internetchecker
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"net/url"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+)
+
+func TestNewProxyFunc(t *testing.T) {
+	proxy := &configv1.Proxy{
+		Status: configv1.ProxyStatus{
+			HTTPProxy:  "http://http-proxy:3128",
+			HTTPSProxy: "http://https-proxy:3128",
+			NoProxy:    ".cluster.local, example.com,10.0.0.0/16,192.0.2.1",
+		},
+	}
+
+	f, err := newProxyFunc(proxy)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		url  string
+		want string
+	}{
+		{url: "https://arosvc.azurecr.io/", want: "http://https-proxy:3128"},
+		{url: "http://arosvc.azurecr.io/", want: "http://http-proxy:3128"},
+		{url: "https://example.com/", want: ""},
+		{url: "https://www.example.com/", want: ""},
+		{url: "https://notexample.com/", want: "http://https-proxy:3128"},
+		{url: "https://api.cluster.local/", want: ""},
+		{url: "https://10.0.1.1/", want: ""},
+		{url: "https://10.1.0.1/", want: "http://https-proxy:3128"},
+		{url: "https://192.0.2.1:8443/", want: ""},
+	} {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := f(u)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotString string
+			if got != nil {
+				gotString = got.String()
+			}
+			if gotString != tt.want {
+				t.Errorf("got %q, want %q", gotString, tt.want)
+			}
+		})
+	}
+
+	f, err = newProxyFunc(&configv1.Proxy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f != nil {
+		t.Error("expected no proxyFunc when no proxy is configured")
+	}
+}
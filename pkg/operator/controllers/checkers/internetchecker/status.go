@@ -0,0 +1,220 @@
+package internetchecker
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+// maxLatencySamples is the number of recent latencies of each target which
+// are counted in its latency histogram
+const maxLatencySamples = 100
+
+// latencyBuckets are the upper bounds of the latency histogram buckets
+var latencyBuckets = []time.Duration{
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2500 * time.Millisecond,
+	5 * time.Second,
+}
+
+// getTargets returns the targets to check.  For compatibility, URLs are
+// checked as HTTP targets accepting any response if no targets are given.
+func getTargets(spec *arov1alpha1.InternetCheckerSpec) []arov1alpha1.InternetCheckerTarget {
+	if len(spec.Targets) > 0 {
+		return spec.Targets
+	}
+
+	targets := make([]arov1alpha1.InternetCheckerTarget, 0, len(spec.URLs))
+	for _, url := range spec.URLs {
+		targets = append(targets, arov1alpha1.InternetCheckerTarget{
+			Name: url,
+			Mode: arov1alpha1.InternetCheckerModeHTTP,
+			URL:  url,
+		})
+	}
+
+	return targets
+}
+
+// getProxyFunc returns a proxyFunc for the cluster-wide proxy, or nil
+func (r *Reconciler) getProxyFunc(ctx context.Context) (proxyFunc, error) {
+	proxy := &configv1.Proxy{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: "cluster"}, proxy)
+	if kerrors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return newProxyFunc(proxy)
+}
+
+// checkError returns an error naming each target which failed and the phase
+// in which it failed
+func checkError(results []*checkResult) error {
+	errs := []error{}
+	for _, result := range results {
+		if result.err == nil {
+			continue
+		}
+
+		if result.phase != "" {
+			errs = append(errs, fmt.Errorf("%s (%s): %w", result.target.Name, result.phase, result.err))
+		} else {
+			errs = append(errs, fmt.Errorf("%s: %w", result.target.Name, result.err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// targetStatuses records the latencies of the checks which passed and returns
+// the status of each target
+func (r *Reconciler) targetStatuses(results []*checkResult) []arov1alpha1.InternetCheckerTargetStatus {
+	names := map[string]struct{}{}
+	statuses := make([]arov1alpha1.InternetCheckerTargetStatus, 0, len(results))
+
+	for _, result := range results {
+		if result.skipped {
+			continue
+		}
+
+		name := result.target.Name
+		names[name] = struct{}{}
+
+		mode := result.target.Mode
+		if mode == "" {
+			mode = arov1alpha1.InternetCheckerModeHTTP
+		}
+
+		status := arov1alpha1.InternetCheckerTargetStatus{
+			Role:        r.role,
+			Name:        name,
+			Mode:        mode,
+			Reachable:   result.err == nil,
+			FailedPhase: result.phase,
+			StatusCode:  result.statusCode,
+			Proxied:     result.proxied,
+		}
+
+		if result.err != nil {
+			status.Message = result.err.Error()
+		} else {
+			status.LatencyMilliseconds = result.latency.Milliseconds()
+
+			latencies := append(r.latencies[name], result.latency)
+			if len(latencies) > maxLatencySamples {
+				latencies = latencies[len(latencies)-maxLatencySamples:]
+			}
+			r.latencies[name] = latencies
+		}
+
+		status.LatencyHistogram = latencyHistogram(r.latencies[name])
+
+		if result.target.LatencySLO != nil {
+			for _, latency := range r.latencies[name] {
+				if latency > result.target.LatencySLO.Duration {
+					status.LatencySLOBreaches++
+				}
+			}
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	// forget targets which are no longer checked
+	for name := range r.latencies {
+		if _, ok := names[name]; !ok {
+			delete(r.latencies, name)
+		}
+	}
+
+	return statuses
+}
+
+func latencyHistogram(latencies []time.Duration) []arov1alpha1.InternetCheckerLatencyBucket {
+	if len(latencies) == 0 {
+		return nil
+	}
+
+	histogram := make([]arov1alpha1.InternetCheckerLatencyBucket, len(latencyBuckets)+1)
+	for i, le := range latencyBuckets {
+		histogram[i].LeMilliseconds = le.Milliseconds()
+	}
+
+	for _, latency := range latencies {
+		i := sort.Search(len(latencyBuckets), func(i int) bool { return latency <= latencyBuckets[i] })
+		histogram[i].Count++
+	}
+
+	return histogram
+}
+
+// setStatus replaces the statuses of the targets checked by our role.  The
+// master and worker operators both check the targets, so this must preserve
+// the statuses of the other role.  The Cluster is only updated if our statuses
+// changed, so that checks which keep getting the same results don't write to
+// it.
+func (r *Reconciler) setStatus(ctx context.Context, statuses []arov1alpha1.InternetCheckerTargetStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		instance := &arov1alpha1.Cluster{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+		if err != nil {
+			return err
+		}
+
+		newStatuses := []arov1alpha1.InternetCheckerTargetStatus{}
+		oldStatuses := []arov1alpha1.InternetCheckerTargetStatus{}
+		previous := map[string]*arov1alpha1.InternetCheckerTargetStatus{}
+		for i, status := range instance.Status.InternetChecker {
+			if status.Role == r.role {
+				oldStatuses = append(oldStatuses, status)
+				previous[status.Name] = &instance.Status.InternetChecker[i]
+				continue
+			}
+			newStatuses = append(newStatuses, status)
+		}
+
+		ourStatuses := make([]arov1alpha1.InternetCheckerTargetStatus, 0, len(statuses))
+		for _, status := range statuses {
+			if p := previous[status.Name]; p != nil && p.Reachable == status.Reachable {
+				status.LastTransitionTime = p.LastTransitionTime
+			} else {
+				status.LastTransitionTime = metav1.NewTime(r.now())
+			}
+			ourStatuses = append(ourStatuses, status)
+		}
+
+		if reflect.DeepEqual(oldStatuses, ourStatuses) {
+			return nil
+		}
+
+		newStatuses = append(newStatuses, ourStatuses...)
+		sort.SliceStable(newStatuses, func(i, j int) bool { return newStatuses[i].Role < newStatuses[j].Role })
+
+		if len(newStatuses) == 0 {
+			newStatuses = nil
+		}
+
+		instance.Status.InternetChecker = newStatuses
+		return r.client.Status().Update(ctx, instance)
+	})
+}
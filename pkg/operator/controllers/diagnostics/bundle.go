@@ -0,0 +1,467 @@
+package diagnostics
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"time"
+
+	configv1 "github.com/openshift/api/config/v1"
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	securityv1 "github.com/openshift/api/security/v1"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	aropreviewv1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/preview.aro.openshift.io/v1alpha1"
+)
+
+const (
+	// maxBundleSize keeps the bundle, plus the secret's metadata, within the
+	// 1MiB limit on the size of a secret
+	maxBundleSize = 1000 * 1024
+
+	logTailLines  = 2000
+	logLimitBytes = 256 * 1024
+)
+
+var (
+	// aroNamespaces are the namespaces of the operator and of the workloads
+	// deployed by its controllers
+	aroNamespaces = []string{
+		operator.Namespace,
+		"openshift-azure-routefix",
+		"openshift-azure-logging",
+		"openshift-azure-guardrails",
+	}
+
+	// dnsmasqMachineConfigs are the MachineConfigs deployed by the dnsmasq
+	// controllers.  dnsmasq runs as a systemd unit on each node rather than as
+	// a DaemonSet, so its rollout is tracked by the MachineConfigPools.
+	dnsmasqMachineConfigs = []string{"99-master-aro-dns", "99-worker-aro-dns"}
+)
+
+// ownedLists returns empty lists of the kinds of resource outside
+// aroNamespaces which the operator's controllers create with the Cluster as
+// their controller
+func ownedLists() map[string]client.ObjectList {
+	return map[string]client.ObjectList{
+		"clusteroperators":           &configv1.ClusterOperatorList{},
+		"clusterrolebindings":        &rbacv1.ClusterRoleBindingList{},
+		"clusterroles":               &rbacv1.ClusterRoleList{},
+		"machinehealthchecks":        &machinev1beta1.MachineHealthCheckList{},
+		"prometheusrules":            &monitoringv1.PrometheusRuleList{},
+		"securitycontextconstraints": &securityv1.SecurityContextConstraintsList{},
+	}
+}
+
+type metadata struct {
+	RequestID       string   `json:"requestId"`
+	GatheredAt      string   `json:"gatheredAt"`
+	OperatorVersion string   `json:"operatorVersion,omitempty"`
+	Errors          []string `json:"errors,omitempty"`
+	Truncated       []string `json:"truncated,omitempty"`
+}
+
+type bundleFile struct {
+	name string
+	data []byte
+
+	// log files are truncated, oldest lines first, if the bundle is too large
+	log     bool
+	dropped int
+}
+
+// bundle holds the files of a diagnostics bundle until they are archived
+type bundle struct {
+	files []*bundleFile
+}
+
+func (b *bundle) addFile(name string, data []byte) {
+	b.files = append(b.files, &bundleFile{name: name, data: data})
+}
+
+func (b *bundle) addLog(name string, data []byte) {
+	b.files = append(b.files, &bundleFile{name: name, data: data, log: true})
+}
+
+func (b *bundle) addJSON(name string, o interface{}) error {
+	data, err := json.MarshalIndent(o, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	b.addFile(name, data)
+	return nil
+}
+
+// truncateLogs drops the oldest half of the lines of each log file.  It
+// returns false if there is nothing left to drop.
+func (b *bundle) truncateLogs() bool {
+	var truncated bool
+	for _, f := range b.files {
+		if !f.log || len(f.data) == 0 {
+			continue
+		}
+
+		data := f.data[len(f.data)/2:]
+		if i := bytes.IndexByte(data, '\n'); i >= 0 && i < len(data)-1 {
+			data = data[i+1:]
+		} else {
+			data = nil
+		}
+
+		f.dropped += len(f.data) - len(data)
+		f.data = data
+		truncated = true
+	}
+
+	return truncated
+}
+
+// archive returns the tar.gz archive of the bundle.  If the archive is larger
+// than maxBundleSize, the oldest lines of the logs are dropped until it fits.
+func (b *bundle) archive(md *metadata, modTime time.Time) ([]byte, error) {
+	for {
+		data, err := b.write(md, modTime)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(data) <= maxBundleSize {
+			return data, nil
+		}
+
+		if !b.truncateLogs() {
+			return nil, fmt.Errorf("diagnostics bundle is %d bytes without logs, larger than the maximum of %d bytes", len(data), maxBundleSize)
+		}
+	}
+}
+
+// write returns the tar.gz archive of the bundle's files followed by its
+// metadata.json, which records the log files which have been truncated
+func (b *bundle) write(md *metadata, modTime time.Time) ([]byte, error) {
+	md.Truncated = nil
+	for _, f := range b.files {
+		if f.dropped > 0 {
+			md.Truncated = append(md.Truncated, fmt.Sprintf("%s: dropped the oldest %d bytes", f.name, f.dropped))
+		}
+	}
+
+	mdData, err := json.MarshalIndent(md, "", "    ")
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+
+	for _, f := range append(b.files, &bundleFile{name: "metadata.json", data: mdData}) {
+		err = tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     f.name,
+			Mode:     0644,
+			Size:     int64(len(f.data)),
+			ModTime:  modTime,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		_, err = tw.Write(f.data)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = tw.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = gz.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// gather builds the diagnostics bundle.  Failures to gather individual items
+// are recorded in the bundle's metadata.json rather than failing the whole
+// bundle: a misbehaving cluster should still yield as much as possible.  For
+// the same reason, if the bundle is too large the oldest lines of the logs are
+// dropped until it fits.
+func (r *Reconciler) gather(ctx context.Context, instance *arov1alpha1.Cluster, requestID string) ([]byte, error) {
+	now := r.now().UTC()
+	b := &bundle{}
+
+	md := &metadata{
+		RequestID:       requestID,
+		GatheredAt:      now.Format(time.RFC3339),
+		OperatorVersion: instance.Status.OperatorVersion,
+	}
+
+	for _, g := range []struct {
+		name   string
+		gather func(context.Context, *bundle, *arov1alpha1.Cluster) error
+	}{
+		{name: "cluster", gather: r.gatherCluster},
+		{name: "previewfeatures", gather: r.gatherPreviewFeatures},
+		{name: "deployments", gather: r.gatherDeployments},
+		{name: "daemonsets", gather: r.gatherDaemonSets},
+		{name: "owned", gather: r.gatherOwned},
+		{name: "dnsmasq", gather: r.gatherDnsmasq},
+		{name: "events", gather: r.gatherEvents},
+		{name: "logs", gather: r.gatherOperatorLogs},
+	} {
+		err := g.gather(ctx, b, instance)
+		if err != nil {
+			r.Log.Warnf("gathering %s: %s", g.name, err)
+			md.Errors = append(md.Errors, fmt.Sprintf("%s: %s", g.name, err))
+		}
+	}
+
+	return b.archive(md, now)
+}
+
+func (r *Reconciler) gatherCluster(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	instance = instance.DeepCopy()
+	instance.ManagedFields = nil
+
+	err := b.addJSON("cluster.json", instance)
+	if err != nil {
+		return err
+	}
+
+	return b.addJSON("conditions.json", instance.Status.Conditions)
+}
+
+func (r *Reconciler) gatherPreviewFeatures(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	previewFeatures := &aropreviewv1alpha1.PreviewFeatureList{}
+	err := r.Client.List(ctx, previewFeatures)
+	if err != nil {
+		return err
+	}
+
+	for i := range previewFeatures.Items {
+		previewFeatures.Items[i].ManagedFields = nil
+	}
+
+	return b.addJSON("previewfeatures.json", previewFeatures.Items)
+}
+
+func (r *Reconciler) gatherDeployments(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	items := []appsv1.Deployment{}
+	for _, namespace := range aroNamespaces {
+		deployments, err := r.kubernetescli.AppsV1().Deployments(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, deployment := range deployments.Items {
+			deployment.ManagedFields = nil
+			items = append(items, deployment)
+		}
+	}
+
+	return b.addJSON("deployments.json", items)
+}
+
+func (r *Reconciler) gatherDaemonSets(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	items := []appsv1.DaemonSet{}
+	for _, namespace := range aroNamespaces {
+		daemonSets, err := r.kubernetescli.AppsV1().DaemonSets(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, ds := range daemonSets.Items {
+			ds.ManagedFields = nil
+			items = append(items, ds)
+		}
+	}
+
+	return b.addJSON("daemonsets.json", items)
+}
+
+// gatherOwned gathers the resources outside aroNamespaces which are
+// controlled by the Cluster, one file per kind
+func (r *Reconciler) gatherOwned(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	lists := ownedLists()
+
+	names := make([]string, 0, len(lists))
+	for name := range lists {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// carry on past kinds which can't be listed, e.g. because their CRD isn't
+	// installed
+	var errs []error
+	for _, name := range names {
+		err := r.Client.List(ctx, lists[name])
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+			continue
+		}
+
+		objects, err := meta.ExtractList(lists[name])
+		if err != nil {
+			return err
+		}
+
+		items := []kruntime.Object{}
+		for _, o := range objects {
+			object, ok := o.(client.Object)
+			if !ok || !metav1.IsControlledBy(object, instance) {
+				continue
+			}
+
+			object.SetManagedFields(nil)
+			items = append(items, object)
+		}
+
+		err = b.addJSON(path.Join("owned", name+".json"), items)
+		if err != nil {
+			return err
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) gatherDnsmasq(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	machineConfigs := []*mcv1.MachineConfig{}
+	for _, name := range dnsmasqMachineConfigs {
+		mc := &mcv1.MachineConfig{}
+		err := r.Client.Get(ctx, types.NamespacedName{Name: name}, mc)
+		if kerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		mc.ManagedFields = nil
+		machineConfigs = append(machineConfigs, mc)
+	}
+
+	err := b.addJSON("machineconfigs.json", machineConfigs)
+	if err != nil {
+		return err
+	}
+
+	machineConfigPools := &mcv1.MachineConfigPoolList{}
+	err = r.Client.List(ctx, machineConfigPools)
+	if err != nil {
+		return err
+	}
+
+	for i := range machineConfigPools.Items {
+		machineConfigPools.Items[i].ManagedFields = nil
+	}
+
+	return b.addJSON("machineconfigpools.json", machineConfigPools.Items)
+}
+
+// gatherEvents gathers the events in aroNamespaces, most recent first
+func (r *Reconciler) gatherEvents(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	items := []corev1.Event{}
+	for _, namespace := range aroNamespaces {
+		events, err := r.kubernetescli.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events.Items {
+			event.ManagedFields = nil
+			items = append(items, event)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool { return eventTime(&items[i]).After(eventTime(&items[j])) })
+
+	return b.addJSON("events.json", items)
+}
+
+func eventTime(event *corev1.Event) time.Time {
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	return event.EventTime.Time
+}
+
+// gatherOperatorLogs gathers the tail of the logs of each operator container,
+// and of its previous instance if it has restarted
+func (r *Reconciler) gatherOperatorLogs(ctx context.Context, b *bundle, instance *arov1alpha1.Cluster) error {
+	pods, err := r.kubernetescli.CoreV1().Pods(operator.Namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	// carry on past containers whose logs can't be read, e.g. because they
+	// haven't started
+	var errs []error
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+
+		for _, cs := range pod.Status.ContainerStatuses {
+			err = r.gatherContainerLogs(ctx, b, pod, cs.Name, false)
+			if err != nil {
+				errs = append(errs, err)
+			}
+
+			if cs.RestartCount > 0 {
+				err = r.gatherContainerLogs(ctx, b, pod, cs.Name, true)
+				if err != nil {
+					errs = append(errs, err)
+				}
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r *Reconciler) gatherContainerLogs(ctx context.Context, b *bundle, pod *corev1.Pod, container string, previous bool) error {
+	tailLines := int64(logTailLines)
+	limitBytes := int64(logLimitBytes)
+
+	data, err := r.kubernetescli.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container:  container,
+		Previous:   previous,
+		TailLines:  &tailLines,
+		LimitBytes: &limitBytes,
+	}).DoRaw(ctx)
+	if err != nil {
+		return fmt.Errorf("%s/%s: %w", pod.Name, container, err)
+	}
+
+	name := container + ".log"
+	if previous {
+		name = container + ".previous.log"
+	}
+
+	b.addLog(path.Join("logs", pod.Name, name), data)
+	return nil
+}
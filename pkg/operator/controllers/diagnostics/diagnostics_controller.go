@@ -0,0 +1,154 @@
+package diagnostics
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/operator/controllers/base"
+)
+
+const (
+	ControllerName = "Diagnostics"
+)
+
+// Reconciler gathers a diagnostics bundle of the operator and the resources
+// it manages whenever the Cluster singleton is annotated with a new
+// diagnostics request, so that it can be fetched by the RP in one call.
+type Reconciler struct {
+	base.AROController
+
+	kubernetescli kubernetes.Interface
+
+	now func() time.Time
+}
+
+func NewReconciler(log *logrus.Entry, client client.Client, kubernetescli kubernetes.Interface) *Reconciler {
+	return &Reconciler{
+		AROController: base.AROController{
+			Log:    log,
+			Client: client,
+			Name:   ControllerName,
+		},
+
+		kubernetescli: kubernetescli,
+
+		now: time.Now,
+	}
+}
+
+func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.Result, error) {
+	instance, err := r.GetCluster(ctx)
+	if err != nil {
+		return reconcile.Result{}, err
+	}
+
+	if !instance.Spec.OperatorFlags.GetSimpleBoolean(operator.DiagnosticsEnabled) {
+		r.Log.Debug("controller is disabled")
+		return reconcile.Result{}, nil
+	}
+
+	r.Log.Debug("running")
+
+	requestID := instance.Annotations[operator.DiagnosticsRequestAnnotation]
+	if requestID == "" {
+		return reconcile.Result{}, nil
+	}
+
+	secret := &corev1.Secret{}
+	err = r.Client.Get(ctx, types.NamespacedName{Namespace: operator.Namespace, Name: operator.DiagnosticsSecretName}, secret)
+	switch {
+	case kerrors.IsNotFound(err):
+		secret = nil
+	case err != nil:
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
+	case secret.Annotations[operator.DiagnosticsRequestAnnotation] == requestID:
+		// the bundle for this request has already been gathered
+		return reconcile.Result{}, nil
+	}
+
+	r.Log.Infof("gathering diagnostics bundle for request %q", requestID)
+	r.SetProgressing(ctx, "Gathering diagnostics bundle")
+
+	b, err := r.gather(ctx, instance, requestID)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
+	}
+
+	err = r.writeBundle(ctx, secret, requestID, b)
+	if err != nil {
+		r.Log.Error(err)
+		r.SetDegraded(ctx, err)
+
+		return reconcile.Result{}, err
+	}
+
+	r.ClearConditions(ctx)
+	return reconcile.Result{}, nil
+}
+
+// writeBundle writes the bundle to the diagnostics secret, replacing any
+// previous bundle
+func (r *Reconciler) writeBundle(ctx context.Context, secret *corev1.Secret, requestID string, b []byte) error {
+	if secret == nil {
+		return r.Client.Create(ctx, &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      operator.DiagnosticsSecretName,
+				Namespace: operator.Namespace,
+				Annotations: map[string]string{
+					operator.DiagnosticsRequestAnnotation: requestID,
+				},
+			},
+			Type: corev1.SecretTypeOpaque,
+			Data: map[string][]byte{
+				operator.DiagnosticsSecretKey: b,
+			},
+		})
+	}
+
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[operator.DiagnosticsRequestAnnotation] = requestID
+	secret.Data = map[string][]byte{
+		operator.DiagnosticsSecretKey: b,
+	}
+
+	return r.Client.Update(ctx, secret)
+}
+
+// SetupWithManager setup our manager
+func (r *Reconciler) SetupWithManager(mgr ctrl.Manager) error {
+	aroClusterPredicate := predicate.NewPredicateFuncs(func(o client.Object) bool {
+		return o.GetName() == arov1alpha1.SingletonClusterName
+	})
+
+	// only annotation changes are of interest: this also stops our own
+	// condition updates from triggering another reconcile
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arov1alpha1.Cluster{}, builder.WithPredicates(aroClusterPredicate, predicate.AnnotationChangedPredicate{})).
+		Named(ControllerName).
+		Complete(r)
+}
@@ -0,0 +1,385 @@
+package diagnostics
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/go-autorest/autorest/to"
+	configv1 "github.com/openshift/api/config/v1"
+	operatorv1 "github.com/openshift/api/operator/v1"
+	mcv1 "github.com/openshift/machine-config-operator/pkg/apis/machineconfiguration.openshift.io/v1"
+	"github.com/sirupsen/logrus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/Azure/ARO-RP/pkg/operator"
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/operator/controllers/base"
+	_ "github.com/Azure/ARO-RP/pkg/util/scheme"
+)
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	conditions := []operatorv1.OperatorCondition{
+		{
+			Type:   arov1alpha1.InternetReachableFromMaster,
+			Status: operatorv1.ConditionFalse,
+		},
+	}
+
+	cluster := func(flag, requestID string) *arov1alpha1.Cluster {
+		c := &arov1alpha1.Cluster{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: arov1alpha1.SingletonClusterName,
+				UID:  "cluster-uid",
+			},
+			Spec: arov1alpha1.ClusterSpec{
+				OperatorFlags: arov1alpha1.OperatorFlags{
+					operator.DiagnosticsEnabled: flag,
+				},
+			},
+			Status: arov1alpha1.ClusterStatus{
+				OperatorVersion: "test",
+				Conditions:      conditions,
+			},
+		}
+		if requestID != "" {
+			c.Annotations = map[string]string{
+				operator.DiagnosticsRequestAnnotation: requestID,
+			}
+		}
+		return c
+	}
+
+	gathered := func(requestID string) *corev1.Secret {
+		return &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      operator.DiagnosticsSecretName,
+				Namespace: operator.Namespace,
+				Annotations: map[string]string{
+					operator.DiagnosticsRequestAnnotation: requestID,
+				},
+			},
+			Data: map[string][]byte{
+				operator.DiagnosticsSecretKey: []byte("old bundle"),
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name          string
+		objects       []client.Object
+		wantRequestID string
+		wantGathered  bool
+	}{
+		{
+			name:    "disabled",
+			objects: []client.Object{cluster(operator.FlagFalse, "1")},
+		},
+		{
+			name:    "not requested",
+			objects: []client.Object{cluster(operator.FlagTrue, "")},
+		},
+		{
+			name:          "requested",
+			objects:       []client.Object{cluster(operator.FlagTrue, "1")},
+			wantRequestID: "1",
+			wantGathered:  true,
+		},
+		{
+			name:          "requested again",
+			objects:       []client.Object{cluster(operator.FlagTrue, "2"), gathered("1")},
+			wantRequestID: "2",
+			wantGathered:  true,
+		},
+		{
+			name:          "already gathered",
+			objects:       []client.Object{cluster(operator.FlagTrue, "1"), gathered("1")},
+			wantRequestID: "1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			objects := append(tt.objects, &mcv1.MachineConfig{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "99-master-aro-dns",
+				},
+			}, &mcv1.MachineConfigPool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "master",
+				},
+			}, &configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "aro",
+					OwnerReferences: []metav1.OwnerReference{
+						{
+							APIVersion: arov1alpha1.GroupVersion.String(),
+							Kind:       "Cluster",
+							Name:       arov1alpha1.SingletonClusterName,
+							UID:        "cluster-uid",
+							Controller: to.BoolPtr(true),
+						},
+					},
+				},
+			}, &configv1.ClusterOperator{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "network",
+				},
+			})
+
+			clientFake := ctrlfake.NewClientBuilder().WithObjects(objects...).Build()
+
+			kubernetescli := fake.NewSimpleClientset(
+				&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aro-operator-master-0",
+						Namespace: operator.Namespace,
+					},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{
+							{
+								Name:         "aro-operator",
+								RestartCount: 1,
+							},
+						},
+					},
+				},
+				&appsv1.Deployment{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "aro-operator-master",
+						Namespace: operator.Namespace,
+					},
+				},
+				&appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "routefix",
+						Namespace: "openshift-azure-routefix",
+					},
+				},
+				&corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "older",
+						Namespace: operator.Namespace,
+					},
+					LastTimestamp: metav1.NewTime(now.Add(-time.Hour)),
+				},
+				&corev1.Event{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      "newer",
+						Namespace: "openshift-azure-logging",
+					},
+					LastTimestamp: metav1.NewTime(now.Add(-time.Minute)),
+				},
+			)
+
+			r := &Reconciler{
+				AROController: base.AROController{
+					Log:    logrus.NewEntry(logrus.StandardLogger()),
+					Client: clientFake,
+					Name:   ControllerName,
+				},
+				kubernetescli: kubernetescli,
+				now:           func() time.Time { return now },
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			secret := &corev1.Secret{}
+			err = clientFake.Get(ctx, types.NamespacedName{Namespace: operator.Namespace, Name: operator.DiagnosticsSecretName}, secret)
+			if tt.wantRequestID == "" {
+				if err == nil {
+					t.Fatal("unexpected diagnostics secret")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if secret.Annotations[operator.DiagnosticsRequestAnnotation] != tt.wantRequestID {
+				t.Errorf("got request %q, want %q", secret.Annotations[operator.DiagnosticsRequestAnnotation], tt.wantRequestID)
+			}
+
+			if !tt.wantGathered {
+				if string(secret.Data[operator.DiagnosticsSecretKey]) != "old bundle" {
+					t.Error("bundle was gathered again")
+				}
+				return
+			}
+
+			files := readBundle(t, secret.Data[operator.DiagnosticsSecretKey])
+
+			names := make([]string, 0, len(files))
+			for name := range files {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			wantNames := []string{
+				"cluster.json",
+				"conditions.json",
+				"daemonsets.json",
+				"deployments.json",
+				"events.json",
+				"logs/aro-operator-master-0/aro-operator.log",
+				"logs/aro-operator-master-0/aro-operator.previous.log",
+				"machineconfigpools.json",
+				"machineconfigs.json",
+				"metadata.json",
+				"owned/clusteroperators.json",
+				"owned/clusterrolebindings.json",
+				"owned/clusterroles.json",
+				"owned/machinehealthchecks.json",
+				"owned/prometheusrules.json",
+				"owned/securitycontextconstraints.json",
+				"previewfeatures.json",
+			}
+			if !reflect.DeepEqual(names, wantNames) {
+				t.Errorf("got files %v", names)
+			}
+
+			var md *metadata
+			err = json.Unmarshal(files["metadata.json"], &md)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			wantMetadata := &metadata{
+				RequestID:       tt.wantRequestID,
+				GatheredAt:      "2023-06-01T12:00:00Z",
+				OperatorVersion: "test",
+			}
+			if !reflect.DeepEqual(md, wantMetadata) {
+				t.Errorf("got metadata %#v", md)
+			}
+
+			var gotConditions []operatorv1.OperatorCondition
+			err = json.Unmarshal(files["conditions.json"], &gotConditions)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotConditions, conditions) {
+				t.Errorf("got conditions %#v", gotConditions)
+			}
+
+			var events []corev1.Event
+			err = json.Unmarshal(files["events.json"], &events)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(events) != 2 || events[0].Name != "newer" || events[1].Name != "older" {
+				t.Errorf("got events %#v", events)
+			}
+
+			var clusterOperators []configv1.ClusterOperator
+			err = json.Unmarshal(files["owned/clusteroperators.json"], &clusterOperators)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(clusterOperators) != 1 || clusterOperators[0].Name != "aro" {
+				t.Errorf("got cluster operators %#v", clusterOperators)
+			}
+
+			if string(files["logs/aro-operator-master-0/aro-operator.log"]) != "fake logs" {
+				t.Errorf("got logs %q", files["logs/aro-operator-master-0/aro-operator.log"])
+			}
+		})
+	}
+}
+
+func TestArchiveTruncatesLogs(t *testing.T) {
+	// random lines barely compress, so these logs are too large for a bundle
+	lines := make([]string, 0, 20000)
+	for i := 0; i < cap(lines); i++ {
+		b := make([]byte, 75)
+		_, err := rand.Read(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, base64.StdEncoding.EncodeToString(b))
+	}
+	log := []byte(strings.Join(lines, "\n") + "\n")
+
+	b := &bundle{}
+	b.addFile("cluster.json", []byte("{}"))
+	b.addLog("logs/pod/container.log", log)
+
+	data, err := b.archive(&metadata{RequestID: "1"}, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data) > maxBundleSize {
+		t.Fatalf("bundle is %d bytes", len(data))
+	}
+
+	files := readBundle(t, data)
+
+	truncated := files["logs/pod/container.log"]
+	if len(truncated) == 0 || !bytes.HasSuffix(log, truncated) || !bytes.HasPrefix(truncated, []byte(lines[len(lines)-bytes.Count(truncated, []byte("\n"))])) {
+		t.Errorf("got %d bytes of logs, wanted the newest whole lines", len(truncated))
+	}
+
+	var gotMetadata *metadata
+	err = json.Unmarshal(files["metadata.json"], &gotMetadata)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantTruncated := []string{fmt.Sprintf("logs/pod/container.log: dropped the oldest %d bytes", len(log)-len(truncated))}
+	if !reflect.DeepEqual(gotMetadata.Truncated, wantTruncated) {
+		t.Errorf("got truncated %#v", gotMetadata.Truncated)
+	}
+}
+
+func readBundle(t *testing.T, b []byte) map[string][]byte {
+	gz, err := gzip.NewReader(bytes.NewReader(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string][]byte{}
+
+	tr := tar.NewReader(gz)
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[h.Name], err = io.ReadAll(tr)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return files
+}
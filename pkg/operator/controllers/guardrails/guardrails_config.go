@@ -37,6 +37,7 @@ const (
 	controllerMutatingWebhookTimeout         = "aro.guardrails.mutatingwebhook.timeoutSeconds"
 
 	controllerReconciliationMinutes     = "aro.guardrails.reconciliationMinutes"
+	controllerRolloutSoakHours          = "aro.guardrails.rolloutSoakHours"
 	controllerPolicyManagedTemplate     = "aro.guardrails.policies.%s.managed"
 	controllerPolicyEnforcementTemplate = "aro.guardrails.policies.%s.enforcement"
 
@@ -54,6 +55,7 @@ const (
 	defaultAuditLimitMem      = "512Mi"
 
 	defaultReconciliationMinutes = "60"
+	defaultRolloutSoakHours      = "168"
 
 	defaultValidatingWebhookFailurePolicy = "Ignore"
 	defaultValidatingWebhookTimeout       = "3"
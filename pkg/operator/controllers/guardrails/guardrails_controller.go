@@ -40,6 +40,8 @@ type Reconciler struct {
 	reconciliationMinutes int
 	cleanupNeeded         bool
 	kubernetescli         kubernetes.Interface
+
+	now func() time.Time
 }
 
 func NewReconciler(log *logrus.Entry, client client.Client, dh dynamichelper.Interface, k8scli kubernetes.Interface) *Reconciler {
@@ -56,6 +58,8 @@ func NewReconciler(log *logrus.Entry, client client.Client, dh dynamichelper.Int
 		readinessTimeout:  5 * time.Minute,
 		cleanupNeeded:     false,
 		kubernetescli:     k8scli,
+
+		now: time.Now,
 	}
 }
 
@@ -123,6 +127,12 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 			if err != nil {
 				return reconcile.Result{}, err
 			}
+
+			// Report the violations of the GateKeeper Constraints
+			err = r.updateStatus(ctx, gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				r.log.Warnf("failed to update guardrails status with error %s", err.Error())
+			}
 		}
 
 		// start a ticker to re-enforce gatekeeper policies periodically
@@ -153,6 +163,11 @@ func (r *Reconciler) Reconcile(ctx context.Context, request ctrl.Request) (ctrl.
 			if err != nil {
 				r.log.Warnf("failed to remove ConstraintTemplates with error %s", err.Error())
 			}
+
+			err = r.setStatus(ctx, nil)
+			if err != nil {
+				r.log.Warnf("failed to clear guardrails status with error %s", err.Error())
+			}
 		}
 		err = r.deployer.Remove(ctx, config.GuardRailsDeploymentConfig{Namespace: r.namespace})
 		if err != nil {
@@ -10,20 +10,36 @@ import (
 	"errors"
 	"fmt"
 	"path/filepath"
+	"reflect"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	kruntime "k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
 
 	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
 	"github.com/Azure/ARO-RP/pkg/operator/controllers/guardrails/config"
 	"github.com/Azure/ARO-RP/pkg/util/dynamichelper"
 )
 
+// enforcement actions which can be set on a policy.  rollout deploys the
+// constraint in dryrun until it has soaked on the cluster, and in deny after.
+// Policies are deployed in dryrun unless their enforcement is set, so rollout
+// is opted in to per policy.
+const (
+	enforcementDeny    = "deny"
+	enforcementWarn    = "warn"
+	enforcementDryrun  = "dryrun"
+	enforcementRollout = "rollout"
+)
+
 func (r *Reconciler) getPolicyConfig(ctx context.Context, instance *arov1alpha1.Cluster, na string) (string, string, error) {
 	parts := strings.Split(na, ".")
 	if len(parts) < 1 {
@@ -35,11 +51,34 @@ func (r *Reconciler) getPolicyConfig(ctx context.Context, instance *arov1alpha1.
 	managed := instance.Spec.OperatorFlags.GetWithDefault(managedPath, "false")
 
 	enforcementPath := fmt.Sprintf(controllerPolicyEnforcementTemplate, name)
-	enforcement := instance.Spec.OperatorFlags.GetWithDefault(enforcementPath, "dryrun")
+	enforcement := strings.ToLower(instance.Spec.OperatorFlags.GetWithDefault(enforcementPath, enforcementDryrun))
+
+	switch enforcement {
+	case enforcementDeny, enforcementWarn, enforcementDryrun, enforcementRollout:
+	default:
+		r.log.Warnf("unrecognised enforcement %q for policy %s, using %s", enforcement, name, enforcementDryrun)
+		enforcement = enforcementDryrun
+	}
 
 	return managed, enforcement, nil
 }
 
+// getRolloutEnforcement returns the enforcement action of a constraint whose
+// rollout started at start: dryrun until the soak period has passed, and deny
+// after that
+func (r *Reconciler) getRolloutEnforcement(instance *arov1alpha1.Cluster, start time.Time) string {
+	hours, err := strconv.Atoi(instance.Spec.OperatorFlags.GetWithDefault(controllerRolloutSoakHours, defaultRolloutSoakHours))
+	if err != nil || hours < 0 {
+		hours, _ = strconv.Atoi(defaultRolloutSoakHours)
+	}
+
+	if r.now().Before(start.Add(time.Duration(hours) * time.Hour)) {
+		return enforcementDryrun
+	}
+
+	return enforcementDeny
+}
+
 func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string) error {
 	template, err := template.ParseFS(fs, filepath.Join(path, "*"))
 	if err != nil {
@@ -52,8 +91,16 @@ func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string)
 		return err
 	}
 
+	// the rollout of a constraint is soaked from when it was first deployed
+	// in rollout, so that neither recreating it nor switching it to rollout
+	// after it has been deployed in another enforcement skips the soak
+	var oldRollouts []arov1alpha1.GuardrailsRolloutStatus
+	if instance.Status.Guardrails != nil {
+		oldRollouts = instance.Status.Guardrails.Rollouts
+	}
+	rollouts := []arov1alpha1.GuardrailsRolloutStatus{}
+
 	creates := make([]kruntime.Object, 0)
-	buffer := new(bytes.Buffer)
 	for _, templ := range template.Templates() {
 		managed, enforcement, err := r.getPolicyConfig(ctx, instance, templ.Name())
 		if err != nil {
@@ -62,6 +109,7 @@ func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string)
 		policyConfig := &config.GuardRailsPolicyConfig{
 			Enforcement: enforcement,
 		}
+		buffer := new(bytes.Buffer)
 		err = templ.Execute(buffer, policyConfig)
 		if err != nil {
 			return err
@@ -81,8 +129,37 @@ func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string)
 			continue
 		}
 
+		if enforcement == enforcementRollout {
+			rollout := arov1alpha1.GuardrailsRolloutStatus{
+				Name:      uns.GetName(),
+				Kind:      uns.GetKind(),
+				StartTime: metav1.NewTime(r.now()),
+			}
+			for _, old := range oldRollouts {
+				if old.Name == rollout.Name && old.Kind == rollout.Kind {
+					rollout.StartTime = old.StartTime
+					break
+				}
+			}
+			rollouts = append(rollouts, rollout)
+
+			err = unstructured.SetNestedField(uns.Object, r.getRolloutEnforcement(instance, rollout.StartTime.Time), "spec", "enforcementAction")
+			if err != nil {
+				return err
+			}
+		}
+
 		creates = append(creates, uns)
 	}
+
+	// record the rollouts before deploying the constraints, so that a
+	// constraint is never deployed in deny without its soak being recorded
+	sort.Slice(rollouts, func(i, j int) bool { return rollouts[i].Name < rollouts[j].Name })
+	err = r.setRollouts(ctx, rollouts)
+	if err != nil {
+		return err
+	}
+
 	err = r.dh.Ensure(ctx, creates...)
 	if err != nil {
 		return err
@@ -90,6 +167,36 @@ func (r *Reconciler) ensurePolicy(ctx context.Context, fs embed.FS, path string)
 	return nil
 }
 
+// setRollouts records the constraints being rolled out in the cluster status,
+// forgetting those which no longer are
+func (r *Reconciler) setRollouts(ctx context.Context, rollouts []arov1alpha1.GuardrailsRolloutStatus) error {
+	if len(rollouts) == 0 {
+		rollouts = nil
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		instance := &arov1alpha1.Cluster{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+		if err != nil {
+			return err
+		}
+
+		if instance.Status.Guardrails == nil {
+			if rollouts == nil {
+				return nil
+			}
+			instance.Status.Guardrails = &arov1alpha1.GuardrailsStatus{}
+		}
+
+		if reflect.DeepEqual(instance.Status.Guardrails.Rollouts, rollouts) {
+			return nil
+		}
+
+		instance.Status.Guardrails.Rollouts = rollouts
+		return r.client.Status().Update(ctx, instance)
+	})
+}
+
 func (r *Reconciler) removePolicy(ctx context.Context, fs embed.FS, path string) error {
 	template, err := template.ParseFS(fs, filepath.Join(path, "*"))
 	if err != nil {
@@ -141,6 +248,11 @@ func (r *Reconciler) policyTicker(ctx context.Context, instance *arov1alpha1.Clu
 			if err != nil {
 				r.log.Errorf("policyTicker ensurePolicy error %s", err.Error())
 			}
+
+			err = r.updateStatus(ctx, gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				r.log.Errorf("policyTicker updateStatus error %s", err.Error())
+			}
 		}
 	}
 }
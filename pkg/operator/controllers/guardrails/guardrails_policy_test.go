@@ -0,0 +1,224 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	mock_dynamichelper "github.com/Azure/ARO-RP/pkg/util/mocks/dynamichelper"
+)
+
+func TestEnsurePolicy(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	constraint := func(kind, name string, created time.Time) *unstructured.Unstructured {
+		uns := &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"enforcementAction": "dryrun",
+				},
+			},
+		}
+		uns.SetCreationTimestamp(metav1.NewTime(created))
+		return uns
+	}
+
+	rollout := func(kind, name string, start time.Time) arov1alpha1.GuardrailsRolloutStatus {
+		return arov1alpha1.GuardrailsRolloutStatus{
+			Name:      name,
+			Kind:      kind,
+			StartTime: metav1.NewTime(start),
+		}
+	}
+
+	for _, tt := range []struct {
+		name            string
+		flags           arov1alpha1.OperatorFlags
+		existing        []*unstructured.Unstructured
+		rollouts        []arov1alpha1.GuardrailsRolloutStatus
+		wantEnforcement map[string]string
+		wantRollouts    []arov1alpha1.GuardrailsRolloutStatus
+	}{
+		{
+			name: "per-constraint enforcement",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":           "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement":       "deny",
+				"aro.guardrails.policies.aro-machine-config-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machine-config-deny.enforcement": "Warn",
+				"aro.guardrails.policies.aro-pull-secret-deny.managed":        "true",
+				"aro.guardrails.policies.aro-pull-secret-deny.enforcement":    "dryrun",
+			},
+			wantEnforcement: map[string]string{
+				"aro-machines-deny":       "deny",
+				"aro-machine-config-deny": "warn",
+				"aro-pull-secret-deny":    "dryrun",
+			},
+		},
+		{
+			name: "unrecognised enforcement uses dryrun",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement": "block",
+			},
+			wantEnforcement: map[string]string{
+				"aro-machines-deny": "dryrun",
+			},
+		},
+		{
+			name: "policies are deployed in dryrun by default and not rolled out",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed": "true",
+			},
+			existing: []*unstructured.Unstructured{
+				constraint("ARODenyLabels", "aro-machines-deny", now.Add(-30*24*time.Hour)),
+			},
+			wantEnforcement: map[string]string{
+				"aro-machines-deny": "dryrun",
+			},
+		},
+		{
+			name: "rollout",
+			flags: arov1alpha1.OperatorFlags{
+				"aro.guardrails.policies.aro-machines-deny.managed":                  "true",
+				"aro.guardrails.policies.aro-machines-deny.enforcement":              "rollout",
+				"aro.guardrails.policies.aro-machine-config-deny.managed":            "true",
+				"aro.guardrails.policies.aro-machine-config-deny.enforcement":        "rollout",
+				"aro.guardrails.policies.aro-pull-secret-deny.managed":               "true",
+				"aro.guardrails.policies.aro-pull-secret-deny.enforcement":           "rollout",
+				"aro.guardrails.policies.aro-privileged-namespace-deny.managed":      "true",
+				"aro.guardrails.policies.aro-privileged-namespace-deny.enforcement":  "deny",
+				"aro.guardrails.policies.aro-master-toleration-pod-deny.managed":     "false",
+				"aro.guardrails.policies.aro-master-toleration-pod-deny.enforcement": "rollout",
+			},
+			existing: []*unstructured.Unstructured{
+				// soaked from the rollout start, not from when it was created
+				constraint("ARODenyMachineConfig", "aro-machine-config-deny", now.Add(-30*24*time.Hour)),
+				// switched to rollout after a long time in dryrun
+				constraint("ARODenyDeletePullSecret", "aro-pull-secret-deny", now.Add(-30*24*time.Hour)),
+			},
+			rollouts: []arov1alpha1.GuardrailsRolloutStatus{
+				// recreated after its rollout started
+				rollout("ARODenyLabels", "aro-machines-deny", now.Add(-8*24*time.Hour)),
+				rollout("ARODenyMachineConfig", "aro-machine-config-deny", now.Add(-24*time.Hour)),
+				// no longer rolled out
+				rollout("ARODenyPrivilegedNamespace", "aro-privileged-namespace-deny", now.Add(-24*time.Hour)),
+				rollout("ARODenyMasterTolerationTaints", "aro-master-toleration-pod-deny", now.Add(-24*time.Hour)),
+			},
+			wantEnforcement: map[string]string{
+				"aro-machines-deny":             "deny",
+				"aro-machine-config-deny":       "dryrun",
+				"aro-pull-secret-deny":          "dryrun",
+				"aro-privileged-namespace-deny": "deny",
+			},
+			wantRollouts: []arov1alpha1.GuardrailsRolloutStatus{
+				rollout("ARODenyMachineConfig", "aro-machine-config-deny", now.Add(-24*time.Hour)),
+				rollout("ARODenyLabels", "aro-machines-deny", now.Add(-8*24*time.Hour)),
+				rollout("ARODenyDeletePullSecret", "aro-pull-secret-deny", now),
+			},
+		},
+		{
+			name: "rollout with configured soak period",
+			flags: arov1alpha1.OperatorFlags{
+				controllerRolloutSoakHours:                                    "12",
+				"aro.guardrails.policies.aro-machine-config-deny.managed":     "true",
+				"aro.guardrails.policies.aro-machine-config-deny.enforcement": "rollout",
+			},
+			rollouts: []arov1alpha1.GuardrailsRolloutStatus{
+				rollout("ARODenyMachineConfig", "aro-machine-config-deny", now.Add(-24*time.Hour)),
+			},
+			wantEnforcement: map[string]string{
+				"aro-machine-config-deny": "deny",
+			},
+			wantRollouts: []arov1alpha1.GuardrailsRolloutStatus{
+				rollout("ARODenyMachineConfig", "aro-machine-config-deny", now.Add(-24*time.Hour)),
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := gomock.NewController(t)
+			defer controller.Finish()
+
+			cluster := &arov1alpha1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: arov1alpha1.SingletonClusterName,
+				},
+				Spec: arov1alpha1.ClusterSpec{
+					OperatorFlags: tt.flags,
+				},
+			}
+			if tt.rollouts != nil {
+				cluster.Status.Guardrails = &arov1alpha1.GuardrailsStatus{
+					Rollouts: tt.rollouts,
+				}
+			}
+
+			clientBuilder := ctrlfake.NewClientBuilder().WithObjects(cluster)
+			for _, uns := range tt.existing {
+				clientBuilder = clientBuilder.WithObjects(uns)
+			}
+
+			dh := mock_dynamichelper.NewMockInterface(controller)
+			dh.EXPECT().EnsureDeletedGVR(gomock.Any(), gomock.Any(), "", gomock.Any(), "v1beta1").AnyTimes().Return(nil)
+
+			gotEnforcement := map[string]string{}
+			dh.EXPECT().Ensure(gomock.Any(), gomock.Any()).Do(func(ctx context.Context, objs ...kruntime.Object) {
+				for _, o := range objs {
+					uns := o.(*unstructured.Unstructured)
+					gotEnforcement[uns.GetName()], _, _ = unstructured.NestedString(uns.Object, "spec", "enforcementAction")
+				}
+			}).Return(nil)
+
+			r := &Reconciler{
+				log:    logrus.NewEntry(logrus.StandardLogger()),
+				dh:     dh,
+				client: clientBuilder.Build(),
+				now:    func() time.Time { return now },
+			}
+
+			err := r.ensurePolicy(context.Background(), gkPolicyConstraints, gkConstraintsPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(gotEnforcement, tt.wantEnforcement) {
+				t.Errorf("got %v, want %v", gotEnforcement, tt.wantEnforcement)
+			}
+
+			err = r.client.Get(context.Background(), types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, cluster)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var gotRollouts []arov1alpha1.GuardrailsRolloutStatus
+			if cluster.Status.Guardrails != nil {
+				gotRollouts = cluster.Status.Guardrails.Rollouts
+			}
+			for i := range gotRollouts {
+				gotRollouts[i].StartTime = metav1.NewTime(gotRollouts[i].StartTime.UTC())
+			}
+
+			if !reflect.DeepEqual(gotRollouts, tt.wantRollouts) {
+				t.Errorf("got rollouts %v, want %v", gotRollouts, tt.wantRollouts)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"path/filepath"
+	"sort"
+	"text/template"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+	"github.com/Azure/ARO-RP/pkg/operator/controllers/guardrails/config"
+	"github.com/Azure/ARO-RP/pkg/util/dynamichelper"
+)
+
+const (
+	// admissionViolationsWindow is how far back admission events are counted
+	admissionViolationsWindow = time.Hour
+
+	// annotations set by Gatekeeper on the events it emits
+	gkEventProcessAnnotation        = "process"
+	gkEventConstraintKindAnnotation = "constraint_kind"
+	gkEventConstraintNameAnnotation = "constraint_name"
+
+	gkEventProcessAdmission = "admission"
+)
+
+type constraintKey struct {
+	kind string
+	name string
+}
+
+// updateStatus summarises the audit results and admission events reported by
+// Gatekeeper for each deployed guardrails constraint into the cluster status
+func (r *Reconciler) updateStatus(ctx context.Context, fs embed.FS, path string) error {
+	instance := &arov1alpha1.Cluster{}
+	err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		return err
+	}
+
+	constraints, err := r.getConstraintStatuses(ctx, fs, path)
+	if err != nil {
+		return err
+	}
+
+	admissionViolations, err := r.countAdmissionViolations(ctx, instance.Spec.OperatorFlags.GetWithDefault(controllerNamespace, defaultNamespace))
+	if err != nil {
+		return err
+	}
+
+	for i := range constraints {
+		constraints[i].AdmissionViolations = admissionViolations[constraintKey{kind: constraints[i].Kind, name: constraints[i].Name}]
+	}
+
+	return r.setStatus(ctx, &arov1alpha1.GuardrailsStatus{
+		LastUpdateTime: metav1.NewTime(r.now()),
+		Constraints:    constraints,
+	})
+}
+
+func (r *Reconciler) setStatus(ctx context.Context, status *arov1alpha1.GuardrailsStatus) error {
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		instance := &arov1alpha1.Cluster{}
+		err := r.client.Get(ctx, types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+		if err != nil {
+			return err
+		}
+
+		if instance.Status.Guardrails == nil && status == nil {
+			return nil
+		}
+
+		// the rollouts are recorded by ensurePolicy, keep them
+		if status != nil && instance.Status.Guardrails != nil {
+			status.Rollouts = instance.Status.Guardrails.Rollouts
+		}
+
+		instance.Status.Guardrails = status
+		return r.client.Status().Update(ctx, instance)
+	})
+}
+
+// getConstraintStatuses returns the audit results of each of the guardrails
+// constraints which is deployed
+func (r *Reconciler) getConstraintStatuses(ctx context.Context, fs embed.FS, path string) ([]arov1alpha1.GuardrailsConstraintStatus, error) {
+	template, err := template.ParseFS(fs, filepath.Join(path, "*"))
+	if err != nil {
+		return nil, err
+	}
+
+	constraints := []arov1alpha1.GuardrailsConstraintStatus{}
+	for _, templ := range template.Templates() {
+		buffer := &bytes.Buffer{}
+		err = templ.Execute(buffer, &config.GuardRailsPolicyConfig{})
+		if err != nil {
+			return nil, err
+		}
+
+		uns, err := dynamichelper.DecodeUnstructured(buffer.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		constraint := &unstructured.Unstructured{}
+		constraint.SetGroupVersionKind(uns.GroupVersionKind())
+
+		err = r.client.Get(ctx, client.ObjectKeyFromObject(uns), constraint)
+		if kerrors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		enforcementAction, _, _ := unstructured.NestedString(constraint.Object, "spec", "enforcementAction")
+		auditTimestamp, _, _ := unstructured.NestedString(constraint.Object, "status", "auditTimestamp")
+		totalViolations, _, _ := unstructured.NestedInt64(constraint.Object, "status", "totalViolations")
+
+		constraints = append(constraints, arov1alpha1.GuardrailsConstraintStatus{
+			Name:              constraint.GetName(),
+			Kind:              constraint.GetKind(),
+			EnforcementAction: enforcementAction,
+			AuditTimestamp:    auditTimestamp,
+			AuditViolations:   int(totalViolations),
+		})
+	}
+
+	sort.Slice(constraints, func(i, j int) bool { return constraints[i].Name < constraints[j].Name })
+
+	return constraints, nil
+}
+
+// countAdmissionViolations counts the admission events emitted by Gatekeeper
+// in the last admissionViolationsWindow, by constraint.  Events are listed
+// directly from the API server rather than through the cache, so that the
+// operator doesn't watch every event in the cluster.
+func (r *Reconciler) countAdmissionViolations(ctx context.Context, namespace string) (map[constraintKey]int, error) {
+	events, err := r.kubernetescli.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{
+		// Gatekeeper emits all its admission events as warnings
+		FieldSelector: fields.OneTermEqualSelector("type", corev1.EventTypeWarning).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	since := r.now().Add(-admissionViolationsWindow)
+
+	counts := map[constraintKey]int{}
+	for i := range events.Items {
+		event := &events.Items[i]
+		if event.Annotations[gkEventProcessAnnotation] != gkEventProcessAdmission {
+			continue
+		}
+
+		count := occurrencesSince(event, since)
+		if count == 0 {
+			continue
+		}
+
+		counts[constraintKey{
+			kind: event.Annotations[gkEventConstraintKindAnnotation],
+			name: event.Annotations[gkEventConstraintNameAnnotation],
+		}] += count
+	}
+
+	return counts, nil
+}
+
+// occurrencesSince returns how many of the occurrences aggregated into event
+// are known to have happened since the given time.  The event only records
+// when its first and last occurrences happened, so if the first is before
+// since only the last is counted.
+func occurrencesSince(event *corev1.Event, since time.Time) int {
+	first, last, count := event.FirstTimestamp.Time, event.LastTimestamp.Time, int(event.Count)
+	if event.Series != nil {
+		first, last, count = event.EventTime.Time, event.Series.LastObservedTime.Time, int(event.Series.Count)
+	}
+	if first.IsZero() {
+		first = event.EventTime.Time
+	}
+	if last.IsZero() {
+		last = first
+	}
+
+	switch {
+	case last.Before(since):
+		return 0
+	case first.Before(since) || count == 0:
+		return 1
+	default:
+		return count
+	}
+}
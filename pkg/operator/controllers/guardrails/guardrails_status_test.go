@@ -0,0 +1,158 @@
+package guardrails
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	ctrlfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arov1alpha1 "github.com/Azure/ARO-RP/pkg/operator/apis/aro.openshift.io/v1alpha1"
+)
+
+func TestUpdateStatus(t *testing.T) {
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	constraint := func(kind, name, enforcementAction string, totalViolations int64) *unstructured.Unstructured {
+		return &unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "constraints.gatekeeper.sh/v1beta1",
+				"kind":       kind,
+				"metadata": map[string]interface{}{
+					"name": name,
+				},
+				"spec": map[string]interface{}{
+					"enforcementAction": enforcementAction,
+				},
+				"status": map[string]interface{}{
+					"auditTimestamp":  "2023-06-01T11:55:00Z",
+					"totalViolations": totalViolations,
+				},
+			},
+		}
+	}
+
+	event := func(name, process, kind, constraintName string, count int32, first, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: defaultNamespace,
+				Annotations: map[string]string{
+					gkEventProcessAnnotation:        process,
+					gkEventConstraintKindAnnotation: kind,
+					gkEventConstraintNameAnnotation: constraintName,
+				},
+			},
+			Type:           corev1.EventTypeWarning,
+			Count:          count,
+			FirstTimestamp: metav1.NewTime(first),
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+
+	cluster := &arov1alpha1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: arov1alpha1.SingletonClusterName,
+		},
+		Status: arov1alpha1.ClusterStatus{
+			Guardrails: &arov1alpha1.GuardrailsStatus{
+				Rollouts: []arov1alpha1.GuardrailsRolloutStatus{
+					{
+						Name: "aro-machine-config-deny",
+						Kind: "ARODenyMachineConfig",
+					},
+				},
+			},
+		},
+	}
+
+	client := ctrlfake.NewClientBuilder().
+		WithObjects(
+			cluster,
+			constraint("ARODenyLabels", "aro-machines-deny", "deny", 2),
+			constraint("ARODenyMachineConfig", "aro-machine-config-deny", "dryrun", 0),
+		).
+		Build()
+
+	kubernetescli := fake.NewSimpleClientset(
+		event("recent", gkEventProcessAdmission, "ARODenyLabels", "aro-machines-deny", 3, now.Add(-30*time.Minute), now.Add(-10*time.Minute)),
+		event("recent-no-count", gkEventProcessAdmission, "ARODenyLabels", "aro-machines-deny", 0, time.Time{}, now.Add(-20*time.Minute)),
+		event("straddling", gkEventProcessAdmission, "ARODenyLabels", "aro-machines-deny", 50, now.Add(-3*time.Hour), now.Add(-5*time.Minute)),
+		event("old", gkEventProcessAdmission, "ARODenyLabels", "aro-machines-deny", 5, now.Add(-3*time.Hour), now.Add(-2*time.Hour)),
+		event("audit", "audit", "ARODenyLabels", "aro-machines-deny", 1, now.Add(-time.Minute), now.Add(-time.Minute)),
+	)
+
+	r := &Reconciler{
+		log:           logrus.NewEntry(logrus.StandardLogger()),
+		client:        client,
+		kubernetescli: kubernetescli,
+		now:           func() time.Time { return now },
+	}
+
+	err := r.updateStatus(context.Background(), gkPolicyConstraints, gkConstraintsPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance := getCluster(t, client)
+
+	want := &arov1alpha1.GuardrailsStatus{
+		LastUpdateTime: metav1.NewTime(now),
+		Constraints: []arov1alpha1.GuardrailsConstraintStatus{
+			{
+				Name:              "aro-machine-config-deny",
+				Kind:              "ARODenyMachineConfig",
+				EnforcementAction: "dryrun",
+				AuditTimestamp:    "2023-06-01T11:55:00Z",
+			},
+			{
+				Name:                "aro-machines-deny",
+				Kind:                "ARODenyLabels",
+				EnforcementAction:   "deny",
+				AuditTimestamp:      "2023-06-01T11:55:00Z",
+				AuditViolations:     2,
+				AdmissionViolations: 5,
+			},
+		},
+	}
+
+	if !instance.Status.Guardrails.LastUpdateTime.Equal(&want.LastUpdateTime) {
+		t.Errorf("got lastUpdateTime %s", instance.Status.Guardrails.LastUpdateTime)
+	}
+	if !reflect.DeepEqual(instance.Status.Guardrails.Constraints, want.Constraints) {
+		t.Errorf("got %#v", instance.Status.Guardrails.Constraints)
+	}
+	if len(instance.Status.Guardrails.Rollouts) != 1 {
+		t.Errorf("got rollouts %#v, wanted them kept", instance.Status.Guardrails.Rollouts)
+	}
+
+	err = r.setStatus(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	instance = getCluster(t, client)
+	if instance.Status.Guardrails != nil {
+		t.Errorf("got %#v, wanted nil", instance.Status.Guardrails)
+	}
+}
+
+func getCluster(t *testing.T, c client.Client) *arov1alpha1.Cluster {
+	instance := &arov1alpha1.Cluster{}
+	err := c.Get(context.Background(), types.NamespacedName{Name: arov1alpha1.SingletonClusterName}, instance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return instance
+}
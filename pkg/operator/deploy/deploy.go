@@ -49,6 +49,10 @@ import (
 //go:embed staticresources
 var embeddedFiles embed.FS
 
+// internetCheckerLatencySLO is the latency above which a check of an
+// internet checker target counts as an SLO breach
+const internetCheckerLatencySLO = 2 * time.Second
+
 type Operator interface {
 	CreateOrUpdate(context.Context) error
 	CreateOrUpdateCredentialsRequest(context.Context) error
@@ -253,21 +257,15 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 				MonitoringGCSEnvironment: o.env.ClusterGenevaLoggingEnvironment(),
 				MonitoringGCSNamespace:   o.env.ClusterGenevaLoggingNamespace(),
 			},
-			ServiceSubnets: serviceSubnets,
-			InternetChecker: arov1alpha1.InternetCheckerSpec{
-				URLs: []string{
-					fmt.Sprintf("https://%s/", o.env.ACRDomain()),
-					o.env.Environment().ActiveDirectoryEndpoint,
-					o.env.Environment().ResourceManagerEndpoint,
-					o.env.Environment().GenevaMonitoringEndpoint,
-				},
-			},
+			ServiceSubnets:  serviceSubnets,
+			InternetChecker: o.internetCheckerSpec(),
 
 			APIIntIP:                 o.oc.Properties.APIServerProfile.IntIP,
 			IngressIP:                ingressIP,
 			GatewayPrivateEndpointIP: o.oc.Properties.NetworkProfile.GatewayPrivateEndpointIP,
 			// Update the OperatorFlags from the version in the RP
-			OperatorFlags: arov1alpha1.OperatorFlags(o.oc.Properties.OperatorFlags),
+			OperatorFlags:  arov1alpha1.OperatorFlags(o.oc.Properties.OperatorFlags),
+			ConsoleBanners: consoleBanners(o.oc.Properties.ConsoleBanners),
 		},
 	}
 
@@ -296,6 +294,69 @@ func (o *operator) resources(ctx context.Context) ([]kruntime.Object, error) {
 	), nil
 }
 
+// internetCheckerSpec returns the endpoints which the cluster must be able to
+// reach.  URLs is kept populated for operators which predate Targets.
+func (o *operator) internetCheckerSpec() arov1alpha1.InternetCheckerSpec {
+	targets := []struct {
+		name string
+		url  string
+	}{
+		{name: "acr", url: fmt.Sprintf("https://%s/", o.env.ACRDomain())},
+		{name: "aad", url: o.env.Environment().ActiveDirectoryEndpoint},
+		{name: "arm", url: o.env.Environment().ResourceManagerEndpoint},
+		{name: "geneva", url: o.env.Environment().GenevaMonitoringEndpoint},
+	}
+
+	spec := arov1alpha1.InternetCheckerSpec{}
+	for _, target := range targets {
+		spec.URLs = append(spec.URLs, target.url)
+		spec.Targets = append(spec.Targets, arov1alpha1.InternetCheckerTarget{
+			Name:       target.name,
+			Mode:       arov1alpha1.InternetCheckerModeHTTP,
+			URL:        target.url,
+			LatencySLO: &metav1.Duration{Duration: internetCheckerLatencySLO},
+		})
+	}
+
+	return spec
+}
+
+// consoleBanners converts the console banners set on the cluster document
+// for the operator to publish
+func consoleBanners(banners []api.ConsoleBanner) []arov1alpha1.ConsoleBanner {
+	if banners == nil {
+		return nil
+	}
+
+	out := make([]arov1alpha1.ConsoleBanner, 0, len(banners))
+	for _, b := range banners {
+		banner := arov1alpha1.ConsoleBanner{
+			Name:       b.Name,
+			Severity:   arov1alpha1.ConsoleBannerSeverity(b.Severity),
+			MessageID:  b.MessageID,
+			Language:   b.Language,
+			Parameters: b.Parameters,
+		}
+		if b.Link != nil {
+			banner.Link = &arov1alpha1.ConsoleBannerLink{
+				Href: b.Link.Href,
+				Text: b.Link.Text,
+			}
+		}
+		if b.StartTime != nil {
+			t := metav1.NewTime(*b.StartTime)
+			banner.StartTime = &t
+		}
+		if b.ExpiryTime != nil {
+			t := metav1.NewTime(*b.ExpiryTime)
+			banner.ExpiryTime = &t
+		}
+		out = append(out, banner)
+	}
+
+	return out
+}
+
 func (o *operator) CreateOrUpdate(ctx context.Context) error {
 	resources, err := o.resources(ctx)
 	if err != nil {
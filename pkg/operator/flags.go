@@ -10,6 +10,7 @@ const (
 	AzureSubnetsServiceEndpointManaged = "aro.azuresubnets.serviceendpoint.managed"
 	BannerEnabled                      = "aro.banner.enabled"
 	CheckerEnabled                     = "aro.checker.enabled"
+	DiagnosticsEnabled                 = "aro.diagnostics.enabled"
 	DnsmasqEnabled                     = "aro.dnsmasq.enabled"
 	RestartDnsmasqEnabled              = "aro.restartdnsmasq.enabled"
 	GenevaLoggingEnabled               = "aro.genevalogging.enabled"
@@ -47,6 +48,7 @@ func DefaultOperatorFlags() map[string]string {
 		AzureSubnetsServiceEndpointManaged: FlagTrue,
 		BannerEnabled:                      FlagFalse,
 		CheckerEnabled:                     FlagTrue,
+		DiagnosticsEnabled:                 FlagTrue,
 		DnsmasqEnabled:                     FlagTrue,
 		RestartDnsmasqEnabled:              FlagFalse,
 		GenevaLoggingEnabled:               FlagTrue,
@@ -0,0 +1,288 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/api/validate"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+)
+
+const (
+	// maxAccessGrantDuration bounds the window of a single access grant
+	maxAccessGrantDuration = 8 * time.Hour
+
+	// maxAccessGrantLeadTime bounds how far in advance access can be
+	// requested
+	maxAccessGrantLeadTime = 7 * 24 * time.Hour
+
+	// accessGrantRetention is how long grants are kept after their window
+	// ends, so that past access can be listed
+	accessGrantRetention = 90 * 24 * time.Hour
+
+	maxJustificationLength = 1024
+)
+
+type AccessGrant struct {
+	ID            string `json:"id"`
+	Username      string `json:"username"`
+	ResourceID    string `json:"resourceId"`
+	Justification string `json:"justification"`
+	NotBefore     string `json:"notBefore"`
+	NotAfter      string `json:"notAfter"`
+	State         string `json:"state"`
+	Active        bool   `json:"active"`
+	RequestedAt   string `json:"requestedAt"`
+	Reviewer      string `json:"reviewer,omitempty"`
+	ReviewedAt    string `json:"reviewedAt,omitempty"`
+}
+
+type accessGrantRequest struct {
+	Justification string    `json:"justification"`
+	NotBefore     time.Time `json:"notBefore"`
+	NotAfter      time.Time `json:"notAfter"`
+}
+
+func newAccessGrant(doc *api.PortalAccessGrantDocument, now time.Time) *AccessGrant {
+	g := doc.PortalAccessGrant
+
+	grant := &AccessGrant{
+		ID:            doc.ID,
+		Username:      g.Username,
+		ResourceID:    g.ResourceID,
+		Justification: g.Justification,
+		NotBefore:     g.NotBefore.Format(time.RFC3339),
+		NotAfter:      g.NotAfter.Format(time.RFC3339),
+		State:         string(g.State),
+		Active:        g.IsActive(now),
+		RequestedAt:   g.RequestedAt.Format(time.RFC3339),
+		Reviewer:      g.Reviewer,
+	}
+
+	if !g.ReviewedAt.IsZero() {
+		grant.ReviewedAt = g.ReviewedAt.Format(time.RFC3339)
+	}
+
+	return grant
+}
+
+func (p *portal) isElevated(r *http.Request) bool {
+	return len(middleware.GroupsIntersect(p.elevatedGroupIDs, r.Context().Value(middleware.ContextKeyGroups).([]string))) > 0
+}
+
+// listAccessGrants returns access grants, most recently requested first,
+// optionally filtered by state and cluster
+func (p *portal) listAccessGrants(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	state := api.PortalAccessGrantState(q.Get("state"))
+	resourceID := strings.ToLower(q.Get("resourceId"))
+
+	docs, err := p.dbPortalAccessGrants.ListAll(ctx)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	now := time.Now()
+
+	grants := []*AccessGrant{}
+	for _, doc := range docs.PortalAccessGrantDocuments {
+		if state != "" && !strings.EqualFold(string(doc.PortalAccessGrant.State), string(state)) {
+			continue
+		}
+		if resourceID != "" && doc.PortalAccessGrant.ResourceID != resourceID {
+			continue
+		}
+
+		grants = append(grants, newAccessGrant(doc, now))
+	}
+
+	sort.SliceStable(grants, func(i, j int) bool { return grants[i].RequestedAt > grants[j].RequestedAt })
+
+	b, err := json.MarshalIndent(grants, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+// requestAccessGrant records a pending request by the caller for elevated
+// access to a cluster.  The window starts now unless notBefore is given.
+func (p *portal) requestAccessGrant(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	apiVars := mux.Vars(r)
+	resourceID := p.getResourceID(apiVars["subscription"], apiVars["resourceGroup"], apiVars["clusterName"])
+	if !validate.RxClusterID.MatchString(resourceID) {
+		http.Error(w, fmt.Sprintf("invalid resourceId %q", resourceID), http.StatusBadRequest)
+		return
+	}
+
+	mediatype, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediatype != "application/json" {
+		http.Error(w, http.StatusText(http.StatusUnsupportedMediaType), http.StatusUnsupportedMediaType)
+		return
+	}
+
+	if !p.isElevated(r) {
+		http.Error(w, "Elevated access is required.", http.StatusForbidden)
+		return
+	}
+
+	var req *accessGrantRequest
+	err := json.NewDecoder(r.Body).Decode(&req)
+	if err != nil || req == nil {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	if req.NotBefore.IsZero() {
+		req.NotBefore = now
+	}
+
+	err = validateAccessGrantRequest(req, now)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	_, err = p.dbOpenShiftClusters.Get(ctx, resourceID)
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		http.Error(w, "Cluster not found", http.StatusNotFound)
+		return
+	} else if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	doc := &api.PortalAccessGrantDocument{
+		ID:  p.dbPortalAccessGrants.NewUUID(),
+		TTL: int((req.NotAfter.Sub(now) + accessGrantRetention) / time.Second),
+		PortalAccessGrant: &api.PortalAccessGrant{
+			Username:      ctx.Value(middleware.ContextKeyUsername).(string),
+			ResourceID:    resourceID,
+			Justification: req.Justification,
+			NotBefore:     req.NotBefore.UTC(),
+			NotAfter:      req.NotAfter.UTC(),
+			State:         api.PortalAccessGrantStatePending,
+			RequestedAt:   now.UTC(),
+		},
+	}
+
+	doc, err = p.dbPortalAccessGrants.Create(ctx, doc)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	p.log.WithField("accessGrant", doc.ID).Infof("%s requested access to %s", doc.PortalAccessGrant.Username, resourceID)
+
+	p.writeAccessGrant(w, http.StatusCreated, newAccessGrant(doc, now))
+}
+
+func validateAccessGrantRequest(req *accessGrantRequest, now time.Time) error {
+	switch {
+	case strings.TrimSpace(req.Justification) == "":
+		return fmt.Errorf("a justification is required")
+	case len(req.Justification) > maxJustificationLength:
+		return fmt.Errorf("the justification must be at most %d characters", maxJustificationLength)
+	case !req.NotAfter.After(req.NotBefore):
+		return fmt.Errorf("notAfter must be after notBefore")
+	case !req.NotAfter.After(now):
+		return fmt.Errorf("notAfter must be in the future")
+	case req.NotAfter.Sub(req.NotBefore) > maxAccessGrantDuration:
+		return fmt.Errorf("access can be granted for at most %s", maxAccessGrantDuration)
+	case req.NotBefore.Sub(now) > maxAccessGrantLeadTime:
+		return fmt.Errorf("access can be requested at most %s in advance", maxAccessGrantLeadTime)
+	}
+
+	return nil
+}
+
+// reviewAccessGrant returns a handler which moves a pending access grant to
+// state.  The reviewer must have elevated access and must not be the
+// requester.
+func (p *portal) reviewAccessGrant(state api.PortalAccessGrantState) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx := r.Context()
+		id := mux.Vars(r)["id"]
+
+		if !p.isElevated(r) {
+			http.Error(w, "Elevated access is required.", http.StatusForbidden)
+			return
+		}
+
+		reviewer := ctx.Value(middleware.ContextKeyUsername).(string)
+		now := time.Now()
+
+		var statusCode int
+		var message string
+
+		doc, err := p.dbPortalAccessGrants.Patch(ctx, id, func(doc *api.PortalAccessGrantDocument) error {
+			g := doc.PortalAccessGrant
+
+			switch {
+			case strings.EqualFold(g.Username, reviewer):
+				statusCode, message = http.StatusForbidden, "Access grants must be reviewed by someone other than the requester."
+			case g.State != api.PortalAccessGrantStatePending:
+				statusCode, message = http.StatusConflict, fmt.Sprintf("The access grant is already %s.", strings.ToLower(string(g.State)))
+			case !now.Before(g.NotAfter):
+				statusCode, message = http.StatusConflict, "The access grant has expired."
+			default:
+				g.State = state
+				g.Reviewer = reviewer
+				g.ReviewedAt = now.UTC()
+				return nil
+			}
+
+			return errAccessGrantNotReviewable
+		})
+		switch {
+		case err == errAccessGrantNotReviewable:
+			http.Error(w, message, statusCode)
+			return
+		case cosmosdb.IsErrorStatusCode(err, http.StatusNotFound):
+			http.Error(w, "Access grant not found", http.StatusNotFound)
+			return
+		case err != nil:
+			p.internalServerError(w, err)
+			return
+		}
+
+		p.log.WithField("accessGrant", doc.ID).Infof("%s %s access by %s to %s", reviewer, strings.ToLower(string(state)), doc.PortalAccessGrant.Username, doc.PortalAccessGrant.ResourceID)
+
+		p.writeAccessGrant(w, http.StatusOK, newAccessGrant(doc, now))
+	}
+}
+
+var errAccessGrantNotReviewable = fmt.Errorf("access grant is not reviewable")
+
+func (p *portal) writeAccessGrant(w http.ResponseWriter, statusCode int, grant *AccessGrant) {
+	b, err := json.MarshalIndent(grant, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_, _ = w.Write(b)
+}
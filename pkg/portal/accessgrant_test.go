@@ -0,0 +1,245 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestAccessGrants(t *testing.T) {
+	resourceID := "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster"
+	elevatedGroupIDs := []string{"10000000-0000-0000-0000-000000000000"}
+	now := time.Now()
+
+	pending := func(username string, notAfter time.Time) *api.PortalAccessGrantDocument {
+		return &api.PortalAccessGrantDocument{
+			PortalAccessGrant: &api.PortalAccessGrant{
+				Username:      username,
+				ResourceID:    resourceID,
+				Justification: "incident",
+				NotBefore:     now.Add(-time.Hour),
+				NotAfter:      notAfter,
+				State:         api.PortalAccessGrantStatePending,
+				RequestedAt:   now.Add(-time.Hour),
+			},
+		}
+	}
+
+	for _, tt := range []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		username       string
+		notElevated    bool
+		accessGrants   []*api.PortalAccessGrantDocument
+		wantStatusCode int
+		wantState      api.PortalAccessGrantState
+		wantReviewer   string
+	}{
+		{
+			name:           "request",
+			method:         http.MethodPost,
+			path:           "/api/00000000-0000-0000-0000-000000000000/rg/cluster/accessgrants",
+			body:           `{"justification":"incident","notAfter":"` + now.Add(time.Hour).Format(time.RFC3339) + `"}`,
+			username:       "alice@example.com",
+			wantStatusCode: http.StatusCreated,
+			wantState:      api.PortalAccessGrantStatePending,
+		},
+		{
+			name:           "request without justification",
+			method:         http.MethodPost,
+			path:           "/api/00000000-0000-0000-0000-000000000000/rg/cluster/accessgrants",
+			body:           `{"notAfter":"` + now.Add(time.Hour).Format(time.RFC3339) + `"}`,
+			username:       "alice@example.com",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "request too long",
+			method:         http.MethodPost,
+			path:           "/api/00000000-0000-0000-0000-000000000000/rg/cluster/accessgrants",
+			body:           `{"justification":"incident","notAfter":"` + now.Add(9*time.Hour).Format(time.RFC3339) + `"}`,
+			username:       "alice@example.com",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "request for missing cluster",
+			method:         http.MethodPost,
+			path:           "/api/00000000-0000-0000-0000-000000000000/rg/missing/accessgrants",
+			body:           `{"justification":"incident","notAfter":"` + now.Add(time.Hour).Format(time.RFC3339) + `"}`,
+			username:       "alice@example.com",
+			wantStatusCode: http.StatusNotFound,
+		},
+		{
+			name:           "request not elevated",
+			method:         http.MethodPost,
+			path:           "/api/00000000-0000-0000-0000-000000000000/rg/cluster/accessgrants",
+			body:           `{"justification":"incident","notAfter":"` + now.Add(time.Hour).Format(time.RFC3339) + `"}`,
+			username:       "alice@example.com",
+			notElevated:    true,
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "approve",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080001/approve",
+			username:       "bob@example.com",
+			accessGrants:   []*api.PortalAccessGrantDocument{pending("alice@example.com", now.Add(time.Hour))},
+			wantStatusCode: http.StatusOK,
+			wantState:      api.PortalAccessGrantStateApproved,
+			wantReviewer:   "bob@example.com",
+		},
+		{
+			name:           "reject",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080001/reject",
+			username:       "bob@example.com",
+			accessGrants:   []*api.PortalAccessGrantDocument{pending("alice@example.com", now.Add(time.Hour))},
+			wantStatusCode: http.StatusOK,
+			wantState:      api.PortalAccessGrantStateRejected,
+			wantReviewer:   "bob@example.com",
+		},
+		{
+			name:           "approve own request",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080001/approve",
+			username:       "Alice@example.com",
+			accessGrants:   []*api.PortalAccessGrantDocument{pending("alice@example.com", now.Add(time.Hour))},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "approve not elevated",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080001/approve",
+			username:       "bob@example.com",
+			notElevated:    true,
+			accessGrants:   []*api.PortalAccessGrantDocument{pending("alice@example.com", now.Add(time.Hour))},
+			wantStatusCode: http.StatusForbidden,
+		},
+		{
+			name:           "approve expired",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080001/approve",
+			username:       "bob@example.com",
+			accessGrants:   []*api.PortalAccessGrantDocument{pending("alice@example.com", now.Add(-time.Minute))},
+			wantStatusCode: http.StatusConflict,
+		},
+		{
+			name:           "approve missing",
+			method:         http.MethodPost,
+			path:           "/api/accessgrants/08080808-0808-0808-0808-080808080002/approve",
+			username:       "bob@example.com",
+			wantStatusCode: http.StatusNotFound,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+			dbPortalAccessGrants, _ := testdatabase.NewFakePortalAccessGrants()
+
+			fixture := testdatabase.NewFixture().
+				WithOpenShiftClusters(dbOpenShiftClusters).
+				WithPortalAccessGrants(dbPortalAccessGrants)
+
+			fixture.AddOpenShiftClusterDocuments(&api.OpenShiftClusterDocument{
+				Key: resourceID,
+				OpenShiftCluster: &api.OpenShiftCluster{
+					ID: resourceID,
+				},
+			})
+			fixture.AddPortalAccessGrantDocuments(tt.accessGrants...)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, log := testlog.New()
+
+			p := &portal{
+				log:                  log,
+				elevatedGroupIDs:     elevatedGroupIDs,
+				dbOpenShiftClusters:  dbOpenShiftClusters,
+				dbPortalAccessGrants: dbPortalAccessGrants,
+			}
+
+			groups := elevatedGroupIDs
+			if tt.notElevated {
+				groups = []string{}
+			}
+
+			ctx := context.WithValue(context.Background(), middleware.ContextKeyUsername, tt.username)
+			ctx = context.WithValue(ctx, middleware.ContextKeyGroups, groups)
+
+			req, err := http.NewRequestWithContext(ctx, tt.method, tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			aadAuthenticatedRouter := mux.NewRouter()
+			p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+			w := httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("got %d, want %d: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if tt.wantState == "" {
+				return
+			}
+
+			var grant AccessGrant
+			err = json.NewDecoder(w.Body).Decode(&grant)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if grant.State != string(tt.wantState) {
+				t.Errorf("got state %s, want %s", grant.State, tt.wantState)
+			}
+			if grant.Reviewer != tt.wantReviewer {
+				t.Errorf("got reviewer %q, want %q", grant.Reviewer, tt.wantReviewer)
+			}
+			if grant.ResourceID != resourceID {
+				t.Errorf("got resourceId %q", grant.ResourceID)
+			}
+			if grant.Active != (tt.wantState == api.PortalAccessGrantStateApproved) {
+				t.Errorf("got active %v", grant.Active)
+			}
+
+			// the grant is listed
+			req, err = http.NewRequestWithContext(ctx, http.MethodGet, "/api/accessgrants?state="+string(tt.wantState), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			w = httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			var grants []*AccessGrant
+			err = json.NewDecoder(w.Body).Decode(&grants)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(grants, []*AccessGrant{&grant}) {
+				t.Errorf("got %#v", grants)
+			}
+		})
+	}
+}
@@ -13,6 +13,8 @@ import (
 	"github.com/Azure/go-autorest/autorest/azure"
 	"github.com/gorilla/mux"
 
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/portal/cluster"
 	"github.com/Azure/ARO-RP/pkg/portal/prometheus"
 )
@@ -25,6 +27,7 @@ type AdminOpenShiftCluster struct {
 	ResourceId              string `json:"resourceId"`
 	ProvisioningState       string `json:"provisioningState"`
 	FailedProvisioningState string `json:"failedprovisioningState"`
+	MaintenanceState        string `json:"maintenanceState"`
 	Version                 string `json:"version"`
 	CreatedAt               string `json:"createdAt"`
 	LastModified            string `json:"lastModified"`
@@ -34,12 +37,39 @@ type AdminOpenShiftCluster struct {
 func (p *portal) clusters(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	docs, err := p.dbOpenShiftClusters.ListAll(ctx)
+	i, err := p.dbOpenShiftClusters.Search(&database.OpenShiftClusterSearch{}, "")
 	if err != nil {
 		p.internalServerError(w, err)
 		return
 	}
 
+	clusters := []*AdminOpenShiftCluster{}
+	for {
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			p.internalServerError(w, err)
+			return
+		}
+		if docs == nil {
+			break
+		}
+
+		clusters = append(clusters, adminOpenShiftClusters(docs)...)
+	}
+
+	sort.SliceStable(clusters, func(i, j int) bool { return strings.Compare(clusters[i].Key, clusters[j].Key) < 0 })
+
+	b, err := json.MarshalIndent(clusters, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+func adminOpenShiftClusters(docs *api.OpenShiftClusterDocuments) []*AdminOpenShiftCluster {
 	clusters := make([]*AdminOpenShiftCluster, 0, len(docs.OpenShiftClusterDocuments))
 	for _, doc := range docs.OpenShiftClusterDocuments {
 		if doc.OpenShiftCluster == nil {
@@ -80,19 +110,11 @@ func (p *portal) clusters(w http.ResponseWriter, r *http.Request) {
 			ProvisionedBy:           doc.OpenShiftCluster.Properties.ProvisionedBy,
 			ProvisioningState:       ps.String(),
 			FailedProvisioningState: fps.String(),
+			MaintenanceState:        string(doc.OpenShiftCluster.Properties.MaintenanceState),
 		})
 	}
 
-	sort.SliceStable(clusters, func(i, j int) bool { return strings.Compare(clusters[i].Key, clusters[j].Key) < 0 })
-
-	b, err := json.MarshalIndent(clusters, "", "    ")
-	if err != nil {
-		p.internalServerError(w, err)
-		return
-	}
-
-	w.Header().Set("Content-Type", "application/json")
-	_, _ = w.Write(b)
+	return clusters
 }
 
 func (p *portal) clusterOperators(w http.ResponseWriter, r *http.Request) {
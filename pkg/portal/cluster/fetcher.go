@@ -26,6 +26,13 @@ type FetchClient interface {
 	Machines(context.Context) (*MachineListInformation, error)
 	MachineSets(context.Context) (*MachineSetListInformation, error)
 	Statistics(context.Context, *http.Client, string, time.Duration, time.Time, string) ([]Metrics, error)
+
+	// The Watch and PodLogs methods stream to send until the context is done
+	// or the cluster closes the stream
+	WatchNodes(context.Context, func(*WatchEvent) error) error
+	WatchMachines(context.Context, func(*WatchEvent) error) error
+	WatchEvents(context.Context, string, func(*WatchEvent) error) error
+	PodLogs(context.Context, string, string, *PodLogOptions, func(string) error) error
 }
 
 // client is an implementation of FetchClient. It currently contains a "fetcher"
@@ -0,0 +1,169 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bufio"
+	"context"
+
+	machinev1beta1 "github.com/openshift/api/machine/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// maxLogLineSize is the longest pod log line which will be streamed
+const maxLogLineSize = 1024 * 1024
+
+// WatchEvent is a change to a watched object.  Type is ADDED, MODIFIED or
+// DELETED, and Object is the frontend-suitable representation of the object.
+type WatchEvent struct {
+	Type   string      `json:"type"`
+	Object interface{} `json:"object"`
+}
+
+type EventInformation struct {
+	Namespace      string `json:"namespace"`
+	Name           string `json:"name"`
+	Type           string `json:"type"`
+	Reason         string `json:"reason"`
+	Message        string `json:"message"`
+	Object         string `json:"object"`
+	Count          int32  `json:"count"`
+	FirstTimestamp string `json:"firstTimestamp"`
+	LastTimestamp  string `json:"lastTimestamp"`
+}
+
+// PodLogOptions selects the pod log lines to stream
+type PodLogOptions struct {
+	Container string
+	TailLines *int64
+}
+
+func EventFromEvent(event *corev1.Event) *EventInformation {
+	return &EventInformation{
+		Namespace:      event.Namespace,
+		Name:           event.Name,
+		Type:           event.Type,
+		Reason:         event.Reason,
+		Message:        event.Message,
+		Object:         event.InvolvedObject.Kind + "/" + event.InvolvedObject.Name,
+		Count:          event.Count,
+		FirstTimestamp: event.FirstTimestamp.String(),
+		LastTimestamp:  event.LastTimestamp.String(),
+	}
+}
+
+// stream calls send for each event on w until ctx is done or the watch is
+// closed by the server
+func (f *realFetcher) stream(ctx context.Context, w watch.Interface, send func(*WatchEvent) error) error {
+	defer w.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case event, ok := <-w.ResultChan():
+			if !ok {
+				return nil
+			}
+
+			var object interface{}
+			switch o := event.Object.(type) {
+			case *metav1.Status:
+				if event.Type == watch.Error {
+					return kerrors.FromObject(o)
+				}
+				continue
+			case *corev1.Node:
+				object = NodesFromNodeList(&corev1.NodeList{Items: []corev1.Node{*o}}).Nodes[0]
+			case *machinev1beta1.Machine:
+				object = MachinesFromMachineList(&machinev1beta1.MachineList{Items: []machinev1beta1.Machine{*o}}).Machines[0]
+			case *corev1.Event:
+				object = EventFromEvent(o)
+			default:
+				continue
+			}
+
+			err := send(&WatchEvent{
+				Type:   string(event.Type),
+				Object: object,
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (f *realFetcher) WatchNodes(ctx context.Context, send func(*WatchEvent) error) error {
+	w, err := f.kubernetesCli.CoreV1().Nodes().Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	return f.stream(ctx, w, send)
+}
+
+func (f *realFetcher) WatchMachines(ctx context.Context, send func(*WatchEvent) error) error {
+	w, err := f.machineClient.MachineV1beta1().Machines("").Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	return f.stream(ctx, w, send)
+}
+
+func (f *realFetcher) WatchEvents(ctx context.Context, namespace string, send func(*WatchEvent) error) error {
+	w, err := f.kubernetesCli.CoreV1().Events(namespace).Watch(ctx, metav1.ListOptions{})
+	if err != nil {
+		return err
+	}
+
+	return f.stream(ctx, w, send)
+}
+
+func (f *realFetcher) PodLogs(ctx context.Context, namespace, name string, options *PodLogOptions, send func(string) error) error {
+	rc, err := f.kubernetesCli.CoreV1().Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+		Container: options.Container,
+		Follow:    true,
+		TailLines: options.TailLines,
+	}).Stream(ctx)
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	scanner := bufio.NewScanner(rc)
+	scanner.Buffer(nil, maxLogLineSize)
+	for scanner.Scan() {
+		err = send(scanner.Text())
+		if err != nil {
+			return err
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+	return scanner.Err()
+}
+
+func (c *client) WatchNodes(ctx context.Context, send func(*WatchEvent) error) error {
+	return c.fetcher.WatchNodes(ctx, send)
+}
+
+func (c *client) WatchMachines(ctx context.Context, send func(*WatchEvent) error) error {
+	return c.fetcher.WatchMachines(ctx, send)
+}
+
+func (c *client) WatchEvents(ctx context.Context, namespace string, send func(*WatchEvent) error) error {
+	return c.fetcher.WatchEvents(ctx, namespace, send)
+}
+
+func (c *client) PodLogs(ctx context.Context, namespace, name string, options *PodLogOptions, send func(string) error) error {
+	return c.fetcher.PodLogs(ctx, namespace, name, options, send)
+}
@@ -0,0 +1,147 @@
+package cluster
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/kubernetes/fake"
+	ktesting "k8s.io/client-go/testing"
+
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestWatchEvents(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	kubernetes := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	kubernetes.PrependWatchReactor("events", ktesting.DefaultWatchReactor(watcher, nil))
+
+	_, log := testlog.New()
+
+	c := &client{
+		fetcher: &realFetcher{
+			kubernetesCli: kubernetes,
+			log:           log,
+		},
+		log: log,
+	}
+
+	go func() {
+		watcher.Add(&corev1.Node{}) // not an event: ignored
+		watcher.Add(&corev1.Event{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: "openshift-etcd",
+				Name:      "etcd-0.1",
+			},
+			InvolvedObject: corev1.ObjectReference{
+				Kind: "Pod",
+				Name: "etcd-0",
+			},
+			Type:    "Warning",
+			Reason:  "Unhealthy",
+			Message: "Readiness probe failed",
+			Count:   2,
+		})
+		watcher.Stop()
+	}()
+
+	var got []*WatchEvent
+	err := c.WatchEvents(ctx, "openshift-etcd", func(e *WatchEvent) error {
+		got = append(got, e)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*WatchEvent{
+		{
+			Type:   string(watch.Added),
+			Object: NodesFromNodeList(&corev1.NodeList{Items: []corev1.Node{{}}}).Nodes[0],
+		},
+		{
+			Type: string(watch.Added),
+			Object: &EventInformation{
+				Namespace:      "openshift-etcd",
+				Name:           "etcd-0.1",
+				Type:           "Warning",
+				Reason:         "Unhealthy",
+				Message:        "Readiness probe failed",
+				Object:         "Pod/etcd-0",
+				Count:          2,
+				FirstTimestamp: metav1.Time{}.String(),
+				LastTimestamp:  metav1.Time{}.String(),
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
+
+func TestWatchStopsOnSendError(t *testing.T) {
+	ctx := context.Background()
+
+	kubernetes := fake.NewSimpleClientset()
+	watcher := watch.NewFake()
+	kubernetes.PrependWatchReactor("nodes", ktesting.DefaultWatchReactor(watcher, nil))
+
+	_, log := testlog.New()
+
+	rf := &realFetcher{
+		kubernetesCli: kubernetes,
+		log:           log,
+	}
+
+	go watcher.Add(&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "aro-master-0"}})
+
+	sendErr := errors.New("client went away")
+	err := rf.WatchNodes(ctx, func(e *WatchEvent) error {
+		return sendErr
+	})
+	if err != sendErr {
+		t.Errorf("got %v, want %v", err, sendErr)
+	}
+}
+
+func TestPodLogs(t *testing.T) {
+	ctx := context.Background()
+
+	kubernetes := fake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "openshift-etcd",
+			Name:      "etcd-0",
+		},
+	})
+
+	_, log := testlog.New()
+
+	rf := &realFetcher{
+		kubernetesCli: kubernetes,
+		log:           log,
+	}
+
+	var lines []string
+	err := rf.PodLogs(ctx, "openshift-etcd", "etcd-0", &PodLogOptions{}, func(line string) error {
+		lines = append(lines, line)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the fake clientset always returns "fake logs"
+	if !reflect.DeepEqual(lines, []string{"fake logs"}) {
+		t.Errorf("got %q", lines)
+	}
+}
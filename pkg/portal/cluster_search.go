@@ -0,0 +1,154 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+const (
+	defaultSearchLimit = 100
+	maxSearchLimit     = 1000
+)
+
+type AdminOpenShiftClusterSearchResult struct {
+	Clusters []*AdminOpenShiftCluster `json:"clusters"`
+	// Continuation fetches the next page of results; it is empty on the last
+	// page
+	Continuation string `json:"continuation,omitempty"`
+	Limit        int    `json:"limit"`
+}
+
+// searchClusters returns a page of the clusters which match the query's
+// filters.  Filtering, sorting and paging all happen in the database.
+func (p *portal) searchClusters(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	q := r.URL.Query()
+
+	search, err := parseClusterSearch(q)
+	if err != nil {
+		p.badRequest(w, err)
+		return
+	}
+
+	limit, err := parseQueryInt(q, "limit", defaultSearchLimit, 1, maxSearchLimit)
+	if err != nil {
+		p.badRequest(w, err)
+		return
+	}
+
+	i, err := p.dbOpenShiftClusters.Search(search, q.Get("continuation"))
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	docs, err := i.Next(ctx, limit)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	result := &AdminOpenShiftClusterSearchResult{
+		Clusters: []*AdminOpenShiftCluster{},
+		Limit:    limit,
+	}
+
+	if docs != nil {
+		result.Clusters = adminOpenShiftClusters(docs)
+		result.Continuation = i.Continuation()
+	}
+
+	b, err := json.MarshalIndent(result, "", "    ")
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write(b)
+}
+
+func parseClusterSearch(q url.Values) (*database.OpenShiftClusterSearch, error) {
+	search := &database.OpenShiftClusterSearch{
+		SubscriptionID:    q.Get("subscription"),
+		Version:           q.Get("version"),
+		ProvisioningState: api.ProvisioningState(q.Get("provisioningState")),
+		MaintenanceState:  api.MaintenanceState(q.Get("maintenanceState")),
+		OperatorFlag:      q.Get("operatorFlag"),
+		OperatorFlagValue: q.Get("operatorFlagValue"),
+	}
+
+	if search.SubscriptionID != "" && !uuid.IsValid(search.SubscriptionID) {
+		return nil, fmt.Errorf("invalid subscription %q", search.SubscriptionID)
+	}
+
+	if (search.OperatorFlag == "") != (search.OperatorFlagValue == "") {
+		return nil, fmt.Errorf("operatorFlag and operatorFlagValue must be set together")
+	}
+
+	if s := q.Get("sort"); s != "" {
+		search.OrderBy = strings.ToLower(s)
+		if _, ok := database.OpenShiftClusterSearchOrderBy[search.OrderBy]; !ok {
+			return nil, fmt.Errorf("invalid sort %q", s)
+		}
+	}
+
+	switch q.Get("order") {
+	case "", "asc":
+	case "desc":
+		search.Descending = true
+	default:
+		return nil, fmt.Errorf("invalid order %q", q.Get("order"))
+	}
+
+	var err error
+	search.HiveShard, err = parseQueryInt(q, "hiveShard", 0, 1, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range []struct {
+		param string
+		value *time.Time
+	}{
+		{param: "createdAfter", value: &search.CreatedAfter},
+		{param: "createdBefore", value: &search.CreatedBefore},
+	} {
+		if v := q.Get(t.param); v != "" {
+			*t.value, err = time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", t.param, v)
+			}
+		}
+	}
+
+	return search, nil
+}
+
+// parseQueryInt parses an integer query parameter which defaults to def and
+// must be at least min and, unless max is negative, at most max
+func parseQueryInt(q url.Values, param string, def, min, max int) (int, error) {
+	v := q.Get(param)
+	if v == "" {
+		return def, nil
+	}
+
+	i, err := strconv.Atoi(v)
+	if err != nil || i < min || (max >= 0 && i > max) {
+		return 0, fmt.Errorf("invalid %s %q", param, v)
+	}
+
+	return i, nil
+}
@@ -0,0 +1,247 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestSearchClusters(t *testing.T) {
+	dbOpenShiftClusters, _ := testdatabase.NewFakeOpenShiftClusters()
+
+	fixture := testdatabase.NewFixture().
+		WithOpenShiftClusters(dbOpenShiftClusters)
+
+	for _, c := range []struct {
+		name              string
+		subscription      string
+		version           string
+		provisioningState api.ProvisioningState
+		maintenanceState  api.MaintenanceState
+		hiveShard         int
+		operatorFlags     api.OperatorFlags
+		createdAt         string
+	}{
+		{
+			name:              "alpha",
+			subscription:      "00000000-0000-0000-0000-000000000000",
+			version:           "4.12.25",
+			provisioningState: api.ProvisioningStateSucceeded,
+			hiveShard:         1,
+			operatorFlags:     api.OperatorFlags{"aro.alertwebhook.enabled": "true"},
+			createdAt:         "2023-01-01T00:00:00Z",
+		},
+		{
+			name:              "bravo",
+			subscription:      "00000000-0000-0000-0000-000000000000",
+			version:           "4.13.4",
+			provisioningState: api.ProvisioningStateSucceeded,
+			maintenanceState:  api.MaintenanceStatePlanned,
+			hiveShard:         2,
+			operatorFlags:     api.OperatorFlags{"aro.alertwebhook.enabled": "false"},
+			createdAt:         "2023-06-01T00:00:00Z",
+		},
+		{
+			name:              "charlie",
+			subscription:      "11111111-1111-1111-1111-111111111111",
+			version:           "4.12.60",
+			provisioningState: api.ProvisioningStateFailed,
+			createdAt:         "2024-01-01T00:00:00Z",
+		},
+		{
+			name:              "delta",
+			subscription:      "11111111-1111-1111-1111-111111111111",
+			version:           "4.14.1",
+			provisioningState: api.ProvisioningStateSucceeded,
+			createdAt:         "2023-06-01T00:00:00.5Z",
+		},
+	} {
+		createdAt, err := time.Parse(time.RFC3339, c.createdAt)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resourceID := "/subscriptions/" + c.subscription + "/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/" + c.name
+
+		doc := &api.OpenShiftClusterDocument{
+			ID:  c.name,
+			Key: resourceID,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID:   resourceID,
+				Name: c.name,
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: c.provisioningState,
+					MaintenanceState:  c.maintenanceState,
+					ClusterProfile: api.ClusterProfile{
+						Version: c.version,
+					},
+					OperatorFlags: c.operatorFlags,
+					CreatedAt:     createdAt,
+				},
+			},
+		}
+
+		if c.hiveShard != 0 {
+			doc.OpenShiftCluster.Properties.HiveProfile = api.HiveProfile{
+				Namespace: "aro-" + c.name,
+				Shard:     c.hiveShard,
+			}
+		}
+
+		fixture.AddOpenShiftClusterDocuments(doc)
+	}
+
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name             string
+		query            string
+		wantStatusCode   int
+		wantNames        []string
+		wantContinuation string
+	}{
+		{
+			name:      "all",
+			wantNames: []string{"alpha", "bravo", "charlie", "delta"},
+		},
+		{
+			name:      "version prefix",
+			query:     "version=4.12",
+			wantNames: []string{"alpha", "charlie"},
+		},
+		{
+			name:      "subscription and provisioning state",
+			query:     "subscription=00000000-0000-0000-0000-000000000000&provisioningState=Succeeded",
+			wantNames: []string{"alpha", "bravo"},
+		},
+		{
+			name:      "maintenance state",
+			query:     "maintenanceState=Planned",
+			wantNames: []string{"bravo"},
+		},
+		{
+			name:      "hive shard",
+			query:     "hiveShard=2",
+			wantNames: []string{"bravo"},
+		},
+		{
+			name:      "operator flag",
+			query:     "operatorFlag=aro.alertwebhook.enabled&operatorFlagValue=true",
+			wantNames: []string{"alpha"},
+		},
+		{
+			name:      "creation date",
+			query:     "createdAfter=2023-03-01T00:00:00Z&createdBefore=2024-01-01T00:00:00Z",
+			wantNames: []string{"bravo", "delta"},
+		},
+		{
+			name:      "creation date with fractional seconds",
+			query:     "createdAfter=2023-06-01T00:00:00.5Z",
+			wantNames: []string{"charlie", "delta"},
+		},
+		{
+			name:             "sorted and paged",
+			query:            "sort=createdAt&order=desc&limit=1",
+			wantNames:        []string{"charlie"},
+			wantContinuation: "1",
+		},
+		{
+			name:      "continued",
+			query:     "sort=createdAt&order=desc&limit=2&continuation=1",
+			wantNames: []string{"delta", "bravo"},
+		},
+		{
+			name:           "invalid order",
+			query:          "order=up",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid sort",
+			query:          "sort=key",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid limit",
+			query:          "limit=1001",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid subscription",
+			query:          "subscription=invalid",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "operator flag without value",
+			query:          "operatorFlag=aro.alertwebhook.enabled",
+			wantStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:           "invalid date",
+			query:          "createdAfter=2023-03-01",
+			wantStatusCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, log := testlog.New()
+
+			p := &portal{
+				log:                 log,
+				dbOpenShiftClusters: dbOpenShiftClusters,
+			}
+
+			req, err := http.NewRequest(http.MethodGet, "/api/clusters/search?"+tt.query, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			aadAuthenticatedRouter := mux.NewRouter()
+			p.aadAuthenticatedRoutes(aadAuthenticatedRouter, nil, nil, nil)
+			w := httptest.NewRecorder()
+			aadAuthenticatedRouter.ServeHTTP(w, req)
+
+			if tt.wantStatusCode == 0 {
+				tt.wantStatusCode = http.StatusOK
+			}
+			if w.Code != tt.wantStatusCode {
+				t.Fatalf("got %d, want %d: %s", w.Code, tt.wantStatusCode, w.Body.String())
+			}
+			if w.Code != http.StatusOK {
+				return
+			}
+
+			var result AdminOpenShiftClusterSearchResult
+			err = json.NewDecoder(w.Body).Decode(&result)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := []string{}
+			for _, c := range result.Clusters {
+				names = append(names, c.Name)
+			}
+
+			if !reflect.DeepEqual(names, tt.wantNames) {
+				t.Errorf("got %v, want %v", names, tt.wantNames)
+			}
+
+			if result.Continuation != tt.wantContinuation {
+				t.Errorf("got continuation %q, want %q", result.Continuation, tt.wantContinuation)
+			}
+		})
+	}
+}
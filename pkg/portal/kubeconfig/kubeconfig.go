@@ -9,6 +9,7 @@ import (
 	"encoding/pem"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
 	"net/http/httputil"
 	"strings"
@@ -22,6 +23,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/portal/util/clientcache"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/roundtripper"
@@ -42,10 +44,17 @@ type Kubeconfig struct {
 	dbOpenShiftClusters database.OpenShiftClusters
 	DbPortal            database.Portal
 
+	// dbPortalAccessGrants is nil if elevated access does not require an
+	// approved access grant
+	dbPortalAccessGrants database.PortalAccessGrants
+
 	dialer      proxy.Dialer
 	clientCache clientcache.ClientCache
 	Env         env.Core
 
+	// recorder records API requests; nil if session recording is disabled
+	recorder *recording.Recorder
+
 	ReverseProxy *httputil.ReverseProxy
 }
 
@@ -57,7 +66,9 @@ func New(baseLog *logrus.Entry,
 	elevatedGroupIDs []string,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
+	dbPortalAccessGrants database.PortalAccessGrants,
 	dialer proxy.Dialer,
+	recorder *recording.Recorder,
 ) *Kubeconfig {
 	k := &Kubeconfig{
 		Log:           baseLog,
@@ -70,9 +81,13 @@ func New(baseLog *logrus.Entry,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		DbPortal:            dbPortal,
 
+		dbPortalAccessGrants: dbPortalAccessGrants,
+
 		dialer:      dialer,
 		clientCache: clientcache.New(time.Hour),
 		Env:         env,
+
+		recorder: recorder,
 	}
 
 	k.ReverseProxy = &httputil.ReverseProxy{
@@ -85,7 +100,10 @@ func New(baseLog *logrus.Entry,
 }
 
 // New creates a New PortalDocument allowing kubeconfig access to a cluster for
-// 6 hours and returns a kubeconfig with the temporary credentials
+// 6 hours and returns a kubeconfig with the temporary credentials.  If elevated
+// access requires an approved access grant, elevated credentials are only
+// issued during the grant's window and expire with it; otherwise the
+// credentials issued are not elevated.
 func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -96,11 +114,28 @@ func (k *Kubeconfig) New(w http.ResponseWriter, r *http.Request) {
 	}
 
 	elevated := len(middleware.GroupsIntersect(k.elevatedGroupIDs, ctx.Value(middleware.ContextKeyGroups).([]string))) > 0
+	timeout := kubeconfigNewTimeout
+
+	if elevated && k.dbPortalAccessGrants != nil {
+		now := time.Now()
+
+		grant, err := k.dbPortalAccessGrants.GetActive(ctx, ctx.Value(middleware.ContextKeyUsername).(string), resourceID, now)
+		if err != nil {
+			k.internalServerError(w, err)
+			return
+		}
+
+		if grant == nil {
+			elevated = false
+		} else if remaining := grant.PortalAccessGrant.NotAfter.Sub(now); remaining < timeout {
+			timeout = remaining
+		}
+	}
 
 	token := k.DbPortal.NewUUID()
 	portalDoc := &api.PortalDocument{
 		ID:  token,
-		TTL: int(kubeconfigNewTimeout / time.Second),
+		TTL: int(math.Ceil(timeout.Seconds())),
 		Portal: &api.Portal{
 			Username: ctx.Value(middleware.ContextKeyUsername).(string),
 			ID:       resourceID,
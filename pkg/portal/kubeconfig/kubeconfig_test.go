@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -33,14 +34,18 @@ func TestNew(t *testing.T) {
 
 	servingCert := &x509.Certificate{}
 
+	kubeconfigBody := "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}"
+
 	for _, tt := range []struct {
-		name           string
-		r              func(*http.Request)
-		elevated       bool
-		fixtureChecker func(*testdatabase.Fixture, *testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
-		wantStatusCode int
-		wantHeaders    http.Header
-		wantBody       string
+		name                string
+		r                   func(*http.Request)
+		elevated            bool
+		requireAccessGrants bool
+		accessGrants        []*api.PortalAccessGrantDocument
+		fixtureChecker      func(*testdatabase.Fixture, *testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
+		wantStatusCode      int
+		wantHeaders         http.Header
+		wantBody            string
 	}{
 		{
 			name: "success - not elevated",
@@ -60,7 +65,7 @@ func TestNew(t *testing.T) {
 			wantHeaders: http.Header{
 				"Content-Disposition": []string{`attachment; filename="cluster.kubeconfig"`},
 			},
-			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+			wantBody: kubeconfigBody,
 		},
 		{
 			name:     "success - elevated",
@@ -83,7 +88,65 @@ func TestNew(t *testing.T) {
 			wantHeaders: http.Header{
 				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
 			},
-			wantBody: "{\n    \"kind\": \"Config\",\n    \"apiVersion\": \"v1\",\n    \"preferences\": {},\n    \"clusters\": [\n        {\n            \"name\": \"cluster\",\n            \"cluster\": {\n                \"server\": \"https://localhost:8444/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/kubeconfig/proxy\",\n                \"certificate-authority-data\": \"LS0tLS1CRUdJTiBDRVJUSUZJQ0FURS0tLS0tCi0tLS0tRU5EIENFUlRJRklDQVRFLS0tLS0K\"\n            }\n        }\n    ],\n    \"users\": [\n        {\n            \"name\": \"user\",\n            \"user\": {\n                \"token\": \"03030303-0303-0303-0303-030303030001\"\n            }\n        }\n    ],\n    \"contexts\": [\n        {\n            \"name\": \"context\",\n            \"context\": {\n                \"cluster\": \"cluster\",\n                \"user\": \"user\",\n                \"namespace\": \"default\"\n            }\n        }\n    ],\n    \"current-context\": \"context\"\n}",
+			wantBody: kubeconfigBody,
+		},
+		{
+			name:                "success - elevated with access grant",
+			elevated:            true,
+			requireAccessGrants: true,
+			accessGrants: []*api.PortalAccessGrantDocument{
+				{
+					PortalAccessGrant: &api.PortalAccessGrant{
+						Username:   username,
+						ResourceID: resourceID,
+						State:      api.PortalAccessGrantStateApproved,
+						NotBefore:  time.Now().Add(-time.Hour),
+						NotAfter:   time.Now().Add(time.Hour),
+					},
+				},
+			},
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				// the credentials expire with the grant
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 3600,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						Kubeconfig: &api.Kubeconfig{
+							Elevated: true,
+						},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster-elevated.kubeconfig"`},
+			},
+			wantBody: kubeconfigBody,
+		},
+		{
+			name:                "success - elevated without access grant",
+			elevated:            true,
+			requireAccessGrants: true,
+			fixtureChecker: func(fixture *testdatabase.Fixture, checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				portalDocument := &api.PortalDocument{
+					ID:  password,
+					TTL: 21600,
+					Portal: &api.Portal{
+						Username:   username,
+						ID:         resourceID,
+						Kubeconfig: &api.Kubeconfig{},
+					},
+				}
+				checker.AddPortalDocuments(portalDocument)
+			},
+			wantStatusCode: http.StatusOK,
+			wantHeaders: http.Header{
+				"Content-Disposition": []string{`attachment; filename="cluster.kubeconfig"`},
+			},
+			wantBody: kubeconfigBody,
 		},
 		{
 			name: "bad path",
@@ -106,9 +169,12 @@ func TestNew(t *testing.T) {
 			ctx := context.Background()
 
 			dbPortal, portalClient := testdatabase.NewFakePortal()
+			dbPortalAccessGrants, _ := testdatabase.NewFakePortalAccessGrants()
 
 			fixture := testdatabase.NewFixture().
-				WithPortal(dbPortal)
+				WithPortal(dbPortal).
+				WithPortalAccessGrants(dbPortalAccessGrants)
+			fixture.AddPortalAccessGrantDocuments(tt.accessGrants...)
 
 			checker := testdatabase.NewChecker()
 
@@ -121,6 +187,10 @@ func TestNew(t *testing.T) {
 				t.Fatal(err)
 			}
 
+			if !tt.requireAccessGrants {
+				dbPortalAccessGrants = nil
+			}
+
 			ctx = context.WithValue(ctx, middleware.ContextKeyUsername, username)
 			if tt.elevated {
 				ctx = context.WithValue(ctx, middleware.ContextKeyGroups, elevatedGroupIDs)
@@ -148,7 +218,7 @@ func TestNew(t *testing.T) {
 			_, audit := testlog.NewAudit()
 			_, baseLog := testlog.New()
 			_, baseAccessLog := testlog.New()
-			k := New(baseLog, audit, _env, baseAccessLog, servingCert, elevatedGroupIDs, nil, dbPortal, nil)
+			k := New(baseLog, audit, _env, baseAccessLog, servingCert, elevatedGroupIDs, nil, dbPortal, dbPortalAccessGrants, nil, nil)
 
 			if tt.r != nil {
 				tt.r(r)
@@ -19,6 +19,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/portal/util/responsewriter"
 	utilpem "github.com/Azure/ARO-RP/pkg/util/pem"
 	"github.com/Azure/ARO-RP/pkg/util/restconfig"
@@ -160,7 +161,11 @@ func (k *Kubeconfig) roundTripper(r *http.Request) (*http.Response, error) {
 	}
 
 	cli := r.Context().Value(contextKeyClient).(*http.Client)
+	t := time.Now()
 	resp, err := cli.Do(r)
+	if k.recorder != nil {
+		k.recordRequest(r, t, resp, err)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -172,6 +177,34 @@ func (k *Kubeconfig) roundTripper(r *http.Request) (*http.Response, error) {
 	return resp, err
 }
 
+// recordRequest records the metadata of a request which was forwarded to the
+// cluster.  The duration is the time taken to receive the response headers.
+func (k *Kubeconfig) recordRequest(r *http.Request, t time.Time, resp *http.Response, err error) {
+	portalDoc := r.Context().Value(middleware.ContextKeyPortalDoc).(*api.PortalDocument)
+
+	req := &recording.KubernetesRequest{
+		Session: &recording.Session{
+			Username:   portalDoc.Portal.Username,
+			ResourceID: portalDoc.Portal.ID,
+		},
+		Time:      t.UTC(),
+		Elevated:  portalDoc.Portal.Kubeconfig.Elevated,
+		Method:    r.Method,
+		Path:      r.URL.Path,
+		Query:     r.URL.RawQuery,
+		UserAgent: r.UserAgent(),
+		Duration:  time.Since(t).Seconds(),
+	}
+
+	if err != nil {
+		req.Error = err.Error()
+	} else {
+		req.StatusCode = resp.StatusCode
+	}
+
+	k.recorder.KubernetesRequest(portalDoc.ID, req)
+}
+
 func (k *Kubeconfig) error(r *http.Request, statusCode int, err error) {
 	if err != nil {
 		k.Log.Warn(err)
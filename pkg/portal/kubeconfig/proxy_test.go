@@ -4,16 +4,20 @@ package kubeconfig
 // Licensed under the Apache License 2.0.
 
 import (
+	"bytes"
 	"context"
 	"crypto/rsa"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/http/httputil"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -22,8 +26,10 @@ import (
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/portal/util/responsewriter"
 	"github.com/Azure/ARO-RP/pkg/util/azureclient"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	mock_env "github.com/Azure/ARO-RP/pkg/util/mocks/env"
 	mock_proxy "github.com/Azure/ARO-RP/pkg/util/mocks/proxy"
 	utilpem "github.com/Azure/ARO-RP/pkg/util/pem"
@@ -398,7 +404,7 @@ func TestProxy(t *testing.T) {
 			_, audit := testlog.NewAudit()
 			_, baseLog := testlog.New()
 			_, baseAccessLog := testlog.New()
-			k := New(baseLog, audit, _env, baseAccessLog, nil, nil, dbOpenShiftClusters, dbPortal, dialer)
+			k := New(baseLog, audit, _env, baseAccessLog, nil, nil, dbOpenShiftClusters, dbPortal, nil, dialer, nil)
 
 			unauthenticatedRouter := &mux.Router{}
 			unauthenticatedRouter.Use(middleware.Bearer(k.DbPortal))
@@ -446,3 +452,94 @@ func TestProxy(t *testing.T) {
 		})
 	}
 }
+
+type fakeStore map[string]*bytes.Buffer
+
+func (s fakeStore) Append(ctx context.Context, name string, b []byte) error {
+	if s[name] == nil {
+		s[name] = &bytes.Buffer{}
+	}
+	s[name].Write(b)
+	return nil
+}
+
+func TestRecordRequest(t *testing.T) {
+	ctx := context.Background()
+
+	aead, err := encryption.NewXChaCha20Poly1305(ctx, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := fakeStore{}
+	_, log := testlog.New()
+
+	k := &Kubeconfig{
+		recorder: recording.NewRecorder(log, aead, store),
+	}
+
+	r, err := http.NewRequest(http.MethodGet, "https://kubernetes:6443/api/v1/pods?watch=true", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.Header.Set("User-Agent", "testua")
+	r = r.WithContext(context.WithValue(ctx, middleware.ContextKeyPortalDoc, &api.PortalDocument{
+		ID: "00000000-0000-0000-0000-000000000000",
+		Portal: &api.Portal{
+			Username: "username",
+			ID:       "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+			Kubeconfig: &api.Kubeconfig{
+				Elevated: true,
+			},
+		},
+	}))
+
+	k.recordRequest(r, time.Now(), &http.Response{StatusCode: http.StatusOK}, nil)
+	k.recordRequest(r, time.Now(), nil, errors.New("connection refused"))
+	k.recorder.Flush(ctx)
+
+	if len(store) != 1 {
+		t.Fatalf("got %d recordings", len(store))
+	}
+
+	for _, blob := range store {
+		buf := &bytes.Buffer{}
+		err = recording.Decode(buf, bytes.NewReader(blob.Bytes()), aead)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []recording.KubernetesRequest
+		for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+			var req recording.KubernetesRequest
+			err = json.Unmarshal([]byte(line), &req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, req)
+		}
+
+		if len(got) != 2 {
+			t.Fatalf("got %d requests", len(got))
+		}
+
+		for _, req := range got {
+			if req.Username != "username" ||
+				!req.Elevated ||
+				req.Method != http.MethodGet ||
+				req.Path != "/api/v1/pods" ||
+				req.Query != "watch=true" ||
+				req.UserAgent != "testua" {
+				t.Errorf("unexpected request %#v", req)
+			}
+		}
+
+		if got[0].StatusCode != http.StatusOK || got[0].Error != "" {
+			t.Errorf("unexpected request %#v", got[0])
+		}
+
+		if got[1].StatusCode != 0 || got[1].Error != "connection refused" {
+			t.Errorf("unexpected request %#v", got[1])
+		}
+	}
+}
@@ -50,6 +50,7 @@ func TestLog(t *testing.T) {
 		r.URL = nil // mutate the request
 
 		_ = w.(http.Hijacker) // must implement http.Hijacker
+		_ = w.(http.Flusher)  // must implement http.Flusher
 
 		w.WriteHeader(http.StatusOK)
 		_, _ = io.Copy(w, r.Body)
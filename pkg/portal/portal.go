@@ -23,6 +23,7 @@ import (
 	"github.com/gorilla/mux"
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/api/validate"
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
@@ -33,6 +34,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/portal/kubeconfig"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
 	"github.com/Azure/ARO-RP/pkg/portal/prometheus"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/portal/ssh"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 	"github.com/Azure/ARO-RP/pkg/util/heartbeat"
@@ -67,8 +69,18 @@ type portal struct {
 	dbPortal            database.Portal
 	dbOpenShiftClusters database.OpenShiftClusters
 
+	dbPortalAccessGrants database.PortalAccessGrants
+
+	// requireAccessGrants restricts elevated SSH and kubeconfig access to
+	// the windows of approved access grants
+	requireAccessGrants bool
+
 	dialer proxy.Dialer
 
+	// recorder records SSH sessions and kubeconfig API requests; nil if
+	// session recording is disabled
+	recorder *recording.Recorder
+
 	templateV1         *template.Template
 	templateV2         *template.Template
 	templatePrometheus *template.Template
@@ -97,7 +109,10 @@ func NewPortal(env env.Core,
 	elevatedGroupIDs []string,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
+	dbPortalAccessGrants database.PortalAccessGrants,
+	requireAccessGrants bool,
 	dialer proxy.Dialer,
+	recorder *recording.Recorder,
 	m metrics.Emitter,
 ) Runnable {
 	return &portal{
@@ -124,8 +139,13 @@ func NewPortal(env env.Core,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbPortal:            dbPortal,
 
+		dbPortalAccessGrants: dbPortalAccessGrants,
+		requireAccessGrants:  requireAccessGrants,
+
 		dialer: dialer,
 
+		recorder: recorder,
+
 		m: m,
 	}
 }
@@ -188,7 +208,12 @@ func (p *portal) setupRouter(kconfig *kubeconfig.Kubeconfig, prom *prometheus.Pr
 }
 
 func (p *portal) setupServices() (*kubeconfig.Kubeconfig, *prometheus.Prometheus, *ssh.SSH, error) {
-	ssh, err := ssh.New(p.env, p.log, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
+	var dbPortalAccessGrants database.PortalAccessGrants
+	if p.requireAccessGrants {
+		dbPortalAccessGrants = p.dbPortalAccessGrants
+	}
+
+	ssh, err := ssh.New(p.env, p.log, p.baseAccessLog, p.sshl, p.sshKey, p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, dbPortalAccessGrants, p.dialer, p.recorder)
 	if err != nil {
 		return nil, nil, nil, err
 	}
@@ -198,7 +223,7 @@ func (p *portal) setupServices() (*kubeconfig.Kubeconfig, *prometheus.Prometheus
 		return nil, nil, nil, err
 	}
 
-	k := kubeconfig.New(p.log, p.audit, p.env, p.baseAccessLog, p.servingCerts[0], p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, p.dialer)
+	k := kubeconfig.New(p.log, p.audit, p.env, p.baseAccessLog, p.servingCerts[0], p.elevatedGroupIDs, p.dbOpenShiftClusters, p.dbPortal, dbPortalAccessGrants, p.dialer, p.recorder)
 
 	prom := prometheus.New(p.log, p.dbOpenShiftClusters, p.dialer)
 
@@ -242,6 +267,10 @@ func (p *portal) Run(ctx context.Context) error {
 		BaseContext: func(net.Listener) context.Context { return ctx },
 	}
 
+	if p.recorder != nil {
+		go p.recorder.Run(ctx)
+	}
+
 	go heartbeat.EmitHeartbeat(p.log, p.m, "portal.heartbeat", nil, func() bool { return true })
 
 	return s.Serve(tls.NewListener(p.l, config))
@@ -287,6 +316,10 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	}
 
 	r.Methods(http.MethodGet).Path("/api/clusters").HandlerFunc(p.clusters)
+	r.Methods(http.MethodGet).Path("/api/clusters/search").HandlerFunc(p.searchClusters)
+	r.Methods(http.MethodGet).Path("/api/accessgrants").HandlerFunc(p.listAccessGrants)
+	r.Methods(http.MethodPost).Path("/api/accessgrants/{id}/approve").HandlerFunc(p.reviewAccessGrant(api.PortalAccessGrantStateApproved))
+	r.Methods(http.MethodPost).Path("/api/accessgrants/{id}/reject").HandlerFunc(p.reviewAccessGrant(api.PortalAccessGrantStateRejected))
 	r.Methods(http.MethodGet).Path("/api/info").HandlerFunc(p.info)
 	r.Methods(http.MethodGet).Path("/api/regions").HandlerFunc(p.regions)
 
@@ -297,6 +330,14 @@ func (p *portal) aadAuthenticatedRoutes(r *mux.Router, prom *prometheus.Promethe
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machines").HandlerFunc(p.machines)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/machine-sets").HandlerFunc(p.machineSets)
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}/statistics/{statisticsType}").HandlerFunc(p.statistics)
+	r.Methods(http.MethodPost).Path("/api/{subscription}/{resourceGroup}/{clusterName}/accessgrants").HandlerFunc(p.requestAccessGrant)
+
+	// Cluster-specific streams (server-sent events)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/stream/events").HandlerFunc(p.streamEvents)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/stream/nodes").HandlerFunc(p.streamNodes)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/stream/machines").HandlerFunc(p.streamMachines)
+	r.Methods(http.MethodGet).Path("/api/{subscription}/{resourceGroup}/{clusterName}/stream/logs/{namespace}/{pod}").HandlerFunc(p.streamPodLogs)
+
 	r.Path("/api/{subscription}/{resourceGroup}/{clusterName}").HandlerFunc(p.clusterInfo)
 
 	// prometheus
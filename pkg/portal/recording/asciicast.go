@@ -0,0 +1,202 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Azure/ARO-RP/pkg/util/uuid"
+)
+
+// Asciicast records a terminal session in asciicast v2 format
+// (https://docs.asciinema.org/manual/asciicast/v2/).  The header has an
+// additional "aro" field identifying the session.
+type Asciicast struct {
+	r    *Recorder
+	name string
+
+	header asciicastHeader
+	start  time.Time
+
+	mu      sync.Mutex
+	started bool
+	closed  bool
+	partial map[string][]byte
+}
+
+type asciicastHeader struct {
+	Version   int               `json:"version"`
+	Width     int               `json:"width"`
+	Height    int               `json:"height"`
+	Timestamp int64             `json:"timestamp"`
+	Env       map[string]string `json:"env,omitempty"`
+	ARO       asciicastMetadata `json:"aro"`
+}
+
+type asciicastMetadata struct {
+	*Session
+	Hostname string `json:"hostname"`
+}
+
+// NewAsciicast returns a new terminal session recording for a session on the
+// given host
+func (r *Recorder) NewAsciicast(session *Session, hostname string) *Asciicast {
+	start := r.now()
+
+	return &Asciicast{
+		r:    r,
+		name: recordingName("ssh", session, start.UTC().Format("20060102T150405Z")+"-"+uuid.DefaultGenerator.Generate(), ".cast"),
+
+		header: asciicastHeader{
+			Version:   2,
+			Width:     80,
+			Height:    24,
+			Timestamp: start.Unix(),
+			ARO: asciicastMetadata{
+				Session:  session,
+				Hostname: hostname,
+			},
+		},
+		start: start,
+
+		partial: map[string][]byte{},
+	}
+}
+
+// Name returns the name of the recording
+func (a *Asciicast) Name() string {
+	return a.name
+}
+
+// Pty records the terminal type and size requested when a pty is allocated
+func (a *Asciicast) Pty(term string, width, height int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.started {
+		a.event("r", fmt.Sprintf("%dx%d", width, height))
+		return
+	}
+
+	if term != "" {
+		a.header.Env = map[string]string{"TERM": term}
+	}
+	a.header.Width = width
+	a.header.Height = height
+}
+
+// Resize records a change of terminal size
+func (a *Asciicast) Resize(width, height int) {
+	a.Pty("", width, height)
+}
+
+// Marker records a marker, for example the command run by an exec request
+func (a *Asciicast) Marker(label string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.event("m", label)
+}
+
+// Output returns a Writer which records terminal output
+func (a *Asciicast) Output() io.Writer {
+	return &asciicastWriter{a: a, code: "o"}
+}
+
+// Input returns a Writer which records terminal input
+func (a *Asciicast) Input() io.Writer {
+	return &asciicastWriter{a: a, code: "i"}
+}
+
+// Close records any incomplete trailing UTF-8 sequences.  Nothing is recorded
+// after Close.
+func (a *Asciicast) Close() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for _, code := range []string{"o", "i"} {
+		if len(a.partial[code]) > 0 {
+			a.event(code, string(a.partial[code]))
+			delete(a.partial, code)
+		}
+	}
+
+	if !a.started {
+		a.writeHeader()
+	}
+
+	a.closed = true
+}
+
+type asciicastWriter struct {
+	a    *Asciicast
+	code string
+}
+
+// Write records p.  Event data must be valid UTF-8, so an incomplete UTF-8
+// sequence at the end of p is held back until the next Write.
+func (w *asciicastWriter) Write(p []byte) (int, error) {
+	w.a.mu.Lock()
+	defer w.a.mu.Unlock()
+
+	b := append(w.a.partial[w.code], p...)
+
+	i := len(b)
+	for j := len(b) - 1; j >= 0 && j >= len(b)-utf8.UTFMax; j-- {
+		if utf8.RuneStart(b[j]) {
+			if !utf8.FullRune(b[j:]) {
+				i = j
+			}
+			break
+		}
+	}
+
+	w.a.partial[w.code] = append([]byte(nil), b[i:]...)
+	if i > 0 {
+		w.a.event(w.code, string(b[:i]))
+	}
+
+	return len(p), nil
+}
+
+// event records an event; a.mu must be held
+func (a *Asciicast) event(code, data string) {
+	if a.closed {
+		return
+	}
+
+	if !a.started {
+		a.writeHeader()
+	}
+
+	b, err := json.Marshal([]interface{}{
+		float64(a.r.now().Sub(a.start).Microseconds()) / 1e6,
+		code,
+		data,
+	})
+	if err != nil {
+		a.r.log.Error(err)
+		return
+	}
+
+	a.r.write(a.name, append(b, '\n'))
+}
+
+// writeHeader records the header; a.mu must be held
+func (a *Asciicast) writeHeader() {
+	a.started = true
+
+	b, err := json.Marshal(a.header)
+	if err != nil {
+		a.r.log.Error(err)
+		return
+	}
+
+	a.r.write(a.name, append(b, '\n'))
+}
@@ -0,0 +1,72 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestAsciicast(t *testing.T) {
+	ctx := context.Background()
+	r, store, aead := newTestRecorder(t)
+
+	now := time.Unix(1700000000, 0)
+	r.now = func() time.Time { return now }
+
+	a := r.NewAsciicast(&Session{
+		Username:   "user@example.com",
+		ResourceID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+	}, "master-0")
+
+	if !regexp.MustCompile(`^ssh/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/20231114T221320Z-[0-9a-f-]{36}\.cast$`).MatchString(a.Name()) {
+		t.Errorf("got name %q", a.Name())
+	}
+
+	a.Pty("xterm", 120, 40)
+
+	now = now.Add(time.Second)
+	_, _ = a.Input().Write([]byte("ls\r"))
+
+	now = now.Add(500 * time.Millisecond)
+	// "é" split across writes is recorded once it is complete
+	_, _ = a.Output().Write([]byte("caf\xc3"))
+	_, _ = a.Output().Write([]byte("\xa9\r\n"))
+
+	now = now.Add(time.Second)
+	a.Resize(100, 30)
+	a.Close()
+
+	// nothing is recorded after Close
+	_, _ = a.Output().Write([]byte("ignored"))
+
+	r.Flush(ctx)
+
+	want := `{"version":2,"width":120,"height":40,"timestamp":1700000000,"env":{"TERM":"xterm"},"aro":{"username":"user@example.com","resourceId":"/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster","hostname":"master-0"}}
+[1,"i","ls\r"]
+[1.5,"o","caf"]
+[1.5,"o","é\r\n"]
+[2.5,"r","100x30"]
+`
+	if got := decode(t, store, aead, a.Name()); got != want {
+		t.Errorf("got %s", got)
+	}
+}
+
+func TestAsciicastEmpty(t *testing.T) {
+	ctx := context.Background()
+	r, store, aead := newTestRecorder(t)
+
+	a := r.NewAsciicast(&Session{}, "master-1")
+	a.Close()
+	r.Flush(ctx)
+
+	// a session without I/O is still recorded
+	want := `{"version":2,"width":80,"height":24,"timestamp":1700000000,"aro":{"username":"","resourceId":"","hostname":"master-1"}}` + "\n"
+	if got := decode(t, store, aead, a.Name()); got != want {
+		t.Errorf("got %s", got)
+	}
+}
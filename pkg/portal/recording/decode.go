@@ -0,0 +1,42 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bufio"
+	"encoding/base64"
+	"fmt"
+	"io"
+
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+)
+
+// Decode writes the plaintext of the recording read from r to w
+func Decode(w io.Writer, r io.Reader, aead encryption.AEAD) error {
+	scanner := bufio.NewScanner(r)
+	// a sealed, base64 encoded chunk is a little over 4/3 of maxChunkSize
+	scanner.Buffer(nil, 2*maxChunkSize)
+
+	var line int
+	for scanner.Scan() {
+		line++
+
+		b, err := base64.StdEncoding.DecodeString(scanner.Text())
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		b, err = aead.Open(b)
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		_, err = w.Write(b)
+		if err != nil {
+			return err
+		}
+	}
+
+	return scanner.Err()
+}
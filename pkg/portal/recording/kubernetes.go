@@ -0,0 +1,42 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// KubernetesRequest is the metadata of a Kubernetes API request made through
+// the kubeconfig proxy.  Request and response bodies are not recorded.
+type KubernetesRequest struct {
+	*Session
+	Time       time.Time `json:"time"`
+	Elevated   bool      `json:"elevated"`
+	Method     string    `json:"method"`
+	Path       string    `json:"path"`
+	Query      string    `json:"query,omitempty"`
+	UserAgent  string    `json:"userAgent,omitempty"`
+	StatusCode int       `json:"statusCode,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	Duration   float64   `json:"duration"`
+}
+
+// KubernetesRequest records the metadata of a request made with the
+// kubeconfig credential identified by token.  All the requests made with a
+// credential are recorded as JSON lines in a single recording; the recording's
+// name is derived from, but does not reveal, the token.
+func (r *Recorder) KubernetesRequest(token string, req *KubernetesRequest) {
+	b, err := json.Marshal(req)
+	if err != nil {
+		r.log.Error(err)
+		return
+	}
+
+	h := sha256.Sum256([]byte(token))
+
+	r.write(recordingName("kubeconfig", req.Session, hex.EncodeToString(h[:16]), ".jsonl"), append(b, '\n'))
+}
@@ -0,0 +1,201 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	"github.com/Azure/ARO-RP/pkg/util/recover"
+)
+
+// Recordings are buffered in memory and periodically appended to the store as
+// chunks.  Each chunk is sealed separately and written as a base64 encoded
+// line, so that a recording can be decrypted by opening each line in turn and
+// concatenating the results (see Decode).  A recording is therefore readable up
+// to its last flush even if the portal goes away mid-session.
+
+const (
+	flushInterval = 10 * time.Second
+
+	// maxChunkSize bounds the plaintext size of a single appended chunk, well
+	// below the maximum append block size
+	maxChunkSize = 1024 * 1024
+
+	// maxBufferSize bounds how much of a recording is held in memory while the
+	// store is failing; beyond this, data is dropped
+	maxBufferSize = 64 * 1024 * 1024
+)
+
+// Store appends data to named recordings, creating them if needed
+type Store interface {
+	Append(ctx context.Context, name string, b []byte) error
+}
+
+// Session identifies who accessed which cluster.  It is attached to every
+// recording made with a credential issued by the portal.
+type Session struct {
+	Username   string `json:"username"`
+	ResourceID string `json:"resourceId"`
+}
+
+type Recorder struct {
+	log   *logrus.Entry
+	aead  encryption.AEAD
+	store Store
+
+	mu      sync.Mutex
+	buffers map[string]*bytes.Buffer
+	full    chan struct{}
+
+	now func() time.Time
+}
+
+// NewRecorder returns a Recorder which seals recordings with aead and writes
+// them to store.  Run must be called for recordings to be written.
+func NewRecorder(log *logrus.Entry, aead encryption.AEAD, store Store) *Recorder {
+	return &Recorder{
+		log:   log,
+		aead:  aead,
+		store: store,
+
+		buffers: map[string]*bytes.Buffer{},
+		full:    make(chan struct{}, 1),
+
+		now: time.Now,
+	}
+}
+
+// Run flushes buffered recordings every flushInterval, or sooner if a buffer
+// grows beyond maxChunkSize, until ctx is done.  It then flushes once more.
+func (r *Recorder) Run(ctx context.Context) {
+	defer recover.Panic(r.log)
+
+	t := time.NewTicker(flushInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// ctx is done, so give the final flush its own deadline
+			flushCtx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			defer cancel()
+
+			r.Flush(flushCtx)
+			return
+		case <-t.C:
+		case <-r.full:
+		}
+
+		r.Flush(ctx)
+	}
+}
+
+// write buffers b, which must hold whole lines, for the named recording
+func (r *Recorder) write(name string, b []byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	buf := r.buffers[name]
+	if buf == nil {
+		buf = &bytes.Buffer{}
+		r.buffers[name] = buf
+	}
+
+	if buf.Len()+len(b) > maxBufferSize {
+		r.log.Errorf("recording %s: buffer full, dropping %d bytes", name, len(b))
+		return
+	}
+
+	buf.Write(b)
+
+	if buf.Len() >= maxChunkSize {
+		select {
+		case r.full <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Flush seals and appends all buffered data.  Data which could not be written
+// is kept for the next flush.
+func (r *Recorder) Flush(ctx context.Context) {
+	r.mu.Lock()
+	buffers := r.buffers
+	r.buffers = map[string]*bytes.Buffer{}
+	r.mu.Unlock()
+
+	names := make([]string, 0, len(buffers))
+	for name := range buffers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		buf := buffers[name]
+
+		err := r.flush(ctx, name, buf)
+		if err != nil {
+			r.log.Errorf("recording %s: %v", name, err)
+			r.requeue(name, buf)
+		}
+	}
+}
+
+// flush appends buf to the named recording in chunks of at most maxChunkSize.
+// On error, buf holds the data which was not written.
+func (r *Recorder) flush(ctx context.Context, name string, buf *bytes.Buffer) error {
+	for buf.Len() > 0 {
+		chunk := buf.Bytes()
+		if len(chunk) > maxChunkSize {
+			chunk = chunk[:maxChunkSize]
+		}
+
+		b, err := r.aead.Seal(chunk)
+		if err != nil {
+			return err
+		}
+
+		err = r.store.Append(ctx, name, []byte(base64.StdEncoding.EncodeToString(b)+"\n"))
+		if err != nil {
+			return err
+		}
+
+		buf.Next(len(chunk))
+	}
+
+	return nil
+}
+
+// requeue puts unwritten data back in front of anything buffered since
+func (r *Recorder) requeue(name string, buf *bytes.Buffer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if newer := r.buffers[name]; newer != nil {
+		buf.Write(newer.Bytes())
+	}
+
+	if buf.Len() > maxBufferSize {
+		r.log.Errorf("recording %s: buffer full, dropping %d bytes", name, buf.Len())
+		delete(r.buffers, name)
+		return
+	}
+
+	r.buffers[name] = buf
+}
+
+// recordingName returns the name of a recording of a session:
+// <kind>/<resource id>/<id><ext>
+func recordingName(kind string, session *Session, id, ext string) string {
+	return kind + "/" + strings.TrimPrefix(strings.ToLower(session.ResourceID), "/") + "/" + id + ext
+}
@@ -0,0 +1,148 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+type fakeStore struct {
+	blobs map[string]*bytes.Buffer
+	err   error
+}
+
+func (s *fakeStore) Append(ctx context.Context, name string, b []byte) error {
+	if s.err != nil {
+		return s.err
+	}
+
+	if s.blobs[name] == nil {
+		s.blobs[name] = &bytes.Buffer{}
+	}
+	s.blobs[name].Write(b)
+
+	return nil
+}
+
+func newTestRecorder(t *testing.T) (*Recorder, *fakeStore, encryption.AEAD) {
+	aead, err := encryption.NewXChaCha20Poly1305(context.Background(), make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := &fakeStore{blobs: map[string]*bytes.Buffer{}}
+
+	_, log := testlog.New()
+
+	r := NewRecorder(log, aead, store)
+	r.now = func() time.Time { return time.Unix(1700000000, 0) }
+
+	return r, store, aead
+}
+
+func decode(t *testing.T, store *fakeStore, aead encryption.AEAD, name string) string {
+	blob := store.blobs[name]
+	if blob == nil {
+		t.Fatalf("recording %s not found", name)
+	}
+
+	buf := &bytes.Buffer{}
+	err := Decode(buf, bytes.NewReader(blob.Bytes()), aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.String()
+}
+
+func TestFlush(t *testing.T) {
+	ctx := context.Background()
+	r, store, aead := newTestRecorder(t)
+
+	r.write("a", []byte("one\n"))
+	r.write("a", []byte("two\n"))
+	r.Flush(ctx)
+
+	// each flush appends one sealed line
+	if n := strings.Count(store.blobs["a"].String(), "\n"); n != 1 {
+		t.Errorf("got %d lines", n)
+	}
+
+	r.write("a", []byte("three\n"))
+
+	// writes which fail are retried on the next flush, in order
+	store.err = errors.New("unavailable")
+	r.Flush(ctx)
+	r.write("a", []byte("four\n"))
+	store.err = nil
+	r.Flush(ctx)
+
+	if got := decode(t, store, aead, "a"); got != "one\ntwo\nthree\nfour\n" {
+		t.Errorf("got %q", got)
+	}
+
+	if len(r.buffers) != 0 {
+		t.Errorf("got %d buffers", len(r.buffers))
+	}
+}
+
+func TestFlushChunks(t *testing.T) {
+	ctx := context.Background()
+	r, store, aead := newTestRecorder(t)
+
+	b := bytes.Repeat([]byte("x"), maxChunkSize+1)
+	r.write("a", b)
+
+	select {
+	case <-r.full:
+	default:
+		t.Error("expected flush to be requested")
+	}
+
+	r.Flush(ctx)
+
+	if n := strings.Count(store.blobs["a"].String(), "\n"); n != 2 {
+		t.Errorf("got %d lines", n)
+	}
+
+	if got := decode(t, store, aead, "a"); got != string(b) {
+		t.Errorf("got %d bytes", len(got))
+	}
+}
+
+func TestKubernetesRequest(t *testing.T) {
+	ctx := context.Background()
+	r, store, aead := newTestRecorder(t)
+
+	session := &Session{
+		Username:   "user@example.com",
+		ResourceID: "/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster",
+	}
+
+	r.KubernetesRequest("00000000-0000-0000-0000-000000000000", &KubernetesRequest{
+		Session:    session,
+		Time:       time.Unix(1700000000, 0).UTC(),
+		Method:     "GET",
+		Path:       "/api/v1/nodes",
+		StatusCode: 200,
+		Duration:   0.5,
+	})
+	r.Flush(ctx)
+
+	// the name is derived from the token, but does not include it
+	name := "kubeconfig/subscriptions/00000000-0000-0000-0000-000000000000/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster/12b9377cbe7e5c94e8a70d9d23929523.jsonl"
+
+	want := `{"username":"user@example.com","resourceId":"/subscriptions/00000000-0000-0000-0000-000000000000/resourceGroups/rg/providers/Microsoft.RedHatOpenShift/openShiftClusters/cluster","time":"2023-11-14T22:13:20Z","elevated":false,"method":"GET","path":"/api/v1/nodes","statusCode":200,"duration":0.5}` + "\n"
+	if got := decode(t, store, aead, name); got != want {
+		t.Errorf("got %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package recording
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	mgmtstorage "github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2019-06-01/storage"
+	azstorage "github.com/Azure/azure-sdk-for-go/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure"
+
+	"github.com/Azure/ARO-RP/pkg/env"
+	"github.com/Azure/ARO-RP/pkg/util/storage"
+)
+
+const (
+	recordingsContainer = "recordings"
+
+	// blobServiceRefresh is how long a SAS-authenticated blob client is reused
+	// for.  The SAS itself is valid for 24 hours.
+	blobServiceRefresh = 12 * time.Hour
+)
+
+// blobStore stores recordings as append blobs in the recordings container of
+// a storage account
+type blobStore struct {
+	storage       storage.Manager
+	resourceGroup string
+	account       string
+
+	mu          sync.Mutex
+	blobService *azstorage.BlobStorageClient
+	expires     time.Time
+}
+
+// NewBlobStore returns a Store which writes to the storage account with the
+// given resource ID
+func NewBlobStore(env env.Core, storageAccountID string, authorizer autorest.Authorizer) (Store, error) {
+	r, err := azure.ParseResourceID(storageAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &blobStore{
+		storage:       storage.NewManager(env, r.SubscriptionID, authorizer),
+		resourceGroup: r.ResourceGroup,
+		account:       r.ResourceName,
+	}, nil
+}
+
+func (s *blobStore) container(ctx context.Context) (*azstorage.Container, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.blobService == nil || time.Now().After(s.expires) {
+		blobService, err := s.storage.BlobService(ctx, s.resourceGroup, s.account, mgmtstorage.Permissions("acw"), mgmtstorage.SignedResourceTypes("co"))
+		if err != nil {
+			return nil, err
+		}
+
+		s.blobService = blobService
+		s.expires = time.Now().Add(blobServiceRefresh)
+	}
+
+	return s.blobService.GetContainerReference(recordingsContainer), nil
+}
+
+// Append appends b to the named append blob.  If the blob (or the container)
+// does not exist yet, it is created; another portal instance may race to
+// create the same blob, so creation never overwrites an existing blob.
+func (s *blobStore) Append(ctx context.Context, name string, b []byte) error {
+	container, err := s.container(ctx)
+	if err != nil {
+		return err
+	}
+
+	blob := container.GetBlobReference(name)
+
+	err = blob.AppendBlock(b, nil)
+	if !isStatusCode(err, http.StatusNotFound) {
+		return err
+	}
+
+	if serviceErr, ok := err.(azstorage.AzureStorageServiceError); ok && serviceErr.Code == "ContainerNotFound" {
+		_, err = container.CreateIfNotExists(nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = blob.PutAppendBlob(&azstorage.PutBlobOptions{IfNoneMatch: "*"})
+	if err != nil &&
+		!isStatusCode(err, http.StatusConflict) &&
+		!isStatusCode(err, http.StatusPreconditionFailed) {
+		return err
+	}
+
+	return blob.AppendBlock(b, nil)
+}
+
+func isStatusCode(err error, statusCode int) bool {
+	serviceErr, ok := err.(azstorage.AzureStorageServiceError)
+	return ok && serviceErr.StatusCode == statusCode
+}
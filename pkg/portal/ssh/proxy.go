@@ -18,6 +18,7 @@ import (
 	"golang.org/x/sync/errgroup"
 
 	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	utillog "github.com/Azure/ARO-RP/pkg/util/log"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 	"github.com/Azure/ARO-RP/pkg/util/uuid"
@@ -129,10 +130,12 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		return err
 	}
 
+	hostname := fmt.Sprintf("master-%d", portalDoc.Portal.SSH.Master)
+
 	// Log the incoming connection attempt.
 	accessLog := utillog.EnrichWithPath(s.baseAccessLog, portalDoc.Portal.ID)
 	accessLog = accessLog.WithFields(logrus.Fields{
-		"hostname":    fmt.Sprintf("master-%d", portalDoc.Portal.SSH.Master),
+		"hostname":    hostname,
 		"remote_addr": clientConn.RemoteAddr().String(),
 		"username":    portalDoc.Portal.Username,
 	})
@@ -189,13 +192,28 @@ func (s *SSH) newConn(ctx context.Context, clientConn net.Conn) error {
 		return err
 	}
 
+	// If session recording is enabled, each SRE->cluster session channel is
+	// recorded separately.
+	var newRecording func() *recording.Asciicast
+	if s.recorder != nil {
+		session := &recording.Session{
+			Username:   portalDoc.Portal.Username,
+			ResourceID: portalDoc.Portal.ID,
+		}
+
+		newRecording = func() *recording.Asciicast {
+			return s.recorder.NewAsciicast(session, hostname)
+		}
+	}
+
 	// Proxy channels and requests between the two connections.
-	return s.proxyConn(ctx, accessLog, keyring, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
+	return s.proxyConn(ctx, accessLog, keyring, newRecording, upstreamConn, downstreamConn, upstreamNewChannels, downstreamNewChannels, upstreamRequests, downstreamRequests)
 }
 
 // proxyConn handles incoming new channel and administrative requests.  It calls
-// newChannel to handle new channels, each on a new goroutine.
-func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
+// newChannel to handle new channels, each on a new goroutine.  newRecording is
+// nil unless SRE->cluster session channels are to be recorded.
+func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring agent.Agent, newRecording func() *recording.Asciicast, upstreamConn, downstreamConn cryptossh.Conn, upstreamNewChannels, downstreamNewChannels <-chan cryptossh.NewChannel, upstreamRequests, downstreamRequests <-chan *cryptossh.Request) error {
 	timer := time.NewTimer(sshTimeout)
 	defer timer.Stop()
 
@@ -219,8 +237,13 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 				sessionOpened = true
 			}
 
+			var channelRecording func() *recording.Asciicast
+			if nc.ChannelType() == "session" {
+				channelRecording = newRecording
+			}
+
 			go func() {
-				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession)
+				_ = s.newChannel(ctx, accessLog, nc, upstreamConn, downstreamConn, firstSession, channelRecording)
 			}()
 
 		case nc := <-downstreamNewChannels:
@@ -235,7 +258,7 @@ func (s *SSH) proxyConn(ctx context.Context, accessLog *logrus.Entry, keyring ag
 				}()
 			} else {
 				go func() {
-					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false)
+					_ = s.newChannel(ctx, accessLog, nc, downstreamConn, upstreamConn, false, nil)
 				}()
 			}
 
@@ -276,8 +299,9 @@ func (s *SSH) handleAgent(accessLog *logrus.Entry, nc cryptossh.NewChannel, keyr
 
 // newChannel handles an incoming request to create a new channel.  If the
 // channel creation is successful, it calls proxyChannel to proxy the channel
-// between SRE and cluster.
-func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool) error {
+// between SRE and cluster.  If newRecording is not nil, the channel is
+// recorded.
+func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc cryptossh.NewChannel, upstreamConn, downstreamConn cryptossh.Conn, firstSession bool, newRecording func() *recording.Asciicast) error {
 	defer recover.Panic(s.log)
 
 	ch2, rs2, err := downstreamConn.OpenChannel(nc.ChannelType(), nc.ExtraData())
@@ -295,6 +319,15 @@ func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc crypto
 	channelLog := accessLog.WithFields(logrus.Fields{
 		"channel": nc.ChannelType(),
 	})
+
+	var rec *recording.Asciicast
+	if newRecording != nil {
+		rec = newRecording()
+		defer rec.Close()
+
+		channelLog = channelLog.WithField("recording", rec.Name())
+	}
+
 	channelLog.Printf("opened")
 	defer channelLog.Printf("closed")
 
@@ -307,7 +340,7 @@ func (s *SSH) newChannel(ctx context.Context, accessLog *logrus.Entry, nc crypto
 		go s.keepAliveConn(ctx, ch1)
 	}
 
-	return s.proxyChannel(ch1, ch2, rs1, rs2)
+	return s.proxyChannel(ch1, ch2, rs1, rs2, rec)
 }
 
 func (s *SSH) proxyGlobalRequest(r *cryptossh.Request, c cryptossh.Conn) error {
@@ -328,15 +361,23 @@ func (s *SSH) proxyRequest(r *cryptossh.Request, ch cryptossh.Channel) error {
 	return r.Reply(ok, nil)
 }
 
-func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request) error {
+// proxyChannel proxies data and requests between ch1 (SRE) and ch2 (cluster).
+// If rec is not nil, the terminal I/O is recorded.
+func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptossh.Request, rec *recording.Asciicast) error {
 	g := errgroup.Group{}
 
+	var r1, r2 io.Reader = ch1, ch2
+	if rec != nil {
+		r1 = io.TeeReader(ch1, rec.Input())
+		r2 = io.TeeReader(ch2, rec.Output())
+	}
+
 	g.Go(func() error {
 		defer recover.Panic(s.log)
 		defer func() {
 			_ = ch1.CloseWrite()
 		}()
-		_, err := io.Copy(ch1, ch2)
+		_, err := io.Copy(ch1, r2)
 		if err != nil {
 			return err
 		}
@@ -348,7 +389,7 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer func() {
 			_ = ch2.CloseWrite()
 		}()
-		_, err := io.Copy(ch2, ch1)
+		_, err := io.Copy(ch2, r1)
 		if err != nil {
 			return err
 		}
@@ -359,6 +400,10 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 		defer recover.Panic(s.log)
 
 		for r := range rs1 {
+			if rec != nil {
+				recordRequest(rec, r)
+			}
+
 			err := s.proxyRequest(r, ch2)
 			if err != nil {
 				break
@@ -382,6 +427,46 @@ func (s *SSH) proxyChannel(ch1, ch2 cryptossh.Channel, rs1, rs2 <-chan *cryptoss
 	return g.Wait()
 }
 
+// recordRequest records the terminal size and any command from an SRE->cluster
+// session channel request (RFC 4254 section 6)
+func recordRequest(rec *recording.Asciicast, r *cryptossh.Request) {
+	switch r.Type {
+	case "pty-req":
+		var m struct {
+			Term                         string
+			Columns, Rows, Width, Height uint32
+			Modes                        string
+		}
+		if cryptossh.Unmarshal(r.Payload, &m) == nil {
+			rec.Pty(m.Term, int(m.Columns), int(m.Rows))
+		}
+
+	case "window-change":
+		var m struct {
+			Columns, Rows, Width, Height uint32
+		}
+		if cryptossh.Unmarshal(r.Payload, &m) == nil {
+			rec.Resize(int(m.Columns), int(m.Rows))
+		}
+
+	case "exec":
+		var m struct {
+			Command string
+		}
+		if cryptossh.Unmarshal(r.Payload, &m) == nil {
+			rec.Marker("exec: " + m.Command)
+		}
+
+	case "subsystem":
+		var m struct {
+			Name string
+		}
+		if cryptossh.Unmarshal(r.Payload, &m) == nil {
+			rec.Marker("subsystem: " + m.Name)
+		}
+	}
+}
+
 func (s *SSH) keepAliveConn(ctx context.Context, channel cryptossh.Channel) {
 	ticker := time.NewTicker(keepAliveInterval)
 	defer ticker.Stop()
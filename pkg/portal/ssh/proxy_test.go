@@ -23,6 +23,8 @@ import (
 
 	"github.com/Azure/ARO-RP/pkg/api"
 	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
+	"github.com/Azure/ARO-RP/pkg/util/encryption"
 	mock_proxy "github.com/Azure/ARO-RP/pkg/util/mocks/proxy"
 	utiltls "github.com/Azure/ARO-RP/pkg/util/tls"
 	testdatabase "github.com/Azure/ARO-RP/test/database"
@@ -413,7 +415,7 @@ func TestProxy(t *testing.T) {
 
 			hook, log := testlog.New()
 
-			s, err := New(nil, nil, log, nil, hostKey, nil, dbOpenShiftClusters, dbPortal, dialer)
+			s, err := New(nil, nil, log, nil, hostKey, nil, dbOpenShiftClusters, dbPortal, nil, dialer, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -454,3 +456,81 @@ func TestProxy(t *testing.T) {
 		})
 	}
 }
+
+type fakeStore map[string]*bytes.Buffer
+
+func (s fakeStore) Append(ctx context.Context, name string, b []byte) error {
+	if s[name] == nil {
+		s[name] = &bytes.Buffer{}
+	}
+	s[name].Write(b)
+	return nil
+}
+
+func TestRecordRequest(t *testing.T) {
+	ctx := context.Background()
+
+	aead, err := encryption.NewXChaCha20Poly1305(ctx, make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store := fakeStore{}
+	_, log := testlog.New()
+	recorder := recording.NewRecorder(log, aead, store)
+
+	rec := recorder.NewAsciicast(&recording.Session{}, "master-0")
+
+	for _, r := range []*cryptossh.Request{
+		{
+			Type: "pty-req",
+			Payload: cryptossh.Marshal(struct {
+				Term                         string
+				Columns, Rows, Width, Height uint32
+				Modes                        string
+			}{"xterm", 120, 40, 0, 0, ""}),
+		},
+		{
+			Type:    "env",
+			Payload: cryptossh.Marshal(struct{ Name, Value string }{"LANG", "C"}),
+		},
+		{
+			Type:    "exec",
+			Payload: cryptossh.Marshal(struct{ Command string }{"uptime"}),
+		},
+		{
+			Type:    "window-change",
+			Payload: cryptossh.Marshal(struct{ Columns, Rows, Width, Height uint32 }{100, 30, 0, 0}),
+		},
+		{
+			Type:    "window-change",
+			Payload: []byte("invalid"),
+		},
+	} {
+		recordRequest(rec, r)
+	}
+
+	rec.Close()
+	recorder.Flush(ctx)
+
+	buf := &bytes.Buffer{}
+	err = recording.Decode(buf, bytes.NewReader(store[rec.Name()].Bytes()), aead)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %q", lines)
+	}
+
+	for i, want := range []string{
+		`{"version":2,"width":120,"height":40,`,
+		`"m","exec: uptime"]`,
+		`"r","100x30"]`,
+	} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d: got %q, want %q", i, lines[i], want)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/Azure/ARO-RP/pkg/database"
 	"github.com/Azure/ARO-RP/pkg/env"
 	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	"github.com/Azure/ARO-RP/pkg/portal/recording"
 	"github.com/Azure/ARO-RP/pkg/proxy"
 )
 
@@ -42,8 +43,15 @@ type SSH struct {
 	dbOpenShiftClusters database.OpenShiftClusters
 	dbPortal            database.Portal
 
+	// dbPortalAccessGrants is nil if elevated access does not require an
+	// approved access grant
+	dbPortalAccessGrants database.PortalAccessGrants
+
 	dialer proxy.Dialer
 
+	// recorder records sessions; nil if session recording is disabled
+	recorder *recording.Recorder
+
 	baseServerConfig *cryptossh.ServerConfig
 
 	hostPubKey cryptossh.PublicKey
@@ -57,7 +65,9 @@ func New(env env.Core,
 	elevatedGroupIDs []string,
 	dbOpenShiftClusters database.OpenShiftClusters,
 	dbPortal database.Portal,
+	dbPortalAccessGrants database.PortalAccessGrants,
 	dialer proxy.Dialer,
+	recorder *recording.Recorder,
 ) (*SSH, error) {
 	hostPubKey, err := cryptossh.NewPublicKey(&hostKey.PublicKey)
 	if err != nil {
@@ -75,8 +85,12 @@ func New(env env.Core,
 		dbOpenShiftClusters: dbOpenShiftClusters,
 		dbPortal:            dbPortal,
 
+		dbPortalAccessGrants: dbPortalAccessGrants,
+
 		dialer: dialer,
 
+		recorder: recorder,
+
 		baseServerConfig: &cryptossh.ServerConfig{},
 
 		hostPubKey: hostPubKey,
@@ -138,6 +152,19 @@ func (s *SSH) New(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if s.dbPortalAccessGrants != nil {
+		grant, err := s.dbPortalAccessGrants.GetActive(ctx, ctx.Value(middleware.ContextKeyUsername).(string), resourceID, time.Now())
+		if err != nil {
+			s.internalServerError(w, err)
+			return
+		}
+
+		if grant == nil {
+			s.sendResponse(w, "", "", "", "An approved access grant for this cluster is required.", s.env.IsLocalDevelopmentMode())
+			return
+		}
+	}
+
 	username := r.Context().Value(middleware.ContextKeyUsername).(string)
 	username = strings.SplitN(username, "@", 2)[0]
 
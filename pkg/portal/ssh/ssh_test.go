@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/gorilla/mux"
@@ -44,12 +45,20 @@ func TestNew(t *testing.T) {
 	}
 	khline := knownhosts.Line([]string{"localhost"}, hostPubKey)
 
+	successBody := `{
+    "command": "echo '` + khline + `' > localhost_known_host ; ssh -o UserKnownHostsFile=localhost_known_host username@localhost",
+    "password": "03030303-0303-0303-0303-030303030001"
+}
+`
+
 	for _, tt := range []struct {
-		name           string
-		r              func(*http.Request)
-		checker        func(*testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
-		wantStatusCode int
-		wantBody       string
+		name                string
+		r                   func(*http.Request)
+		requireAccessGrants bool
+		accessGrants        []*api.PortalAccessGrantDocument
+		checker             func(*testdatabase.Checker, *cosmosdb.FakePortalDocumentClient)
+		wantStatusCode      int
+		wantBody            string
 	}{
 		{
 			name: "success",
@@ -67,11 +76,72 @@ func TestNew(t *testing.T) {
 				})
 			},
 			wantStatusCode: http.StatusOK,
-			wantBody: `{
-    "command": "echo '` + khline + `' > localhost_known_host ; ssh -o UserKnownHostsFile=localhost_known_host username@localhost",
-    "password": "03030303-0303-0303-0303-030303030001"
-}
-`,
+			wantBody:       successBody,
+		},
+		{
+			name:                "success with access grant",
+			requireAccessGrants: true,
+			accessGrants: []*api.PortalAccessGrantDocument{
+				{
+					PortalAccessGrant: &api.PortalAccessGrant{
+						Username:   username,
+						ResourceID: resourceID,
+						State:      api.PortalAccessGrantStateApproved,
+						NotBefore:  time.Now().Add(-time.Hour),
+						NotAfter:   time.Now().Add(time.Hour),
+					},
+				},
+			},
+			checker: func(checker *testdatabase.Checker, portalClient *cosmosdb.FakePortalDocumentClient) {
+				checker.AddPortalDocuments(&api.PortalDocument{
+					ID:  password,
+					TTL: 60,
+					Portal: &api.Portal{
+						Username: username,
+						ID:       resourceID,
+						SSH: &api.SSH{
+							Master: master,
+						},
+					},
+				})
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       successBody,
+		},
+		{
+			name:                "no active access grant",
+			requireAccessGrants: true,
+			accessGrants: []*api.PortalAccessGrantDocument{
+				{
+					PortalAccessGrant: &api.PortalAccessGrant{
+						Username:   username,
+						ResourceID: resourceID,
+						State:      api.PortalAccessGrantStatePending,
+						NotBefore:  time.Now().Add(-time.Hour),
+						NotAfter:   time.Now().Add(time.Hour),
+					},
+				},
+				{
+					PortalAccessGrant: &api.PortalAccessGrant{
+						Username:   username,
+						ResourceID: resourceID,
+						State:      api.PortalAccessGrantStateApproved,
+						NotBefore:  time.Now().Add(-2 * time.Hour),
+						NotAfter:   time.Now().Add(-time.Hour),
+					},
+				},
+				{
+					PortalAccessGrant: &api.PortalAccessGrant{
+						Username:   username,
+						ResourceID: resourceID + "2",
+						State:      api.PortalAccessGrantStateApproved,
+						NotBefore:  time.Now().Add(-time.Hour),
+						NotAfter:   time.Now().Add(time.Hour),
+					},
+				},
+			},
+			wantStatusCode: http.StatusOK,
+			wantBody:       "{\n    \"error\": \"An approved access grant for this cluster is required.\"\n}\n",
 		},
 		{
 			name: "bad path",
@@ -126,6 +196,20 @@ func TestNew(t *testing.T) {
 			ctx := context.Background()
 
 			dbPortal, portalClient := testdatabase.NewFakePortal()
+			dbPortalAccessGrants, _ := testdatabase.NewFakePortalAccessGrants()
+
+			fixture := testdatabase.NewFixture().
+				WithPortalAccessGrants(dbPortalAccessGrants)
+			fixture.AddPortalAccessGrantDocuments(tt.accessGrants...)
+
+			err := fixture.Create()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !tt.requireAccessGrants {
+				dbPortalAccessGrants = nil
+			}
 
 			checker := testdatabase.NewChecker()
 
@@ -149,7 +233,7 @@ func TestNew(t *testing.T) {
 			env := mock_env.NewMockCore(ctrl)
 			env.EXPECT().IsLocalDevelopmentMode().AnyTimes().Return(false)
 
-			s, err := New(env, logrus.NewEntry(logrus.StandardLogger()), nil, nil, hostKey, elevatedGroupIDs, nil, dbPortal, nil)
+			s, err := New(env, logrus.NewEntry(logrus.StandardLogger()), nil, nil, hostKey, elevatedGroupIDs, nil, dbPortal, dbPortalAccessGrants, nil, nil)
 			if err != nil {
 				t.Fatal(err)
 			}
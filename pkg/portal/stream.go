@@ -0,0 +1,184 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"github.com/Azure/ARO-RP/pkg/portal/cluster"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+)
+
+const (
+	// streamTimeout bounds how long a single stream is held open; browsers'
+	// EventSource reconnects automatically
+	streamTimeout = time.Hour
+
+	// streamKeepalive is how often a comment is sent on an otherwise idle
+	// stream, so that intermediaries don't time it out
+	streamKeepalive = 30 * time.Second
+)
+
+// sseWriter writes server-sent events
+type sseWriter struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+	f  http.Flusher
+}
+
+func newSSEWriter(w http.ResponseWriter) (*sseWriter, error) {
+	f, ok := w.(http.Flusher)
+	if !ok {
+		return nil, fmt.Errorf("streaming is not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	f.Flush()
+
+	return &sseWriter{w: w, f: f}, nil
+}
+
+// send writes v as the JSON encoded data of an event of the given type
+func (s *sseWriter) send(event string, v interface{}) error {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	return s.write(fmt.Sprintf("event: %s\ndata: %s\n\n", event, b))
+}
+
+func (s *sseWriter) write(msg string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := s.w.Write([]byte(msg))
+	if err != nil {
+		return err
+	}
+
+	s.f.Flush()
+	return nil
+}
+
+// keepalive sends a comment every interval until ctx is done
+func (s *sseWriter) keepalive(ctx context.Context, interval time.Duration) {
+	t := time.NewTicker(interval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			if s.write(":\n\n") != nil {
+				return
+			}
+		}
+	}
+}
+
+// stream serves a server-sent event stream fed by f.  Errors which occur
+// before the stream starts are returned as HTTP errors; later errors are sent
+// as an "error" event which ends the stream.
+func (p *portal) stream(w http.ResponseWriter, r *http.Request, f func(context.Context, cluster.FetchClient, *sseWriter) error) {
+	ctx, cancel := context.WithTimeout(r.Context(), streamTimeout)
+	defer cancel()
+
+	fetcher, err := p.makeFetcher(ctx, r)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	s, err := newSSEWriter(w)
+	if err != nil {
+		p.internalServerError(w, err)
+		return
+	}
+
+	// the keepalive must stop writing before the handler returns
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.keepalive(ctx, streamKeepalive)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	err = f(ctx, fetcher, s)
+	if err != nil && ctx.Err() == nil {
+		p.log.Warn(err)
+		_ = s.send("error", err.Error())
+	}
+}
+
+func watchEventSender(s *sseWriter) func(*cluster.WatchEvent) error {
+	return func(event *cluster.WatchEvent) error {
+		return s.send("watch", event)
+	}
+}
+
+func (p *portal) streamNodes(w http.ResponseWriter, r *http.Request) {
+	p.stream(w, r, func(ctx context.Context, fetcher cluster.FetchClient, s *sseWriter) error {
+		return fetcher.WatchNodes(ctx, watchEventSender(s))
+	})
+}
+
+func (p *portal) streamMachines(w http.ResponseWriter, r *http.Request) {
+	p.stream(w, r, func(ctx context.Context, fetcher cluster.FetchClient, s *sseWriter) error {
+		return fetcher.WatchMachines(ctx, watchEventSender(s))
+	})
+}
+
+func (p *portal) streamEvents(w http.ResponseWriter, r *http.Request) {
+	namespace := r.URL.Query().Get("namespace")
+
+	p.stream(w, r, func(ctx context.Context, fetcher cluster.FetchClient, s *sseWriter) error {
+		return fetcher.WatchEvents(ctx, namespace, watchEventSender(s))
+	})
+}
+
+// streamPodLogs streams a pod's logs.  Logs may hold sensitive data, so like
+// kubeconfig and SSH access this requires membership of an elevated group.
+func (p *portal) streamPodLogs(w http.ResponseWriter, r *http.Request) {
+	groups, _ := r.Context().Value(middleware.ContextKeyGroups).([]string)
+	if len(middleware.GroupsIntersect(p.elevatedGroupIDs, groups)) == 0 {
+		http.Error(w, "Elevated access is required.", http.StatusForbidden)
+		return
+	}
+
+	apiVars := mux.Vars(r)
+	options := &cluster.PodLogOptions{
+		Container: r.URL.Query().Get("container"),
+	}
+
+	if tailLines := r.URL.Query().Get("tailLines"); tailLines != "" {
+		n, err := strconv.ParseInt(tailLines, 10, 64)
+		if err != nil || n < 0 {
+			p.badRequest(w, fmt.Errorf("invalid tailLines %q", tailLines))
+			return
+		}
+		options.TailLines = &n
+	}
+
+	p.stream(w, r, func(ctx context.Context, fetcher cluster.FetchClient, s *sseWriter) error {
+		return fetcher.PodLogs(ctx, apiVars["namespace"], apiVars["pod"], options, func(line string) error {
+			return s.send("log", line)
+		})
+	})
+}
@@ -0,0 +1,85 @@
+package portal
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Azure/ARO-RP/pkg/portal/cluster"
+	"github.com/Azure/ARO-RP/pkg/portal/middleware"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func TestSSEWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+
+	s, err := newSSEWriter(w)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.send("watch", &cluster.WatchEvent{Type: "ADDED", Object: "node"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = s.send("log", "line\nwith newline")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("got Content-Type %q", ct)
+	}
+
+	want := "event: watch\ndata: {\"type\":\"ADDED\",\"object\":\"node\"}\n\n" +
+		"event: log\ndata: \"line\\nwith newline\"\n\n"
+	if w.Body.String() != want {
+		t.Errorf("got %q, want %q", w.Body.String(), want)
+	}
+}
+
+func TestStreamPodLogsElevation(t *testing.T) {
+	_, log := testlog.New()
+
+	for _, tt := range []struct {
+		name     string
+		groups   []string
+		url      string
+		wantCode int
+	}{
+		{
+			name:     "not elevated",
+			groups:   []string{"00000000-0000-0000-0000-000000000000"},
+			url:      "/?tailLines=10",
+			wantCode: http.StatusForbidden,
+		},
+		{
+			name:     "elevated, invalid tailLines",
+			groups:   []string{"00000000-0000-0000-0000-000000000001"},
+			url:      "/?tailLines=-1",
+			wantCode: http.StatusBadRequest,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &portal{
+				log:              log,
+				elevatedGroupIDs: []string{"00000000-0000-0000-0000-000000000001"},
+			}
+
+			r := httptest.NewRequest(http.MethodGet, tt.url, nil)
+			r = r.WithContext(context.WithValue(r.Context(), middleware.ContextKeyGroups, tt.groups))
+			w := httptest.NewRecorder()
+
+			p.streamPodLogs(w, r)
+
+			if w.Code != tt.wantCode {
+				t.Errorf("got %d, want %d", w.Code, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package proxy
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// destinationPattern matches CONNECT destinations.  It is parsed from a
+// host:port pattern where host is a hostname, a "*." prefixed wildcard
+// hostname, an IP address or a CIDR, and port is a port number, a lo-hi port
+// range or "*".
+type destinationPattern struct {
+	host   string
+	subnet *net.IPNet
+	portLo int
+	portHi int
+}
+
+func parseDestinationPattern(pattern string) (*destinationPattern, error) {
+	i := strings.LastIndexByte(pattern, ':')
+	if i == -1 {
+		return nil, fmt.Errorf("invalid allowlist pattern %q: missing port", pattern)
+	}
+	host, port := strings.ToLower(strings.Trim(pattern[:i], "[]")), pattern[i+1:]
+
+	p := &destinationPattern{}
+
+	switch {
+	case strings.Contains(host, "/"):
+		_, subnet, err := net.ParseCIDR(host)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowlist pattern %q: %w", pattern, err)
+		}
+		p.subnet = subnet
+	case host == "" || host == "*." || strings.Contains(strings.TrimPrefix(host, "*."), "*"):
+		return nil, fmt.Errorf("invalid allowlist pattern %q: invalid host", pattern)
+	default:
+		p.host = host
+	}
+
+	if port == "*" {
+		p.portLo, p.portHi = 1, 65535
+		return p, nil
+	}
+
+	lo, hi, isRange := strings.Cut(port, "-")
+	if !isRange {
+		hi = lo
+	}
+
+	var err error
+	p.portLo, err = strconv.Atoi(lo)
+	if err == nil {
+		p.portHi, err = strconv.Atoi(hi)
+	}
+	if err != nil || p.portLo < 1 || p.portHi > 65535 || p.portLo > p.portHi {
+		return nil, fmt.Errorf("invalid allowlist pattern %q: invalid port", pattern)
+	}
+
+	return p, nil
+}
+
+func (p *destinationPattern) matches(host string, port int) bool {
+	if port < p.portLo || port > p.portHi {
+		return false
+	}
+
+	host = strings.ToLower(host)
+
+	switch {
+	case p.subnet != nil:
+		ip := net.ParseIP(host)
+		return ip != nil && p.subnet.Contains(ip)
+	case strings.HasPrefix(p.host, "*."):
+		return strings.HasSuffix(host, p.host[1:])
+	default:
+		return host == p.host
+	}
+}
+
+func parseAllowlist(patterns []string) ([]*destinationPattern, error) {
+	allowlist := make([]*destinationPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		p, err := parseDestinationPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		allowlist = append(allowlist, p)
+	}
+	return allowlist, nil
+}
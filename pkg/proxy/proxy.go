@@ -4,29 +4,46 @@ package proxy
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
 	"errors"
+	"fmt"
 	"io"
 	"net"
 	"net/http"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
 	utilnet "github.com/Azure/ARO-RP/pkg/util/net"
 	"github.com/Azure/ARO-RP/pkg/util/recover"
 )
 
 type Server struct {
 	Log *logrus.Entry
+	M   metrics.Emitter
 
-	CertFile       string
-	KeyFile        string
-	ClientCertFile string
-	Subnet         string
-	subnet         *net.IPNet
+	CertFile string
+	KeyFile  string
+	// ClientCertFile enables mutual TLS: only clients presenting this
+	// certificate may connect.  It is required unless InsecureNoClientAuth is
+	// set.
+	ClientCertFile       string
+	InsecureNoClientAuth bool
+	Subnet               string
+	// Allowlist, if not empty, restricts CONNECT destinations to those
+	// matching one of its host:port patterns (see destinationPattern).
+	Allowlist []string
+
+	subnet    *net.IPNet
+	allowlist []*destinationPattern
+	lookupIP  func(ctx context.Context, network, host string) ([]net.IP, error)
 }
 
 func (s *Server) Run() error {
@@ -36,25 +53,27 @@ func (s *Server) Run() error {
 	}
 	s.subnet = subnet
 
-	b, err := os.ReadFile(s.ClientCertFile)
+	s.allowlist, err = parseAllowlist(s.Allowlist)
 	if err != nil {
 		return err
 	}
 
-	clientCert, err := x509.ParseCertificate(b)
-	if err != nil {
-		return err
+	if s.ClientCertFile == "" && !s.InsecureNoClientAuth {
+		return errors.New("no client certificate configured; refusing to start without mutual TLS")
 	}
 
-	pool := x509.NewCertPool()
-	pool.AddCert(clientCert)
+	if s.M == nil {
+		s.M = &noop.Noop{}
+	}
+
+	s.lookupIP = net.DefaultResolver.LookupIP
 
 	cert, err := os.ReadFile(s.CertFile)
 	if err != nil {
 		return err
 	}
 
-	b, err = os.ReadFile(s.KeyFile)
+	b, err := os.ReadFile(s.KeyFile)
 	if err != nil {
 		return err
 	}
@@ -64,7 +83,7 @@ func (s *Server) Run() error {
 		return err
 	}
 
-	l, err := tls.Listen("tcp", ":8443", &tls.Config{
+	config := &tls.Config{
 		Certificates: []tls.Certificate{
 			{
 				Certificate: [][]byte{
@@ -73,15 +92,33 @@ func (s *Server) Run() error {
 				PrivateKey: key,
 			},
 		},
-		ClientCAs:              pool,
-		ClientAuth:             tls.RequireAndVerifyClientCert,
 		SessionTicketsDisabled: true,
 		MinVersion:             tls.VersionTLS12,
 		CurvePreferences: []tls.CurveID{
 			tls.CurveP256,
 			tls.X25519,
 		},
-	})
+	}
+
+	if s.ClientCertFile != "" {
+		b, err := os.ReadFile(s.ClientCertFile)
+		if err != nil {
+			return err
+		}
+
+		clientCert, err := x509.ParseCertificate(b)
+		if err != nil {
+			return err
+		}
+
+		config.ClientCAs = x509.NewCertPool()
+		config.ClientCAs.AddCert(clientCert)
+		config.ClientAuth = tls.RequireAndVerifyClientCert
+	} else {
+		s.Log.Warn("no client certificate configured, mutual TLS is disabled")
+	}
+
+	l, err := tls.Listen("tcp", ":8443", config)
 	if err != nil {
 		return err
 	}
@@ -89,42 +126,135 @@ func (s *Server) Run() error {
 	return http.Serve(l, http.HandlerFunc(s.proxyHandler))
 }
 
-func (s Server) proxyHandler(w http.ResponseWriter, r *http.Request) {
-	err := s.validateProxyRequest(w, r)
+// proxyHandler validates and proxies a CONNECT request, writing a structured
+// audit log entry for the decision and, if allowed, for the finished
+// connection.
+func (s *Server) proxyHandler(w http.ResponseWriter, r *http.Request) {
+	log := s.Log.WithFields(logrus.Fields{
+		"client":      r.RemoteAddr,
+		"destination": r.Host,
+	})
+	if r.TLS != nil && len(r.TLS.PeerCertificates) > 0 {
+		log = log.WithField("clientCertificate", r.TLS.PeerCertificates[0].Subject.String())
+	}
+
+	address, err := s.validateProxyRequest(w, r)
 	if err != nil {
+		log.WithField("reason", err.Error()).Print("access denied")
+		s.M.EmitGauge("proxy.connections", 1, map[string]string{
+			"action": "denied",
+		})
 		return
 	}
-	Proxy(s.Log, w, r, 0)
+
+	log.Print("access allowed")
+	s.M.EmitGauge("proxy.connections", 1, map[string]string{
+		"action": "allowed",
+	})
+
+	t := time.Now()
+	sent, received := Proxy(s.Log, w, address, 0)
+	duration := time.Since(t)
+
+	log.WithFields(logrus.Fields{
+		"bytesSent":     sent,
+		"bytesReceived": received,
+		"duration":      duration.Seconds(),
+	}).Print("connection closed")
+
+	s.M.EmitGauge("proxy.connections.bytes", sent, map[string]string{
+		"direction": "sent",
+	})
+	s.M.EmitGauge("proxy.connections.bytes", received, map[string]string{
+		"direction": "received",
+	})
+	s.M.EmitGauge("proxy.connections.duration", duration.Milliseconds(), nil)
 }
 
-// validateProxyRequest checks that the request is valid. If not, it writes the
-// appropriate http headers and returns an error.
-func (s Server) validateProxyRequest(w http.ResponseWriter, r *http.Request) error {
-	ip, _, err := net.SplitHostPort(r.Host)
+// validateProxyRequest checks that the request is valid and returns the
+// address to dial.  Hostnames are resolved here and the address returned
+// contains the resolved IP, so that the destination which was checked against
+// the subnet is the one which is dialled.  If the request is not valid, it
+// writes the appropriate http headers and returns an error.
+func (s *Server) validateProxyRequest(w http.ResponseWriter, r *http.Request) (string, error) {
+	host, port, err := net.SplitHostPort(r.Host)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
-		return err
+		return "", err
 	}
 
 	if r.Method != http.MethodConnect {
 		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
-		return errors.New("request is not valid, method is not CONNECT")
+		return "", errors.New("request is not valid, method is not CONNECT")
 	}
 
-	if !s.subnet.Contains(net.ParseIP(ip)) {
+	ip := net.ParseIP(host)
+	if ip != nil && !s.subnet.Contains(ip) {
 		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
-		return errors.New("request is not allowed, the originating IP is not part of the allowed subnet")
+		return "", errors.New("request is not allowed, the originating IP is not part of the allowed subnet")
+	}
+
+	// hostnames can only be reached if they are explicitly allowlisted
+	if ip == nil && len(s.allowlist) == 0 {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return "", errors.New("request is not allowed, the destination is not an IP address")
+	}
+
+	if len(s.allowlist) > 0 && !s.isAllowlisted(host, port) {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return "", errors.New("request is not allowed, the destination is not in the allowlist")
+	}
+
+	if ip == nil {
+		ip, err = s.resolve(r.Context(), host)
+		if err != nil {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return "", err
+		}
+	}
+
+	return net.JoinHostPort(ip.String(), port), nil
+}
+
+// resolve returns the first IP address of host which is part of the allowed
+// subnet
+func (s *Server) resolve(ctx context.Context, host string) (net.IP, error) {
+	ips, err := s.lookupIP(ctx, "ip", host)
+	if err != nil {
+		return nil, fmt.Errorf("request is not allowed, the destination could not be resolved: %w", err)
+	}
+
+	for _, ip := range ips {
+		if s.subnet.Contains(ip) {
+			return ip, nil
+		}
+	}
+
+	return nil, errors.New("request is not allowed, the destination does not resolve to an IP in the allowed subnet")
+}
+
+func (s *Server) isAllowlisted(host, port string) bool {
+	p, err := strconv.Atoi(port)
+	if err != nil {
+		return false
+	}
+
+	for _, pattern := range s.allowlist {
+		if pattern.matches(host, p) {
+			return true
+		}
 	}
 
-	return nil
+	return false
 }
 
-// Proxy takes an HTTP/1.x CONNECT Request and ResponseWriter from the Golang
+// Proxy takes the ResponseWriter of an HTTP/1.x CONNECT Request from the Golang
 // HTTP stack and uses Hijack() to get the underlying Connection (c1).  It dials
-// a second Connection (c2) to the requested end Host and then copies data in
-// both directions (c1->c2 and c2->c1).
-func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
-	c2, err := utilnet.Dial("tcp", r.Host, sz)
+// a second Connection (c2) to the validated address of the requested end Host
+// and then copies data in both directions (c1->c2 and c2->c1).  It returns the
+// number of bytes copied from c1 to c2 and from c2 to c1 respectively.
+func Proxy(log *logrus.Entry, w http.ResponseWriter, address string, sz int) (sent, received int64) {
+	c2, err := utilnet.Dial("tcp", address, sz)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -168,7 +298,7 @@ func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
 				conn2.CloseWrite()
 			}
 		}()
-		_, _ = io.Copy(c2, buf)
+		sent, _ = io.Copy(c2, buf)
 	}()
 
 	// copy from c2->c1.  Call c1.CloseWrite() when done.
@@ -178,5 +308,6 @@ func Proxy(log *logrus.Entry, w http.ResponseWriter, r *http.Request, sz int) {
 			closeWriter.CloseWrite()
 		}
 	}()
-	_, _ = io.Copy(c1, c2)
+	received, _ = io.Copy(c1, c2)
+	return
 }
@@ -4,11 +4,21 @@ package proxy
 // Licensed under the Apache License 2.0.
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/onsi/gomega"
+	"github.com/onsi/gomega/types"
+	"github.com/sirupsen/logrus"
+
+	mock_metrics "github.com/Azure/ARO-RP/pkg/util/mocks/metrics"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
 )
 
 func TestRequestValidation(t *testing.T) {
@@ -66,7 +76,7 @@ func TestRequestValidation(t *testing.T) {
 			recorder := httptest.NewRecorder()
 			request := httptest.NewRequest(tt.method, tt.hostname, nil)
 
-			err = server.validateProxyRequest(recorder, request)
+			_, err = server.validateProxyRequest(recorder, request)
 			if (err != nil && !tt.wantErr) || (err == nil && tt.wantErr) {
 				t.Error(err)
 			}
@@ -80,3 +90,131 @@ func TestRequestValidation(t *testing.T) {
 		})
 	}
 }
+
+func TestAllowlist(t *testing.T) {
+	allowlist, err := parseAllowlist([]string{
+		"10.0.0.0/24:6443",
+		"10.0.1.5:22",
+		"*.example.com:443",
+		"api.example.org:8000-8999",
+		"[fd00::1]:*",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lookupIP := func(ctx context.Context, network, host string) ([]net.IP, error) {
+		switch host {
+		case "api.example.com":
+			return []net.IP{net.ParseIP("192.168.0.2"), net.ParseIP("10.0.2.1")}, nil
+		case "api.example.org":
+			return []net.IP{net.ParseIP("10.0.3.1")}, nil
+		case "evil.example.com":
+			return []net.IP{net.ParseIP("192.168.0.3")}, nil
+		}
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+
+	for _, tt := range []struct {
+		host        string
+		wantStatus  int
+		wantAddress string
+	}{
+		{host: "10.0.0.4:6443", wantStatus: http.StatusOK, wantAddress: "10.0.0.4:6443"},
+		{host: "10.0.0.4:22", wantStatus: http.StatusForbidden},
+		{host: "10.0.1.5:22", wantStatus: http.StatusOK, wantAddress: "10.0.1.5:22"},
+		{host: "10.0.1.6:22", wantStatus: http.StatusForbidden},
+		{host: "192.168.0.1:6443", wantStatus: http.StatusForbidden},
+		{host: "api.example.com:443", wantStatus: http.StatusOK, wantAddress: "10.0.2.1:443"},
+		{host: "evil.example.com:443", wantStatus: http.StatusForbidden},
+		{host: "missing.example.com:443", wantStatus: http.StatusForbidden},
+		{host: "example.com:443", wantStatus: http.StatusForbidden},
+		{host: "api.example.org:8443", wantStatus: http.StatusOK, wantAddress: "10.0.3.1:8443"},
+		{host: "api.example.org:443", wantStatus: http.StatusForbidden},
+		{host: "[fd00::1]:1234", wantStatus: http.StatusForbidden},
+	} {
+		t.Run(tt.host, func(t *testing.T) {
+			server := &Server{subnet: subnet, allowlist: allowlist, lookupIP: lookupIP}
+
+			recorder := httptest.NewRecorder()
+			request := httptest.NewRequest(http.MethodConnect, tt.host, nil)
+
+			address, _ := server.validateProxyRequest(recorder, request)
+
+			if recorder.Result().StatusCode != tt.wantStatus {
+				t.Error(recorder.Result().StatusCode)
+			}
+			if address != tt.wantAddress {
+				t.Error(address)
+			}
+		})
+	}
+}
+
+func TestRunRequiresClientCert(t *testing.T) {
+	server := &Server{Subnet: "10.0.0.0/8"}
+
+	err := server.Run()
+	utilerror.AssertErrorMessage(t, err, "no client certificate configured; refusing to start without mutual TLS")
+}
+
+func TestParseAllowlist(t *testing.T) {
+	for _, tt := range []struct {
+		pattern string
+		wantErr string
+	}{
+		{pattern: "example.com", wantErr: `invalid allowlist pattern "example.com": missing port`},
+		{pattern: "*:443", wantErr: `invalid allowlist pattern "*:443": invalid host`},
+		{pattern: "a*.example.com:443", wantErr: `invalid allowlist pattern "a*.example.com:443": invalid host`},
+		{pattern: "10.0.0.0/33:443", wantErr: `invalid allowlist pattern "10.0.0.0/33:443": invalid CIDR address: 10.0.0.0/33`},
+		{pattern: "example.com:0", wantErr: `invalid allowlist pattern "example.com:0": invalid port`},
+		{pattern: "example.com:9-8", wantErr: `invalid allowlist pattern "example.com:9-8": invalid port`},
+		{pattern: "example.com:http", wantErr: `invalid allowlist pattern "example.com:http": invalid port`},
+	} {
+		t.Run(tt.pattern, func(t *testing.T) {
+			_, err := parseAllowlist([]string{tt.pattern})
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestProxyHandlerDenied(t *testing.T) {
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	m := mock_metrics.NewMockEmitter(controller)
+	m.EXPECT().EmitGauge("proxy.connections", int64(1), map[string]string{"action": "denied"})
+
+	h, log := testlog.New()
+
+	_, subnet, err := net.ParseCIDR("10.0.0.0/8")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &Server{Log: log, M: m, subnet: subnet}
+
+	recorder := httptest.NewRecorder()
+	server.proxyHandler(recorder, httptest.NewRequest(http.MethodConnect, "192.168.0.1:443", nil))
+
+	if recorder.Result().StatusCode != http.StatusForbidden {
+		t.Error(recorder.Result().StatusCode)
+	}
+
+	err = testlog.AssertLoggingOutput(h, []map[string]types.GomegaMatcher{
+		{
+			"level":       gomega.Equal(logrus.InfoLevel),
+			"msg":         gomega.Equal("access denied"),
+			"destination": gomega.Equal("192.168.0.1:443"),
+			"reason":      gomega.Equal("request is not allowed, the originating IP is not part of the allowed subnet"),
+		},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+}
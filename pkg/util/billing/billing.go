@@ -59,7 +59,11 @@ func storageClient(env env.Interface, billing database.Billing, sub database.Sub
 		return nil, nil
 	}
 
-	r, err := azure.ParseResourceID(os.Getenv("BILLING_E2E_STORAGE_ACCOUNT_ID"))
+	return newStorageClient(env, os.Getenv("BILLING_E2E_STORAGE_ACCOUNT_ID"))
+}
+
+func newStorageClient(env env.Interface, storageAccountID string) (*azstorage.Client, error) {
+	r, err := azure.ParseResourceID(storageAccountID)
 	if err != nil {
 		return nil, err
 	}
@@ -69,9 +73,9 @@ func storageClient(env env.Interface, billing database.Billing, sub database.Sub
 		return nil, err
 	}
 
-	e2estorage := storage.NewAccountsClient(env.Environment(), r.SubscriptionID, localFPAuthorizer)
+	accounts := storage.NewAccountsClient(env.Environment(), r.SubscriptionID, localFPAuthorizer)
 
-	keys, err := e2estorage.ListKeys(context.Background(), r.ResourceGroup, r.ResourceName, "")
+	keys, err := accounts.ListKeys(context.Background(), r.ResourceGroup, r.ResourceName, "")
 	if err != nil {
 		return nil, err
 	}
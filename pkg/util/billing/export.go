@@ -0,0 +1,192 @@
+package billing
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	azstorage "github.com/Azure/azure-sdk-for-go/storage"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/env"
+)
+
+const usageContainer = "usage"
+
+// UsageRecord is a row of the usage export: the number of workers of one VM
+// size which a cluster ran during an interval.  See docs/billing.md for the
+// schema.
+type UsageRecord struct {
+	ID         string `json:"id"`
+	ResourceID string `json:"resourceId"`
+	TenantID   string `json:"tenantId"`
+	Location   string `json:"location"`
+
+	// StartTime and EndTime are RFC3339 timestamps; EndTime is empty while
+	// the interval is open
+	StartTime string `json:"startTime"`
+	EndTime   string `json:"endTime,omitempty"`
+
+	// VMSize is empty and WorkerCount is zero if the workers of the cluster
+	// were never recorded
+	VMSize      string `json:"vmSize,omitempty"`
+	WorkerCount int    `json:"workerCount"`
+}
+
+var usageCSVHeader = []string{"id", "resourceId", "tenantId", "location", "startTime", "endTime", "vmSize", "workerCount"}
+
+// usageRecords returns the usage records of a billing record.  Records
+// created before usage intervals were recorded are exported as a single
+// interval covering the lifetime of the cluster.
+func usageRecords(doc *api.BillingDocument) []UsageRecord {
+	intervals := doc.Billing.UsageIntervals
+	if len(intervals) == 0 {
+		intervals = []api.BillingUsageInterval{
+			{
+				StartTime: doc.Billing.CreationTime,
+			},
+		}
+	}
+
+	var records []UsageRecord
+	for i, interval := range intervals {
+		endTime := interval.EndTime
+		if i == len(intervals)-1 {
+			endTime = doc.Billing.DeletionTime
+		}
+
+		record := UsageRecord{
+			ID:         doc.ID,
+			ResourceID: doc.Key,
+			TenantID:   doc.Billing.TenantID,
+			Location:   doc.Billing.Location,
+			StartTime:  formatTime(interval.StartTime),
+			EndTime:    formatTime(endTime),
+		}
+
+		if len(interval.Workers) == 0 {
+			records = append(records, record)
+			continue
+		}
+
+		for _, workers := range interval.Workers {
+			record.VMSize = string(workers.VMSize)
+			record.WorkerCount = workers.Count
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+func formatTime(t int) string {
+	if t == 0 {
+		return ""
+	}
+	return time.Unix(int64(t), 0).UTC().Format(time.RFC3339)
+}
+
+// WriteUsageJSON writes the report as JSON
+func WriteUsageJSON(w io.Writer, report *ReconcileReport) error {
+	findings := report.Findings
+	if findings == nil {
+		findings = []Finding{}
+	}
+	usage := report.Usage
+	if usage == nil {
+		usage = []UsageRecord{}
+	}
+
+	e := json.NewEncoder(w)
+	e.SetIndent("", "    ")
+	return e.Encode(struct {
+		GeneratedAt string        `json:"generatedAt"`
+		Findings    []Finding     `json:"findings"`
+		Usage       []UsageRecord `json:"usage"`
+	}{
+		GeneratedAt: report.Time.Format(time.RFC3339),
+		Findings:    findings,
+		Usage:       usage,
+	})
+}
+
+// WriteUsageCSV writes the usage records of the report as CSV, with a header
+// row
+func WriteUsageCSV(w io.Writer, report *ReconcileReport) error {
+	cw := csv.NewWriter(w)
+
+	err := cw.Write(usageCSVHeader)
+	if err != nil {
+		return err
+	}
+
+	for _, r := range report.Usage {
+		err = cw.Write([]string{r.ID, r.ResourceID, r.TenantID, r.Location, r.StartTime, r.EndTime, r.VMSize, strconv.Itoa(r.WorkerCount)})
+		if err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// Exporter uploads usage exports to a storage account
+type Exporter struct {
+	storageClient *azstorage.Client
+}
+
+// NewExporter returns an Exporter which uploads to the storage account with
+// the given resource ID
+func NewExporter(env env.Interface, storageAccountID string) (*Exporter, error) {
+	storageClient, err := newStorageClient(env, storageAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Exporter{
+		storageClient: storageClient,
+	}, nil
+}
+
+// Export uploads the report to the usage container as
+// YYYY/MM/DD/usage-<timestamp>.json and .csv
+func (e *Exporter) Export(ctx context.Context, report *ReconcileReport) error {
+	blobclient := e.storageClient.GetBlobService()
+
+	containerRef := blobclient.GetContainerReference(usageContainer)
+	_, err := containerRef.CreateIfNotExists(nil)
+	if err != nil {
+		return err
+	}
+
+	prefix := report.Time.Format("2006/01/02") + "/usage-" + report.Time.Format("20060102T150405Z")
+
+	for _, f := range []struct {
+		ext   string
+		write func(io.Writer, *ReconcileReport) error
+	}{
+		{ext: ".json", write: WriteUsageJSON},
+		{ext: ".csv", write: WriteUsageCSV},
+	} {
+		buf := &bytes.Buffer{}
+		err = f.write(buf, report)
+		if err != nil {
+			return err
+		}
+
+		err = containerRef.GetBlobReference(prefix+f.ext).CreateBlockBlobFromReader(buf, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,75 @@
+package billing
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteUsage(t *testing.T) {
+	report := &ReconcileReport{
+		Time: time.Unix(3000, 0).UTC(),
+		Findings: []Finding{
+			{Kind: FindingMissing, ID: "missing", ResourceID: "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/missing"},
+		},
+		Usage: []UsageRecord{
+			{
+				ID:          "cluster",
+				ResourceID:  "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+				TenantID:    "tenant",
+				Location:    "eastus",
+				StartTime:   "1970-01-01T00:16:40Z",
+				VMSize:      "Standard_D4s_v3",
+				WorkerCount: 3,
+			},
+		},
+	}
+
+	buf := &bytes.Buffer{}
+	err := WriteUsageCSV(buf, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantCSV := `id,resourceId,tenantId,location,startTime,endTime,vmSize,workerCount
+cluster,/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster,tenant,eastus,1970-01-01T00:16:40Z,,Standard_D4s_v3,3
+`
+	if buf.String() != wantCSV {
+		t.Errorf("got CSV %q, want %q", buf.String(), wantCSV)
+	}
+
+	buf.Reset()
+	err = WriteUsageJSON(buf, report)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantJSON := `{
+    "generatedAt": "1970-01-01T00:50:00Z",
+    "findings": [
+        {
+            "kind": "Missing",
+            "id": "missing",
+            "resourceId": "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/missing"
+        }
+    ],
+    "usage": [
+        {
+            "id": "cluster",
+            "resourceId": "/subscriptions/sub/resourcegroups/rg/providers/microsoft.redhatopenshift/openshiftclusters/cluster",
+            "tenantId": "tenant",
+            "location": "eastus",
+            "startTime": "1970-01-01T00:16:40Z",
+            "vmSize": "Standard_D4s_v3",
+            "workerCount": 3
+        }
+    ]
+}
+`
+	if buf.String() != wantJSON {
+		t.Errorf("got JSON %s, want %s", buf.String(), wantJSON)
+	}
+}
@@ -0,0 +1,307 @@
+package billing
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+	"github.com/Azure/ARO-RP/pkg/metrics"
+	"github.com/Azure/ARO-RP/pkg/util/clusterdata"
+)
+
+const (
+	// enrichBatchSize is the number of clusters whose workers are counted at
+	// once
+	enrichBatchSize = 50
+
+	// enrichTimeout bounds how long counting the workers of a batch of
+	// clusters may take
+	enrichTimeout = 30 * time.Second
+)
+
+// FindingKind classifies a discrepancy between the billing records and the
+// cluster documents
+type FindingKind string
+
+const (
+	// FindingMissing is an installed cluster without a billing record: the
+	// cluster is not being billed
+	FindingMissing FindingKind = "Missing"
+
+	// FindingNeverClosed is an open billing record whose cluster document no
+	// longer exists: the cluster is billed after it was deleted
+	FindingNeverClosed FindingKind = "NeverClosed"
+
+	// FindingOrphaned is a closed billing record whose cluster still exists
+	// and is not being deleted: the cluster is no longer billed
+	FindingOrphaned FindingKind = "Orphaned"
+)
+
+// Finding is a discrepancy found by the Reconciler
+type Finding struct {
+	Kind       FindingKind `json:"kind"`
+	ID         string      `json:"id"`
+	ResourceID string      `json:"resourceId"`
+}
+
+// ReconcileReport is the outcome of a reconciliation
+type ReconcileReport struct {
+	Time     time.Time
+	Findings []Finding
+	Usage    []UsageRecord
+}
+
+// Reconciler compares the billing records with the cluster documents, and
+// records each cluster's workers in its billing record so that usage can be
+// exported per interval.  Workers are counted from the clusters' MachineSets,
+// as the worker profiles in the cluster documents are only set at install time.
+type Reconciler struct {
+	log      *logrus.Entry
+	m        metrics.Emitter
+	enricher clusterdata.BestEffortEnricher
+
+	dbBilling           database.Billing
+	dbOpenShiftClusters database.OpenShiftClusters
+
+	now func() time.Time
+}
+
+// NewReconciler returns a new Reconciler
+func NewReconciler(log *logrus.Entry, dbBilling database.Billing, dbOpenShiftClusters database.OpenShiftClusters, m metrics.Emitter, enricher clusterdata.BestEffortEnricher) *Reconciler {
+	return &Reconciler{
+		log:      log,
+		m:        m,
+		enricher: enricher,
+
+		dbBilling:           dbBilling,
+		dbOpenShiftClusters: dbOpenShiftClusters,
+
+		now: time.Now,
+	}
+}
+
+// Reconcile reports the billing records which don't match the cluster
+// documents, updates the usage intervals of open billing records and returns
+// the usage of every billing record.
+func (r *Reconciler) Reconcile(ctx context.Context) (*ReconcileReport, error) {
+	report := &ReconcileReport{
+		Time: r.now().UTC(),
+	}
+
+	clusters := map[string]*api.OpenShiftClusterDocument{}
+	i := r.dbOpenShiftClusters.List("")
+	for {
+		docs, err := i.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if docs == nil {
+			break
+		}
+
+		for _, doc := range docs.OpenShiftClusterDocuments {
+			clusters[doc.ID] = doc
+		}
+	}
+
+	r.countWorkers(ctx, clusters)
+
+	billed := map[string]struct{}{}
+	bi := r.dbBilling.List("")
+	for {
+		docs, err := bi.Next(ctx, -1)
+		if err != nil {
+			return nil, err
+		}
+		if docs == nil {
+			break
+		}
+
+		for _, doc := range docs.BillingDocuments {
+			billed[doc.ID] = struct{}{}
+			cluster := clusters[doc.ID]
+
+			switch {
+			case cluster == nil && doc.Billing.DeletionTime == 0:
+				report.Findings = append(report.Findings, r.finding(FindingNeverClosed, doc.ID, doc.Key))
+
+			case cluster != nil && doc.Billing.DeletionTime != 0 &&
+				cluster.OpenShiftCluster.Properties.ProvisioningState != api.ProvisioningStateDeleting:
+				report.Findings = append(report.Findings, r.finding(FindingOrphaned, doc.ID, doc.Key))
+
+			case cluster != nil && doc.Billing.DeletionTime == 0:
+				doc, err = r.recordUsage(ctx, doc, cluster)
+				if err != nil {
+					return nil, err
+				}
+			}
+
+			report.Usage = append(report.Usage, usageRecords(doc)...)
+		}
+	}
+
+	for _, cluster := range clusters {
+		if _, ok := billed[cluster.ID]; !ok && isBillable(cluster) {
+			report.Findings = append(report.Findings, r.finding(FindingMissing, cluster.ID, cluster.Key))
+		}
+	}
+
+	sort.SliceStable(report.Usage, func(i, j int) bool { return report.Usage[i].ID < report.Usage[j].ID })
+
+	sort.Slice(report.Findings, func(i, j int) bool {
+		if report.Findings[i].Kind != report.Findings[j].Kind {
+			return report.Findings[i].Kind < report.Findings[j].Kind
+		}
+		return report.Findings[i].ID < report.Findings[j].ID
+	})
+
+	counts := map[FindingKind]int{}
+	for _, f := range report.Findings {
+		counts[f.Kind]++
+	}
+	for _, kind := range []FindingKind{FindingMissing, FindingNeverClosed, FindingOrphaned} {
+		r.m.EmitGauge("billing.reconcile.findings", int64(counts[kind]), map[string]string{
+			"kind": string(kind),
+		})
+	}
+
+	r.log.Printf("reconciled %d billing records against %d clusters: %d findings", len(billed), len(clusters), len(report.Findings))
+
+	return report, nil
+}
+
+func (r *Reconciler) finding(kind FindingKind, id, resourceID string) Finding {
+	r.log.WithFields(logrus.Fields{
+		"kind":       kind,
+		"id":         id,
+		"resourceId": resourceID,
+	}).Warn("billing record mismatch")
+
+	return Finding{
+		Kind:       kind,
+		ID:         id,
+		ResourceID: resourceID,
+	}
+}
+
+// countWorkers sets the WorkerProfilesStatus of the billable clusters from
+// their MachineSets.  It is left unset on clusters which can't be reached.
+func (r *Reconciler) countWorkers(ctx context.Context, clusters map[string]*api.OpenShiftClusterDocument) {
+	var ocs []*api.OpenShiftCluster
+	for _, doc := range clusters {
+		if isBillable(doc) {
+			ocs = append(ocs, doc.OpenShiftCluster)
+		}
+	}
+	sort.Slice(ocs, func(i, j int) bool { return ocs[i].ID < ocs[j].ID })
+
+	for len(ocs) > 0 {
+		n := enrichBatchSize
+		if n > len(ocs) {
+			n = len(ocs)
+		}
+
+		timeoutCtx, cancel := context.WithTimeout(ctx, enrichTimeout)
+		r.enricher.Enrich(timeoutCtx, r.log, ocs[:n]...)
+		cancel()
+
+		ocs = ocs[n:]
+	}
+}
+
+// recordUsage starts a new usage interval on the billing record if the
+// cluster's workers changed since the last one.  If the cluster's workers
+// couldn't be counted, the last interval is kept: only a record without
+// intervals falls back to the workers requested at install time.
+func (r *Reconciler) recordUsage(ctx context.Context, doc *api.BillingDocument, cluster *api.OpenShiftClusterDocument) (*api.BillingDocument, error) {
+	workers := clusterWorkers(cluster.OpenShiftCluster.Properties.WorkerProfilesStatus)
+	if cluster.OpenShiftCluster.Properties.WorkerProfilesStatus == nil {
+		if len(doc.Billing.UsageIntervals) > 0 {
+			r.log.WithField("resourceId", doc.Key).Warn("couldn't count workers, keeping the last usage interval")
+			return doc, nil
+		}
+		workers = clusterWorkers(cluster.OpenShiftCluster.Properties.WorkerProfiles)
+	}
+
+	if !usageChanged(doc.Billing, workers) {
+		return doc, nil
+	}
+
+	now := int(r.now().Unix())
+	updated, err := r.dbBilling.Patch(ctx, doc.ID, func(doc *api.BillingDocument) error {
+		if !usageChanged(doc.Billing, workers) {
+			return nil
+		}
+
+		start := now
+		if n := len(doc.Billing.UsageIntervals); n > 0 {
+			doc.Billing.UsageIntervals[n-1].EndTime = now
+		} else if doc.Billing.CreationTime != 0 {
+			start = doc.Billing.CreationTime
+		}
+
+		doc.Billing.UsageIntervals = append(doc.Billing.UsageIntervals, api.BillingUsageInterval{
+			StartTime: start,
+			Workers:   workers,
+		})
+		return nil
+	})
+	if cosmosdb.IsErrorStatusCode(err, http.StatusNotFound) {
+		return doc, nil
+	}
+
+	return updated, err
+}
+
+func usageChanged(billing *api.Billing, workers []api.BillingWorkers) bool {
+	n := len(billing.UsageIntervals)
+	if n == 0 || len(billing.UsageIntervals[n-1].Workers) != len(workers) {
+		return true
+	}
+
+	for i, w := range billing.UsageIntervals[n-1].Workers {
+		if w.VMSize != workers[i].VMSize || w.Count != workers[i].Count {
+			return true
+		}
+	}
+
+	return false
+}
+
+// clusterWorkers counts the workers of the given profiles by VM size
+func clusterWorkers(workerProfiles []api.WorkerProfile) []api.BillingWorkers {
+	counts := map[api.VMSize]int{}
+	for _, wp := range workerProfiles {
+		if wp.Count > 0 {
+			counts[wp.VMSize] += wp.Count
+		}
+	}
+
+	workers := make([]api.BillingWorkers, 0, len(counts))
+	for vmSize, count := range counts {
+		workers = append(workers, api.BillingWorkers{
+			VMSize: vmSize,
+			Count:  count,
+		})
+	}
+	sort.Slice(workers, func(i, j int) bool { return workers[i].VMSize < workers[j].VMSize })
+
+	return workers
+}
+
+// isBillable returns true if the cluster should have a billing record, i.e.
+// its installation completed
+func isBillable(doc *api.OpenShiftClusterDocument) bool {
+	props := doc.OpenShiftCluster.Properties
+	return props.ProvisioningState != api.ProvisioningStateCreating &&
+		props.FailedProvisioningState != api.ProvisioningStateCreating
+}
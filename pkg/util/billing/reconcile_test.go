@@ -0,0 +1,214 @@
+package billing
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/metrics/noop"
+	utillog "github.com/Azure/ARO-RP/pkg/util/log"
+	testdatabase "github.com/Azure/ARO-RP/test/database"
+)
+
+// fakeEnricher sets the WorkerProfilesStatus of the clusters it knows, by
+// resource ID, as if it had counted their MachineSets
+type fakeEnricher map[string][]api.WorkerProfile
+
+func (f fakeEnricher) Enrich(ctx context.Context, log *logrus.Entry, ocs ...*api.OpenShiftCluster) {
+	for _, oc := range ocs {
+		if workerProfiles, ok := f[oc.ID]; ok {
+			oc.Properties.WorkerProfilesStatus = workerProfiles
+		}
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	ctx := context.Background()
+
+	resourceID := func(name string) string {
+		return strings.ToLower(testdatabase.GetResourcePath("00000000-0000-0000-0000-000000000000", name))
+	}
+
+	cluster := func(id string, state, failedState api.ProvisioningState, workers ...api.WorkerProfile) *api.OpenShiftClusterDocument {
+		return &api.OpenShiftClusterDocument{
+			ID:  id,
+			Key: resourceID(id),
+			OpenShiftCluster: &api.OpenShiftCluster{
+				ID: resourceID(id),
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState:       state,
+					FailedProvisioningState: failedState,
+					WorkerProfiles:          workers,
+				},
+			},
+		}
+	}
+
+	billingDoc := func(id string, deletionTime int, intervals ...api.BillingUsageInterval) *api.BillingDocument {
+		return &api.BillingDocument{
+			ID:  id,
+			Key: resourceID(id),
+			Billing: &api.Billing{
+				CreationTime:   1000,
+				DeletionTime:   deletionTime,
+				Location:       "eastus",
+				UsageIntervals: intervals,
+			},
+		}
+	}
+
+	openShiftClustersDatabase, _ := testdatabase.NewFakeOpenShiftClusters()
+	billingDatabase, billingClient := testdatabase.NewFakeBilling()
+
+	fixture := testdatabase.NewFixture().WithOpenShiftClusters(openShiftClustersDatabase)
+	fixture.AddOpenShiftClusterDocuments(
+		cluster("recorded", api.ProvisioningStateSucceeded, "",
+			api.WorkerProfile{VMSize: api.VMSizeStandardD4sV3, Count: 2},
+			api.WorkerProfile{VMSize: api.VMSizeStandardD4sV3, Count: 1},
+		),
+		cluster("resized", api.ProvisioningStateSucceeded, "",
+			api.WorkerProfile{VMSize: api.VMSizeStandardD4sV3, Count: 3},
+		),
+		cluster("unreachable", api.ProvisioningStateSucceeded, "",
+			api.WorkerProfile{VMSize: api.VMSizeStandardD8sV3, Count: 1},
+		),
+		cluster("unrecorded", api.ProvisioningStateUpdating, "",
+			api.WorkerProfile{VMSize: api.VMSizeStandardD4sV3, Count: 3},
+		),
+		cluster("missing", api.ProvisioningStateSucceeded, ""),
+		cluster("creating", api.ProvisioningStateCreating, ""),
+		cluster("createfailed", api.ProvisioningStateDeleting, api.ProvisioningStateCreating),
+		cluster("orphaned", api.ProvisioningStateSucceeded, ""),
+		cluster("deleting", api.ProvisioningStateDeleting, ""),
+	)
+	err := fixture.Create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// create billing documents without the creation trigger, so that their
+	// creation time is kept
+	for _, doc := range []*api.BillingDocument{
+		billingDoc("recorded", 0, api.BillingUsageInterval{
+			StartTime: 1000,
+			Workers:   []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}},
+		}),
+		billingDoc("resized", 0, api.BillingUsageInterval{
+			StartTime: 1000,
+			Workers:   []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}},
+		}),
+		billingDoc("unreachable", 0, api.BillingUsageInterval{
+			StartTime: 1000,
+			Workers:   []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}},
+		}),
+		billingDoc("unrecorded", 0),
+		billingDoc("orphaned", 2000),
+		billingDoc("deleting", 2000),
+		billingDoc("neverclosed", 0),
+		billingDoc("deleted", 2000),
+	} {
+		_, err = billingClient.Create(ctx, doc.ID, doc, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// resized's worker profiles are stale: the cluster has been scaled since
+	// it was installed.  unreachable and unrecorded can't be counted
+	enricher := fakeEnricher{
+		resourceID("recorded"): {
+			{Name: "worker-1", VMSize: api.VMSizeStandardD4sV3, Count: 2},
+			{Name: "worker-2", VMSize: api.VMSizeStandardD4sV3, Count: 1},
+		},
+		resourceID("resized"): {
+			{Name: "worker-1", VMSize: api.VMSizeStandardD8sV3, Count: 3},
+		},
+	}
+
+	r := NewReconciler(utillog.GetLogger(), billingDatabase, openShiftClustersDatabase, &noop.Noop{}, enricher)
+	r.now = func() time.Time { return time.Unix(3000, 0) }
+
+	report, err := r.Reconcile(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	wantFindings := []Finding{
+		{Kind: FindingMissing, ID: "missing", ResourceID: resourceID("missing")},
+		{Kind: FindingNeverClosed, ID: "neverclosed", ResourceID: resourceID("neverclosed")},
+		{Kind: FindingOrphaned, ID: "orphaned", ResourceID: resourceID("orphaned")},
+	}
+	if !reflect.DeepEqual(report.Findings, wantFindings) {
+		t.Errorf("got findings %#v, want %#v", report.Findings, wantFindings)
+	}
+
+	wantIntervals := map[string][]api.BillingUsageInterval{
+		"recorded": {
+			{StartTime: 1000, Workers: []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}}},
+		},
+		"resized": {
+			{StartTime: 1000, EndTime: 3000, Workers: []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}}},
+			{StartTime: 3000, Workers: []api.BillingWorkers{{VMSize: api.VMSizeStandardD8sV3, Count: 3}}},
+		},
+		"unreachable": {
+			{StartTime: 1000, Workers: []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}}},
+		},
+		"unrecorded": {
+			{StartTime: 1000, Workers: []api.BillingWorkers{{VMSize: api.VMSizeStandardD4sV3, Count: 3}}},
+		},
+		"orphaned":    nil,
+		"deleting":    nil,
+		"neverclosed": nil,
+		"deleted":     nil,
+	}
+	for id, want := range wantIntervals {
+		doc, err := billingDatabase.Get(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(doc.Billing.UsageIntervals, want) {
+			t.Errorf("%s: got intervals %#v, want %#v", id, doc.Billing.UsageIntervals, want)
+		}
+	}
+
+	var resized []UsageRecord
+	for _, u := range report.Usage {
+		if u.ID == "resized" {
+			resized = append(resized, u)
+		}
+	}
+	wantResized := []UsageRecord{
+		{
+			ID:          "resized",
+			ResourceID:  resourceID("resized"),
+			Location:    "eastus",
+			StartTime:   "1970-01-01T00:16:40Z",
+			EndTime:     "1970-01-01T00:50:00Z",
+			VMSize:      string(api.VMSizeStandardD4sV3),
+			WorkerCount: 3,
+		},
+		{
+			ID:          "resized",
+			ResourceID:  resourceID("resized"),
+			Location:    "eastus",
+			StartTime:   "1970-01-01T00:50:00Z",
+			VMSize:      string(api.VMSizeStandardD8sV3),
+			WorkerCount: 3,
+		},
+	}
+	if !reflect.DeepEqual(resized, wantResized) {
+		t.Errorf("got usage %#v, want %#v", resized, wantResized)
+	}
+
+	if len(report.Usage) != 9 {
+		t.Errorf("got %d usage records, want 9", len(report.Usage))
+	}
+}
@@ -0,0 +1,122 @@
+package consolebanner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"text/template"
+)
+
+// DefaultLanguage is the language of a banner whose language isn't set
+const DefaultLanguage = "en"
+
+type message struct {
+	// parameters are the names of the parameters which the message requires
+	parameters []string
+
+	// text is the message's text/template template, by language
+	text map[string]string
+}
+
+// messages are the messages which console banners can show, by message ID.
+// The RP only refers to messages by ID so that their text can be reviewed and
+// localised here.  Messages are text/template templates: {{.ResourceID}},
+// {{.StartTime}}, {{.ExpiryTime}} and {{.Parameters.<name>}} are replaced when
+// the banner is published.  Each translation must use exactly the parameters
+// of its message.
+//
+// Banner messages are approved by PM, don't modify the messages without
+// re-approval.  New messages and translations must be approved before they are
+// added here.
+var messages = map[string]*message{
+	"ContactSupport": {
+		text: map[string]string{
+			"en": "We have noticed an issue regarding your cluster requiring an action on your part. Please contact support with your cluster resource ID: {{.ResourceID}}",
+		},
+	},
+}
+
+// Data holds the values which replace the placeholders of a message
+type Data struct {
+	ResourceID string
+	StartTime  string
+	ExpiryTime string
+	Parameters map[string]string
+}
+
+// MessageIDs returns the known message IDs, sorted
+func MessageIDs() []string {
+	ids := make([]string, 0, len(messages))
+	for id := range messages {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	return ids
+}
+
+// Languages returns the languages in which the message is available, sorted
+func Languages(messageID string) []string {
+	m, ok := messages[messageID]
+	if !ok {
+		return nil
+	}
+
+	languages := make([]string, 0, len(m.text))
+	for language := range m.text {
+		languages = append(languages, language)
+	}
+	sort.Strings(languages)
+
+	return languages
+}
+
+// Parameters returns the names of the parameters which the message requires
+func Parameters(messageID string) []string {
+	m, ok := messages[messageID]
+	if !ok {
+		return nil
+	}
+
+	return m.parameters
+}
+
+// Render returns the text of the message in the given language, or in
+// DefaultLanguage if language is empty, with its placeholders replaced
+func Render(messageID, language string, data *Data) (string, error) {
+	m, ok := messages[messageID]
+	if !ok {
+		return "", fmt.Errorf("unknown message ID %q", messageID)
+	}
+
+	if language == "" {
+		language = DefaultLanguage
+	}
+
+	text, ok := m.text[language]
+	if !ok {
+		return "", fmt.Errorf("message ID %q is not available in language %q", messageID, language)
+	}
+
+	t, err := template.New(messageID).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+
+	if data.Parameters == nil {
+		d := *data
+		d.Parameters = map[string]string{}
+		data = &d
+	}
+
+	buf := &bytes.Buffer{}
+	err = t.Execute(buf, data)
+	if err != nil {
+		return "", err
+	}
+
+	return buf.String(), nil
+}
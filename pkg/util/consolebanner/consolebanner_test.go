@@ -0,0 +1,109 @@
+package consolebanner
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+)
+
+func TestMessages(t *testing.T) {
+	for _, messageID := range MessageIDs() {
+		t.Run(messageID, func(t *testing.T) {
+			if _, ok := messages[messageID].text[DefaultLanguage]; !ok {
+				t.Errorf("message is not available in %q", DefaultLanguage)
+			}
+
+			for _, language := range Languages(messageID) {
+				parameters := map[string]string{}
+				for _, name := range Parameters(messageID) {
+					parameters[name] = name
+				}
+
+				// each translation must render with exactly the message's
+				// parameters
+				_, err := Render(messageID, language, &Data{Parameters: parameters})
+				if err != nil {
+					t.Errorf("%s: %s", language, err)
+				}
+
+				for name := range parameters {
+					missing := map[string]string{}
+					for n, v := range parameters {
+						if n != name {
+							missing[n] = v
+						}
+					}
+
+					_, err = Render(messageID, language, &Data{Parameters: missing})
+					if err == nil {
+						t.Errorf("%s: rendered without parameter %q", language, name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		messageID string
+		language  string
+		want      string
+		wantErr   string
+	}{
+		{
+			name:      "default language",
+			messageID: "ContactSupport",
+			want:      "We have noticed an issue regarding your cluster requiring an action on your part. Please contact support with your cluster resource ID: FAKE_RESOURCE_ID",
+		},
+		{
+			name:      "unknown message ID",
+			messageID: "Unknown",
+			wantErr:   `unknown message ID "Unknown"`,
+		},
+		{
+			name:      "unknown language",
+			messageID: "ContactSupport",
+			language:  "xx",
+			wantErr:   `message ID "ContactSupport" is not available in language "xx"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.messageID, tt.language, &Data{ResourceID: "FAKE_RESOURCE_ID"})
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			if got != tt.want {
+				t.Error(got)
+			}
+		})
+	}
+}
+
+func TestRenderParameters(t *testing.T) {
+	// no approved message takes parameters or is translated yet
+	messages["Test"] = &message{
+		parameters: []string{"windowStart"},
+		text: map[string]string{
+			"en": "Maintenance starts at {{.Parameters.windowStart}}.",
+			"de": "Die Wartung beginnt um {{.Parameters.windowStart}}.",
+		},
+	}
+	defer delete(messages, "Test")
+
+	got, err := Render("Test", "de", &Data{Parameters: map[string]string{"windowStart": "22:00 UTC"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "Die Wartung beginnt um 22:00 UTC." {
+		t.Error(got)
+	}
+
+	_, err = Render("Test", "", &Data{})
+	if err == nil {
+		t.Error("expected error rendering without a required parameter")
+	}
+}
@@ -7,3 +7,13 @@ type AEAD interface {
 	Open([]byte) ([]byte, error)
 	Seal([]byte) ([]byte, error)
 }
+
+// KeyVersionedAEAD is an AEAD backed by several key versions, which can report
+// which of them opens a given value.  Key versions are named
+// "<secret name>/<version>".
+type KeyVersionedAEAD interface {
+	AEAD
+	OpenWithKeyVersion([]byte) ([]byte, string, error)
+	SealKeyVersion() string
+	KeyVersions() []string
+}
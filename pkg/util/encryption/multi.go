@@ -4,19 +4,28 @@ package encryption
 // Licensed under the Apache License 2.0.
 
 import (
+	"bytes"
 	"context"
+	"fmt"
+	"sort"
 
 	"github.com/Azure/ARO-RP/pkg/util/keyvault"
 )
 
+type opener struct {
+	keyVersion string
+	aead       AEAD
+}
+
 type multi struct {
-	sealer  AEAD
-	openers []AEAD
+	sealer         AEAD
+	sealKeyVersion string
+	openers        []opener
 }
 
-var _ AEAD = (*multi)(nil)
+var _ KeyVersionedAEAD = (*multi)(nil)
 
-func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName, legacySecretName string) (AEAD, error) {
+func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName, legacySecretName string) (KeyVersionedAEAD, error) {
 	key, err := serviceKeyvault.GetBase64Secret(ctx, secretName, "")
 	if err != nil {
 		return nil, err
@@ -43,30 +52,68 @@ func NewMulti(ctx context.Context, serviceKeyvault keyvault.Manager, secretName,
 			return nil, err
 		}
 
-		for _, key := range keys {
-			aead, err = x.aeadFactory(ctx, key)
+		versions := make([]string, 0, len(keys))
+		for version := range keys {
+			versions = append(versions, version)
+		}
+		sort.Strings(versions)
+
+		for _, version := range versions {
+			aead, err = x.aeadFactory(ctx, keys[version])
 			if err != nil {
 				return nil, err
 			}
 
-			m.openers = append(m.openers, aead)
+			o := opener{
+				keyVersion: x.secretName + "/" + version,
+				aead:       aead,
+			}
+
+			// try the sealing key first, as most values are sealed with it
+			if x.secretName == secretName && bytes.Equal(keys[version], key) {
+				m.sealKeyVersion = o.keyVersion
+				m.openers = append([]opener{o}, m.openers...)
+			} else {
+				m.openers = append(m.openers, o)
+			}
 		}
 	}
 
+	if m.sealKeyVersion == "" {
+		return nil, fmt.Errorf("current version of secret %q is not enabled", secretName)
+	}
+
 	return m, nil
 }
 
-func (c *multi) Open(input []byte) (b []byte, err error) {
+func (c *multi) Open(input []byte) ([]byte, error) {
+	b, _, err := c.OpenWithKeyVersion(input)
+	return b, err
+}
+
+func (c *multi) OpenWithKeyVersion(input []byte) (b []byte, keyVersion string, err error) {
 	for _, opener := range c.openers {
-		b, err = opener.Open(input)
+		b, err = opener.aead.Open(input)
 		if err == nil {
-			return
+			return b, opener.keyVersion, nil
 		}
 	}
 
-	return nil, err
+	return nil, "", err
 }
 
 func (c *multi) Seal(input []byte) ([]byte, error) {
 	return c.sealer.Seal(input)
 }
+
+func (c *multi) SealKeyVersion() string {
+	return c.sealKeyVersion
+}
+
+func (c *multi) KeyVersions() []string {
+	keyVersions := make([]string, 0, len(c.openers))
+	for _, opener := range c.openers {
+		keyVersions = append(keyVersions, opener.keyVersion)
+	}
+	return keyVersions
+}
@@ -4,6 +4,8 @@ package encryption
 // Licensed under the Apache License 2.0.
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"reflect"
 	"testing"
@@ -11,6 +13,7 @@ import (
 	"github.com/golang/mock/gomock"
 
 	mock_encryption "github.com/Azure/ARO-RP/pkg/util/mocks/encryption"
+	mock_keyvault "github.com/Azure/ARO-RP/pkg/util/mocks/keyvault"
 	utilerror "github.com/Azure/ARO-RP/test/util/error"
 )
 
@@ -57,9 +60,9 @@ func TestOpen(t *testing.T) {
 			secondOpener := mock_encryption.NewMockAEAD(controller)
 
 			multi := multi{
-				openers: []AEAD{
-					firstOpener,
-					secondOpener,
+				openers: []opener{
+					{keyVersion: "secret/1", aead: firstOpener},
+					{keyVersion: "secret/2", aead: secondOpener},
 				},
 			}
 
@@ -74,3 +77,57 @@ func TestOpen(t *testing.T) {
 		})
 	}
 }
+
+func TestNewMulti(t *testing.T) {
+	ctx := context.Background()
+
+	current := bytes.Repeat([]byte{1}, 64)
+	previous := bytes.Repeat([]byte{2}, 64)
+	legacy := bytes.Repeat([]byte{3}, 32)
+
+	controller := gomock.NewController(t)
+	defer controller.Finish()
+
+	kv := mock_keyvault.NewMockManager(controller)
+	kv.EXPECT().GetBase64Secret(ctx, "secret-v2", "").Return(current, nil)
+	kv.EXPECT().GetBase64Secrets(ctx, "secret-v2").Return(map[string][]byte{"a": previous, "b": current}, nil)
+	kv.EXPECT().GetBase64Secrets(ctx, "secret").Return(map[string][]byte{"c": legacy}, nil)
+
+	aead, err := NewMulti(ctx, kv, "secret-v2", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if aead.SealKeyVersion() != "secret-v2/b" {
+		t.Error(aead.SealKeyVersion())
+	}
+
+	wantKeyVersions := []string{"secret-v2/b", "secret-v2/a", "secret/c"}
+	if !reflect.DeepEqual(aead.KeyVersions(), wantKeyVersions) {
+		t.Error(aead.KeyVersions())
+	}
+
+	for keyVersion, key := range map[string][]byte{
+		"secret-v2/a": previous,
+		"secret-v2/b": current,
+	} {
+		sealer, err := NewAES256SHA512(ctx, key)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		sealed, err := sealer.Seal([]byte("data"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, gotKeyVersion, err := aead.OpenWithKeyVersion(sealed)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(b) != "data" || gotKeyVersion != keyVersion {
+			t.Errorf("got %q %s, want %q %s", b, gotKeyVersion, "data", keyVersion)
+		}
+	}
+}
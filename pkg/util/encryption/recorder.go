@@ -0,0 +1,52 @@
+package encryption
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"sync"
+)
+
+// Recorder is a KeyVersionedAEAD which counts the values opened with each key
+// version.  It is used to find out which key versions stored data depends on.
+type Recorder struct {
+	KeyVersionedAEAD
+
+	mu     sync.Mutex
+	opened map[string]int
+}
+
+var _ KeyVersionedAEAD = (*Recorder)(nil)
+
+func NewRecorder(aead KeyVersionedAEAD) *Recorder {
+	return &Recorder{
+		KeyVersionedAEAD: aead,
+		opened:           map[string]int{},
+	}
+}
+
+func (r *Recorder) Open(input []byte) ([]byte, error) {
+	b, keyVersion, err := r.OpenWithKeyVersion(input)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.opened[keyVersion]++
+
+	return b, nil
+}
+
+// Reset returns the number of values opened with each key version since the
+// last Reset, and clears the counts.
+func (r *Recorder) Reset() map[string]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	opened := r.opened
+	r.opened = map[string]int{}
+
+	return opened
+}
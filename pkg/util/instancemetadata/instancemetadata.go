@@ -18,6 +18,7 @@ type InstanceMetadata interface {
 	SubscriptionID() string
 	Location() string
 	ResourceGroup() string
+	Zone() string
 	Environment() *azureclient.AROEnvironment
 }
 
@@ -27,6 +28,7 @@ type instanceMetadata struct {
 	subscriptionID string
 	location       string
 	resourceGroup  string
+	zone           string
 	environment    *azureclient.AROEnvironment
 }
 
@@ -50,6 +52,12 @@ func (im *instanceMetadata) ResourceGroup() string {
 	return im.resourceGroup
 }
 
+// Zone returns the availability zone of the instance, or an empty string if it
+// is not known or the instance is not zonal.
+func (im *instanceMetadata) Zone() string {
+	return im.zone
+}
+
 func (im *instanceMetadata) Environment() *azureclient.AROEnvironment {
 	return im.environment
 }
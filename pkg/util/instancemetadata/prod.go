@@ -96,6 +96,7 @@ func (p *prod) populateInstanceMetadata() error {
 		ResourceGroupName string `json:"resourceGroupName,omitempty"`
 		SubscriptionID    string `json:"subscriptionId,omitempty"`
 		AzEnvironment     string `json:"azEnvironment,omitempty"`
+		Zone              string `json:"zone,omitempty"`
 	}
 
 	err = json.NewDecoder(resp.Body).Decode(&m)
@@ -111,6 +112,7 @@ func (p *prod) populateInstanceMetadata() error {
 	p.subscriptionID = m.SubscriptionID
 	p.location = m.Location
 	p.resourceGroup = m.ResourceGroupName
+	p.zone = m.Zone
 
 	hostname, err := os.Hostname()
 	if err != nil {
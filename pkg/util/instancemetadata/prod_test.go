@@ -23,6 +23,7 @@ func TestPopulateInstanceMetadata(t *testing.T) {
 		wantSubscriptionID string
 		wantLocation       string
 		wantResourceGroup  string
+		wantZone           string
 		wantEnvironment    *azureclient.AROEnvironment
 		wantErr            string
 	}{
@@ -39,7 +40,8 @@ func TestPopulateInstanceMetadata(t *testing.T) {
 							"subscriptionId": "rpSubscriptionId",
 							"location": "eastus",
 							"resourceGroupName": "rpResourceGroup",
-							"azEnvironment": "AzurePublicCloud"
+							"azEnvironment": "AzurePublicCloud",
+							"zone": "2"
 						}`,
 					)),
 				}, nil
@@ -47,6 +49,7 @@ func TestPopulateInstanceMetadata(t *testing.T) {
 			wantSubscriptionID: "rpSubscriptionId",
 			wantLocation:       "eastus",
 			wantResourceGroup:  "rpResourceGroup",
+			wantZone:           "2",
 			wantEnvironment:    &azureclient.PublicCloud,
 		},
 		{
@@ -148,6 +151,10 @@ func TestPopulateInstanceMetadata(t *testing.T) {
 				t.Error(p.resourceGroup)
 			}
 
+			if p.zone != tt.wantZone {
+				t.Error(p.zone)
+			}
+
 			if !reflect.DeepEqual(p.environment, tt.wantEnvironment) {
 				t.Error(p.environment)
 			}
@@ -35,7 +35,7 @@ type Manager interface {
 	CreateSignedCertificate(context.Context, string, string, string, Eku) error
 	EnsureCertificateDeleted(context.Context, string) error
 	GetBase64Secret(context.Context, string, string) ([]byte, error)
-	GetBase64Secrets(context.Context, string) ([][]byte, error)
+	GetBase64Secrets(context.Context, string) (map[string][]byte, error)
 	GetCertificateSecret(context.Context, string) (*rsa.PrivateKey, []*x509.Certificate, error)
 	GetSecret(context.Context, string) (azkeyvault.SecretBundle, error)
 	GetSecrets(context.Context) ([]azkeyvault.SecretItem, error)
@@ -152,12 +152,15 @@ func (m *manager) GetBase64Secret(ctx context.Context, secretName string, secret
 	return base64.StdEncoding.DecodeString(*bundle.Value)
 }
 
-func (m *manager) GetBase64Secrets(ctx context.Context, secretName string) (bs [][]byte, err error) {
+// GetBase64Secrets returns the decoded value of every enabled version of the
+// secret, keyed by version.
+func (m *manager) GetBase64Secrets(ctx context.Context, secretName string) (map[string][]byte, error) {
 	versions, err := m.kv.GetSecretVersions(ctx, m.keyvaultURI, secretName, nil)
 	if err != nil {
 		return nil, err
 	}
 
+	bs := make(map[string][]byte, len(versions))
 	for _, version := range versions {
 		if !*version.Attributes.Enabled {
 			continue
@@ -168,7 +171,7 @@ func (m *manager) GetBase64Secrets(ctx context.Context, secretName string) (bs [
 			return nil, err
 		}
 
-		bs = append(bs, b)
+		bs[filepath.Base(*version.ID)] = b
 	}
 
 	return bs, nil
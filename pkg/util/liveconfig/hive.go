@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	mgmtcontainerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-10-01/containerservice"
@@ -72,7 +73,6 @@ func parseKubeconfig(credentials []mgmtcontainerservice.CredentialResult) (*rest
 }
 
 func (p *prod) HiveRestConfig(ctx context.Context, shard int) (*rest.Config, error) {
-	// Hive shards are planned but not implemented yet
 	p.hiveCredentialsMutex.RLock()
 	cached, exists := p.cachedCredentials[shard]
 	p.hiveCredentialsMutex.RUnlock()
@@ -95,6 +95,55 @@ func (p *prod) HiveRestConfig(ctx context.Context, shard int) (*rest.Config, err
 	return rest.CopyConfig(kubeConfig), nil
 }
 
+// hiveShardsFromEnv reads the Hive shard layout of the region. Shards are
+// numbered from 1; by default there is a single shard with unbounded capacity.
+func hiveShardsFromEnv() ([]HiveShard, error) {
+	count := 1
+	if v := os.Getenv(hiveShardCountEnvVar); v != "" {
+		var err error
+		count, err = strconv.Atoi(v)
+		if err != nil || count < 1 {
+			return nil, fmt.Errorf("invalid %s %q", hiveShardCountEnvVar, v)
+		}
+	}
+
+	var capacity int
+	if v := os.Getenv(hiveShardCapacityEnvVar); v != "" {
+		var err error
+		capacity, err = strconv.Atoi(v)
+		if err != nil || capacity < 0 {
+			return nil, fmt.Errorf("invalid %s %q", hiveShardCapacityEnvVar, v)
+		}
+	}
+
+	draining := map[int]bool{}
+	if v := os.Getenv(hiveDrainingShardsEnvVar); v != "" {
+		for _, s := range strings.Split(v, ",") {
+			shard, err := strconv.Atoi(strings.TrimSpace(s))
+			if err != nil || shard < 1 || shard > count {
+				return nil, fmt.Errorf("invalid %s %q", hiveDrainingShardsEnvVar, v)
+			}
+			draining[shard] = true
+		}
+	}
+
+	shards := make([]HiveShard, 0, count)
+	for i := 1; i <= count; i++ {
+		shards = append(shards, HiveShard{
+			Index:    i,
+			Capacity: capacity,
+			Draining: draining[i],
+		})
+	}
+
+	return shards, nil
+}
+
+func (p *prod) HiveShards(ctx context.Context) ([]HiveShard, error) {
+	// TODO: Replace with RP Live Service Config (KeyVault)
+	return hiveShardsFromEnv()
+}
+
 func (p *prod) InstallViaHive(ctx context.Context) (bool, error) {
 	// TODO: Replace with RP Live Service Config (KeyVault)
 	installViaHive := os.Getenv(hiveInstallerEnableEnvVar)
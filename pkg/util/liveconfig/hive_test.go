@@ -6,6 +6,7 @@ package liveconfig
 import (
 	"context"
 	"embed"
+	"reflect"
 	"testing"
 
 	mgmtcontainerservice "github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2021-10-01/containerservice"
@@ -100,3 +101,62 @@ func TestProdHiveAdmin(t *testing.T) {
 		t.Error("Invalid admin BearerToken returned for test 2")
 	}
 }
+
+func TestHiveShardsFromEnv(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		count    string
+		capacity string
+		draining string
+		want     []HiveShard
+		wantErr  string
+	}{
+		{
+			name: "default is a single unbounded shard",
+			want: []HiveShard{{Index: 1}},
+		},
+		{
+			name:     "multiple shards, one draining",
+			count:    "3",
+			capacity: "500",
+			draining: "1, 3",
+			want: []HiveShard{
+				{Index: 1, Capacity: 500, Draining: true},
+				{Index: 2, Capacity: 500},
+				{Index: 3, Capacity: 500, Draining: true},
+			},
+		},
+		{
+			name:    "invalid count",
+			count:   "0",
+			wantErr: `invalid ARO_HIVE_SHARD_COUNT "0"`,
+		},
+		{
+			name:     "invalid capacity",
+			capacity: "lots",
+			wantErr:  `invalid ARO_HIVE_SHARD_CAPACITY "lots"`,
+		},
+		{
+			name:     "draining shard out of range",
+			count:    "2",
+			draining: "3",
+			wantErr:  `invalid ARO_HIVE_DRAINING_SHARDS "3"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(hiveShardCountEnvVar, tt.count)
+			t.Setenv(hiveShardCapacityEnvVar, tt.capacity)
+			t.Setenv(hiveDrainingShardsEnvVar, tt.draining)
+
+			shards, err := hiveShardsFromEnv()
+			if err != nil && err.Error() != tt.wantErr ||
+				err == nil && tt.wantErr != "" {
+				t.Fatalf("got error %v, want %q", err, tt.wantErr)
+			}
+
+			if !reflect.DeepEqual(shards, tt.want) {
+				t.Errorf("got %v, want %v", shards, tt.want)
+			}
+		})
+	}
+}
@@ -17,11 +17,28 @@ const (
 	hiveInstallerEnableEnvVar = "ARO_INSTALL_VIA_HIVE"
 	hiveDefaultPullSpecEnvVar = "ARO_HIVE_DEFAULT_INSTALLER_PULLSPEC"
 	hiveAdoptEnableEnvVar     = "ARO_ADOPT_BY_HIVE"
+	hiveShardCountEnvVar      = "ARO_HIVE_SHARD_COUNT"
+	hiveShardCapacityEnvVar   = "ARO_HIVE_SHARD_CAPACITY"
+	hiveDrainingShardsEnvVar  = "ARO_HIVE_DRAINING_SHARDS"
 	useCheckAccess            = "USE_CHECKACCESS"
 )
 
+// HiveShard describes a Hive (AKS) shard in the region
+type HiveShard struct {
+	Index int
+
+	// Capacity is the maximum number of clusters the shard should manage, or
+	// zero if it is unbounded
+	Capacity int
+
+	// Draining shards continue to manage their existing clusters, but are not
+	// assigned new ones
+	Draining bool
+}
+
 type Manager interface {
 	HiveRestConfig(context.Context, int) (*rest.Config, error)
+	HiveShards(context.Context) ([]HiveShard, error)
 	InstallViaHive(context.Context) (bool, error)
 	AdoptByHive(context.Context) (bool, error)
 	UseCheckAccess(context.Context) (bool, error)
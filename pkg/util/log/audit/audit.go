@@ -26,6 +26,7 @@ const (
 	MetadataSource         = "source"
 
 	SourceAdminPortal = "aro-admin"
+	SourceDBToken     = "aro-dbtoken"
 	SourceRP          = "aro-rp"
 
 	EnvKeyAppID               = "envAppID"
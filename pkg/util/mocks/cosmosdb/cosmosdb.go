@@ -1,5 +1,5 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: github.com/Azure/ARO-RP/pkg/database/cosmosdb (interfaces: PermissionClient)
+// Source: github.com/Azure/ARO-RP/pkg/database/cosmosdb (interfaces: PermissionClient,PermissionTokenClient)
 
 // Package mock_cosmosdb is a generated GoMock package.
 package mock_cosmosdb
@@ -7,6 +7,7 @@ package mock_cosmosdb
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 
@@ -123,3 +124,144 @@ func (mr *MockPermissionClientMockRecorder) Replace(arg0, arg1 interface{}) *gom
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockPermissionClient)(nil).Replace), arg0, arg1)
 }
+
+// MockPermissionTokenClient is a mock of PermissionTokenClient interface.
+type MockPermissionTokenClient struct {
+	ctrl     *gomock.Controller
+	recorder *MockPermissionTokenClientMockRecorder
+}
+
+// MockPermissionTokenClientMockRecorder is the mock recorder for MockPermissionTokenClient.
+type MockPermissionTokenClientMockRecorder struct {
+	mock *MockPermissionTokenClient
+}
+
+// NewMockPermissionTokenClient creates a new mock instance.
+func NewMockPermissionTokenClient(ctrl *gomock.Controller) *MockPermissionTokenClient {
+	mock := &MockPermissionTokenClient{ctrl: ctrl}
+	mock.recorder = &MockPermissionTokenClientMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPermissionTokenClient) EXPECT() *MockPermissionTokenClientMockRecorder {
+	return m.recorder
+}
+
+// Create mocks base method.
+func (m *MockPermissionTokenClient) Create(arg0 context.Context, arg1 *cosmosdb.Permission) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Create", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Create indicates an expected call of Create.
+func (mr *MockPermissionTokenClientMockRecorder) Create(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Create", reflect.TypeOf((*MockPermissionTokenClient)(nil).Create), arg0, arg1)
+}
+
+// Delete mocks base method.
+func (m *MockPermissionTokenClient) Delete(arg0 context.Context, arg1 *cosmosdb.Permission) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPermissionTokenClientMockRecorder) Delete(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPermissionTokenClient)(nil).Delete), arg0, arg1)
+}
+
+// Get mocks base method.
+func (m *MockPermissionTokenClient) Get(arg0 context.Context, arg1 string) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Get", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Get indicates an expected call of Get.
+func (mr *MockPermissionTokenClientMockRecorder) Get(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockPermissionTokenClient)(nil).Get), arg0, arg1)
+}
+
+// GetToken mocks base method.
+func (m *MockPermissionTokenClient) GetToken(arg0 context.Context, arg1 string, arg2 time.Duration) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetToken", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetToken indicates an expected call of GetToken.
+func (mr *MockPermissionTokenClientMockRecorder) GetToken(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetToken", reflect.TypeOf((*MockPermissionTokenClient)(nil).GetToken), arg0, arg1, arg2)
+}
+
+// List mocks base method.
+func (m *MockPermissionTokenClient) List() cosmosdb.PermissionIterator {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "List")
+	ret0, _ := ret[0].(cosmosdb.PermissionIterator)
+	return ret0
+}
+
+// List indicates an expected call of List.
+func (mr *MockPermissionTokenClientMockRecorder) List() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "List", reflect.TypeOf((*MockPermissionTokenClient)(nil).List))
+}
+
+// ListAll mocks base method.
+func (m *MockPermissionTokenClient) ListAll(arg0 context.Context) (*cosmosdb.Permissions, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAll", arg0)
+	ret0, _ := ret[0].(*cosmosdb.Permissions)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAll indicates an expected call of ListAll.
+func (mr *MockPermissionTokenClientMockRecorder) ListAll(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAll", reflect.TypeOf((*MockPermissionTokenClient)(nil).ListAll), arg0)
+}
+
+// Replace mocks base method.
+func (m *MockPermissionTokenClient) Replace(arg0 context.Context, arg1 *cosmosdb.Permission) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Replace", arg0, arg1)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Replace indicates an expected call of Replace.
+func (mr *MockPermissionTokenClientMockRecorder) Replace(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Replace", reflect.TypeOf((*MockPermissionTokenClient)(nil).Replace), arg0, arg1)
+}
+
+// Upsert mocks base method.
+func (m *MockPermissionTokenClient) Upsert(arg0 context.Context, arg1 *cosmosdb.Permission, arg2 string) (*cosmosdb.Permission, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Upsert", arg0, arg1, arg2)
+	ret0, _ := ret[0].(*cosmosdb.Permission)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Upsert indicates an expected call of Upsert.
+func (mr *MockPermissionTokenClientMockRecorder) Upsert(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Upsert", reflect.TypeOf((*MockPermissionTokenClient)(nil).Upsert), arg0, arg1, arg2)
+}
@@ -227,3 +227,17 @@ func (mr *MockCoreMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockCore)(nil).TenantID))
 }
+
+// Zone mocks base method.
+func (m *MockCore) Zone() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Zone")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Zone indicates an expected call of Zone.
+func (mr *MockCoreMockRecorder) Zone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Zone", reflect.TypeOf((*MockCore)(nil).Zone))
+}
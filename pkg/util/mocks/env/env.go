@@ -583,6 +583,20 @@ func (mr *MockInterfaceMockRecorder) TenantID() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockInterface)(nil).TenantID))
 }
 
+// UpgradeGraphURL mocks base method.
+func (m *MockInterface) UpgradeGraphURL() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpgradeGraphURL")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// UpgradeGraphURL indicates an expected call of UpgradeGraphURL.
+func (mr *MockInterfaceMockRecorder) UpgradeGraphURL() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpgradeGraphURL", reflect.TypeOf((*MockInterface)(nil).UpgradeGraphURL))
+}
+
 // VMSku mocks base method.
 func (m *MockInterface) VMSku(vmSize string) (*compute.ResourceSku, error) {
 	m.ctrl.T.Helper()
@@ -597,3 +611,17 @@ func (mr *MockInterfaceMockRecorder) VMSku(vmSize interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VMSku", reflect.TypeOf((*MockInterface)(nil).VMSku), vmSize)
 }
+
+// Zone mocks base method.
+func (m *MockInterface) Zone() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Zone")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Zone indicates an expected call of Zone.
+func (mr *MockInterfaceMockRecorder) Zone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Zone", reflect.TypeOf((*MockInterface)(nil).Zone))
+}
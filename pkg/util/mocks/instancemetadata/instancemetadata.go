@@ -118,3 +118,17 @@ func (mr *MockInstanceMetadataMockRecorder) TenantID() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "TenantID", reflect.TypeOf((*MockInstanceMetadata)(nil).TenantID))
 }
+
+// Zone mocks base method.
+func (m *MockInstanceMetadata) Zone() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Zone")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Zone indicates an expected call of Zone.
+func (mr *MockInstanceMetadataMockRecorder) Zone() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Zone", reflect.TypeOf((*MockInstanceMetadata)(nil).Zone))
+}
@@ -83,10 +83,10 @@ func (mr *MockManagerMockRecorder) GetBase64Secret(arg0, arg1, arg2 interface{})
 }
 
 // GetBase64Secrets mocks base method.
-func (m *MockManager) GetBase64Secrets(arg0 context.Context, arg1 string) ([][]byte, error) {
+func (m *MockManager) GetBase64Secrets(arg0 context.Context, arg1 string) (map[string][]byte, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetBase64Secrets", arg0, arg1)
-	ret0, _ := ret[0].([][]byte)
+	ret0, _ := ret[0].(map[string][]byte)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
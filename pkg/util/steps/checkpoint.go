@@ -0,0 +1,53 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Checkpointer records which steps of a run have completed, so that a later
+// run of the same steps (for example after the backend lease was lost) can
+// resume rather than starting from the top.
+type Checkpointer interface {
+	IsCompleted(key string) bool
+	SetCompleted(ctx context.Context, key string) error
+}
+
+// Skippable returns a wrapper Step which opts `step` in to being skipped by
+// RunWithCheckpoints when a previous run has already completed it. Only
+// idempotent steps whose effects are persisted outside of the process (Azure
+// resources, database fields, etc.) should be skippable: steps which populate
+// in-memory state, such as client initialisation, must always run.
+func Skippable(step Step) Step {
+	return skippableStep{
+		Step: step,
+	}
+}
+
+type skippableStep struct {
+	Step
+}
+
+func (s skippableStep) run(ctx context.Context, log *logrus.Entry) error {
+	return s.Step.run(ctx, log)
+}
+
+func (s skippableStep) String() string {
+	return fmt.Sprintf("[Skippable %s]", s.Step)
+}
+
+func (s skippableStep) metricsName() string {
+	return s.Step.metricsName()
+}
+
+// checkpointKey returns the key under which the completion of the step at
+// index i is recorded. The index is included so that steps which appear more
+// than once in a list are tracked independently.
+func checkpointKey(i int, step Step) string {
+	return fmt.Sprintf("%d.%s", i, step.metricsName())
+}
@@ -0,0 +1,104 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Parallel returns a Step which runs the provided steps concurrently and waits
+// for all of them to finish. It must only be used for steps which have no
+// ordering dependencies between each other and which do not mutate shared
+// state (e.g. the manager's copy of the cluster document).
+//
+// All steps are run to completion, even if one of them fails. If a single
+// step fails, its error is returned directly; if several fail, their errors
+// are joined. Each failed step's error is logged as it fails, naming the step;
+// the error returned for the group is not logged again. Timings are recorded
+// for each inner step individually.
+func Parallel(steps ...Step) Step {
+	return parallelStep{
+		steps: steps,
+	}
+}
+
+type parallelStep struct {
+	steps []Step
+}
+
+func (s parallelStep) run(ctx context.Context, log *logrus.Entry) error {
+	_, err := s.runTimed(ctx, log, nil)
+	return err
+}
+
+// runTimed runs the inner steps concurrently, returning the time taken by
+// each of them keyed by metrics name. Timings are only recorded if now is
+// non-nil.
+func (s parallelStep) runTimed(ctx context.Context, log *logrus.Entry, now func() time.Time) (map[string]int64, error) {
+	var (
+		mu   sync.Mutex
+		wg   sync.WaitGroup
+		errs []error
+	)
+	stepTimeRun := make(map[string]int64)
+
+	for _, step := range s.steps {
+		wg.Add(1)
+		go func(step Step) {
+			defer wg.Done()
+
+			log.Infof("running step %s", step)
+
+			timings, err := runStep(ctx, log, step, now)
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			if err != nil {
+				logStepError(log, step, err)
+				errs = append(errs, err)
+				return
+			}
+
+			for name, duration := range timings {
+				stepTimeRun[name] = duration
+			}
+		}(step)
+	}
+
+	wg.Wait()
+
+	switch len(errs) {
+	case 0:
+		return stepTimeRun, nil
+	case 1:
+		// preserve the error type (e.g. *api.CloudError) for callers
+		return nil, errs[0]
+	default:
+		return nil, errors.Join(errs...)
+	}
+}
+
+func (s parallelStep) String() string {
+	names := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		names = append(names, step.String())
+	}
+	return fmt.Sprintf("[Parallel %s]", strings.Join(names, ", "))
+}
+
+func (s parallelStep) metricsName() string {
+	names := make([]string, 0, len(s.steps))
+	for _, step := range s.steps {
+		names = append(names, step.metricsName())
+	}
+	return fmt.Sprintf("parallel[%s]", strings.Join(names, ","))
+}
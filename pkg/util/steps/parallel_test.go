@@ -0,0 +1,149 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	utilerror "github.com/Azure/ARO-RP/test/util/error"
+	testlog "github.com/Azure/ARO-RP/test/util/log"
+)
+
+func otherFailingFunc(context.Context) error { return errors.New("oh dear!") }
+
+func TestParallel(t *testing.T) {
+	// each action waits for the other to start, which can only succeed if
+	// they are run concurrently
+	first, second := make(chan struct{}), make(chan struct{})
+	rendezvous := func(mine, theirs chan struct{}) actionFunction {
+		return func(ctx context.Context) error {
+			close(mine)
+			select {
+			case <-theirs:
+				return nil
+			case <-time.After(5 * time.Second):
+				return errors.New("steps were not run concurrently")
+			}
+		}
+	}
+
+	cloudErr := api.NewCloudError(400, api.CloudErrorCodeInvalidParameter, "", "bad")
+
+	for _, tt := range []struct {
+		name        string
+		steps       []Step
+		wantTimings map[string]int64
+		wantErr     string
+		wantErrs    []string
+		wantErrType error
+	}{
+		{
+			name: "steps run concurrently and record individual timings",
+			steps: []Step{
+				Parallel(
+					Action(rendezvous(first, second)),
+					Action(rendezvous(second, first)),
+				),
+				Action(successfulFunc),
+			},
+			wantTimings: map[string]int64{
+				"action.func1":          0,
+				"action.successfulFunc": 0,
+			},
+		},
+		{
+			name: "a single failure is returned directly",
+			steps: []Step{
+				Parallel(
+					Action(successfulFunc),
+					Action(func(context.Context) error { return cloudErr }),
+				),
+			},
+			wantErr:     cloudErr.Error(),
+			wantErrType: cloudErr,
+		},
+		{
+			name: "multiple failures are joined",
+			steps: []Step{
+				Skippable(Parallel(
+					Action(failingFunc),
+					Action(otherFailingFunc),
+				)),
+			},
+			wantErrs: []string{"oh no!", "oh dear!"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			_, log := testlog.New()
+
+			timings, err := Run(ctx, log, 25*time.Millisecond, tt.steps, currentTimeFunc)
+			if tt.wantErrType != nil {
+				if err != tt.wantErrType {
+					t.Errorf("got error %#v, want %#v", err, tt.wantErrType)
+				}
+			} else if tt.wantErrs != nil {
+				// ordering of joined errors depends on which step finished first
+				for _, wantErr := range tt.wantErrs {
+					if err == nil || !strings.Contains(err.Error(), wantErr) {
+						t.Errorf("got error %v, want it to contain %q", err, wantErr)
+					}
+				}
+			} else {
+				utilerror.AssertErrorMessage(t, err, tt.wantErr)
+			}
+
+			if err == nil && !reflect.DeepEqual(timings, tt.wantTimings) {
+				t.Errorf("got timings %v, want %v", timings, tt.wantTimings)
+			}
+		})
+	}
+}
+
+func TestParallelLogsFailuresOnce(t *testing.T) {
+	ctx := context.Background()
+	h, log := testlog.New()
+
+	_, err := Run(ctx, log, 25*time.Millisecond, []Step{
+		Parallel(
+			Action(successfulFunc),
+			Action(failingFunc),
+		),
+	}, currentTimeFunc)
+	utilerror.AssertErrorMessage(t, err, "oh no!")
+
+	var failures []string
+	for _, e := range h.AllEntries() {
+		if e.Level == logrus.ErrorLevel {
+			failures = append(failures, e.Message)
+		}
+	}
+
+	want := []string{"step [Action github.com/Azure/ARO-RP/pkg/util/steps.failingFunc] encountered error: oh no!"}
+	if !reflect.DeepEqual(failures, want) {
+		t.Errorf("got error log entries %v, want %v", failures, want)
+	}
+}
+
+func TestParallelNaming(t *testing.T) {
+	step := Parallel(Action(successfulFunc), Condition(alwaysTrueCondition, time.Second, true))
+
+	wantString := "[Parallel [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 1s]]"
+	if got := step.String(); got != wantString {
+		t.Errorf("got %s, want %s", got, wantString)
+	}
+
+	wantMetricsName := "parallel[action.successfulFunc,condition.alwaysTrueCondition]"
+	if got := step.metricsName(); got != wantMetricsName {
+		t.Errorf("got %s, want %s", got, wantMetricsName)
+	}
+}
@@ -0,0 +1,88 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"time"
+)
+
+// PlannedStep describes what RunWithCheckpoints would do with a step, without
+// running it.
+type PlannedStep struct {
+	Step string `json:"step"`
+
+	// Timeout is how long the step will poll or retry for before giving up,
+	// if it does.
+	Timeout string `json:"timeout,omitempty"`
+
+	// ContinueOnTimeout is true for conditions which do not fail the run when
+	// they time out.
+	ContinueOnTimeout bool `json:"continueOnTimeout,omitempty"`
+
+	// Skip is true for Skippable steps which have already been completed
+	// according to the Checkpointer.
+	Skip bool `json:"skip,omitempty"`
+}
+
+// Plan returns a description of each of the provided steps. If cp is non-nil,
+// Skippable steps which it reports as completed are marked to be skipped.
+func Plan(steps []Step, cp Checkpointer) []PlannedStep {
+	planned := make([]PlannedStep, 0, len(steps))
+
+	for i, step := range steps {
+		p := PlannedStep{
+			Step: step.String(),
+		}
+
+		if timeout := stepTimeout(step); timeout != 0 {
+			p.Timeout = timeout.String()
+		}
+
+		if c, ok := unwrapSkippable(step).(conditionStep); ok {
+			p.ContinueOnTimeout = !c.fail
+		}
+
+		if _, skippable := step.(skippableStep); skippable && cp != nil {
+			p.Skip = cp.IsCompleted(checkpointKey(i, step))
+		}
+
+		planned = append(planned, p)
+	}
+
+	return planned
+}
+
+// stepTimeout returns how long the step will poll or retry for before giving
+// up, or zero if it does not.
+func stepTimeout(step Step) time.Duration {
+	switch s := unwrapSkippable(step).(type) {
+	case conditionStep:
+		return s.timeout
+	case *authorizationRefreshingActionStep:
+		if s.retryTimeout == 0 {
+			return defaultAuthorizationRetryTimeout
+		}
+		return s.retryTimeout
+	case parallelStep:
+		var longest time.Duration
+		for _, inner := range s.steps {
+			timeout := stepTimeout(inner)
+			if timeout == 0 {
+				return 0
+			}
+			if timeout > longest {
+				longest = timeout
+			}
+		}
+		return longest
+	}
+	return 0
+}
+
+func unwrapSkippable(step Step) Step {
+	if s, ok := step.(skippableStep); ok {
+		return s.Step
+	}
+	return step
+}
@@ -0,0 +1,60 @@
+package steps
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-test/deep"
+)
+
+func TestPlan(t *testing.T) {
+	s := []Step{
+		Action(successfulFunc),
+		Skippable(Action(successfulFunc)),
+		Skippable(Condition(alwaysTrueCondition, time.Minute, true)),
+		Condition(alwaysFalseCondition, 5*time.Minute, false),
+		AuthorizationRetryingAction(nil, successfulFunc),
+		Parallel(
+			Condition(alwaysTrueCondition, time.Minute, true),
+			Condition(alwaysTrueCondition, 2*time.Minute, true),
+		),
+	}
+
+	cp := fakeCheckpointer{
+		"1.action.successfulFunc": true,
+	}
+
+	want := []PlannedStep{
+		{
+			Step: "[Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+		},
+		{
+			Step: "[Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]]",
+			Skip: true,
+		},
+		{
+			Step:    "[Skippable [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 1m0s]]",
+			Timeout: "1m0s",
+		},
+		{
+			Step:              "[Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysFalseCondition, timeout 5m0s]",
+			Timeout:           "5m0s",
+			ContinueOnTimeout: true,
+		},
+		{
+			Step:    "[AuthorizationRetryingAction github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]",
+			Timeout: "10m0s",
+		},
+		{
+			Step:    "[Parallel [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 1m0s], [Condition github.com/Azure/ARO-RP/pkg/util/steps.alwaysTrueCondition, timeout 2m0s]]",
+			Timeout: "2m0s",
+		},
+	}
+
+	for _, diff := range deep.Equal(Plan(s, cp), want) {
+		t.Error(diff)
+	}
+}
@@ -20,6 +20,9 @@ import (
 
 var ErrWantRefresh = errors.New("want refresh")
 
+// ARM role caching can be 5 minutes
+const defaultAuthorizationRetryTimeout = 10 * time.Minute
+
 // AuthorizationRefreshingAction returns a wrapper Step which will refresh
 // `authorizer` if the step returns an Azure AuthenticationError and rerun it.
 // The step will be retried until `retryTimeout` is hit. Any other error will be
@@ -45,9 +48,8 @@ func (s *authorizationRefreshingActionStep) run(ctx context.Context, log *logrus
 		retryTimeout time.Duration
 	)
 
-	// ARM role caching can be 5 minutes
 	if s.retryTimeout == time.Duration(0) {
-		retryTimeout = 10 * time.Minute
+		retryTimeout = defaultAuthorizationRetryTimeout
 	} else {
 		retryTimeout = s.retryTimeout
 	}
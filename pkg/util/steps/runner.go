@@ -41,29 +41,86 @@ type Step interface {
 	metricsName() string
 }
 
-// Run executes the provided steps in order until one fails or all steps
-// are completed. Errors from failed steps are returned directly.
+// Run executes the provided steps in order until one fails, ctx is
+// cancelled or all steps are completed. Errors from failed steps are returned directly.
 // time cost for each step run will be recorded for metrics usage
 func Run(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, steps []Step, now func() time.Time) (map[string]int64, error) {
+	return RunWithCheckpoints(ctx, log, pollInterval, steps, now, nil)
+}
+
+// RunWithCheckpoints behaves like Run, but additionally records the completion
+// of each Skippable step with cp. Skippable steps which cp reports as already
+// completed by a previous run are skipped, so that the run resumes at the
+// first incomplete step. Steps which are not Skippable always run.
+func RunWithCheckpoints(ctx context.Context, log *logrus.Entry, pollInterval time.Duration, steps []Step, now func() time.Time, cp Checkpointer) (map[string]int64, error) {
 	stepTimeRun := make(map[string]int64)
-	for _, step := range steps {
-		log.Infof("running step %s", step)
+	for i, step := range steps {
+		// stop between steps if the run has been cancelled
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		_, skippable := step.(skippableStep)
+		key := checkpointKey(i, step)
+
+		if cp != nil && skippable && cp.IsCompleted(key) {
+			log.Infof("skipping completed step %s", step)
+			continue
+		}
 
-		startTime := time.Now()
-		err := step.run(ctx, log)
+		log.Infof("running step %s", step)
 
+		timings, err := runStep(ctx, log, step, now)
 		if err != nil {
-			log.Errorf("step %s encountered error: %s", step, err.Error())
-			if oDataError, ok := err.(msgraph_errors.ODataErrorable); ok {
-				spew.Fdump(log.Writer(), oDataError.GetErrorEscaped())
-			}
+			logStepError(log, step, err)
 			return nil, err
 		}
 
-		if now != nil {
-			currentTime := now()
-			stepTimeRun[step.metricsName()] = int64(currentTime.Sub(startTime).Seconds())
+		for name, duration := range timings {
+			stepTimeRun[name] = duration
 		}
+
+		if cp != nil && skippable {
+			err = cp.SetCompleted(ctx, key)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return stepTimeRun, nil
+}
+
+// logStepError logs the error of a failed step.  Parallel groups log the
+// errors of their own steps as they fail, so they are not logged again.
+func logStepError(log *logrus.Entry, step Step, err error) {
+	if _, ok := unwrapSkippable(step).(parallelStep); ok {
+		return
+	}
+
+	log.Errorf("step %s encountered error: %s", step, err.Error())
+	if oDataError, ok := err.(msgraph_errors.ODataErrorable); ok {
+		spew.Fdump(log.Writer(), oDataError.GetErrorEscaped())
+	}
+}
+
+// runStep runs a single step, returning the time it took keyed by its metrics
+// name. Steps which group other steps report the time taken by each inner
+// step instead. Timings are only recorded if now is non-nil.
+func runStep(ctx context.Context, log *logrus.Entry, step Step, now func() time.Time) (map[string]int64, error) {
+	if p, ok := unwrapSkippable(step).(parallelStep); ok {
+		return p.runTimed(ctx, log, now)
+	}
+
+	startTime := time.Now()
+	err := step.run(ctx, log)
+	if err != nil {
+		return nil, err
+	}
+
+	stepTimeRun := make(map[string]int64)
+	if now != nil {
+		currentTime := now()
+		stepTimeRun[step.metricsName()] = int64(currentTime.Sub(startTime).Seconds())
 	}
 	return stepTimeRun, nil
 }
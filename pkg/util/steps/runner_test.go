@@ -6,6 +6,7 @@ package steps
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 	"time"
 
@@ -248,6 +249,30 @@ func TestStepRunner(t *testing.T) {
 	}
 }
 
+func TestStepRunnerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var ran bool
+	steps := []Step{
+		Action(func(context.Context) error {
+			cancel()
+			return nil
+		}),
+		Action(func(context.Context) error {
+			ran = true
+			return nil
+		}),
+	}
+
+	_, err := Run(ctx, logrus.NewEntry(logrus.StandardLogger()), 25*time.Millisecond, steps, currentTimeFunc)
+	utilerror.AssertErrorMessage(t, err, "context canceled")
+
+	if ran {
+		t.Error("step ran after the context was cancelled")
+	}
+}
+
 func TestStepMetricsNameFormatting(t *testing.T) {
 	for _, tt := range []struct {
 		desc string
@@ -277,3 +302,117 @@ func TestStepMetricsNameFormatting(t *testing.T) {
 		})
 	}
 }
+
+type fakeCheckpointer map[string]bool
+
+func (cp fakeCheckpointer) IsCompleted(key string) bool {
+	return cp[key]
+}
+
+func (cp fakeCheckpointer) SetCompleted(ctx context.Context, key string) error {
+	cp[key] = true
+	return nil
+}
+
+func TestStepRunnerWithCheckpoints(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		steps         []Step
+		checkpoints   fakeCheckpointer
+		wantEntries   []map[string]types.GomegaMatcher
+		wantCompleted fakeCheckpointer
+		wantErr       string
+	}{
+		{
+			name: "Skippable steps are checkpointed",
+			steps: []Step{
+				Action(successfulFunc),
+				Skippable(Action(successfulFunc)),
+			},
+			checkpoints: fakeCheckpointer{},
+			wantEntries: []map[string]types.GomegaMatcher{
+				{
+					"msg":   gomega.Equal("running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+				{
+					"msg":   gomega.Equal("running step [Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+			},
+			wantCompleted: fakeCheckpointer{
+				"1.action.successfulFunc": true,
+			},
+		},
+		{
+			name: "Completed skippable steps are skipped, other steps are rerun",
+			steps: []Step{
+				Action(successfulFunc),
+				Skippable(Action(successfulFunc)),
+				Skippable(Action(failingFunc)),
+			},
+			checkpoints: fakeCheckpointer{
+				"1.action.successfulFunc": true,
+			},
+			wantEntries: []map[string]types.GomegaMatcher{
+				{
+					"msg":   gomega.Equal("running step [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+				{
+					"msg":   gomega.Equal("skipping completed step [Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+				{
+					"msg":   gomega.Equal("running step [Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.failingFunc]]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+				{
+					"msg":   gomega.Equal("step [Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.failingFunc]] encountered error: oh no!"),
+					"level": gomega.Equal(logrus.ErrorLevel),
+				},
+			},
+			wantCompleted: fakeCheckpointer{
+				"1.action.successfulFunc": true,
+			},
+			wantErr: "oh no!",
+		},
+		{
+			name: "Checkpoints of a different step at the same position are ignored",
+			steps: []Step{
+				Skippable(Action(successfulFunc)),
+			},
+			checkpoints: fakeCheckpointer{
+				"0.action.failingFunc": true,
+			},
+			wantEntries: []map[string]types.GomegaMatcher{
+				{
+					"msg":   gomega.Equal("running step [Skippable [Action github.com/Azure/ARO-RP/pkg/util/steps.successfulFunc]]"),
+					"level": gomega.Equal(logrus.InfoLevel),
+				},
+			},
+			wantCompleted: fakeCheckpointer{
+				"0.action.failingFunc":    true,
+				"0.action.successfulFunc": true,
+			},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			h, log := testlog.New()
+
+			_, err := RunWithCheckpoints(ctx, log, 25*time.Millisecond, tt.steps, currentTimeFunc, tt.checkpoints)
+			utilerror.AssertErrorMessage(t, err, tt.wantErr)
+
+			err = testlog.AssertLoggingOutput(h, tt.wantEntries)
+			if err != nil {
+				t.Error(err)
+			}
+
+			if !reflect.DeepEqual(tt.checkpoints, tt.wantCompleted) {
+				t.Errorf("got checkpoints %v, want %v", tt.checkpoints, tt.wantCompleted)
+			}
+		})
+	}
+}
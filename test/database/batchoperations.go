@@ -0,0 +1,54 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func injectBatchOperations(c *cosmosdb.FakeBatchOperationDocumentClient) {
+	c.SetQueryHandler(database.BatchOperationsDequeueQuery, fakeBatchOperationsDequeueQuery)
+
+	c.SetTriggerHandler("renewLease", fakeBatchOperationsRenewLeaseTrigger)
+
+	c.SetSorter(func(in []*api.BatchOperationDocument) {
+		sort.Slice(in, func(i, j int) bool { return in[i].ID < in[j].ID })
+	})
+}
+
+func fakeBatchOperationsDequeueQuery(client cosmosdb.BatchOperationDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.BatchOperationDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), nil)
+	if err != nil {
+		return cosmosdb.NewFakeBatchOperationDocumentErroringRawIterator(err)
+	}
+
+	var docs []*api.BatchOperationDocument
+	for _, doc := range input.BatchOperationDocuments {
+		switch doc.BatchOperation.State {
+		case api.BatchOperationStatePending, api.BatchOperationStateRunning:
+		default:
+			continue
+		}
+
+		if int64(doc.LeaseExpires) >= time.Now().Unix() {
+			continue
+		}
+
+		docs = append(docs, doc)
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].ID < docs[j].ID })
+
+	return cosmosdb.NewFakeBatchOperationDocumentIterator(docs, 0)
+}
+
+func fakeBatchOperationsRenewLeaseTrigger(ctx context.Context, doc *api.BatchOperationDocument) error {
+	doc.LeaseExpires = int(time.Now().Unix()) + 60
+	return nil
+}
@@ -18,14 +18,16 @@ import (
 const deletionTimeSetSentinel = 123456789
 
 type Checker struct {
-	openshiftClusterDocuments []*api.OpenShiftClusterDocument
-	subscriptionDocuments     []*api.SubscriptionDocument
-	billingDocuments          []*api.BillingDocument
-	asyncOperationDocuments   []*api.AsyncOperationDocument
-	portalDocuments           []*api.PortalDocument
-	gatewayDocuments          []*api.GatewayDocument
-	openShiftVersionDocuments []*api.OpenShiftVersionDocument
-	validationResult          []*api.ValidationResult
+	openshiftClusterDocuments  []*api.OpenShiftClusterDocument
+	subscriptionDocuments      []*api.SubscriptionDocument
+	billingDocuments           []*api.BillingDocument
+	asyncOperationDocuments    []*api.AsyncOperationDocument
+	portalDocuments            []*api.PortalDocument
+	gatewayDocuments           []*api.GatewayDocument
+	openShiftVersionDocuments  []*api.OpenShiftVersionDocument
+	batchOperationDocuments    []*api.BatchOperationDocument
+	portalAccessGrantDocuments []*api.PortalAccessGrantDocument
+	validationResult           []*api.ValidationResult
 }
 
 func NewChecker() *Checker {
@@ -120,6 +122,28 @@ func (f *Checker) AddValidationResult(docs ...*api.ValidationResult) {
 	}
 }
 
+func (f *Checker) AddBatchOperationDocuments(docs ...*api.BatchOperationDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.batchOperationDocuments = append(f.batchOperationDocuments, docCopy.(*api.BatchOperationDocument))
+	}
+}
+
+func (f *Checker) AddPortalAccessGrantDocuments(docs ...*api.PortalAccessGrantDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.portalAccessGrantDocuments = append(f.portalAccessGrantDocuments, docCopy.(*api.PortalAccessGrantDocument))
+	}
+}
+
 func (f *Checker) CheckOpenShiftClusters(openShiftClusters *cosmosdb.FakeOpenShiftClusterDocumentClient) (errs []error) {
 	ctx := context.Background()
 
@@ -273,3 +297,49 @@ func (f *Checker) CheckOpenShiftVersions(versions *cosmosdb.FakeOpenShiftVersion
 
 	return errs
 }
+
+func (f *Checker) CheckBatchOperations(batchOperations *cosmosdb.FakeBatchOperationDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := batchOperations.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	sort.Slice(all.BatchOperationDocuments, func(i, j int) bool { return all.BatchOperationDocuments[i].ID < all.BatchOperationDocuments[j].ID })
+
+	if len(f.batchOperationDocuments) != 0 && len(all.BatchOperationDocuments) == len(f.batchOperationDocuments) {
+		diff := deep.Equal(all.BatchOperationDocuments, f.batchOperationDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.BatchOperationDocuments) != 0 || len(f.batchOperationDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("batchOperations length different, %d vs %d", len(all.BatchOperationDocuments), len(f.batchOperationDocuments)))
+	}
+
+	return errs
+}
+
+func (f *Checker) CheckPortalAccessGrants(portalAccessGrants *cosmosdb.FakePortalAccessGrantDocumentClient) (errs []error) {
+	ctx := context.Background()
+
+	all, err := portalAccessGrants.ListAll(ctx, nil)
+	if err != nil {
+		return []error{err}
+	}
+
+	sort.Slice(all.PortalAccessGrantDocuments, func(i, j int) bool {
+		return all.PortalAccessGrantDocuments[i].ID < all.PortalAccessGrantDocuments[j].ID
+	})
+
+	if len(f.portalAccessGrantDocuments) != 0 && len(all.PortalAccessGrantDocuments) == len(f.portalAccessGrantDocuments) {
+		diff := deep.Equal(all.PortalAccessGrantDocuments, f.portalAccessGrantDocuments)
+		for _, i := range diff {
+			errs = append(errs, errors.New(i))
+		}
+	} else if len(all.PortalAccessGrantDocuments) != 0 || len(f.portalAccessGrantDocuments) != 0 {
+		errs = append(errs, fmt.Errorf("portalAccessGrants length different, %d vs %d", len(all.PortalAccessGrantDocuments), len(f.portalAccessGrantDocuments)))
+	}
+
+	return errs
+}
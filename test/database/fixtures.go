@@ -20,6 +20,8 @@ type Fixture struct {
 	gatewayDocuments                     []*api.GatewayDocument
 	openShiftVersionDocuments            []*api.OpenShiftVersionDocument
 	clusterManagerConfigurationDocuments []*api.ClusterManagerConfigurationDocument
+	batchOperationDocuments              []*api.BatchOperationDocument
+	portalAccessGrantDocuments           []*api.PortalAccessGrantDocument
 
 	openShiftClustersDatabase            database.OpenShiftClusters
 	billingDatabase                      database.Billing
@@ -29,6 +31,8 @@ type Fixture struct {
 	gatewayDatabase                      database.Gateway
 	openShiftVersionsDatabase            database.OpenShiftVersions
 	clusterManagerConfigurationsDatabase database.ClusterManagerConfigurations
+	batchOperationsDatabase              database.BatchOperations
+	portalAccessGrantsDatabase           database.PortalAccessGrants
 
 	openShiftVersionsUUID uuid.Generator
 }
@@ -78,6 +82,16 @@ func (f *Fixture) WithOpenShiftVersions(db database.OpenShiftVersions, uuid uuid
 	return f
 }
 
+func (f *Fixture) WithBatchOperations(db database.BatchOperations) *Fixture {
+	f.batchOperationsDatabase = db
+	return f
+}
+
+func (f *Fixture) WithPortalAccessGrants(db database.PortalAccessGrants) *Fixture {
+	f.portalAccessGrantsDatabase = db
+	return f
+}
+
 func (f *Fixture) AddOpenShiftClusterDocuments(docs ...*api.OpenShiftClusterDocument) {
 	for _, doc := range docs {
 		docCopy, err := deepCopy(doc)
@@ -166,6 +180,28 @@ func (f *Fixture) AddClusterManagerConfigurationDocuments(docs ...*api.ClusterMa
 	}
 }
 
+func (f *Fixture) AddBatchOperationDocuments(docs ...*api.BatchOperationDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.batchOperationDocuments = append(f.batchOperationDocuments, docCopy.(*api.BatchOperationDocument))
+	}
+}
+
+func (f *Fixture) AddPortalAccessGrantDocuments(docs ...*api.PortalAccessGrantDocument) {
+	for _, doc := range docs {
+		docCopy, err := deepCopy(doc)
+		if err != nil {
+			panic(err)
+		}
+
+		f.portalAccessGrantDocuments = append(f.portalAccessGrantDocuments, docCopy.(*api.PortalAccessGrantDocument))
+	}
+}
+
 func (f *Fixture) Create() error {
 	ctx := context.Background()
 
@@ -234,5 +270,25 @@ func (f *Fixture) Create() error {
 		}
 	}
 
+	for _, i := range f.batchOperationDocuments {
+		if i.ID == "" {
+			i.ID = f.batchOperationsDatabase.NewUUID()
+		}
+		_, err := f.batchOperationsDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, i := range f.portalAccessGrantDocuments {
+		if i.ID == "" {
+			i.ID = f.portalAccessGrantsDatabase.NewUUID()
+		}
+		_, err := f.portalAccessGrantsDatabase.Create(ctx, i)
+		if err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
@@ -80,3 +80,19 @@ func NewFakeClusterManager() (db database.ClusterManagerConfigurations, client *
 	db = database.NewClusterManagerConfigurationsWithProvidedClient(client, coll, "", uuid)
 	return db, client
 }
+
+func NewFakeBatchOperations() (db database.BatchOperations, client *cosmosdb.FakeBatchOperationDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.BATCHOPERATIONS)
+	client = cosmosdb.NewFakeBatchOperationDocumentClient(jsonHandle)
+	injectBatchOperations(client)
+	db = database.NewBatchOperationsWithProvidedClient(client, "", uuid)
+	return db, client
+}
+
+func NewFakePortalAccessGrants() (db database.PortalAccessGrants, client *cosmosdb.FakePortalAccessGrantDocumentClient) {
+	uuid := deterministicuuid.NewTestUUIDGenerator(deterministicuuid.PORTALACCESSGRANTS)
+	client = cosmosdb.NewFakePortalAccessGrantDocumentClient(jsonHandle)
+	injectPortalAccessGrants(client)
+	db = database.NewPortalAccessGrantsWithProvidedClient(client, uuid)
+	return db, client
+}
@@ -53,11 +53,40 @@ func getQueuedOpenShiftDocuments(client cosmosdb.OpenShiftClusterDocumentClient)
 }
 
 func fakeOpenShiftClustersQueueLengthQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
-	results, err := getQueuedOpenShiftDocuments(client)
+	docs, err := getQueuedOpenShiftDocuments(client)
+	if err != nil {
+		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+	}
+
+	results := make([]map[string]interface{}, 0, len(docs))
+	for _, r := range docs {
+		results = append(results, map[string]interface{}{
+			"partitionKey":      r.PartitionKey,
+			"provisioningState": r.OpenShiftCluster.Properties.ProvisioningState,
+		})
+	}
+	return &fakeProjectionIterator{documents: results}
+}
+
+func fakeOpenShiftClustersHiveShardQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	docs, err := fakeOpenShiftClustersGetAllDocuments(client)
 	if err != nil {
 		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
 	}
-	return &fakeOpenShiftClustersQueueLengthIterator{resultCount: len(results)}
+
+	var count int
+	for _, r := range docs {
+		hiveProfile := r.OpenShiftCluster.Properties.HiveProfile
+
+		shard := hiveProfile.Shard
+		if shard == 0 && hiveProfile.Namespace != "" {
+			shard = 1
+		}
+		if strconv.Itoa(shard) == query.Parameters[0].Value {
+			count++
+		}
+	}
+	return &fakeOpenShiftClustersQueueLengthIterator{resultCount: count}
 }
 
 func fakeOpenShiftClustersDequeueQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
@@ -65,7 +94,41 @@ func fakeOpenShiftClustersDequeueQuery(client cosmosdb.OpenShiftClusterDocumentC
 	if err != nil {
 		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
 	}
-	return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(docs, 0)
+
+	results := make([]*api.OpenShiftClusterDocument, 0, len(docs))
+	for _, r := range docs {
+		results = append(results, &api.OpenShiftClusterDocument{
+			ID:               r.ID,
+			Key:              r.Key,
+			PartitionKey:     r.PartitionKey,
+			EnqueueTime:      r.EnqueueTime,
+			AsyncOperationID: r.AsyncOperationID,
+			Timestamp:        r.Timestamp,
+			ETag:             r.ETag,
+			OpenShiftCluster: &api.OpenShiftCluster{
+				Properties: api.OpenShiftClusterProperties{
+					ProvisioningState: r.OpenShiftCluster.Properties.ProvisioningState,
+				},
+			},
+		})
+	}
+	return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(results, 0)
+}
+
+func fakeOpenShiftClustersLeasedCreatesQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	docs, err := fakeOpenShiftClustersGetAllDocuments(client)
+	if err != nil {
+		return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+	}
+
+	results := []string{}
+	for _, r := range docs {
+		if r.OpenShiftCluster.Properties.ProvisioningState == api.ProvisioningStateCreating &&
+			int64(r.LeaseExpires) >= time.Now().Unix() {
+			results = append(results, r.PartitionKey)
+		}
+	}
+	return &fakeProjectionIterator{documents: results}
 }
 
 func fakeOpenshiftClustersMatchQuery(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
@@ -136,6 +199,103 @@ func fakeOpenshiftClustersPrefixQuery(client cosmosdb.OpenShiftClusterDocumentCl
 	return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(results, startingIndex)
 }
 
+// fakeOpenShiftClustersSearchOrderBy compares two clusters by the properties
+// of database.OpenShiftClusterSearchOrderBy
+var fakeOpenShiftClustersSearchOrderBy = map[string]func(a, b *api.OpenShiftClusterDocument) bool{
+	"name": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.OpenShiftCluster.Name < b.OpenShiftCluster.Name
+	},
+	"subscription": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.PartitionKey < b.PartitionKey
+	},
+	"version": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.OpenShiftCluster.Properties.ClusterProfile.Version < b.OpenShiftCluster.Properties.ClusterProfile.Version
+	},
+	"provisioningstate": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.OpenShiftCluster.Properties.ProvisioningState < b.OpenShiftCluster.Properties.ProvisioningState
+	},
+	"maintenancestate": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.OpenShiftCluster.Properties.MaintenanceState < b.OpenShiftCluster.Properties.MaintenanceState
+	},
+	"createdat": func(a, b *api.OpenShiftClusterDocument) bool {
+		return a.OpenShiftCluster.Properties.CreatedAt.Before(b.OpenShiftCluster.Properties.CreatedAt)
+	},
+	"lastmodified": func(a, b *api.OpenShiftClusterDocument) bool {
+		var ta, tb time.Time
+		if a.OpenShiftCluster.SystemData.LastModifiedAt != nil {
+			ta = *a.OpenShiftCluster.SystemData.LastModifiedAt
+		}
+		if b.OpenShiftCluster.SystemData.LastModifiedAt != nil {
+			tb = *b.OpenShiftCluster.SystemData.LastModifiedAt
+		}
+		return ta.Before(tb)
+	},
+}
+
+func fakeOpenShiftClustersSearchQuery(orderBy string, descending bool) func(cosmosdb.OpenShiftClusterDocumentClient, *cosmosdb.Query, *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+	return func(client cosmosdb.OpenShiftClusterDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.OpenShiftClusterDocumentRawIterator {
+		startingIndex, err := fakeOpenShiftClustersGetContinuation(options)
+		if err != nil {
+			return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+		}
+
+		docs, err := fakeOpenShiftClustersGetAllDocuments(client)
+		if err != nil {
+			return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+		}
+
+		params := map[string]string{}
+		for _, p := range query.Parameters {
+			params[p.Name] = p.Value
+		}
+
+		var createdAfter, createdBefore time.Time
+		if params["@createdAfter"] != "" {
+			createdAfter, err = time.Parse(time.RFC3339Nano, params["@createdAfter"])
+			if err != nil {
+				return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+			}
+		}
+		if params["@createdBefore"] != "" {
+			createdBefore, err = time.Parse(time.RFC3339Nano, params["@createdBefore"])
+			if err != nil {
+				return cosmosdb.NewFakeOpenShiftClusterDocumentErroringRawIterator(err)
+			}
+		}
+
+		var results []*api.OpenShiftClusterDocument
+		for _, r := range docs {
+			props := r.OpenShiftCluster.Properties
+
+			shard := props.HiveProfile.Shard
+			if shard == 0 {
+				shard = 1
+			}
+
+			if (params["@subscriptionId"] == "" || r.PartitionKey == params["@subscriptionId"]) &&
+				strings.HasPrefix(props.ClusterProfile.Version, params["@version"]) &&
+				(params["@provisioningState"] == "" || string(props.ProvisioningState) == params["@provisioningState"]) &&
+				(params["@maintenanceState"] == "" || string(props.MaintenanceState) == params["@maintenanceState"]) &&
+				(params["@hiveShard"] == "" || props.HiveProfile.Namespace != "" && strconv.Itoa(shard) == params["@hiveShard"]) &&
+				(params["@operatorFlag"] == "" || props.OperatorFlags[params["@operatorFlag"]] == params["@operatorFlagValue"]) &&
+				(createdAfter.IsZero() || !props.CreatedAt.Before(createdAfter)) &&
+				(createdBefore.IsZero() || props.CreatedAt.Before(createdBefore)) {
+				results = append(results, r)
+			}
+		}
+
+		less := fakeOpenShiftClustersSearchOrderBy[orderBy]
+		sort.SliceStable(results, func(i, j int) bool {
+			if descending {
+				return less(results[j], results[i])
+			}
+			return less(results[i], results[j])
+		})
+
+		return cosmosdb.NewFakeOpenShiftClusterDocumentIterator(results, startingIndex)
+	}
+}
+
 func fakeOpenShiftClustersRenewLeaseTrigger(ctx context.Context, doc *api.OpenShiftClusterDocument) error {
 	doc.LeaseExpires = int(time.Now().Unix()) + 60
 	return nil
@@ -154,10 +314,18 @@ func openShiftClusterConflictChecker(one *api.OpenShiftClusterDocument, two *api
 func injectOpenShiftClusters(c *cosmosdb.FakeOpenShiftClusterDocumentClient) {
 	c.SetQueryHandler(database.OpenShiftClustersDequeueQuery, fakeOpenShiftClustersDequeueQuery)
 	c.SetQueryHandler(database.OpenShiftClustersQueueLengthQuery, fakeOpenShiftClustersQueueLengthQuery)
+	c.SetQueryHandler(database.OpenShiftClustersLeasedCreatesQuery, fakeOpenShiftClustersLeasedCreatesQuery)
+	c.SetQueryHandler(database.OpenShiftClustersHiveShardQuery, fakeOpenShiftClustersHiveShardQuery)
 	c.SetQueryHandler(database.OpenShiftClustersGetQuery, fakeOpenshiftClustersMatchQuery)
 	c.SetQueryHandler(database.OpenshiftClustersClientIdQuery, fakeOpenshiftClustersMatchQuery)
 	c.SetQueryHandler(database.OpenshiftClustersResourceGroupQuery, fakeOpenshiftClustersMatchQuery)
 	c.SetQueryHandler(database.OpenshiftClustersPrefixQuery, fakeOpenshiftClustersPrefixQuery)
+	for orderBy := range database.OpenShiftClusterSearchOrderBy {
+		for _, descending := range []bool{false, true} {
+			query, _ := database.OpenShiftClustersSearchOrderedQuery(orderBy, descending)
+			c.SetQueryHandler(query, fakeOpenShiftClustersSearchQuery(orderBy, descending))
+		}
+	}
 
 	c.SetTriggerHandler("renewLease", fakeOpenShiftClustersRenewLeaseTrigger)
 
@@ -190,6 +358,36 @@ func (i *fakeOpenShiftClustersQueueLengthIterator) Continuation() string {
 	return ""
 }
 
+// fakeProjectionIterator is a RawIterator that will produce a single page
+// holding the results of a query which projects documents
+type fakeProjectionIterator struct {
+	called    bool
+	documents interface{}
+}
+
+func (i *fakeProjectionIterator) Next(ctx context.Context, maxItemCount int) (*api.OpenShiftClusterDocuments, error) {
+	return nil, cosmosdb.ErrNotImplemented
+}
+
+func (i *fakeProjectionIterator) NextRaw(ctx context.Context, continuation int, out interface{}) error {
+	if i.called {
+		return errors.New("can't call twice")
+	}
+	i.called = true
+
+	b, err := json.Marshal(map[string]interface{}{
+		"Documents": i.documents,
+	})
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, out)
+}
+
+func (i *fakeProjectionIterator) Continuation() string {
+	return ""
+}
+
 func GetResourcePath(subscriptionID string, resourceID string) string {
 	return fmt.Sprintf("/subscriptions/%s/resourcegroups/resourceGroup/providers/Microsoft.RedHatOpenShift/openShiftClusters/%s", subscriptionID, resourceID)
 }
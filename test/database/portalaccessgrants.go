@@ -0,0 +1,44 @@
+package database
+
+// Copyright (c) Microsoft Corporation.
+// Licensed under the Apache License 2.0.
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Azure/ARO-RP/pkg/api"
+	"github.com/Azure/ARO-RP/pkg/database"
+	"github.com/Azure/ARO-RP/pkg/database/cosmosdb"
+)
+
+func injectPortalAccessGrants(c *cosmosdb.FakePortalAccessGrantDocumentClient) {
+	c.SetQueryHandler(database.PortalAccessGrantsListApprovedQuery, fakePortalAccessGrantsListApprovedQuery)
+
+	c.SetSorter(func(in []*api.PortalAccessGrantDocument) {
+		sort.Slice(in, func(i, j int) bool { return in[i].ID < in[j].ID })
+	})
+}
+
+func fakePortalAccessGrantsListApprovedQuery(client cosmosdb.PortalAccessGrantDocumentClient, query *cosmosdb.Query, options *cosmosdb.Options) cosmosdb.PortalAccessGrantDocumentRawIterator {
+	input, err := client.ListAll(context.Background(), nil)
+	if err != nil {
+		return cosmosdb.NewFakePortalAccessGrantDocumentErroringRawIterator(err)
+	}
+
+	params := map[string]string{}
+	for _, p := range query.Parameters {
+		params[p.Name] = p.Value
+	}
+
+	var docs []*api.PortalAccessGrantDocument
+	for _, doc := range input.PortalAccessGrantDocuments {
+		if doc.PortalAccessGrant.State == api.PortalAccessGrantStateApproved &&
+			doc.PortalAccessGrant.Username == params["@username"] &&
+			doc.PortalAccessGrant.ResourceID == params["@resourceId"] {
+			docs = append(docs, doc)
+		}
+	}
+
+	return cosmosdb.NewFakePortalAccessGrantDocumentIterator(docs, 0)
+}
@@ -19,6 +19,8 @@ const (
 	GATEWAY
 	OPENSHIFT_VERSIONS
 	CLUSTERMANAGER
+	BATCHOPERATIONS
+	PORTALACCESSGRANTS
 )
 
 type gen struct {
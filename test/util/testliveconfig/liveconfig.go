@@ -16,6 +16,7 @@ type testLiveConfig struct {
 	adoptByHive    bool
 	installViaHive bool
 	useCheckAccess bool
+	hiveShards     []liveconfig.HiveShard
 }
 
 func (t *testLiveConfig) HiveRestConfig(ctx context.Context, shard int) (*rest.Config, error) {
@@ -25,6 +26,13 @@ func (t *testLiveConfig) HiveRestConfig(ctx context.Context, shard int) (*rest.C
 	return nil, errors.New("testLiveConfig does not have a Hive")
 }
 
+func (t *testLiveConfig) HiveShards(ctx context.Context) ([]liveconfig.HiveShard, error) {
+	if t.hiveShards != nil {
+		return t.hiveShards, nil
+	}
+	return []liveconfig.HiveShard{{Index: 1}}, nil
+}
+
 func (t *testLiveConfig) InstallViaHive(ctx context.Context) (bool, error) {
 	return t.installViaHive, nil
 }
@@ -51,3 +59,11 @@ func NewTestLiveConfig(adoptByHive, installViaHive, useCheckAccess bool) livecon
 		useCheckAccess: useCheckAccess,
 	}
 }
+
+func NewTestLiveConfigWithHiveShards(adoptByHive, installViaHive bool, hiveShards []liveconfig.HiveShard) liveconfig.Manager {
+	return &testLiveConfig{
+		adoptByHive:    adoptByHive,
+		installViaHive: installViaHive,
+		hiveShards:     hiveShards,
+	}
+}